@@ -17,10 +17,61 @@ const (
 	AlertInfo     AlertLevel = "INFO"
 )
 
+// IncidentAction tells incident-management channels (PagerDuty, Opsgenie)
+// whether an alert opens or closes an incident. Alerts that should not page
+// anyone leave it empty and those channels skip them.
+type IncidentAction string
+
+const (
+	IncidentTrigger IncidentAction = "trigger"
+	IncidentResolve IncidentAction = "resolve"
+)
+
+// AlertKind is what an alert is about. Webhooks can subscribe to a subset
+// of kinds (database.WebhookFilter.AlertKinds); notifications with no kind
+// (reports) bypass webhook filters.
+type AlertKind string
+
+const (
+	AlertKindMissedBlocks AlertKind = "missed_blocks"
+	AlertKindStagnation   AlertKind = "stagnation"
+	AlertKindValsetChange AlertKind = "valset_change"
+	AlertKindRPCError     AlertKind = "rpc_error"
+	// AlertKindConsensusRound is a height stuck in a high consensus round.
+	AlertKindConsensusRound AlertKind = "consensus_round"
+	// AlertKindEndpointDivergence is RPC endpoints of a chain disagreeing
+	// on a block or lagging each other (rpc_endpoints cross-check).
+	AlertKindEndpointDivergence AlertKind = "endpoint_divergence"
+	// AlertKindBlockTime is a chain producing blocks much slower than
+	// usual.
+	AlertKindBlockTime AlertKind = "block_time"
+	// AlertKindNodeVersion is a validator running another node version
+	// than the majority.
+	AlertKindNodeVersion AlertKind = "node_version"
+	// AlertKindPlannedHalt is the countdown to a planned chain halt and the
+	// report of its restart.
+	AlertKindPlannedHalt AlertKind = "planned_halt"
+	// AlertKindPeerCount is the RPC node of a chain short of peers.
+	AlertKindPeerCount AlertKind = "peer_count"
+	// AlertKindSentryMissing is a validator's node gone from the peer list
+	// of the RPC node.
+	AlertKindSentryMissing AlertKind = "sentry_missing"
+	// AlertKindLowBalance is a monitored account short of GNOT for gas.
+	AlertKindLowBalance AlertKind = "low_balance"
+	// AlertKindTxWatch is a transaction touching a watched realm or address.
+	AlertKindTxWatch AlertKind = "tx_watch"
+)
+
+// AlertKinds lists every AlertKind a webhook filter may name.
+var AlertKinds = []AlertKind{AlertKindMissedBlocks, AlertKindStagnation, AlertKindValsetChange, AlertKindRPCError, AlertKindConsensusRound, AlertKindEndpointDivergence, AlertKindBlockTime, AlertKindNodeVersion, AlertKindPlannedHalt, AlertKindPeerCount, AlertKindSentryMissing, AlertKindLowBalance, AlertKindTxWatch}
+
 // AlertField is one labeled fact shown in an alert (e.g. "addr" -> "g1...").
+// When URL is set, renderers turn Value into a link to it (GovDAO proposal
+// source/tx/vote links).
 type AlertField struct {
 	Name  string
 	Value string
+	URL   string
 }
 
 // AlertData is the channel-neutral content of one alert/notification. Each
@@ -45,10 +96,56 @@ type AlertData struct {
 
 	Fields []AlertField
 
-	// Mentions holds raw Discord/Slack mention tags (as stored in
-	// AlertContact). Empty for most alerts (only CRITICAL missed-block
-	// alerts populate it today).
+	// Mentions holds raw mention tags as stored in AlertContact: a
+	// Discord/Slack user ID, a Matrix user ID (@alice:example.org) or a
+	// Mattermost username, depending on the webhook. Empty for most alerts
+	// (only CRITICAL missed-block alerts and their escalations populate it
+	// today).
 	Mentions []string
+
+	// Addr, Moniker, StartHeight and EndHeight are structured copies of
+	// what validator alerts also show in Fields, for machine consumers
+	// (the generic JSON webhook). Chat renderers ignore them. Zero values
+	// mean "not applicable" (e.g. chain-level alerts have no Addr).
+	Addr        string
+	Moniker     string
+	StartHeight int64
+	EndHeight   int64
+
+	// Kind drives per-webhook routing (see webhookAccepts). ResolvedLevel
+	// is, on RESOLVED / "Activity Restored" alerts, the level of the alert
+	// being resolved, so a webhook only hears about the end of incidents
+	// whose start passed its minimum level.
+	Kind          AlertKind
+	ResolvedLevel AlertLevel
+
+	// Incident is set on alerts that open (CRITICAL missed blocks,
+	// "Blockchain stuck") or close (RESOLVED, "Activity Restored") an
+	// incident. The trigger and its resolve must carry the same ChainID,
+	// Addr and StartHeight so they share IncidentKey.
+	Incident IncidentAction
+
+	// AlertLogID is the alert_logs row the alert was recorded as (0 when
+	// there is none). WARNING/CRITICAL alerts with an ID can be
+	// acknowledged: Telegram shows an "Acknowledge" button, machine
+	// consumers call POST /alerts/{id}/ack. Set by EnqueueAlert.
+	AlertLogID uint
+}
+
+// Acknowledgeable reports whether d can be acknowledged.
+func (d AlertData) Acknowledgeable() bool {
+	return d.AlertLogID != 0 && (d.Level == AlertWarning || d.Level == AlertCritical)
+}
+
+// IncidentKey is the dedup key identifying d's incident in external
+// incident tools: chain, validator address ("all" for chain-level
+// incidents) and the alert_logs start_height of the incident.
+func (d AlertData) IncidentKey() string {
+	addr := d.Addr
+	if addr == "" {
+		addr = "all"
+	}
+	return fmt.Sprintf("gnomonitoring/%s/%s/%d", d.ChainID, addr, d.StartHeight)
 }
 
 const (
@@ -86,7 +183,11 @@ func RenderAlertDiscordEmbed(d AlertData) (content string, embed DiscordEmbed) {
 
 	fields := make([]DiscordEmbedField, len(d.Fields))
 	for i, f := range d.Fields {
-		fields[i] = DiscordEmbedField{Name: f.Name, Value: f.Value}
+		value := f.Value
+		if f.URL != "" {
+			value = fmt.Sprintf("[%s](%s)", f.Value, f.URL)
+		}
+		fields[i] = DiscordEmbedField{Name: f.Name, Value: value}
 	}
 
 	embed = DiscordEmbed{
@@ -117,6 +218,10 @@ func RenderAlertSlackBlocks(d AlertData) []SlackBlock {
 		lines = append(lines, d.Description)
 	}
 	for _, f := range d.Fields {
+		if f.URL != "" {
+			lines = append(lines, fmt.Sprintf("*%s*: <%s|%s>", f.Name, f.URL, f.Value))
+			continue
+		}
 		lines = append(lines, fmt.Sprintf("*%s*: %s", f.Name, f.Value))
 	}
 	if len(lines) > 0 {
@@ -166,6 +271,10 @@ func RenderAlertTelegramHTML(d AlertData) string {
 	for _, f := range d.Fields {
 		name := html.EscapeString(f.Name)
 		value := html.EscapeString(f.Value)
+		if f.URL != "" {
+			sb.WriteString(fmt.Sprintf("<b>%s</b>: <a href=\"%s\">%s</a>\n", name, html.EscapeString(f.URL), value))
+			continue
+		}
 		if strings.Contains(strings.ToLower(f.Name), "addr") {
 			sb.WriteString(fmt.Sprintf("<b>%s</b>: <code>%s</code>\n", name, value))
 		} else {
@@ -179,3 +288,90 @@ func RenderAlertTelegramHTML(d AlertData) string {
 
 	return strings.TrimRight(sb.String(), "\n")
 }
+
+// matrixMentionPill returns the Matrix user ID of an AlertContact mention
+// tag and the HTML "pill" linking to it, which clients render as a mention.
+func matrixMentionPill(tag string) (userID, pill string) {
+	userID = "@" + strings.TrimPrefix(tag, "@")
+	return userID, fmt.Sprintf(`<a href="https://matrix.to/#/%s">%s</a>`, html.EscapeString(userID), html.EscapeString(userID))
+}
+
+// RenderAlertMatrix formats d for a Matrix hookshot webhook: an HTML body
+// laid out like RenderAlertTelegramHTML (title in the level color, addresses
+// in <code>), and the same content as plain text. Mentions go on a last
+// line, as user ID pills in the HTML body; the plain-text body carries the
+// bare user IDs, which is what clients match to notify.
+func RenderAlertMatrix(d AlertData) MatrixMessage {
+	title := fmt.Sprintf("[%s] %s %s", d.ChainID, d.Emoji, d.Title)
+	text := []string{title}
+	body := []string{fmt.Sprintf(`<b><font data-mx-color="#%06X">%s</font></b>`, alertColor(d.Level), html.EscapeString(title))}
+
+	if d.Description != "" {
+		text = append(text, d.Description)
+		body = append(body, html.EscapeString(d.Description))
+	}
+	for _, f := range d.Fields {
+		name, value := html.EscapeString(f.Name), html.EscapeString(f.Value)
+		switch {
+		case f.URL != "":
+			text = append(text, fmt.Sprintf("%s: %s (%s)", f.Name, f.Value, f.URL))
+			body = append(body, fmt.Sprintf(`<b>%s</b>: <a href="%s">%s</a>`, name, html.EscapeString(f.URL), value))
+		case strings.Contains(strings.ToLower(f.Name), "addr"):
+			text = append(text, fmt.Sprintf("%s: %s", f.Name, f.Value))
+			body = append(body, fmt.Sprintf("<b>%s</b>: <code>%s</code>", name, value))
+		default:
+			text = append(text, fmt.Sprintf("%s: %s", f.Name, f.Value))
+			body = append(body, fmt.Sprintf("<b>%s</b>: %s", name, value))
+		}
+	}
+	if d.Date != "" {
+		text = append(text, d.Date)
+		body = append(body, "<i>"+html.EscapeString(d.Date)+"</i>")
+	}
+
+	if len(d.Mentions) > 0 {
+		ids := make([]string, len(d.Mentions))
+		pills := make([]string, len(d.Mentions))
+		for i, m := range d.Mentions {
+			ids[i], pills[i] = matrixMentionPill(m)
+		}
+		text = append(text, strings.Join(ids, " "))
+		body = append(body, strings.Join(pills, " "))
+	}
+
+	return MatrixMessage{Text: strings.Join(text, "\n"), HTML: strings.Join(body, "<br>\n")}
+}
+
+// RenderAlertMattermost builds a Mattermost message for d: one attachment
+// colored by level, with a field per AlertField and the date as footer.
+// Like Discord, Mattermost only notifies @mentions written in the message
+// text, not inside attachments, so any mentions become the message text.
+func RenderAlertMattermost(d AlertData) MattermostMessage {
+	var msg MattermostMessage
+	if len(d.Mentions) > 0 {
+		mentions := make([]string, len(d.Mentions))
+		for i, m := range d.Mentions {
+			mentions[i] = "@" + strings.TrimPrefix(m, "@")
+		}
+		msg.Text = strings.Join(mentions, " ")
+	}
+
+	title := fmt.Sprintf("[%s] %s %s", d.ChainID, d.Emoji, d.Title)
+	fields := make([]MattermostField, len(d.Fields))
+	for i, f := range d.Fields {
+		value := f.Value
+		if f.URL != "" {
+			value = fmt.Sprintf("[%s](%s)", f.Value, f.URL)
+		}
+		fields[i] = MattermostField{Title: f.Name, Value: value}
+	}
+	msg.Attachments = []MattermostAttachment{{
+		Fallback: title,
+		Color:    fmt.Sprintf("#%06X", alertColor(d.Level)),
+		Title:    title,
+		Text:     d.Description,
+		Fields:   fields,
+		Footer:   d.Date,
+	}}
+	return msg
+}
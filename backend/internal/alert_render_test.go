@@ -197,3 +197,81 @@ func TestRenderAlertSlackBlocks_DateBeforeMentions(t *testing.T) {
 		t.Fatalf("mentions block = %+v, want context with mention text", mentionsBlock)
 	}
 }
+
+func TestRenderAlert_FieldURLRendersAsLink(t *testing.T) {
+	d := AlertData{
+		ChainID: "test12",
+		Level:   AlertInfo,
+		Title:   "New Proposal",
+		Fields:  []AlertField{{Name: "Tx", Value: "Gnoscan", URL: "https://gnoscan.io/tx?a=1&b=2"}},
+	}
+
+	_, embed := RenderAlertDiscordEmbed(d)
+	if embed.Fields[0].Value != "[Gnoscan](https://gnoscan.io/tx?a=1&b=2)" {
+		t.Fatalf("discord field = %q, want markdown link", embed.Fields[0].Value)
+	}
+
+	blocks := RenderAlertSlackBlocks(d)
+	if !strings.Contains(blocks[1].Text.Text, "*Tx*: <https://gnoscan.io/tx?a=1&b=2|Gnoscan>") {
+		t.Fatalf("slack section = %q, want mrkdwn link", blocks[1].Text.Text)
+	}
+
+	html := RenderAlertTelegramHTML(d)
+	if !strings.Contains(html, `<b>Tx</b>: <a href="https://gnoscan.io/tx?a=1&amp;b=2">Gnoscan</a>`) {
+		t.Fatalf("telegram html = %q, want escaped anchor", html)
+	}
+}
+
+func TestRenderAlertMatrix_EscapesHTMLAndPillsMentions(t *testing.T) {
+	d := AlertData{
+		ChainID: "test12", Level: AlertCritical, Emoji: "🚨", Title: "CRITICAL",
+		Fields:   []AlertField{{Name: "addr", Value: "g1addr"}, {Name: "moniker", Value: "<b>mon</b>"}},
+		Date:     "2025-11-02",
+		Mentions: []string{"@alice:example.org", "bob:example.org"},
+	}
+	m := RenderAlertMatrix(d)
+
+	if !strings.HasPrefix(m.HTML, `<b><font data-mx-color="#E74C3C">[test12] 🚨 CRITICAL</font></b>`) {
+		t.Fatalf("html title = %q, want the level color", m.HTML)
+	}
+	if !strings.Contains(m.HTML, "<b>addr</b>: <code>g1addr</code>") {
+		t.Fatalf("html = %q, want the address in <code>", m.HTML)
+	}
+	if strings.Contains(m.HTML, "<b>mon</b>") || !strings.Contains(m.HTML, "&lt;b&gt;mon&lt;/b&gt;") {
+		t.Fatalf("html = %q, want the moniker escaped", m.HTML)
+	}
+	if !strings.Contains(m.HTML, `<a href="https://matrix.to/#/@bob:example.org">@bob:example.org</a>`) {
+		t.Fatalf("html = %q, want a pill for a tag stored without @", m.HTML)
+	}
+	wantText := "[test12] 🚨 CRITICAL\naddr: g1addr\nmoniker: <b>mon</b>\n2025-11-02\n@alice:example.org @bob:example.org"
+	if m.Text != wantText {
+		t.Fatalf("text = %q, want %q", m.Text, wantText)
+	}
+}
+
+func TestRenderAlertMattermost_AttachmentAndMentionsInText(t *testing.T) {
+	d := AlertData{
+		ChainID: "test12", Level: AlertWarning, Emoji: "⚠️", Title: "WARNING",
+		Fields:   []AlertField{{Name: "Tx", Value: "Gnoscan", URL: "https://gnoscan.io/tx?a=1"}},
+		Date:     "2025-11-02",
+		Mentions: []string{"alice", "@bob"},
+	}
+	m := RenderAlertMattermost(d)
+
+	if m.Text != "@alice @bob" {
+		t.Fatalf("text = %q, want the mentions", m.Text)
+	}
+	if len(m.Attachments) != 1 {
+		t.Fatalf("len(attachments) = %d, want 1", len(m.Attachments))
+	}
+	a := m.Attachments[0]
+	if a.Title != "[test12] ⚠️ WARNING" || a.Color != "#F39C12" || a.Footer != "2025-11-02" {
+		t.Fatalf("attachment = %+v", a)
+	}
+	if len(a.Fields) != 1 || a.Fields[0].Value != "[Gnoscan](https://gnoscan.io/tx?a=1)" {
+		t.Fatalf("fields = %+v, want a markdown link", a.Fields)
+	}
+	if strings.Contains(a.Text, "@alice") {
+		t.Fatalf("mentions must not go inside the attachment, got %q", a.Text)
+	}
+}
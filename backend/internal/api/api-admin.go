@@ -3,6 +3,8 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
@@ -217,6 +219,33 @@ func adminRouter(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
 	case path == "/govdao/proposals" && r.Method == http.MethodGet:
 		handleGetGovDAOProposals(w, r, db)
 
+	// 2.11 — Alert delivery outbox
+	case path == "/deliveries" && r.Method == http.MethodGet:
+		handleGetDeliveries(w, r, db)
+	case path == "/deliveries/replay" && r.Method == http.MethodPost:
+		handleReplayDeadDeliveries(w, r, db)
+	case strings.HasPrefix(path, "/deliveries/") && strings.HasSuffix(path, "/replay") && r.Method == http.MethodPost:
+		idStr := strings.TrimSuffix(strings.TrimPrefix(path, "/deliveries/"), "/replay")
+		handleReplayDelivery(w, r, db, idStr)
+
+	// 2.12 — Silences
+	case path == "/silences" && r.Method == http.MethodGet:
+		handleGetSilences(w, r, db)
+	case path == "/silences" && r.Method == http.MethodPost:
+		handlePostSilence(w, r, db)
+	case strings.HasPrefix(path, "/silences/") && r.Method == http.MethodDelete:
+		idStr := strings.TrimPrefix(path, "/silences/")
+		handleDeleteSilence(w, r, db, idStr)
+
+	// 2.13 — Planned halts
+	case path == "/halts" && r.Method == http.MethodGet:
+		handleGetPlannedHalts(w, r, db)
+	case path == "/halts" && r.Method == http.MethodPost:
+		handlePostPlannedHalt(w, r, db)
+	case strings.HasPrefix(path, "/halts/") && r.Method == http.MethodDelete:
+		idStr := strings.TrimPrefix(path, "/halts/")
+		handleDeletePlannedHalt(w, r, db, idStr)
+
 	default:
 		http.Error(w, "not found", http.StatusNotFound)
 	}
@@ -791,3 +820,186 @@ func handleGetGovDAOProposals(w http.ResponseWriter, r *http.Request, db *gorm.D
 	}
 	writeJSON(w, http.StatusOK, proposals)
 }
+
+// ── 2.11 Alert delivery outbox ───────────────────────────────────────────────
+
+func handleGetDeliveries(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
+	status := r.URL.Query().Get("status")
+	chainID := r.URL.Query().Get("chain")
+	limit := 200
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if n, err := strconv.Atoi(limitStr); err == nil && n > 0 {
+			limit = n
+		}
+	}
+
+	deliveries, err := database.ListAlertDeliveries(db, status, chainID, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, deliveries)
+}
+
+func handleReplayDelivery(w http.ResponseWriter, _ *http.Request, db *gorm.DB, idStr string) {
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid delivery id", http.StatusBadRequest)
+		return
+	}
+	if err := database.ReplayAlertDelivery(db, uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "delivery not found or already sent", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "requeued"})
+}
+
+func handleReplayDeadDeliveries(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
+	chainID := r.URL.Query().Get("chain")
+	n, err := database.ReplayDeadAlertDeliveries(db, chainID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"status": "requeued", "count": n})
+}
+
+// ── 2.12 Silences ────────────────────────────────────────────────────────────
+
+func handleGetSilences(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
+	silences, err := database.ListSilences(db, r.URL.Query().Get("chain"), "", time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, silences)
+}
+
+// handlePostSilence creates a global silence, with no duration cap.
+func handlePostSilence(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
+	silence, err := decodeSilence(r, 0)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	userID, err := authUserIDFromContext(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	silence.CreatedBy = database.AdminSilencePrefix + userID
+	if err := database.CreateSilence(db, &silence); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, http.StatusCreated, silence)
+}
+
+// handleDeleteSilence ends any silence now, whoever created it.
+func handleDeleteSilence(w http.ResponseWriter, _ *http.Request, db *gorm.DB, idStr string) {
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid silence id", http.StatusBadRequest)
+		return
+	}
+	if err := database.EndSilence(db, uint(id), ""); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "silence not found or already over", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ended"})
+}
+
+// ── 2.13 Planned halts ───────────────────────────────────────────────────────
+
+// defaultHaltWindowMinutes is how long stagnation alerts are held back
+// after a planned halt when the request does not say.
+const defaultHaltWindowMinutes = 120
+
+func handleGetPlannedHalts(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
+	halts, err := database.ListPlannedHalts(db, r.URL.Query().Get("chain"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, halts)
+}
+
+// decodePlannedHalt reads a planned halt from the request body. The halt
+// height must still be ahead of the chain.
+func decodePlannedHalt(r *http.Request) (database.PlannedHalt, error) {
+	var in struct {
+		ChainID       string `json:"chain_id"`
+		HaltHeight    int64  `json:"halt_height"`
+		WindowMinutes int    `json:"window_minutes"`
+		Reason        string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		return database.PlannedHalt{}, fmt.Errorf("invalid JSON")
+	}
+	if err := requireChainID(&in.ChainID); err != nil {
+		return database.PlannedHalt{}, err
+	}
+	if in.HaltHeight <= 0 {
+		return database.PlannedHalt{}, fmt.Errorf("halt_height is required")
+	}
+	if latest := gnovalidator.GetLastHeight(in.ChainID); in.HaltHeight <= latest {
+		return database.PlannedHalt{}, fmt.Errorf("halt_height must be above the current height %d", latest)
+	}
+	if in.WindowMinutes == 0 {
+		in.WindowMinutes = defaultHaltWindowMinutes
+	}
+	if in.WindowMinutes < 0 {
+		return database.PlannedHalt{}, fmt.Errorf("window_minutes must be positive")
+	}
+	return database.PlannedHalt{
+		ChainID:       in.ChainID,
+		HaltHeight:    in.HaltHeight,
+		WindowMinutes: in.WindowMinutes,
+		Reason:        in.Reason,
+	}, nil
+}
+
+func handlePostPlannedHalt(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
+	halt, err := decodePlannedHalt(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	userID, err := authUserIDFromContext(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	halt.CreatedBy = "admin:" + userID
+	if err := database.CreatePlannedHalt(db, &halt); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, http.StatusCreated, halt)
+}
+
+// handleDeletePlannedHalt cancels a planned halt.
+func handleDeletePlannedHalt(w http.ResponseWriter, _ *http.Request, db *gorm.DB, idStr string) {
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid halt id", http.StatusBadRequest)
+		return
+	}
+	if err := database.DeletePlannedHalt(db, uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "planned halt not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
@@ -2,10 +2,14 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"net/url"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -15,8 +19,10 @@ import (
 	clerk "github.com/clerk/clerk-sdk-go/v2"
 	"github.com/samouraiworld/gnomonitoring/backend/internal"
 	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/discord"
 	"github.com/samouraiworld/gnomonitoring/backend/internal/gnovalidator"
 	"github.com/samouraiworld/gnomonitoring/backend/internal/scheduler"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/slack"
 	"gorm.io/gorm"
 )
 
@@ -26,9 +32,19 @@ import (
 // internal/loopback URL and have the server fire outbound requests at it on
 // every alert. See also the send-time DialContext guard in
 // internal/fonction.go, which additionally defends against DNS rebinding.
+//
+// A nil host list means the type may target any host (the "generic" JSON
+// webhook posts to the user's own tooling, Matrix hookshot and Mattermost
+// are self-hosted); those URLs only have to name a public host, see
+// validatePublicWebhookHost.
 var allowedWebhookHosts = map[string][]string{
-	"discord": {"discord.com", "discordapp.com"},
-	"slack":   {"hooks.slack.com"},
+	"discord":    {"discord.com", "discordapp.com"},
+	"slack":      {"hooks.slack.com"},
+	"generic":    nil,
+	"pagerduty":  {"events.pagerduty.com", "events.eu.pagerduty.com"},
+	"opsgenie":   {"api.opsgenie.com", "api.eu.opsgenie.com"},
+	"matrix":     nil,
+	"mattermost": nil,
 }
 
 // requireChainID validates that chainID is present, non-empty, and names a
@@ -59,6 +75,9 @@ func validateWebhookURL(webhookType, rawURL string) error {
 		return fmt.Errorf("webhook URL must use https")
 	}
 	host := strings.ToLower(u.Hostname())
+	if hosts == nil {
+		return validatePublicWebhookHost(host)
+	}
 	for _, h := range hosts {
 		if host == h {
 			return nil
@@ -67,6 +86,74 @@ func validateWebhookURL(webhookType, rawURL string) error {
 	return fmt.Errorf("webhook URL host %q is not allowed for type %q", host, webhookType)
 }
 
+// validateWebhookFilter checks the routing filter of a validator webhook:
+// a known minimum level and known alert kinds.
+func validateWebhookFilter(f database.WebhookFilter) error {
+	switch internal.AlertLevel(f.MinLevel) {
+	case "", internal.AlertInfo, internal.AlertWarning, internal.AlertCritical:
+	default:
+		return fmt.Errorf("invalid min_level %q (want INFO, WARNING or CRITICAL)", f.MinLevel)
+	}
+	for _, k := range f.AlertKinds {
+		if !slices.Contains(internal.AlertKinds, internal.AlertKind(k)) {
+			return fmt.Errorf("unknown alert kind %q", k)
+		}
+	}
+	return nil
+}
+
+// validatePublicWebhookHost rejects the obviously internal targets of an
+// any-host webhook type at registration time: localhost names and
+// non-public IP literals. Hostnames resolving to private addresses are
+// still caught by the send-time dial guard.
+func validatePublicWebhookHost(host string) error {
+	if host == "" {
+		return fmt.Errorf("webhook URL has no host")
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("webhook URL host %q is not allowed", host)
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+			ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+			return fmt.Errorf("webhook URL host %q is not allowed", host)
+		}
+	}
+	return nil
+}
+
+var (
+	matrixUserIDRe       = regexp.MustCompile(`^@[a-z0-9._=/+-]+:[A-Za-z0-9.-]+(:[0-9]+)?$`)
+	mattermostUsernameRe = regexp.MustCompile(`^@?[A-Za-z0-9._-]{1,64}$`)
+)
+
+// validateMentionTag checks an alert contact's mention_tag against the
+// mention syntax of the webhook it is attached to: a Matrix user ID
+// (@alice:example.org), a Mattermost username, or otherwise a numeric
+// Discord/Slack snowflake. An empty tag is always accepted.
+func validateMentionTag(webhookType, tag string) error {
+	if tag == "" {
+		return nil
+	}
+	switch webhookType {
+	case "matrix":
+		if !matrixUserIDRe.MatchString(tag) {
+			return fmt.Errorf("Invalid mention_tag: must be a Matrix user ID like @alice:example.org")
+		}
+	case "mattermost":
+		if !mattermostUsernameRe.MatchString(tag) {
+			return fmt.Errorf("Invalid mention_tag: must be a Mattermost username")
+		}
+	default:
+		for _, c := range tag {
+			if c < '0' || c > '9' {
+				return fmt.Errorf("Invalid mention_tag: must be numeric")
+			}
+		}
+	}
+	return nil
+}
+
 // GetChainIDFromRequest extracts and validates chainID from query parameter
 // Returns default chain if not specified
 func GetChainIDFromRequest(r *http.Request) (string, error) {
@@ -127,6 +214,10 @@ func ListWebhooksHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	for i := range webhooks {
+		webhooks[i].HasSecret = hasSecret(webhooks[i].Secret)
+		webhooks[i].Secret = nil
+	}
 
 	if len(webhooks) == 0 {
 		json.NewEncoder(w).Encode(map[string]string{"message": "no webhook found"})
@@ -156,6 +247,10 @@ func CreateWebhookHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
 		http.Error(w, "Missing required fields", http.StatusBadRequest)
 		return
 	}
+	if database.IsIncidentWebhookType(webhook.Type) {
+		http.Error(w, "GovDAO webhooks do not support type "+webhook.Type, http.StatusBadRequest)
+		return
+	}
 	if err := validateWebhookURL(webhook.Type, webhook.URL); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -195,9 +290,6 @@ func CreateWebhookHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
 		http.Error(w, "error get lastID GovDao: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
-	if err := internal.SendReportGovdao(chainIDForSample, govdaolist.Id, govdaolist.Title, govdaolist.Url, govdaolist.Tx, webhook.Type, webhook.URL); err != nil {
-		log.Printf("❌ SendReportGovdao: %v", err)
-	}
 
 	// If not exist insert (with chain_id support).
 	err = database.InsertWebhook(webhook.UserID, webhook.URL, webhook.Description, webhook.Type, webhook.ChainID, db)
@@ -207,10 +299,40 @@ func CreateWebhookHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
 		return
 	}
 
+	// The sample goes out after the insert so a "generic" webhook is signed
+	// with the secret generated for it.
+	created, err := database.FindWebhookGovDAO(db, webhook.UserID, webhook.URL, webhook.Type)
+	if err != nil {
+		log.Printf("❌ FindWebhookGovDAO: %v", err)
+	} else if err := internal.SendReportGovdao(chainIDForSample, govdaolist.Id, govdaolist.Title, govdaolist.Url, govdaolist.Tx, internal.GovdaoWebhookDestination(*created)); err != nil {
+		log.Printf("❌ SendReportGovdao: %v", err)
+	}
+
+	if created != nil && webhook.Type == "generic" {
+		writeWebhookSecret(w, created.ID, created.Secret)
+		return
+	}
 	w.WriteHeader(http.StatusCreated)
 	w.Write([]byte("Webhook created successfully"))
 }
 
+// hasSecret reports whether a webhook has a secret set.
+func hasSecret(secret *string) bool {
+	return secret != nil && *secret != ""
+}
+
+// writeWebhookSecret answers the creation of a "generic" webhook with its
+// signing secret, the only time it is returned besides a rotation.
+func writeWebhookSecret(w http.ResponseWriter, id int, secret *string) {
+	var s string
+	if secret != nil {
+		s = *secret
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{"id": id, "secret": s})
+}
+
 func DeleteWebhookHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
 	EnableCORS(w, r)
 	idStr := r.URL.Query().Get("id")
@@ -249,6 +371,10 @@ func UpdateWebhookHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
 		return
 	}
 	webhook.UserID = userID
+	if database.IsIncidentWebhookType(webhook.Type) {
+		http.Error(w, "GovDAO webhooks do not support type "+webhook.Type, http.StatusBadRequest)
+		return
+	}
 	if err := validateWebhookURL(webhook.Type, webhook.URL); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -273,6 +399,37 @@ func UpdateWebhookHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// RotateWebhookSecretHandler issues a new signing secret for one of the
+// caller's "generic" webhooks (?id=) in tablename and returns it. The old
+// secret stops being used for the very next alert.
+func RotateWebhookSecretHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB, tablename string) {
+	EnableCORS(w, r)
+	userID, err := authUserIDFromContext(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+
+	secret, err := database.RotateWebhookSecret(db, id, userID, tablename)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "generic webhook not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"id": id, "secret": secret})
+}
+
 // ========== VALIDATOR ==========
 
 func ListMonitoringWebhooksHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
@@ -295,6 +452,10 @@ func ListMonitoringWebhooksHandler(w http.ResponseWriter, r *http.Request, db *g
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	for i := range webhooks {
+		webhooks[i].HasSecret = hasSecret(webhooks[i].Secret)
+		webhooks[i].Secret = nil
+	}
 
 	if len(webhooks) == 0 {
 		json.NewEncoder(w).Encode(map[string]string{"message": "no webhook found"})
@@ -330,6 +491,19 @@ func CreateMonitoringWebhookHandler(w http.ResponseWriter, r *http.Request, db *
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	// PagerDuty/Opsgenie webhooks carry their routing/API key as secret.
+	var secret string
+	if webhook.Secret != nil {
+		secret = *webhook.Secret
+	}
+	if database.IsIncidentWebhookType(webhook.Type) && secret == "" {
+		http.Error(w, "secret (integration key) is required for "+webhook.Type+" webhooks", http.StatusBadRequest)
+		return
+	}
+	if err := validateWebhookFilter(webhook.Filter); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	// chain_id is required: see requireChainID's doc comment for why an
 	// unscoped webhook is no longer allowed.
@@ -358,12 +532,24 @@ func CreateMonitoringWebhookHandler(w http.ResponseWriter, r *http.Request, db *
 	}
 
 	// ✅ If not exist insert
-	err = database.InsertMonitoringWebhook(webhook.UserID, webhook.URL, webhook.Description, webhook.Type, chainID, db)
+	created := database.WebhookValidator{
+		UserID:      webhook.UserID,
+		URL:         webhook.URL,
+		Description: webhook.Description,
+		Type:        webhook.Type,
+		ChainID:     &chainID,
+		Filter:      webhook.Filter,
+	}
+	err = database.CreateMonitoringWebhook(db, &created, secret)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	if created.Type == "generic" {
+		writeWebhookSecret(w, created.ID, created.Secret)
+		return
+	}
 	w.WriteHeader(http.StatusCreated)
 	w.Write([]byte("Webhook created successfully"))
 }
@@ -394,12 +580,18 @@ func DeleteMonitoringWebhookHandler(w http.ResponseWriter, r *http.Request, db *
 
 func UpdateMonitoringWebhookHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
 	EnableCORS(w, r)
-	var webhook database.WebhookValidator
-	err := json.NewDecoder(r.Body).Decode(&webhook)
+	// Filter shadows the embedded one so an update that leaves out
+	// "filter" keeps the webhook's current routing rules.
+	var body struct {
+		database.WebhookValidator
+		Filter *database.WebhookFilter `json:"filter"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	webhook := body.WebhookValidator
 	// for get userid with apiclerk
 	userID, err := authUserIDFromContext(r)
 	if err != nil {
@@ -411,12 +603,31 @@ func UpdateMonitoringWebhookHandler(w http.ResponseWriter, r *http.Request, db *
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if body.Filter != nil {
+		if err := validateWebhookFilter(*body.Filter); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
 
 	err = database.UpdateMonitoringWebhook(db, webhook.ID, webhook.UserID, webhook.Description, webhook.URL, webhook.Type, nil, "webhook_validators")
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	// A new PagerDuty/Opsgenie integration key may come with the update.
+	if database.IsIncidentWebhookType(webhook.Type) && webhook.Secret != nil && *webhook.Secret != "" {
+		if err := database.SetWebhookSecret(db, webhook.ID, webhook.UserID, "webhook_validators", *webhook.Secret); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	if body.Filter != nil {
+		if err := database.UpdateWebhookFilter(db, webhook.ID, webhook.UserID, *body.Filter); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -518,8 +729,10 @@ func UpdateUserHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
 	}
 
 	var user struct {
-		Name  string `json:"name"`
-		Email string `json:"email"`
+		Name         string `json:"name"`
+		Email        string `json:"email"`
+		EmailAlerts  *bool  `json:"email_alerts"`  // optional: opt in/out of alert emails
+		EmailReports *bool  `json:"email_reports"` // optional: opt in/out of daily report emails
 	}
 
 	err := json.NewDecoder(r.Body).Decode(&user)
@@ -539,6 +752,10 @@ func UpdateUserHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
 		http.Error(w, "Failed to update user: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if err := database.SetUserEmailOptIn(db, userID, user.EmailAlerts, user.EmailReports); err != nil {
+		http.Error(w, "Failed to update email settings: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	w.WriteHeader(http.StatusOK)
 }
@@ -632,24 +849,24 @@ func InsertAlertContactHandler(w http.ResponseWriter, r *http.Request, db *gorm.
 		return
 	}
 
-	// F6: validate mention_tag is a numeric Discord/Slack snowflake (or empty)
-	for _, c := range input.MentionTag {
-		if c < '0' || c > '9' {
-			http.Error(w, "Invalid mention_tag: must be numeric", http.StatusBadRequest)
-			return
-		}
-	}
-
 	// F7: verify the referenced webhook belongs to the calling user
+	var webhookType string
 	if input.IDwebhook != 0 {
-		var count int64
+		var types []string
 		db.Model(&database.WebhookValidator{}).
 			Where("id = ? AND user_id = ?", input.IDwebhook, userID).
-			Count(&count)
-		if count == 0 {
+			Pluck("type", &types)
+		if len(types) == 0 {
 			http.Error(w, "Webhook not found", http.StatusBadRequest)
 			return
 		}
+		webhookType = types[0]
+	}
+
+	// F6: validate mention_tag against the webhook's mention syntax (or empty)
+	if err := validateMentionTag(webhookType, input.MentionTag); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	err = database.InsertAlertContact(db, input.UserID, input.Moniker, input.NameContact, input.MentionTag, input.IDwebhook)
@@ -709,13 +926,21 @@ func UpdateAlertContactHandler(w http.ResponseWriter, r *http.Request, db *gorm.
 		return
 	}
 
-	// F6: validate mention_tag is numeric (Discord/Slack snowflake) or empty
-	for _, c := range data.MentionTag {
-		if c < '0' || c > '9' {
-			http.Error(w, "Invalid mention_tag: must be numeric", http.StatusBadRequest)
-			return
+	// F6: validate mention_tag against the webhook's mention syntax (or empty)
+	var webhookType string
+	if data.IDwebhook != 0 {
+		var types []string
+		db.Model(&database.WebhookValidator{}).
+			Where("id = ? AND user_id = ?", data.IDwebhook, userID).
+			Pluck("type", &types)
+		if len(types) > 0 {
+			webhookType = types[0]
 		}
 	}
+	if err := validateMentionTag(webhookType, data.MentionTag); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	err = database.UpdateAlertContact(db, data.ID, userID, data.Moniker, data.NameContact, data.MentionTag, data.IDwebhook)
 	if err != nil {
@@ -738,27 +963,544 @@ func DeleteAlertContactHandler(w http.ResponseWriter, r *http.Request, db *gorm.
 		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
 		return
 	}
-
-	idStr := r.URL.Query().Get("id")
-	if idStr == "" {
-		http.Error(w, "Missing id", http.StatusBadRequest)
-		return
-	}
-
-	id, err := strconv.Atoi(idStr)
+
+	idStr := r.URL.Query().Get("id")
+	if idStr == "" {
+		http.Error(w, "Missing id", http.StatusBadRequest)
+		return
+	}
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+
+	err = database.DeleteAlertContact(db, id, userID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to delete alert contact: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Alert contact deleted"))
+}
+
+// ====================== Silences ================================
+
+// silenceInput is the body of POST /silences and POST /admin/silences.
+// start_at is optional (defaults to now); times are RFC 3339.
+type silenceInput struct {
+	ChainID string    `json:"chain_id"`
+	Addr    string    `json:"addr"`
+	StartAt time.Time `json:"start_at"`
+	EndAt   time.Time `json:"end_at"`
+	Reason  string    `json:"reason"`
+}
+
+// decodeSilence reads and validates a silenceInput. maxDuration caps the
+// silence length (0 = no cap).
+func decodeSilence(r *http.Request, maxDuration time.Duration) (database.Silence, error) {
+	var in silenceInput
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		return database.Silence{}, fmt.Errorf("invalid JSON")
+	}
+	if err := requireChainID(&in.ChainID); err != nil {
+		return database.Silence{}, err
+	}
+	if in.Addr == "" {
+		return database.Silence{}, fmt.Errorf(`addr is required (a validator address or "all")`)
+	}
+	if in.EndAt.IsZero() {
+		return database.Silence{}, fmt.Errorf("end_at is required")
+	}
+	start := in.StartAt
+	if start.IsZero() {
+		start = time.Now()
+	}
+	if maxDuration > 0 && in.EndAt.Sub(start) > maxDuration {
+		return database.Silence{}, fmt.Errorf("a silence cannot last more than %s", maxDuration)
+	}
+	return database.Silence{
+		ChainID: in.ChainID,
+		Addr:    in.Addr,
+		StartAt: in.StartAt,
+		EndAt:   in.EndAt,
+		Reason:  in.Reason,
+	}, nil
+}
+
+// ListSilencesHandler returns the active and upcoming silences, optionally
+// for ?chain= only: the global ones admins created and the caller's own.
+func ListSilencesHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
+	EnableCORS(w, r)
+	userID, err := authUserIDFromContext(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+	chainID := r.URL.Query().Get("chain")
+	if chainID != "" {
+		if err := internal.Config.ValidateChainID(chainID); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	silences, err := database.ListSilences(db, chainID, userID, time.Now())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list silences: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(silences)
+}
+
+// CreateSilenceHandler creates a personal silence: it only mutes the
+// caller's own webhooks. Global silences are created through
+// /admin/silences.
+func CreateSilenceHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
+	EnableCORS(w, r)
+	userID, err := authUserIDFromContext(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+	silence, err := decodeSilence(r, database.MaxSilenceDuration)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	silence.CreatedBy = userID
+	if err := database.CreateSilence(db, &silence); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(silence)
+}
+
+// DeleteSilenceHandler ends one of the caller's silences now (?id=).
+func DeleteSilenceHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
+	EnableCORS(w, r)
+	userID, err := authUserIDFromContext(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.ParseUint(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+	if err := database.EndSilence(db, uint(id), userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Silence not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to end silence: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Silence ended"))
+}
+
+// ====================== Alert acknowledgement =====================
+
+// AckAlertHandler handles POST /alerts/{id}/ack: the caller takes the
+// WARNING/CRITICAL alert id (an alert_logs ID, as shown by
+// /latest_incidents and the generic webhook's alert_id), which stops
+// resends of its incident until it is RESOLVED. Only users with a webhook
+// or escalation policy covering the alert's validator and chain may ack it.
+func AckAlertHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
+	EnableCORS(w, r)
+	userID, err := authUserIDFromContext(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+	idStr, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/alerts/"), "/ack")
+	if !ok {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid alert id", http.StatusBadRequest)
+		return
+	}
+	watches, err := database.UserWatchesAlert(db, uint(id), userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, fmt.Sprintf("Failed to acknowledge alert: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if err == nil && !watches {
+		http.Error(w, "Forbidden: none of your webhooks or escalation policies covers this alert", http.StatusForbidden)
+		return
+	}
+	alert, err := database.AckAlert(db, uint(id), userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Alert not found or not acknowledgeable", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to acknowledge alert: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(alert)
+}
+
+// ====================== Escalation policies =====================
+
+// decodeEscalationPolicy reads an EscalationPolicy body for userID and checks
+// that its chain exists and that every webhook and alert contact it names is
+// one of the user's.
+func decodeEscalationPolicy(r *http.Request, db *gorm.DB, userID string) (database.EscalationPolicy, error) {
+	var p database.EscalationPolicy
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		return p, fmt.Errorf("invalid JSON")
+	}
+	p.UserID = userID
+	if p.ChainID != nil && *p.ChainID == "" {
+		p.ChainID = nil
+	}
+	if p.ChainID != nil {
+		if err := internal.Config.ValidateChainID(*p.ChainID); err != nil {
+			return p, err
+		}
+	}
+	if err := p.Validate(); err != nil {
+		return p, err
+	}
+	for _, ref := range []struct {
+		model any
+		ids   []int
+		what  string
+	}{
+		{&database.WebhookValidator{}, append(append([]int{}, p.Tier2Webhooks...), p.Tier3Webhooks...), "webhook"},
+		{&database.AlertContact{}, append(append([]int{}, p.Tier2Contacts...), p.Tier3Contacts...), "alert contact"},
+	} {
+		for _, id := range ref.ids {
+			var count int64
+			if err := db.Model(ref.model).Where("id = ? AND user_id = ?", id, userID).Count(&count).Error; err != nil {
+				return p, err
+			}
+			if count == 0 {
+				return p, fmt.Errorf("%s %d not found", ref.what, id)
+			}
+		}
+	}
+	return p, nil
+}
+
+func ListEscalationPoliciesHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
+	EnableCORS(w, r)
+	userID, err := authUserIDFromContext(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+	policies, err := database.ListEscalationPolicies(db, userID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list escalation policies: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(policies)
+}
+
+func CreateEscalationPolicyHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
+	EnableCORS(w, r)
+	userID, err := authUserIDFromContext(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+	policy, err := decodeEscalationPolicy(r, db, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	policy.ID = 0
+	if err := database.CreateEscalationPolicy(db, &policy); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to create escalation policy: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(policy)
+}
+
+// UpdateEscalationPolicyHandler replaces one of the caller's policies; the
+// body is a full policy including its id.
+func UpdateEscalationPolicyHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
+	EnableCORS(w, r)
+	userID, err := authUserIDFromContext(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+	policy, err := decodeEscalationPolicy(r, db, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := database.UpdateEscalationPolicy(db, &policy); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Escalation policy not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to update escalation policy: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Escalation policy updated"))
+}
+
+// DeleteEscalationPolicyHandler deletes one of the caller's policies (?id=).
+func DeleteEscalationPolicyHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
+	EnableCORS(w, r)
+	userID, err := authUserIDFromContext(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.ParseUint(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+	if err := database.DeleteEscalationPolicy(db, uint(id), userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Escalation policy not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to delete escalation policy: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Escalation policy deleted"))
+}
+
+// ====================== Monitored accounts =====================
+
+// decodeMonitoredAccount reads a MonitoredAccount body for userID and checks
+// that its chain exists.
+func decodeMonitoredAccount(r *http.Request, userID string) (database.MonitoredAccount, error) {
+	var a database.MonitoredAccount
+	if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+		return a, fmt.Errorf("invalid JSON")
+	}
+	a.UserID = userID
+	a.Addr = strings.TrimSpace(a.Addr)
+	if err := internal.Config.ValidateChainID(a.ChainID); err != nil {
+		return a, err
+	}
+	return a, nil
+}
+
+func ListMonitoredAccountsHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
+	EnableCORS(w, r)
+	userID, err := authUserIDFromContext(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+	accounts, err := database.ListMonitoredAccounts(db, userID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list accounts: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(accounts)
+}
+
+func CreateMonitoredAccountHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
+	EnableCORS(w, r)
+	userID, err := authUserIDFromContext(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+	account, err := decodeMonitoredAccount(r, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var count int64
+	if err := db.Model(&database.MonitoredAccount{}).
+		Where("user_id = ? AND chain_id = ? AND addr = ?", userID, account.ChainID, account.Addr).
+		Count(&count).Error; err != nil {
+		http.Error(w, fmt.Sprintf("Failed to create account: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if count > 0 {
+		http.Error(w, "Account already monitored on this chain", http.StatusConflict)
+		return
+	}
+	if err := database.CreateMonitoredAccount(db, &account); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(account)
+}
+
+func DeleteMonitoredAccountHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
+	EnableCORS(w, r)
+	userID, err := authUserIDFromContext(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.ParseUint(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+	if err := database.DeleteMonitoredAccount(db, uint(id), userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Account not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to delete account: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Account deleted"))
+}
+
+// MonitoredAccountBalancesHandler serves the balances of account `id` of the
+// user sampled over the last `hours` hours (default 24, at most a week),
+// oldest first.
+func MonitoredAccountBalancesHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
+	EnableCORS(w, r)
+	userID, err := authUserIDFromContext(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.ParseUint(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid id", http.StatusBadRequest)
+		return
+	}
+	hours, err := balanceHours(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	account, err := database.GetMonitoredAccount(db, uint(id), userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Account not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to get account: %v", err), http.StatusInternalServerError)
+		return
+	}
+	balances, err := database.GetAccountBalances(db, account.ChainID, account.Addr, time.Now().Add(-time.Duration(hours)*time.Hour))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get balances: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(balances)
+}
+
+// ====================== Tx watches =====================
+
+// decodeTxWatch reads a TxWatch body for userID and checks its chain and
+// target.
+func decodeTxWatch(r *http.Request, userID string) (database.TxWatch, error) {
+	var tw database.TxWatch
+	if err := json.NewDecoder(r.Body).Decode(&tw); err != nil {
+		return tw, fmt.Errorf("invalid JSON")
+	}
+	tw.CreatedBy = userID
+	if err := internal.Config.ValidateChainID(tw.ChainID); err != nil {
+		return tw, err
+	}
+	target, err := database.NormalizeWatchTarget(tw.Target)
+	if err != nil {
+		return tw, err
+	}
+	tw.Target = target
+	return tw, nil
+}
+
+func ListTxWatchesHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
+	EnableCORS(w, r)
+	userID, err := authUserIDFromContext(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+	watches, err := database.ListTxWatches(db, userID, r.URL.Query().Get("chain"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list tx watches: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(watches)
+}
+
+func CreateTxWatchHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
+	EnableCORS(w, r)
+	userID, err := authUserIDFromContext(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+	watch, err := decodeTxWatch(r, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var count int64
+	if err := db.Model(&database.TxWatch{}).
+		Where("created_by = ? AND chain_id = ? AND target = ?", userID, watch.ChainID, watch.Target).
+		Count(&count).Error; err != nil {
+		http.Error(w, fmt.Sprintf("Failed to create tx watch: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if count > 0 {
+		http.Error(w, "Target already watched on this chain", http.StatusConflict)
+		return
+	}
+	if err := database.CreateTxWatch(db, &watch); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to create tx watch: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(watch)
+}
+
+func DeleteTxWatchHandler(w http.ResponseWriter, r *http.Request, db *gorm.DB) {
+	EnableCORS(w, r)
+	userID, err := authUserIDFromContext(r)
+	if err != nil {
+		http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.ParseUint(r.URL.Query().Get("id"), 10, 64)
 	if err != nil {
 		http.Error(w, "Invalid id", http.StatusBadRequest)
 		return
 	}
-
-	err = database.DeleteAlertContact(db, id, userID)
-	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to delete alert contact: %v", err), http.StatusInternalServerError)
+	if err := database.DeleteTxWatch(db, uint(id), userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Tx watch not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to delete tx watch: %v", err), http.StatusInternalServerError)
 		return
 	}
-
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("Alert contact deleted"))
+	w.Write([]byte("Tx watch deleted"))
 }
 
 // ====================== Block Height ============
@@ -1180,6 +1922,273 @@ func GetChainHealth(w http.ResponseWriter, r *http.Request, db *gorm.DB, chainID
 	json.NewEncoder(w).Encode(resp)
 }
 
+// missedProposalsResponse is the body of /api/chain/<chainID>/missed_proposals.
+type missedProposalsResponse struct {
+	Since      time.Time                      `json:"since"`
+	Validators []database.MissedProposalCount `json:"validators"`
+	Events     []database.MissedProposal      `json:"events"`
+}
+
+// GetMissedProposals lists the proposer slots missed on chainID over the
+// last `hours` hours (default 24, at most 720): a count per validator and
+// the latest `limit` events (default 100), optionally for one `addr`.
+func GetMissedProposals(w http.ResponseWriter, r *http.Request, db *gorm.DB, chainID string) {
+	EnableCORS(w, r)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := internal.Config.ValidateChainID(chainID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	hours, limit := 24, 100
+	if v := r.URL.Query().Get("hours"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 720 {
+			http.Error(w, "hours must be between 1 and 720", http.StatusBadRequest)
+			return
+		}
+		hours = n
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 1000 {
+			http.Error(w, "limit must be between 1 and 1000", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+	addr := r.URL.Query().Get("addr")
+	since := time.Now().Add(-time.Duration(hours) * time.Hour)
+
+	resp := missedProposalsResponse{Since: since.UTC()}
+	var err error
+	if resp.Events, err = database.GetMissedProposals(db, chainID, addr, since, limit); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if resp.Validators, err = database.CountMissedProposals(db, chainID, since); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if addr != "" {
+		kept := resp.Validators[:0]
+		for _, c := range resp.Validators {
+			if c.Addr == addr {
+				kept = append(kept, c)
+			}
+		}
+		resp.Validators = kept
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// chainBlocksResponse is the body of /api/chain/<chainID>/blocks.
+type chainBlocksResponse struct {
+	Days   []database.BlockDailyAgrega `json:"days"`
+	Recent []database.Block            `json:"recent"`
+}
+
+// GetChainBlocks serves the block statistics of chainID: the daily rollups
+// of the last `days` days (default 30, at most 365) and the latest `limit`
+// blocks (default 100, at most 1000), highest first.
+func GetChainBlocks(w http.ResponseWriter, r *http.Request, db *gorm.DB, chainID string) {
+	EnableCORS(w, r)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := internal.Config.ValidateChainID(chainID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	days, limit := 30, 100
+	if v := r.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 365 {
+			http.Error(w, "days must be between 1 and 365", http.StatusBadRequest)
+			return
+		}
+		days = n
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 1000 {
+			http.Error(w, "limit must be between 1 and 1000", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	var resp chainBlocksResponse
+	var err error
+	if resp.Days, err = database.GetBlockDailyAgregas(db, chainID, time.Now().AddDate(0, 0, -days)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if resp.Recent, err = database.GetRecentBlocks(db, chainID, limit); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// nodeVersionsResponse is the body of /api/chain/<chainID>/versions.
+type nodeVersionsResponse struct {
+	Majority     string                      `json:"majority"`
+	Distribution []gnovalidator.VersionCount `json:"distribution"`
+	Nodes        []database.NodeVersion      `json:"nodes"`
+	History      []database.NodeVersion      `json:"history,omitempty"`
+}
+
+// GetNodeVersions serves the node version inventory of chainID: the
+// version of each node seen in the last 24 hours, how many nodes and
+// validators run each version, and the version of the majority of the
+// validators ("" when none has one). With `node_id`, the versions that node
+// was seen with are listed too, oldest first.
+func GetNodeVersions(w http.ResponseWriter, r *http.Request, db *gorm.DB, chainID string) {
+	EnableCORS(w, r)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := internal.Config.ValidateChainID(chainID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	nodes, err := database.GetCurrentNodeVersions(db, chainID, time.Now().Add(-gnovalidator.NodeVersionStaleAfter))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resp := nodeVersionsResponse{
+		Distribution: gnovalidator.NodeVersionDistribution(nodes),
+		Nodes:        nodes,
+	}
+	resp.Majority, _ = gnovalidator.MajorityVersion(nodes)
+	if nodeID := r.URL.Query().Get("node_id"); nodeID != "" {
+		if resp.History, err = database.GetNodeVersionHistory(db, chainID, nodeID); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+type chainPeersResponse struct {
+	Peers   []database.NetPeer   `json:"peers"`
+	History []database.PeerCount `json:"history"`
+}
+
+// GetChainPeers serves the peer list of the RPC node of chainID: the peers
+// of the last sample, empty when it is more than 5 minutes old, and the
+// peer count of each sample of the last `hours` hours (default 24, at most
+// a week), oldest first.
+func GetChainPeers(w http.ResponseWriter, r *http.Request, db *gorm.DB, chainID string) {
+	EnableCORS(w, r)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := internal.Config.ValidateChainID(chainID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	hours := 24
+	if v := r.URL.Query().Get("hours"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 168 {
+			http.Error(w, "hours must be between 1 and 168", http.StatusBadRequest)
+			return
+		}
+		hours = n
+	}
+
+	var resp chainPeersResponse
+	var err error
+	now := time.Now()
+	if resp.Peers, err = database.GetLatestNetPeers(db, chainID, now.Add(-gnovalidator.PeerSampleStaleAfter)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if resp.History, err = database.GetPeerCounts(db, chainID, now.Add(-time.Duration(hours)*time.Hour)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// balanceHours reads the `hours` a balance history covers: 24 by default, at
+// most a week.
+func balanceHours(r *http.Request) (int, error) {
+	v := r.URL.Query().Get("hours")
+	if v == "" {
+		return 24, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 || n > 168 {
+		return 0, fmt.Errorf("hours must be between 1 and 168")
+	}
+	return n, nil
+}
+
+// GetChainBalances serves the balances of the valoper operator address
+// `addr` on chainID sampled over the last `hours` hours (default 24, at most
+// a week), oldest first. The accounts users registered are private: their
+// balances are served to their owner by MonitoredAccountBalancesHandler.
+func GetChainBalances(w http.ResponseWriter, r *http.Request, db *gorm.DB, chainID string) {
+	EnableCORS(w, r)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := internal.Config.ValidateChainID(chainID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	addr := r.URL.Query().Get("addr")
+	if addr == "" {
+		http.Error(w, "addr is required", http.StatusBadRequest)
+		return
+	}
+	hours, err := balanceHours(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !gnovalidator.IsValoperOperator(chainID, addr) {
+		http.Error(w, "addr is not a valoper operator address", http.StatusNotFound)
+		return
+	}
+	balances, err := database.GetAccountBalances(db, chainID, addr, time.Now().Add(-time.Duration(hours)*time.Hour))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, balances)
+}
+
 // ======================CORS=============================================
 func EnableCORS(w http.ResponseWriter, r ...*http.Request) {
 	origin := ""
@@ -1227,6 +2236,17 @@ func corsThenAuth(next http.Handler, protect func(http.Handler) http.Handler) ht
 }
 
 // ======================== Start API =====================================
+// publicRoutes are handlers built outside the api package (the Telegram
+// webhook routes, whose bots are set up by main) and mounted as-is when the
+// server starts. They authenticate their requests themselves.
+var publicRoutes = map[string]http.Handler{}
+
+// HandlePublic mounts h on pattern when StartWebhookAPI starts. It must be
+// called before StartWebhookAPI.
+func HandlePublic(pattern string, h http.Handler) {
+	publicRoutes[pattern] = h
+}
+
 func StartWebhookAPI(db *gorm.DB) {
 	clerk.SetKey(internal.Config.ClerkSecretKey)
 	mux := http.NewServeMux()
@@ -1271,6 +2291,20 @@ func StartWebhookAPI(db *gorm.DB) {
 		}
 	})
 
+	rotateSecretHandler := func(tablename string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodPost:
+				RotateWebhookSecretHandler(w, r, db, tablename)
+			case http.MethodOptions:
+				EnableCORS(w, r)
+				w.WriteHeader(http.StatusOK)
+			default:
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+		})
+	}
+
 	userHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
@@ -1321,13 +2355,111 @@ func StartWebhookAPI(db *gorm.DB) {
 		}
 	})
 
+	silencesHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			ListSilencesHandler(w, r, db)
+		case http.MethodPost:
+			CreateSilenceHandler(w, r, db)
+		case http.MethodDelete:
+			DeleteSilenceHandler(w, r, db)
+		case http.MethodOptions:
+			EnableCORS(w, r)
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	alertAckHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			AckAlertHandler(w, r, db)
+		case http.MethodOptions:
+			EnableCORS(w, r)
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	escalationPoliciesHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			ListEscalationPoliciesHandler(w, r, db)
+		case http.MethodPost:
+			CreateEscalationPolicyHandler(w, r, db)
+		case http.MethodPut:
+			UpdateEscalationPolicyHandler(w, r, db)
+		case http.MethodDelete:
+			DeleteEscalationPolicyHandler(w, r, db)
+		case http.MethodOptions:
+			EnableCORS(w, r)
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	accountsHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			ListMonitoredAccountsHandler(w, r, db)
+		case http.MethodPost:
+			CreateMonitoredAccountHandler(w, r, db)
+		case http.MethodDelete:
+			DeleteMonitoredAccountHandler(w, r, db)
+		case http.MethodOptions:
+			EnableCORS(w, r)
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	accountBalancesHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			MonitoredAccountBalancesHandler(w, r, db)
+		case http.MethodOptions:
+			EnableCORS(w, r)
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	txWatchesHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			ListTxWatchesHandler(w, r, db)
+		case http.MethodPost:
+			CreateTxWatchHandler(w, r, db)
+		case http.MethodDelete:
+			DeleteTxWatchHandler(w, r, db)
+		case http.MethodOptions:
+			EnableCORS(w, r)
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
 	if internal.Config.DevMode {
 		// In development mode, don't use Clerk protection
 		mux.Handle("/webhooks/govdao", webhookGovDAOHandler)
 		mux.Handle("/webhooks/validator", webhookValidatorHandler)
+		mux.Handle("/webhooks/govdao/rotate-secret", rotateSecretHandler("webhook_gov_daos"))
+		mux.Handle("/webhooks/validator/rotate-secret", rotateSecretHandler("webhook_validators"))
 		mux.Handle("/users", userHandler)
 		mux.Handle("/alert-contacts", alertContactsHandler)
 		mux.Handle("/usersH", usersHHandler)
+		mux.Handle("/silences", silencesHandler)
+		mux.Handle("/alerts/", alertAckHandler)
+		mux.Handle("/escalation-policies", escalationPoliciesHandler)
+		mux.Handle("/accounts", accountsHandler)
+		mux.Handle("/accounts/balances", accountBalancesHandler)
+		mux.Handle("/tx-watches", txWatchesHandler)
 	} else {
 		// In production mode, use Clerk protection.
 		// CORS headers (and OPTIONS preflight short-circuit) must be applied
@@ -1337,9 +2469,17 @@ func StartWebhookAPI(db *gorm.DB) {
 		protected := clerkhttp.RequireHeaderAuthorization()
 		mux.Handle("/webhooks/govdao", corsThenAuth(webhookGovDAOHandler, protected))
 		mux.Handle("/webhooks/validator", corsThenAuth(webhookValidatorHandler, protected))
+		mux.Handle("/webhooks/govdao/rotate-secret", corsThenAuth(rotateSecretHandler("webhook_gov_daos"), protected))
+		mux.Handle("/webhooks/validator/rotate-secret", corsThenAuth(rotateSecretHandler("webhook_validators"), protected))
 		mux.Handle("/users", corsThenAuth(userHandler, protected))
 		mux.Handle("/alert-contacts", corsThenAuth(alertContactsHandler, protected))
 		mux.Handle("/usersH", corsThenAuth(usersHHandler, protected))
+		mux.Handle("/silences", corsThenAuth(silencesHandler, protected))
+		mux.Handle("/alerts/", corsThenAuth(alertAckHandler, protected))
+		mux.Handle("/escalation-policies", corsThenAuth(escalationPoliciesHandler, protected))
+		mux.Handle("/accounts", corsThenAuth(accountsHandler, protected))
+		mux.Handle("/accounts/balances", corsThenAuth(accountBalancesHandler, protected))
+		mux.Handle("/tx-watches", corsThenAuth(txWatchesHandler, protected))
 	}
 
 	// ====================== Dashboard =================
@@ -1496,13 +2636,14 @@ func StartWebhookAPI(db *gorm.DB) {
 		}
 	})
 
-	// /api/chain/<chainID>/health
+	// /api/chain/<chainID>/health, /api/chain/<chainID>/missed_proposals,
+	// /api/chain/<chainID>/blocks, /api/chain/<chainID>/versions
 	mux.HandleFunc("/api/chain/", func(w http.ResponseWriter, r *http.Request) {
-		// Expected path: /api/chain/<chainID>/health
-		// Strip the prefix "/api/chain/" to get "<chainID>/health"
+		// Expected path: /api/chain/<chainID>/<resource>
+		// Strip the prefix "/api/chain/" to get "<chainID>/<resource>"
 		rest := strings.TrimPrefix(r.URL.Path, "/api/chain/")
 		parts := strings.SplitN(rest, "/", 2)
-		if len(parts) != 2 || parts[1] != "health" {
+		if len(parts) != 2 || (parts[1] != "health" && parts[1] != "missed_proposals" && parts[1] != "blocks" && parts[1] != "versions" && parts[1] != "peers" && parts[1] != "balances") {
 			http.NotFound(w, r)
 			return
 		}
@@ -1511,9 +2652,46 @@ func StartWebhookAPI(db *gorm.DB) {
 			http.Error(w, "Missing chain ID", http.StatusBadRequest)
 			return
 		}
-		GetChainHealth(w, r, db, chainID)
+		switch parts[1] {
+		case "missed_proposals":
+			GetMissedProposals(w, r, db, chainID)
+		case "blocks":
+			GetChainBlocks(w, r, db, chainID)
+		case "versions":
+			GetNodeVersions(w, r, db, chainID)
+		case "peers":
+			GetChainPeers(w, r, db, chainID)
+		case "balances":
+			GetChainBalances(w, r, db, chainID)
+		default:
+			GetChainHealth(w, r, db, chainID)
+		}
 	})
 
+	// ====================== Discord bot ===============================
+	// Public: Discord signs every interaction and the bot verifies it.
+	if internal.Config.DiscordBot.Enabled() {
+		cfg := internal.Config.DiscordBot
+		bot, err := discord.NewBot(db, cfg.PublicKey, cfg.ApplicationID, internal.Config.DefaultChain, internal.EnabledChains)
+		if err != nil {
+			log.Printf("[discord] bot disabled: %v", err)
+		} else {
+			mux.Handle("/discord/interactions", bot)
+		}
+	}
+
+	// ====================== Slack app =================================
+	// Public: Slack signs the slash command and interactivity requests and
+	// the app verifies them.
+	if internal.Config.SlackApp.Enabled() {
+		mux.Handle("/slack/interactions", slack.NewApp(db, internal.Config.SlackApp.SigningSecret, internal.Config.DefaultChain, internal.EnabledChains))
+	}
+
+	// ====================== Telegram webhooks ========================
+	for pattern, h := range publicRoutes {
+		mux.Handle(pattern, h)
+	}
+
 	// Starting the HTTP server -
 	addr := ":" + internal.Config.BackendPort
 
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/testoutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// faucetAddr is a valid g1 address for the account tests.
+const faucetAddr = "g17s95c5jpc6x2l3edwh4dm8yhac68yru7tl9klf"
+
+// TestMonitoredAccountHandlers checks that users follow accounts of their
+// own, once per chain and address.
+func TestMonitoredAccountHandlers(t *testing.T) {
+	withTestChain(t)
+	internal.Config.DevMode = true
+	defer func() { internal.Config.DevMode = false }()
+	db := testoutils.NewTestDB(t)
+
+	do := func(method, target, userID, body string, handler func(http.ResponseWriter, *http.Request)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+		req.Header.Set("X-Debug-UserID", userID)
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w
+	}
+	create := func(w http.ResponseWriter, r *http.Request) { CreateMonitoredAccountHandler(w, r, db) }
+	list := func(w http.ResponseWriter, r *http.Request) { ListMonitoredAccountsHandler(w, r, db) }
+	del := func(w http.ResponseWriter, r *http.Request) { DeleteMonitoredAccountHandler(w, r, db) }
+
+	body := `{"chain_id":"test12","addr":" ` + faucetAddr + ` ","label":"faucet","min_balance":5000000}`
+	w := do(http.MethodPost, "/accounts", "u1", body, create)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var account database.MonitoredAccount
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&account))
+	assert.Equal(t, faucetAddr, account.Addr)
+
+	assert.Equal(t, http.StatusConflict, do(http.MethodPost, "/accounts", "u1", body, create).Code)
+	assert.Equal(t, http.StatusCreated, do(http.MethodPost, "/accounts", "u2", body, create).Code, "another user")
+	for _, bad := range []string{
+		`{"chain_id":"nope","addr":"` + faucetAddr + `"}`,
+		`{"chain_id":"test12","addr":"faucet"}`,
+		`{"chain_id":"test12","addr":"g1faucet"}`,
+		`{"chain_id":"test12","addr":"` + faucetAddr + `","min_balance":-1}`,
+		`not json`,
+	} {
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/accounts", "u1", bad, create).Code, bad)
+	}
+
+	w = do(http.MethodGet, "/accounts", "u1", "", list)
+	require.Equal(t, http.StatusOK, w.Code)
+	var accounts []database.MonitoredAccount
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&accounts))
+	require.Len(t, accounts, 1)
+
+	target := fmt.Sprintf("/accounts?id=%d", account.ID)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodDelete, target, "u2", "", del).Code, "not the owner")
+	assert.Equal(t, http.StatusOK, do(http.MethodDelete, target, "u1", "", del).Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodDelete, "/accounts?id=x", "u1", "", del).Code)
+}
+
+// TestMonitoredAccountBalancesHandler checks that the balances of a
+// registered account are served to its owner only.
+func TestMonitoredAccountBalancesHandler(t *testing.T) {
+	withTestChain(t)
+	internal.Config.DevMode = true
+	defer func() { internal.Config.DevMode = false }()
+	db := testoutils.NewTestDB(t)
+
+	account := database.MonitoredAccount{UserID: "u1", ChainID: "test12", Addr: faucetAddr}
+	require.NoError(t, database.CreateMonitoredAccount(db, &account))
+	now := time.Now().UTC()
+	require.NoError(t, database.InsertAccountBalances(db, []database.AccountBalance{
+		{ChainID: "test12", Addr: faucetAddr, Time: now.Add(-2 * time.Hour), Balance: 3000000},
+		{ChainID: "test12", Addr: faucetAddr, Time: now.Add(-time.Minute), Balance: 2000000},
+	}))
+
+	do := func(target, userID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("X-Debug-UserID", userID)
+		w := httptest.NewRecorder()
+		MonitoredAccountBalancesHandler(w, req, db)
+		return w
+	}
+	target := fmt.Sprintf("/accounts/balances?id=%d&hours=1", account.ID)
+	assert.Equal(t, http.StatusNotFound, do(target, "u2").Code, "not the owner")
+	assert.Equal(t, http.StatusBadRequest, do("/accounts/balances?id=x", "u1").Code)
+	assert.Equal(t, http.StatusBadRequest, do(fmt.Sprintf("/accounts/balances?id=%d&hours=500", account.ID), "u1").Code)
+
+	w := do(target, "u1")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var balances []database.AccountBalance
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&balances))
+	require.Len(t, balances, 1)
+	assert.Equal(t, int64(2000000), balances[0].Balance)
+}
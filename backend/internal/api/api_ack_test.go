@@ -0,0 +1,26 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal"
+)
+
+func TestAckAlertHandler_RejectsBadPaths(t *testing.T) {
+	internal.Config.DevMode = true
+	defer func() { internal.Config.DevMode = false }()
+
+	cases := map[string]int{
+		"/alerts/abc/ack": http.StatusBadRequest,
+		"/alerts/12":      http.StatusNotFound,
+	}
+	for path, want := range cases {
+		rec := httptest.NewRecorder()
+		AckAlertHandler(rec, httptest.NewRequest(http.MethodPost, path, nil), nil)
+		if rec.Code != want {
+			t.Fatalf("%s: status = %d, want %d", path, rec.Code, want)
+		}
+	}
+}
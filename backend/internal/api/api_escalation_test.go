@@ -0,0 +1,54 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/testoutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDecodeEscalationPolicy checks that a policy may only target the
+// caller's own webhooks and alert contacts.
+func TestDecodeEscalationPolicy(t *testing.T) {
+	withTestChain(t)
+	db := testoutils.NewTestDB(t)
+
+	own := database.WebhookValidator{UserID: "u1", URL: "https://discord.com/api/webhooks/1/a", Type: "discord"}
+	other := database.WebhookValidator{UserID: "u2", URL: "https://discord.com/api/webhooks/2/b", Type: "discord"}
+	require.NoError(t, db.Create(&own).Error)
+	require.NoError(t, db.Create(&other).Error)
+	contact := database.AlertContact{UserID: "u2", Moniker: "val", NameContact: "bob", IDwebhook: other.ID}
+	require.NoError(t, db.Create(&contact).Error)
+
+	cases := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{"own webhook", fmt.Sprintf(`{"chain_id":"test12","tier2_after_minutes":15,"tier2_webhooks":[%d]}`, own.ID), ""},
+		{"every chain", fmt.Sprintf(`{"tier3_after_minutes":60,"tier3_webhooks":[%d]}`, own.ID), ""},
+		{"foreign webhook", fmt.Sprintf(`{"tier2_after_minutes":15,"tier2_webhooks":[%d]}`, other.ID), "webhook"},
+		{"foreign contact", fmt.Sprintf(`{"tier2_after_minutes":15,"tier2_contacts":[%d]}`, contact.ID), "alert contact"},
+		{"unknown chain", fmt.Sprintf(`{"chain_id":"nope","tier2_after_minutes":15,"tier2_webhooks":[%d]}`, own.ID), "nope"},
+		{"no tier", `{"chain_id":"test12"}`, "at least one"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/escalation-policies", bytes.NewBufferString(tc.body))
+			p, err := decodeEscalationPolicy(req, db, "u1")
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+				assert.Equal(t, "u1", p.UserID)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/gnovalidator"
+)
+
+func TestDecodePlannedHalt(t *testing.T) {
+	withTestChain(t)
+	gnovalidator.SetLastHeight("test12", 1000)
+	t.Cleanup(func() { gnovalidator.SetLastHeight("test12", 0) })
+
+	cases := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{"planned halt", `{"chain_id":"test12","halt_height":5000,"window_minutes":30,"reason":"v2 upgrade"}`, ""},
+		{"default window", `{"chain_id":"test12","halt_height":5000}`, ""},
+		{"unknown chain", `{"chain_id":"nope","halt_height":5000}`, "nope"},
+		{"missing height", `{"chain_id":"test12"}`, "halt_height is required"},
+		{"height already passed", `{"chain_id":"test12","halt_height":1000}`, "above the current height 1000"},
+		{"negative window", `{"chain_id":"test12","halt_height":5000,"window_minutes":-5}`, "window_minutes must be positive"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/admin/halts", bytes.NewBufferString(tc.body))
+			halt, err := decodePlannedHalt(req)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if halt.WindowMinutes <= 0 {
+					t.Fatalf("window_minutes = %d, want a positive default", halt.WindowMinutes)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("err = %v, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
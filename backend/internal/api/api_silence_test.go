@@ -0,0 +1,49 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+)
+
+func TestDecodeSilence(t *testing.T) {
+	withTestChain(t)
+	end := time.Now().Add(2 * time.Hour).UTC().Format(time.RFC3339)
+	tooLong := time.Now().Add(8 * 24 * time.Hour).UTC().Format(time.RFC3339)
+
+	cases := []struct {
+		name    string
+		body    string
+		max     time.Duration
+		wantErr string
+	}{
+		{"validator silence", fmt.Sprintf(`{"chain_id":"test12","addr":"g1abc","end_at":%q,"reason":"upgrade"}`, end), database.MaxSilenceDuration, ""},
+		{"chain-wide silence", fmt.Sprintf(`{"chain_id":"test12","addr":"all","end_at":%q}`, end), database.MaxSilenceDuration, ""},
+		{"unknown chain", fmt.Sprintf(`{"chain_id":"nope","addr":"all","end_at":%q}`, end), database.MaxSilenceDuration, "nope"},
+		{"missing addr", fmt.Sprintf(`{"chain_id":"test12","end_at":%q}`, end), database.MaxSilenceDuration, "addr is required"},
+		{"missing end", `{"chain_id":"test12","addr":"all"}`, database.MaxSilenceDuration, "end_at is required"},
+		{"longer than the cap", fmt.Sprintf(`{"chain_id":"test12","addr":"all","end_at":%q}`, tooLong), database.MaxSilenceDuration, "cannot last more than"},
+		{"admins have no cap", fmt.Sprintf(`{"chain_id":"test12","addr":"all","end_at":%q}`, tooLong), 0, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/silences", bytes.NewBufferString(tc.body))
+			_, err := decodeSilence(req, tc.max)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("err = %v, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/samouraiworld/gnomonitoring/backend/internal"
 	"github.com/samouraiworld/gnomonitoring/backend/internal/api"
 	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/gnovalidator"
 	"github.com/samouraiworld/gnomonitoring/backend/internal/testoutils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -420,3 +421,224 @@ func TestGetBlockHeight_ChainIsolation(t *testing.T) {
 	assert.Equal(t, int64(1233), resp2["last_stored"],
 		"net2 last_stored should be MAX(block_height)-1 = 1233, not net1's value")
 }
+
+// ---------- TEST /api/chain/<chainID>/missed_proposals ----------
+
+func TestGetMissedProposals(t *testing.T) {
+	internal.Config.Chains = map[string]*internal.ChainConfig{
+		"test12": {RPCEndpoints: []string{"http://localhost:26657"}, Enabled: true},
+	}
+	internal.EnabledChains = []string{"test12"}
+	internal.Config.DefaultChain = "test12"
+	defer func() {
+		internal.Config.Chains = nil
+		internal.EnabledChains = []string{}
+		internal.Config.DefaultChain = ""
+	}()
+
+	db := testoutils.NewTestDB(t)
+	require.NoError(t, database.InsertMissedProposals(db, []database.MissedProposal{
+		{ChainID: "test12", Height: 10, Round: 0, Addr: "g1a", Moniker: "a", CommitRound: 2, CommittedAt: time.Now()},
+		{ChainID: "test12", Height: 10, Round: 1, Addr: "g1b", Moniker: "b", CommitRound: 2, CommittedAt: time.Now()},
+	}))
+
+	for _, bad := range []string{"hours=0", "hours=abc", "limit=5000"} {
+		w := httptest.NewRecorder()
+		api.GetMissedProposals(w, httptest.NewRequest(http.MethodGet, "/api/chain/test12/missed_proposals?"+bad, nil), db, "test12")
+		assert.Equal(t, http.StatusBadRequest, w.Code, bad)
+	}
+	w := httptest.NewRecorder()
+	api.GetMissedProposals(w, httptest.NewRequest(http.MethodGet, "/api/chain/nope/missed_proposals", nil), db, "nope")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	api.GetMissedProposals(w, httptest.NewRequest(http.MethodGet, "/api/chain/test12/missed_proposals?addr=g1b", nil), db, "test12")
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Validators []database.MissedProposalCount `json:"validators"`
+		Events     []database.MissedProposal      `json:"events"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp.Events, 1)
+	assert.Equal(t, 1, resp.Events[0].Round)
+	require.Len(t, resp.Validators, 1)
+	assert.Equal(t, "g1b", resp.Validators[0].Addr)
+}
+
+// ---------- TEST /api/chain/<chainID>/blocks ----------
+
+func TestGetChainBlocks(t *testing.T) {
+	internal.Config.Chains = map[string]*internal.ChainConfig{
+		"test12": {RPCEndpoints: []string{"http://localhost:26657"}, Enabled: true},
+	}
+	internal.EnabledChains = []string{"test12"}
+	internal.Config.DefaultChain = "test12"
+	defer func() {
+		internal.Config.Chains = nil
+		internal.EnabledChains = []string{}
+		internal.Config.DefaultChain = ""
+	}()
+
+	db := testoutils.NewTestDB(t)
+	now := time.Now().UTC()
+	require.NoError(t, database.UpsertBlocks(db, []database.Block{
+		{ChainID: "test12", Height: 10, Time: now.Add(-2 * time.Second)},
+		{ChainID: "test12", Height: 11, Time: now},
+	}))
+	require.NoError(t, db.Create(&database.BlockDailyAgrega{
+		ChainID: "test12", BlockDate: now.AddDate(0, 0, -1).Format("2006-01-02"), BlockCount: 43200, AvgBlockTime: 2,
+	}).Error)
+
+	for _, bad := range []string{"days=0", "days=abc", "limit=5000"} {
+		w := httptest.NewRecorder()
+		api.GetChainBlocks(w, httptest.NewRequest(http.MethodGet, "/api/chain/test12/blocks?"+bad, nil), db, "test12")
+		assert.Equal(t, http.StatusBadRequest, w.Code, bad)
+	}
+	w := httptest.NewRecorder()
+	api.GetChainBlocks(w, httptest.NewRequest(http.MethodGet, "/api/chain/nope/blocks", nil), db, "nope")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	api.GetChainBlocks(w, httptest.NewRequest(http.MethodGet, "/api/chain/test12/blocks?limit=1", nil), db, "test12")
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Days   []database.BlockDailyAgrega `json:"days"`
+		Recent []database.Block            `json:"recent"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp.Days, 1)
+	assert.Equal(t, 43200, resp.Days[0].BlockCount)
+	require.Len(t, resp.Recent, 1)
+	assert.Equal(t, int64(11), resp.Recent[0].Height)
+}
+
+func TestGetNodeVersions(t *testing.T) {
+	internal.Config.Chains = map[string]*internal.ChainConfig{
+		"test12": {RPCEndpoints: []string{"http://localhost:26657"}, Enabled: true},
+	}
+	internal.EnabledChains = []string{"test12"}
+	internal.Config.DefaultChain = "test12"
+	defer func() {
+		internal.Config.Chains = nil
+		internal.EnabledChains = []string{}
+		internal.Config.DefaultChain = ""
+	}()
+
+	db := testoutils.NewTestDB(t)
+	now := time.Now().UTC()
+	require.NoError(t, database.UpsertNodeVersions(db, []database.NodeVersion{
+		{ChainID: "test12", NodeID: "a", Addr: "g1a", Version: "v1", FirstSeen: now.Add(-time.Hour), LastSeen: now.Add(-time.Hour)},
+		{ChainID: "test12", NodeID: "a", Addr: "g1a", Version: "v2", FirstSeen: now, LastSeen: now},
+		{ChainID: "test12", NodeID: "b", Addr: "g1b", Version: "v2", FirstSeen: now, LastSeen: now},
+		{ChainID: "test12", NodeID: "c", Addr: "g1c", Version: "v1", FirstSeen: now, LastSeen: now},
+	}))
+
+	w := httptest.NewRecorder()
+	api.GetNodeVersions(w, httptest.NewRequest(http.MethodGet, "/api/chain/nope/versions", nil), db, "nope")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	api.GetNodeVersions(w, httptest.NewRequest(http.MethodGet, "/api/chain/test12/versions?node_id=a", nil), db, "test12")
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Majority     string                 `json:"majority"`
+		Distribution []map[string]any       `json:"distribution"`
+		Nodes        []database.NodeVersion `json:"nodes"`
+		History      []database.NodeVersion `json:"history"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, "v2", resp.Majority)
+	assert.Len(t, resp.Distribution, 2)
+	assert.Len(t, resp.Nodes, 3)
+	require.Len(t, resp.History, 2)
+	assert.Equal(t, "v1", resp.History[0].Version)
+}
+
+func TestGetChainPeers(t *testing.T) {
+	internal.Config.Chains = map[string]*internal.ChainConfig{
+		"test12": {RPCEndpoints: []string{"http://localhost:26657"}, Enabled: true},
+	}
+	internal.EnabledChains = []string{"test12"}
+	internal.Config.DefaultChain = "test12"
+	defer func() {
+		internal.Config.Chains = nil
+		internal.EnabledChains = []string{}
+		internal.Config.DefaultChain = ""
+	}()
+
+	db := testoutils.NewTestDB(t)
+	now := time.Now().UTC()
+	require.NoError(t, database.InsertNetPeers(db, "test12", now.Add(-2*time.Hour), []database.NetPeer{
+		{NodeID: "a", Direction: "outbound"},
+	}))
+	require.NoError(t, database.InsertNetPeers(db, "test12", now.Add(-time.Minute), []database.NetPeer{
+		{NodeID: "a", Direction: "outbound"},
+		{NodeID: "b", Direction: "inbound"},
+	}))
+
+	for _, bad := range []string{"hours=0", "hours=abc", "hours=500"} {
+		w := httptest.NewRecorder()
+		api.GetChainPeers(w, httptest.NewRequest(http.MethodGet, "/api/chain/test12/peers?"+bad, nil), db, "test12")
+		assert.Equal(t, http.StatusBadRequest, w.Code, bad)
+	}
+	w := httptest.NewRecorder()
+	api.GetChainPeers(w, httptest.NewRequest(http.MethodGet, "/api/chain/nope/peers", nil), db, "nope")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	api.GetChainPeers(w, httptest.NewRequest(http.MethodGet, "/api/chain/test12/peers?hours=1", nil), db, "test12")
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Peers   []database.NetPeer   `json:"peers"`
+		History []database.PeerCount `json:"history"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Len(t, resp.Peers, 2)
+	require.Len(t, resp.History, 1)
+	assert.Equal(t, 2, resp.History[0].Peers)
+}
+
+func TestGetChainBalances(t *testing.T) {
+	internal.Config.Chains = map[string]*internal.ChainConfig{
+		"test12": {RPCEndpoints: []string{"http://localhost:26657"}, Enabled: true},
+	}
+	internal.EnabledChains = []string{"test12"}
+	internal.Config.DefaultChain = "test12"
+	defer func() {
+		internal.Config.Chains = nil
+		internal.EnabledChains = []string{}
+		internal.Config.DefaultChain = ""
+	}()
+
+	gnovalidator.SetCachedValopers("test12", []gnovalidator.Valoper{{Name: "a", Address: "g1a"}})
+	defer gnovalidator.SetCachedValopers("test12", nil)
+
+	db := testoutils.NewTestDB(t)
+	now := time.Now().UTC()
+	require.NoError(t, database.InsertAccountBalances(db, []database.AccountBalance{
+		{ChainID: "test12", Addr: "g1a", Time: now.Add(-2 * time.Hour), Balance: 3000000},
+		{ChainID: "test12", Addr: "g1a", Time: now.Add(-time.Minute), Balance: 2000000},
+		{ChainID: "test12", Addr: "g1b", Time: now.Add(-time.Minute), Balance: 1},
+	}))
+
+	for _, bad := range []string{"", "addr=g1a&hours=0", "addr=g1a&hours=abc", "addr=g1a&hours=500"} {
+		w := httptest.NewRecorder()
+		api.GetChainBalances(w, httptest.NewRequest(http.MethodGet, "/api/chain/test12/balances?"+bad, nil), db, "test12")
+		assert.Equal(t, http.StatusBadRequest, w.Code, bad)
+	}
+	w := httptest.NewRecorder()
+	api.GetChainBalances(w, httptest.NewRequest(http.MethodGet, "/api/chain/nope/balances?addr=g1a", nil), db, "nope")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	api.GetChainBalances(w, httptest.NewRequest(http.MethodGet, "/api/chain/test12/balances?addr=g1b", nil), db, "test12")
+	assert.Equal(t, http.StatusNotFound, w.Code, "a registered account is not public")
+
+	w = httptest.NewRecorder()
+	api.GetChainBalances(w, httptest.NewRequest(http.MethodGet, "/api/chain/test12/balances?addr=g1a&hours=1", nil), db, "test12")
+	require.Equal(t, http.StatusOK, w.Code)
+	var balances []database.AccountBalance
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&balances))
+	require.Len(t, balances, 1)
+	assert.Equal(t, int64(2000000), balances[0].Balance)
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/testoutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTxWatchHandlers checks that users watch targets of their own, once per
+// chain and target.
+func TestTxWatchHandlers(t *testing.T) {
+	withTestChain(t)
+	internal.Config.DevMode = true
+	defer func() { internal.Config.DevMode = false }()
+	db := testoutils.NewTestDB(t)
+
+	do := func(method, target, userID, body string, handler func(http.ResponseWriter, *http.Request)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+		req.Header.Set("X-Debug-UserID", userID)
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w
+	}
+	create := func(w http.ResponseWriter, r *http.Request) { CreateTxWatchHandler(w, r, db) }
+	list := func(w http.ResponseWriter, r *http.Request) { ListTxWatchesHandler(w, r, db) }
+	del := func(w http.ResponseWriter, r *http.Request) { DeleteTxWatchHandler(w, r, db) }
+
+	body := `{"chain_id":"test12","target":" gno.land/r/gov/dao/ ","label":"dao"}`
+	w := do(http.MethodPost, "/tx-watches", "u1", body, create)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var watch database.TxWatch
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&watch))
+	assert.Equal(t, "gno.land/r/gov/dao", watch.Target)
+
+	assert.Equal(t, http.StatusConflict, do(http.MethodPost, "/tx-watches", "u1", body, create).Code)
+	assert.Equal(t, http.StatusCreated, do(http.MethodPost, "/tx-watches", "u2", body, create).Code, "another user")
+	for _, bad := range []string{
+		`{"chain_id":"nope","target":"gno.land/r/gov/dao"}`,
+		`{"chain_id":"test12","target":"gov/dao"}`,
+		`not json`,
+	} {
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/tx-watches", "u1", bad, create).Code, bad)
+	}
+
+	w = do(http.MethodGet, "/tx-watches?chain=test12", "u1", "", list)
+	require.Equal(t, http.StatusOK, w.Code)
+	var watches []database.TxWatch
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&watches))
+	require.Len(t, watches, 1)
+
+	target := fmt.Sprintf("/tx-watches?id=%d", watch.ID)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodDelete, target, "u2", "", del).Code, "not the owner")
+	assert.Equal(t, http.StatusOK, do(http.MethodDelete, target, "u1", "", del).Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodDelete, "/tx-watches?id=x", "u1", "", del).Code)
+}
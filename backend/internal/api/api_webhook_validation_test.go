@@ -2,8 +2,10 @@ package api
 
 import (
 	"bytes"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -27,6 +29,19 @@ func TestValidateWebhookURL(t *testing.T) {
 		{"slack valid hooks.slack.com", "slack", "https://hooks.slack.com/services/T0/B0/xyz", false},
 		{"slack wrong host", "slack", "https://hooks.evil.example/services/T0/B0/xyz", true},
 		{"unknown type rejected", "teams", "https://discord.com/api/webhooks/1/abc", true},
+		{"generic any public host", "generic", "https://incident-bot.example.com/hooks/gno", false},
+		{"generic http scheme rejected", "generic", "http://incident-bot.example.com/hooks/gno", true},
+		{"generic localhost rejected", "generic", "https://localhost/hooks/gno", true},
+		{"generic private ip rejected", "generic", "https://10.0.0.5/hooks/gno", true},
+		{"generic loopback ip rejected", "generic", "https://127.0.0.1/hooks/gno", true},
+		{"pagerduty valid events host", "pagerduty", "https://events.pagerduty.com/v2/enqueue", false},
+		{"pagerduty wrong host", "pagerduty", "https://pagerduty.evil.example/v2/enqueue", true},
+		{"opsgenie valid eu host", "opsgenie", "https://api.eu.opsgenie.com", false},
+		{"opsgenie wrong host", "opsgenie", "https://opsgenie.evil.example", true},
+		{"matrix self-hosted hookshot", "matrix", "https://hookshot.example.org/webhook/abc", false},
+		{"matrix private ip rejected", "matrix", "https://192.168.1.10/webhook/abc", true},
+		{"mattermost self-hosted", "mattermost", "https://chat.example.org/hooks/abc", false},
+		{"mattermost localhost rejected", "mattermost", "https://localhost/hooks/abc", true},
 		{"malformed url rejected", "discord", "://not a url", true},
 	}
 	for _, tc := range cases {
@@ -42,6 +57,63 @@ func TestValidateWebhookURL(t *testing.T) {
 	}
 }
 
+func TestValidateMentionTag(t *testing.T) {
+	cases := []struct {
+		name      string
+		whType    string
+		tag       string
+		wantError bool
+	}{
+		{"empty tag", "matrix", "", false},
+		{"discord snowflake", "discord", "123456789012345678", false},
+		{"discord non numeric", "discord", "alice", true},
+		{"no webhook defaults to numeric", "", "@alice", true},
+		{"matrix user id", "matrix", "@alice:example.org", false},
+		{"matrix user id with port", "matrix", "@ops.bot:matrix.example.org:8448", false},
+		{"matrix missing server", "matrix", "@alice", true},
+		{"matrix html injection", "matrix", "@a:b\"><script>", true},
+		{"mattermost username", "mattermost", "alice.ops", false},
+		{"mattermost with at", "mattermost", "@alice", false},
+		{"mattermost space", "mattermost", "alice ops", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateMentionTag(tc.whType, tc.tag)
+			if tc.wantError && err == nil {
+				t.Fatalf("expected error for %s/%q, got nil", tc.whType, tc.tag)
+			}
+			if !tc.wantError && err != nil {
+				t.Fatalf("expected no error for %s/%q, got %v", tc.whType, tc.tag, err)
+			}
+		})
+	}
+}
+
+func TestValidateWebhookFilter(t *testing.T) {
+	cases := []struct {
+		name      string
+		filter    database.WebhookFilter
+		wantError bool
+	}{
+		{"empty filter", database.WebhookFilter{}, false},
+		{"critical only, own validators", database.WebhookFilter{MinLevel: "CRITICAL", IncludeValidators: database.StringList{"g1abc"}, AlertKinds: database.StringList{"missed_blocks", "rpc_error"}}, false},
+		{"unknown level", database.WebhookFilter{MinLevel: "PANIC"}, true},
+		{"lowercase level", database.WebhookFilter{MinLevel: "critical"}, true},
+		{"unknown kind", database.WebhookFilter{AlertKinds: database.StringList{"govdao"}}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateWebhookFilter(tc.filter)
+			if tc.wantError && err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !tc.wantError && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
 func TestCreateMonitoringWebhookHandler_RejectsDisallowedHost(t *testing.T) {
 	db := testoutils.NewTestDB(t)
 	internal.Config.DevMode = true
@@ -186,3 +258,89 @@ func TestUpdateWebhookHandler_RejectsClearingChainID(t *testing.T) {
 		t.Fatalf("body = %q, want it to mention chain_id is required", rec.Body.String())
 	}
 }
+
+// TestRotateWebhookSecretHandler checks that a generic webhook gets a secret
+// at creation, that rotation replaces it, and that non-generic webhooks
+// cannot be rotated.
+func TestRotateWebhookSecretHandler(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+	internal.Config.DevMode = true
+	defer func() { internal.Config.DevMode = false }()
+
+	if err := database.InsertMonitoringWebhook("test-user", "https://bot.example.com/hook", "x", "generic", "test12", db); err != nil {
+		t.Fatalf("insert generic webhook: %v", err)
+	}
+	if err := database.InsertMonitoringWebhook("test-user", "https://discord.com/api/webhooks/1/abc", "x", "discord", "test12", db); err != nil {
+		t.Fatalf("insert discord webhook: %v", err)
+	}
+
+	var generic, discord database.WebhookValidator
+	if err := db.Where("type = ?", "generic").First(&generic).Error; err != nil {
+		t.Fatalf("load generic webhook: %v", err)
+	}
+	if err := db.Where("type = ?", "discord").First(&discord).Error; err != nil {
+		t.Fatalf("load discord webhook: %v", err)
+	}
+	if generic.Secret == nil || len(*generic.Secret) != 64 {
+		t.Fatalf("generic webhook secret = %v, want a 64-char hex secret", generic.Secret)
+	}
+	if discord.Secret != nil {
+		t.Fatalf("discord webhook must not get a secret, got %q", *discord.Secret)
+	}
+
+	rotate := func(id int) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/validator/rotate-secret?id="+strconv.Itoa(id), nil)
+		req.Header.Set("X-Debug-UserID", "test-user")
+		rec := httptest.NewRecorder()
+		RotateWebhookSecretHandler(rec, req, db, "webhook_validators")
+		return rec
+	}
+
+	rec := rotate(generic.ID)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200, body = %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Secret string `json:"secret"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Secret == "" || resp.Secret == *generic.Secret {
+		t.Fatalf("rotated secret = %q, want a new non-empty secret", resp.Secret)
+	}
+
+	if rec := rotate(discord.ID); rec.Code != http.StatusNotFound {
+		t.Fatalf("rotating a discord webhook: status = %d, want 404", rec.Code)
+	}
+}
+
+// TestListMonitoringWebhooksHandler_RedactsSecret checks that the list
+// endpoint reports whether a secret is set without returning it.
+func TestListMonitoringWebhooksHandler_RedactsSecret(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+	internal.Config.DevMode = true
+	defer func() { internal.Config.DevMode = false }()
+
+	if err := database.InsertMonitoringWebhook("test-user", "https://bot.example.com/hook", "x", "generic", "test12", db); err != nil {
+		t.Fatalf("insert generic webhook: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/webhooks/validator", nil)
+	req.Header.Set("X-Debug-UserID", "test-user")
+	rec := httptest.NewRecorder()
+	ListMonitoringWebhooksHandler(rec, req, db)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200, body = %s", rec.Code, rec.Body.String())
+	}
+	if strings.Contains(rec.Body.String(), `"secret"`) {
+		t.Fatalf("list response leaks the secret: %s", rec.Body.String())
+	}
+	var webhooks []database.WebhookValidator
+	if err := json.NewDecoder(rec.Body).Decode(&webhooks); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(webhooks) != 1 || !webhooks[0].HasSecret {
+		t.Fatalf("webhooks = %+v, want one with has_secret", webhooks)
+	}
+}
@@ -314,3 +314,58 @@ func TestDisabledChainsFiltering(t *testing.T) {
 		assert.NotEqual(t, "archived", id)
 	}
 }
+
+// TestTelegramWebhookConfig verifies that webhook mode is selected per bot
+// and needs a public URL.
+func TestTelegramWebhookConfig(t *testing.T) {
+	var cfg config
+	require.NoError(t, yaml.Unmarshal([]byte(`
+telegram_webhook:
+  public_url: "https://monitoring.example.com"
+  validator: true
+`), &cfg))
+
+	assert.True(t, cfg.TelegramWebhook.Enabled("validator"))
+	assert.False(t, cfg.TelegramWebhook.Enabled("govdao"), "the govdao bot keeps long-polling")
+
+	cfg.TelegramWebhook.PublicURL = ""
+	assert.False(t, cfg.TelegramWebhook.Enabled("validator"), "no public URL, no webhook")
+}
+
+// TestChainConfig_CrossCheckEndpoints verifies the endpoint cross-check flag
+// is read with both endpoint forms and is off by default.
+func TestChainConfig_CrossCheckEndpoints(t *testing.T) {
+	var chains map[string]*ChainConfig
+	require.NoError(t, yaml.Unmarshal([]byte(`
+checked:
+  rpc_endpoints: ["https://rpc1.example.com", "https://rpc2.example.com"]
+  cross_check_endpoints: true
+  enabled: true
+plain:
+  rpc_endpoint: "https://rpc.example.com"
+  enabled: true
+`), &chains))
+	assert.True(t, chains["checked"].CrossCheckEndpoints)
+	assert.Len(t, chains["checked"].RPCEndpoints, 2)
+	assert.False(t, chains["plain"].CrossCheckEndpoints)
+}
+
+func TestChainConfig_BlockIngestion(t *testing.T) {
+	var chains map[string]*ChainConfig
+	require.NoError(t, yaml.Unmarshal([]byte(`
+pushed:
+  rpc_endpoint: "https://rpc.example.com"
+  graphql: "https://indexer.example.com/graphql/query"
+  block_ingestion: websocket
+plain:
+  rpc_endpoint: "https://rpc.example.com"
+`), &chains))
+	assert.Equal(t, BlockIngestionWebsocket, chains["pushed"].BlockIngestion)
+	assert.Empty(t, chains["plain"].BlockIngestion)
+
+	err := yaml.Unmarshal([]byte(`
+typo:
+  block_ingestion: websockets
+`), &chains)
+	assert.ErrorContains(t, err, "block_ingestion")
+}
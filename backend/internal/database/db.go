@@ -2,7 +2,9 @@ package database
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"log"
@@ -85,23 +87,42 @@ func InsertWebhook(userID, url, description, type_ string, args ...interface{})
 		Type:        type_,
 	}
 
-	// args may be: (chainID *string, db *gorm.DB)  or  (db *gorm.DB)
+	// args may be: (chainID *string, db *gorm.DB)  or  (db *gorm.DB),
+	// optionally followed by a user-supplied secret string.
 	var db *gorm.DB
+	var provided string
 	for _, a := range args {
 		switch v := a.(type) {
 		case *string:
 			webhook.ChainID = v
 		case *gorm.DB:
 			db = v
+		case string:
+			provided = v
 		}
 	}
 	if db == nil {
 		return fmt.Errorf("InsertWebhook: db argument is required")
 	}
+	secret, err := webhookSecretFor(type_, provided)
+	if err != nil {
+		return err
+	}
+	webhook.Secret = secret
 
 	return db.Create(&webhook).Error
 }
 
+// FindWebhookGovDAO returns the GovDAO webhook a user registered for url/type.
+func FindWebhookGovDAO(db *gorm.DB, userID, url, typ string) (*WebhookGovDAO, error) {
+	var wh WebhookGovDAO
+	if err := db.Where("user_id = ? AND url = ? AND type = ?", userID, url, typ).
+		First(&wh).Error; err != nil {
+		return nil, err
+	}
+	return &wh, nil
+}
+
 func LoadWebhooks(db *gorm.DB) ([]WebhookGovDAO, error) {
 	var webhooks []WebhookGovDAO
 	err := db.Find(&webhooks).Error
@@ -119,7 +140,7 @@ func UpdateLastCheckedID(url string, newID int, db *gorm.DB) error {
 func ListWebhooks(db *gorm.DB, userID string, chainID ...string) ([]WebhookGovDAO, error) {
 	var list []WebhookGovDAO
 	q := db.
-		Select("id, description, user_id, url, type, last_checked_id, chain_id").
+		Select("id, description, user_id, url, type, last_checked_id, chain_id, secret").
 		Where("user_id = ?", userID)
 
 	if len(chainID) > 0 && chainID[0] != "" {
@@ -144,7 +165,10 @@ func DeleteWebhook(id int, userID string, db *gorm.DB) error {
 
 // // ==========================webhooks_validator ===============================================
 
-func InsertMonitoringWebhook(userID, url, description, typ, chainID string, db *gorm.DB) error {
+// InsertMonitoringWebhook stores a validator webhook. secret is the
+// integration key of a "pagerduty"/"opsgenie" webhook and is ignored for
+// other types ("generic" webhooks get a generated one).
+func InsertMonitoringWebhook(userID, url, description, typ, chainID string, db *gorm.DB, secret ...string) error {
 	wh := WebhookValidator{
 		UserID:      userID,
 		URL:         url,
@@ -154,12 +178,40 @@ func InsertMonitoringWebhook(userID, url, description, typ, chainID string, db *
 	if chainID != "" {
 		wh.ChainID = &chainID
 	}
+	var provided string
+	if len(secret) > 0 {
+		provided = secret[0]
+	}
+	return CreateMonitoringWebhook(db, &wh, provided)
+}
+
+// CreateMonitoringWebhook inserts wh as given (including its Filter), with
+// the secret webhookSecretFor picks for its type. wh.ID is set on success.
+func CreateMonitoringWebhook(db *gorm.DB, wh *WebhookValidator, secret string) error {
+	whSecret, err := webhookSecretFor(wh.Type, secret)
+	if err != nil {
+		return err
+	}
+	wh.Secret = whSecret
 
-	if err := createHourReport(db, userID); err != nil {
+	if err := createHourReport(db, wh.UserID); err != nil {
 		log.Printf("⚠️ createHourReport: %v", err)
 	}
 
-	return db.Create(&wh).Error
+	return db.Create(wh).Error
+}
+
+// UpdateWebhookFilter replaces the routing filter of a validator webhook
+// owned by userID.
+func UpdateWebhookFilter(db *gorm.DB, id int, userID string, f WebhookFilter) error {
+	return db.Model(&WebhookValidator{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Updates(map[string]interface{}{
+			"min_level":          f.MinLevel,
+			"include_validators": f.IncludeValidators,
+			"exclude_validators": f.ExcludeValidators,
+			"alert_kinds":        f.AlertKinds,
+		}).Error
 }
 
 func DeleteMonitoringWebhook(id int, userID string, db *gorm.DB) error {
@@ -171,7 +223,7 @@ func DeleteMonitoringWebhook(id int, userID string, db *gorm.DB) error {
 
 func ListMonitoringWebhooks(db *gorm.DB, userID string, chainID ...string) ([]WebhookValidator, error) {
 	var result []WebhookValidator
-	q := db.Select("id, description, user_id, url, type, chain_id").
+	q := db.Select("id, description, user_id, url, type, chain_id, secret, min_level, include_validators, exclude_validators, alert_kinds").
 		Where("user_id = ?", userID)
 	if len(chainID) > 0 && chainID[0] != "" {
 		q = q.Where("chain_id = ? OR chain_id IS NULL", chainID[0])
@@ -194,6 +246,15 @@ func UpdateMonitoringWebhook(db *gorm.DB, id int, userID, description, newURL, n
 	if chainID != nil {
 		updates["chain_id"] = chainID
 	}
+	// A webhook switched to "generic" needs a signing secret; keep the
+	// current one if it already has it.
+	if newType == "generic" {
+		secret, err := NewWebhookSecret()
+		if err != nil {
+			return err
+		}
+		updates["secret"] = gorm.Expr("COALESCE(secret, ?)", secret)
+	}
 
 	switch tablename {
 	case "webhook_gov_daos":
@@ -209,6 +270,92 @@ func UpdateMonitoringWebhook(db *gorm.DB, id int, userID, description, newURL, n
 	}
 }
 
+// IsIncidentWebhookType reports whether typ is an incident-management
+// integration whose secret is a key supplied by the user.
+func IsIncidentWebhookType(typ string) bool {
+	return typ == "pagerduty" || typ == "opsgenie"
+}
+
+// webhookSecretFor returns the secret to store for a new webhook of type
+// typ: a generated HMAC key for "generic", the user-supplied key for
+// incident types (required), nil otherwise.
+func webhookSecretFor(typ, provided string) (*string, error) {
+	switch {
+	case typ == "generic":
+		secret, err := NewWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		return &secret, nil
+	case IsIncidentWebhookType(typ):
+		if provided == "" {
+			return nil, fmt.Errorf("a %s webhook requires its integration key as secret", typ)
+		}
+		return &provided, nil
+	default:
+		return nil, nil
+	}
+}
+
+// SetWebhookSecret stores a user-supplied integration key on one of the
+// user's webhooks. tablename must be either "webhook_gov_daos" or
+// "webhook_validators".
+func SetWebhookSecret(db *gorm.DB, id int, userID, tablename, secret string) error {
+	switch tablename {
+	case "webhook_gov_daos":
+		return db.Model(&WebhookGovDAO{}).
+			Where("id = ? AND user_id = ?", id, userID).
+			Update("secret", secret).Error
+	case "webhook_validators":
+		return db.Model(&WebhookValidator{}).
+			Where("id = ? AND user_id = ?", id, userID).
+			Update("secret", secret).Error
+	default:
+		return fmt.Errorf("unknown table: %q", tablename)
+	}
+}
+
+// NewWebhookSecret returns a random 32-byte hex-encoded HMAC key for a
+// "generic" webhook.
+func NewWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("NewWebhookSecret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// RotateWebhookSecret replaces the signing secret of a user's "generic"
+// webhook and returns the new one. tablename must be either
+// "webhook_gov_daos" or "webhook_validators". gorm.ErrRecordNotFound is
+// returned when the user owns no generic webhook with that id.
+func RotateWebhookSecret(db *gorm.DB, id int, userID, tablename string) (string, error) {
+	var model interface{}
+	switch tablename {
+	case "webhook_gov_daos":
+		model = &WebhookGovDAO{}
+	case "webhook_validators":
+		model = &WebhookValidator{}
+	default:
+		return "", fmt.Errorf("unknown table: %q", tablename)
+	}
+
+	secret, err := NewWebhookSecret()
+	if err != nil {
+		return "", err
+	}
+	res := db.Model(model).
+		Where("id = ? AND user_id = ? AND type = ?", id, userID, "generic").
+		Update("secret", secret)
+	if res.Error != nil {
+		return "", res.Error
+	}
+	if res.RowsAffected == 0 {
+		return "", gorm.ErrRecordNotFound
+	}
+	return secret, nil
+}
+
 func GetWebhookByID(db *gorm.DB, userID, table string) (*WebhookValidator, error) {
 	var wh WebhookValidator
 	var query string
@@ -256,7 +403,7 @@ func DeleteUser(userID string, db *gorm.DB) error {
 	return db.Transaction(func(tx *gorm.DB) error {
 		tables := []any{
 			&WebhookGovDAO{}, &WebhookValidator{},
-			&AlertContact{}, &HourReport{},
+			&AlertContact{}, &HourReport{}, &EscalationPolicy{},
 			&User{},
 		}
 		for _, model := range tables {
@@ -276,10 +423,52 @@ func UpdateUser(db *gorm.DB, name, email, userID string) error {
 			"email":    email,
 		}).Error
 }
+
+// SetUserEmailOptIn updates the email opt-ins of userID; nil leaves a
+// setting unchanged.
+func SetUserEmailOptIn(db *gorm.DB, userID string, alerts, reports *bool) error {
+	updates := map[string]interface{}{}
+	if alerts != nil {
+		updates["email_alerts"] = *alerts
+	}
+	if reports != nil {
+		updates["email_reports"] = *reports
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	return db.Model(&User{}).Where("user_id = ?", userID).Updates(updates).Error
+}
+
+// ListEmailAlertUsers returns the users who opted in to alert emails for
+// chainID: the chains of their validator webhooks, or defaultChain when they
+// have none (the chains their daily report covers, see SheduleUserReport).
+func ListEmailAlertUsers(db *gorm.DB, chainID, defaultChain string) ([]User, error) {
+	var users []User
+	err := db.Raw(`
+		SELECT u.* FROM users u
+		WHERE u.email_alerts AND u.email <> ''
+		  AND (
+		      EXISTS (
+		          SELECT 1 FROM webhook_validators w
+		          WHERE w.user_id = u.user_id AND w.chain_id = ?
+		      )
+		      OR (
+		          ? = ?
+		          AND NOT EXISTS (
+		              SELECT 1 FROM webhook_validators w
+		              WHERE w.user_id = u.user_id AND w.chain_id IS NOT NULL AND w.chain_id != ''
+		          )
+		      )
+		  )
+		ORDER BY u.user_id
+	`, chainID, chainID, defaultChain).Scan(&users).Error
+	return users, err
+}
 func GetUserById(db *gorm.DB, userID string) (*User, error) {
 	var usr User
 	err := db.
-		Select("user_id, nameuser, email").
+		Select("user_id, nameuser, email, email_alerts, email_reports").
 		Where("user_id = ?", userID).
 		First(&usr).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -0,0 +1,109 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"gorm.io/gorm"
+)
+
+// ====================================== ACCOUNTS ======================================
+// monitored accounts are registered by users on top of the valoper operator
+// addresses; gnovalidator.WatchAccountBalances samples the balance of both
+// into account_balances, pruned with the raw participation rows.
+
+// validateAddress checks that addr is a bech32 account address (g1...).
+func validateAddress(addr string) error {
+	if _, err := crypto.AddressFromBech32(addr); err != nil {
+		return fmt.Errorf("%q is not a valid g1 address: %w", addr, err)
+	}
+	return nil
+}
+
+// CreateMonitoredAccount validates and inserts a.
+func CreateMonitoredAccount(db *gorm.DB, a *MonitoredAccount) error {
+	if a.UserID == "" {
+		return errors.New("user_id is required")
+	}
+	if a.ChainID == "" {
+		return errors.New("chain_id is required")
+	}
+	if err := validateAddress(a.Addr); err != nil {
+		return fmt.Errorf("addr: %w", err)
+	}
+	if a.MinBalance < 0 {
+		return errors.New("min_balance cannot be negative")
+	}
+	if err := db.Create(a).Error; err != nil {
+		return fmt.Errorf("CreateMonitoredAccount: %w", err)
+	}
+	return nil
+}
+
+// ListMonitoredAccounts returns the accounts userID follows, oldest first.
+func ListMonitoredAccounts(db *gorm.DB, userID string) ([]MonitoredAccount, error) {
+	var rows []MonitoredAccount
+	if err := db.Where("user_id = ?", userID).Order("id asc").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("ListMonitoredAccounts: %w", err)
+	}
+	return rows, nil
+}
+
+// GetMonitoredAccount returns account id of userID. Returns
+// gorm.ErrRecordNotFound when userID has no such account.
+func GetMonitoredAccount(db *gorm.DB, id uint, userID string) (MonitoredAccount, error) {
+	var a MonitoredAccount
+	if err := db.Where("id = ? AND user_id = ?", id, userID).First(&a).Error; err != nil {
+		return a, fmt.Errorf("GetMonitoredAccount: %w", err)
+	}
+	return a, nil
+}
+
+// MonitoredAccountsForChain returns the accounts every user follows on
+// chainID.
+func MonitoredAccountsForChain(db *gorm.DB, chainID string) ([]MonitoredAccount, error) {
+	var rows []MonitoredAccount
+	if err := db.Where("chain_id = ?", chainID).Order("id asc").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("MonitoredAccountsForChain: %w", err)
+	}
+	return rows, nil
+}
+
+// DeleteMonitoredAccount removes account id of userID. Returns
+// gorm.ErrRecordNotFound when userID has no such account.
+func DeleteMonitoredAccount(db *gorm.DB, id uint, userID string) error {
+	res := db.Where("id = ? AND user_id = ?", id, userID).Delete(&MonitoredAccount{})
+	if res.Error != nil {
+		return fmt.Errorf("DeleteMonitoredAccount: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// InsertAccountBalances stores a sample of account balances.
+func InsertAccountBalances(db *gorm.DB, balances []AccountBalance) error {
+	if len(balances) == 0 {
+		return nil
+	}
+	if err := db.CreateInBatches(&balances, 500).Error; err != nil {
+		return fmt.Errorf("InsertAccountBalances: %w", err)
+	}
+	return nil
+}
+
+// GetAccountBalances returns the balances of addr on chainID sampled at or
+// after since, oldest first.
+func GetAccountBalances(db *gorm.DB, chainID, addr string, since time.Time) ([]AccountBalance, error) {
+	var rows []AccountBalance
+	err := db.Where("chain_id = ? AND addr = ? AND time >= ?", chainID, addr, since).
+		Order("time asc").
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetAccountBalances: %w", err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,70 @@
+package database_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/testoutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+const (
+	testAddr1 = "g1e2tczyk2rw7u47kzxxee5g7ufkncdmlcyaveyl"
+	testAddr2 = "g18c37s9sq89v55vuffajkfcd3xj9m67sqsw76xw"
+)
+
+func TestMonitoredAccounts(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+
+	for _, bad := range []database.MonitoredAccount{
+		{ChainID: "test12", Addr: testAddr1},
+		{UserID: "u1", Addr: testAddr1},
+		{UserID: "u1", ChainID: "test12", Addr: "nope"},
+		{UserID: "u1", ChainID: "test12", Addr: "g1a"},
+		{UserID: "u1", ChainID: "test12", Addr: testAddr1, MinBalance: -1},
+	} {
+		assert.Error(t, database.CreateMonitoredAccount(db, &bad))
+	}
+
+	a := database.MonitoredAccount{UserID: "u1", ChainID: "test12", Addr: testAddr1, Label: "faucet", MinBalance: 5000000}
+	require.NoError(t, database.CreateMonitoredAccount(db, &a))
+	require.NoError(t, database.CreateMonitoredAccount(db, &database.MonitoredAccount{UserID: "u2", ChainID: "test12", Addr: testAddr1}))
+	require.NoError(t, database.CreateMonitoredAccount(db, &database.MonitoredAccount{UserID: "u1", ChainID: "other", Addr: testAddr2}))
+	assert.Error(t, database.CreateMonitoredAccount(db, &database.MonitoredAccount{UserID: "u1", ChainID: "test12", Addr: testAddr1}), "duplicate")
+
+	mine, err := database.ListMonitoredAccounts(db, "u1")
+	require.NoError(t, err)
+	require.Len(t, mine, 2)
+	assert.Equal(t, "faucet", mine[0].Label)
+
+	onChain, err := database.MonitoredAccountsForChain(db, "test12")
+	require.NoError(t, err)
+	assert.Len(t, onChain, 2)
+
+	assert.ErrorIs(t, database.DeleteMonitoredAccount(db, a.ID, "u2"), gorm.ErrRecordNotFound, "not the owner")
+	require.NoError(t, database.DeleteMonitoredAccount(db, a.ID, "u1"))
+	assert.ErrorIs(t, database.DeleteMonitoredAccount(db, a.ID, "u1"), gorm.ErrRecordNotFound)
+}
+
+func TestAccountBalances(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	require.NoError(t, database.InsertAccountBalances(db, nil))
+	require.NoError(t, database.InsertAccountBalances(db, []database.AccountBalance{
+		{ChainID: "test12", Addr: "g1a", Time: now, Balance: 900},
+		{ChainID: "test12", Addr: "g1a", Time: now.Add(-10 * time.Minute), Balance: 1000},
+		{ChainID: "test12", Addr: "g1a", Time: now.Add(-2 * time.Hour), Balance: 1500},
+		{ChainID: "test12", Addr: "g1b", Time: now, Balance: 5},
+		{ChainID: "other", Addr: "g1a", Time: now, Balance: 7},
+	}))
+
+	rows, err := database.GetAccountBalances(db, "test12", "g1a", now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, int64(1000), rows[0].Balance, "oldest first")
+	assert.Equal(t, int64(900), rows[1].Balance)
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"gorm.io/gorm"
 )
@@ -32,6 +33,37 @@ func GetAdminConfigInt(db *gorm.DB, key string, fallback int) int {
 	return n
 }
 
+// GetAdminConfigFloat returns the float value for a given admin_config key.
+// Returns fallback if the key is missing or not a valid number.
+func GetAdminConfigFloat(db *gorm.DB, key string, fallback float64) float64 {
+	val, err := GetAdminConfig(db, key)
+	if err != nil {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return fallback
+	}
+	return f
+}
+
+// GetAdminConfigsWithPrefix returns the admin_config values whose key starts
+// with prefix, keyed by the rest of the key.
+func GetAdminConfigsWithPrefix(db *gorm.DB, prefix string) (map[string]string, error) {
+	var configs []AdminConfig
+	if err := db.Where("key LIKE ?", prefix+"%").Find(&configs).Error; err != nil {
+		return nil, fmt.Errorf("GetAdminConfigsWithPrefix %q: %w", prefix, err)
+	}
+	out := make(map[string]string, len(configs))
+	for _, c := range configs {
+		// LIKE reads _ and % in prefix as wildcards.
+		if rest, ok := strings.CutPrefix(c.Key, prefix); ok {
+			out[rest] = c.Value
+		}
+	}
+	return out, nil
+}
+
 // SetAdminConfig upserts a key/value pair in admin_config.
 func SetAdminConfig(db *gorm.DB, key, value string) error {
 	return db.Exec(
@@ -298,7 +330,7 @@ func UpdateHourReportAdmin(db *gorm.DB, userID string, hour, minute int, timezon
 // ── chain data purge ──────────────────────────────────────────────────────────
 
 // PurgeChainAllData deletes all chain data: participations, aggregates, alerts,
-// monikers, and telegram subscriptions for the given chain.
+// monikers, and telegram, discord and slack subscriptions for the given chain.
 func PurgeChainAllData(db *gorm.DB, chainID string) error {
 	tx := db.Begin()
 	if tx.Error != nil {
@@ -308,10 +340,29 @@ func PurgeChainAllData(db *gorm.DB, chainID string) error {
 		&DailyParticipation{},
 		&DailyParticipationAgrega{},
 		&AlertLog{},
+		&AlertDelivery{},
+		&Silence{},
+		&AlertEscalation{},
 		&AddrMoniker{},
 		&Telegram{},
 		&TelegramHourReport{},
 		&TelegramValidatorSub{},
+		&DiscordChannel{},
+		&DiscordHourReport{},
+		&DiscordValidatorSub{},
+		&SlackChannel{},
+		&SlackValidatorSub{},
+		&MissedProposal{},
+		&Block{},
+		&BlockDailyAgrega{},
+		&NodeVersion{},
+		&NetPeer{},
+		&NetPeerSample{},
+		&PlannedHalt{},
+		&MonitoredAccount{},
+		&AccountBalance{},
+		&TxWatch{},
+		&TxWatchCursor{},
 	} {
 		if err := tx.Where("chain_id = ?", chainID).Delete(model).Error; err != nil {
 			tx.Rollback()
@@ -332,6 +383,13 @@ func PurgeChainParticipations(db *gorm.DB, chainID string) error {
 		&DailyParticipation{},
 		&DailyParticipationAgrega{},
 		&AlertLog{},
+		&AlertDelivery{},
+		&AlertEscalation{},
+		&MissedProposal{},
+		&Block{},
+		&BlockDailyAgrega{},
+		&NetPeer{},
+		&AccountBalance{},
 	} {
 		if err := tx.Where("chain_id = ?", chainID).Delete(model).Error; err != nil {
 			tx.Rollback()
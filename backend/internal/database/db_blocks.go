@@ -0,0 +1,76 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ====================================== BLOCKS ======================================
+// blocks are written next to the participation rows of each block by the
+// realtime loop and the backfills (gnovalidator.CollectParticipation,
+// BackfillRange, BackfillParallel), rolled up per day into
+// block_daily_agregas by the aggregator and pruned with the raw
+// participation rows.
+
+// UpsertBlocks stores blocks, overwriting the rows of heights already
+// stored, so a re-processed block is harmless.
+func UpsertBlocks(db *gorm.DB, blocks []Block) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chain_id"}, {Name: "height"}},
+		UpdateAll: true,
+	}).CreateInBatches(&blocks, 1000).Error
+	if err != nil {
+		return fmt.Errorf("UpsertBlocks: %w", err)
+	}
+	return nil
+}
+
+// GetRecentBlocks returns the limit highest stored blocks of chainID,
+// highest first.
+func GetRecentBlocks(db *gorm.DB, chainID string, limit int) ([]Block, error) {
+	var blocks []Block
+	err := db.Where("chain_id = ?", chainID).
+		Order("height DESC").
+		Limit(limit).
+		Find(&blocks).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetRecentBlocks: %w", err)
+	}
+	return blocks, nil
+}
+
+// GetAverageBlockTime returns the average block time of chainID, in
+// seconds, over the daily rollups from the day of since onwards, each day
+// weighted by its blocks; 0 when there is none.
+func GetAverageBlockTime(db *gorm.DB, chainID string, since time.Time) (float64, error) {
+	var avg float64
+	err := db.Raw(`
+		SELECT COALESCE(SUM(avg_block_time * (last_height - first_height))
+		                / NULLIF(SUM(last_height - first_height), 0), 0)
+		FROM block_daily_agregas
+		WHERE chain_id = ? AND block_date >= ?
+	`, chainID, since.UTC().Format("2006-01-02")).Scan(&avg).Error
+	if err != nil {
+		return 0, fmt.Errorf("GetAverageBlockTime: %w", err)
+	}
+	return avg, nil
+}
+
+// GetBlockDailyAgregas returns the daily block rollups of chainID from the
+// day of since onwards, oldest first.
+func GetBlockDailyAgregas(db *gorm.DB, chainID string, since time.Time) ([]BlockDailyAgrega, error) {
+	var days []BlockDailyAgrega
+	err := db.Where("chain_id = ? AND block_date >= ?", chainID, since.UTC().Format("2006-01-02")).
+		Order("block_date ASC").
+		Find(&days).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetBlockDailyAgregas: %w", err)
+	}
+	return days, nil
+}
@@ -0,0 +1,70 @@
+package database_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/testoutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlocks(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+	const chain = "test12"
+	now := time.Now().UTC().Truncate(time.Second)
+
+	require.NoError(t, database.UpsertBlocks(db, []database.Block{
+		{ChainID: chain, Height: 10, Time: now.Add(-4 * time.Second), ProposerAddr: "g1a"},
+		{ChainID: chain, Height: 11, Time: now.Add(-2 * time.Second), ProposerAddr: "g1b", TxCount: 1},
+		{ChainID: "other", Height: 12, Time: now, ProposerAddr: "g1a"},
+	}))
+	// Re-processing a block overwrites its row.
+	require.NoError(t, database.UpsertBlocks(db, []database.Block{
+		{ChainID: chain, Height: 11, Time: now.Add(-2 * time.Second), ProposerAddr: "g1b", TxCount: 1, GasUsed: 42},
+	}))
+
+	blocks, err := database.GetRecentBlocks(db, chain, 10)
+	require.NoError(t, err)
+	require.Len(t, blocks, 2)
+	assert.Equal(t, int64(11), blocks[0].Height, "highest first")
+	assert.Equal(t, int64(42), blocks[0].GasUsed)
+
+	blocks, err = database.GetRecentBlocks(db, chain, 1)
+	require.NoError(t, err)
+	assert.Len(t, blocks, 1)
+
+	require.NoError(t, db.Create(&[]database.BlockDailyAgrega{
+		{ChainID: chain, BlockDate: now.AddDate(0, 0, -3).Format("2006-01-02"), BlockCount: 1},
+		{ChainID: chain, BlockDate: now.AddDate(0, 0, -1).Format("2006-01-02"), BlockCount: 2},
+		{ChainID: "other", BlockDate: now.AddDate(0, 0, -1).Format("2006-01-02"), BlockCount: 3},
+	}).Error)
+	days, err := database.GetBlockDailyAgregas(db, chain, now.AddDate(0, 0, -7))
+	require.NoError(t, err)
+	require.Len(t, days, 2)
+	assert.Equal(t, 1, days[0].BlockCount, "oldest first")
+
+	days, err = database.GetBlockDailyAgregas(db, chain, now.AddDate(0, 0, -2))
+	require.NoError(t, err)
+	assert.Len(t, days, 1)
+}
+
+func TestGetAverageBlockTime(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+	const chain = "test12"
+	now := time.Now().UTC()
+
+	avg, err := database.GetAverageBlockTime(db, chain, now.AddDate(0, 0, -7))
+	require.NoError(t, err)
+	assert.Zero(t, avg, "no rollup yet")
+
+	require.NoError(t, db.Create(&[]database.BlockDailyAgrega{
+		{ChainID: chain, BlockDate: now.AddDate(0, 0, -30).Format("2006-01-02"), FirstHeight: 0, LastHeight: 100, AvgBlockTime: 60},
+		{ChainID: chain, BlockDate: now.AddDate(0, 0, -2).Format("2006-01-02"), FirstHeight: 100, LastHeight: 400, AvgBlockTime: 2},
+		{ChainID: chain, BlockDate: now.AddDate(0, 0, -1).Format("2006-01-02"), FirstHeight: 400, LastHeight: 500, AvgBlockTime: 6},
+	}).Error)
+	avg, err = database.GetAverageBlockTime(db, chain, now.AddDate(0, 0, -7))
+	require.NoError(t, err)
+	assert.InDelta(t, 3.0, avg, 1e-9, "days weighted by their blocks")
+}
@@ -0,0 +1,162 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ====================================== ALERT DELIVERIES ======================================
+// alert_deliveries is the notification outbox: every alert is stored here
+// once per destination and sent by the delivery worker (internal/outbox.go).
+
+// EnqueueAlertDeliveries inserts rows as pending deliveries due now.
+func EnqueueAlertDeliveries(db *gorm.DB, rows []AlertDelivery) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	now := time.Now()
+	for i := range rows {
+		rows[i].Status = DeliveryPending
+		if rows[i].NextAttemptAt.IsZero() {
+			rows[i].NextAttemptAt = now
+		}
+	}
+	if err := db.Create(&rows).Error; err != nil {
+		return fmt.Errorf("EnqueueAlertDeliveries: %w", err)
+	}
+	return nil
+}
+
+// claimAlertDeliveriesLock is the advisory lock key ClaimAlertDeliveries
+// serializes claims on.
+const claimAlertDeliveriesLock = "alert_deliveries_claim"
+
+// ClaimAlertDeliveries claims up to limit pending deliveries whose next
+// attempt is due at now, oldest first, by pushing their next attempt to
+// leaseUntil in the same statement. Rows another replica is claiming are
+// skipped, so each delivery is sent by one worker only; a worker that dies
+// mid-batch leaves its rows to be picked up again once the lease expires. A
+// row is held back while an older pending row for the same destination is
+// still waiting for its retry, so one destination always receives its
+// alerts in order (a RESOLVED never overtakes the CRITICAL it resolves).
+// Claims are serialized with a transaction-level advisory lock: the hold-back
+// check reads a snapshot, and two replicas claiming at once would otherwise
+// both see a destination's older rows as due and split its rows between them.
+func ClaimAlertDeliveries(db *gorm.DB, now, leaseUntil time.Time, limit int) ([]AlertDelivery, error) {
+	var rows []AlertDelivery
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", claimAlertDeliveriesLock).Error; err != nil {
+			return err
+		}
+		return tx.Raw(`
+			UPDATE alert_deliveries
+			SET next_attempt_at = ?
+			WHERE id IN (
+			    SELECT d.id
+			    FROM alert_deliveries d
+			    WHERE d.status = ?
+			      AND d.next_attempt_at <= ?
+			      AND NOT EXISTS (
+			          SELECT 1 FROM alert_deliveries p
+			          WHERE p.dest_key = d.dest_key
+			            AND p.status = ?
+			            AND p.id < d.id
+			            AND p.next_attempt_at > ?
+			      )
+			    ORDER BY d.id
+			    LIMIT ?
+			    FOR UPDATE OF d SKIP LOCKED
+			)
+			RETURNING *
+		`, leaseUntil, DeliveryPending, now, DeliveryPending, now, limit).Scan(&rows).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ClaimAlertDeliveries: %w", err)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ID < rows[j].ID })
+	return rows, nil
+}
+
+// ReleaseAlertDelivery gives a claimed delivery back, due at at, without
+// counting an attempt.
+func ReleaseAlertDelivery(db *gorm.DB, id uint, at time.Time) error {
+	return db.Model(&AlertDelivery{}).Where("id = ? AND status = ?", id, DeliveryPending).
+		Update("next_attempt_at", at).Error
+}
+
+// SaveAlertDeliveryAttempt persists the outcome of one send attempt.
+func SaveAlertDeliveryAttempt(db *gorm.DB, d AlertDelivery) error {
+	return db.Model(&AlertDelivery{}).Where("id = ?", d.ID).Updates(map[string]any{
+		"status":          d.Status,
+		"attempts":        d.Attempts,
+		"next_attempt_at": d.NextAttemptAt,
+		"last_error":      d.LastError,
+		"sent_at":         d.SentAt,
+	}).Error
+}
+
+// ListAlertDeliveries returns deliveries newest first with optional filters.
+// Pass empty string to skip a filter.
+func ListAlertDeliveries(db *gorm.DB, status, chainID string, limit int) ([]AlertDelivery, error) {
+	q := db.Model(&AlertDelivery{}).Order("id desc")
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	if chainID != "" {
+		q = q.Where("chain_id = ?", chainID)
+	}
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	var rows []AlertDelivery
+	if err := q.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ReplayAlertDelivery puts a dead (or still pending) delivery back in the
+// queue with a fresh attempt budget, due immediately. Sent deliveries are
+// never replayed; gorm.ErrRecordNotFound is returned for them and for
+// unknown IDs.
+func ReplayAlertDelivery(db *gorm.DB, id uint) error {
+	res := db.Model(&AlertDelivery{}).
+		Where("id = ? AND status <> ?", id, DeliverySent).
+		Updates(replayColumns())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ReplayDeadAlertDeliveries re-queues every dead delivery, optionally only
+// for chainID, and returns how many were re-queued.
+func ReplayDeadAlertDeliveries(db *gorm.DB, chainID string) (int64, error) {
+	q := db.Model(&AlertDelivery{}).Where("status = ?", DeliveryDead)
+	if chainID != "" {
+		q = q.Where("chain_id = ?", chainID)
+	}
+	res := q.Updates(replayColumns())
+	return res.RowsAffected, res.Error
+}
+
+func replayColumns() map[string]any {
+	return map[string]any{
+		"status":          DeliveryPending,
+		"attempts":        0,
+		"next_attempt_at": time.Now(),
+		"last_error":      "",
+	}
+}
+
+// PruneSentAlertDeliveries deletes deliveries sent before cutoff.
+func PruneSentAlertDeliveries(db *gorm.DB, cutoff time.Time) (int64, error) {
+	res := db.Where("status = ? AND sent_at < ?", DeliverySent, cutoff).Delete(&AlertDelivery{})
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,127 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ============================ Discord =============================================
+// The Discord validator bot keeps the same per-channel state as the Telegram
+// bot keeps per chat: the active chain, validator subscriptions and the
+// daily report schedule.
+
+// EnsureDiscordChannel returns the channel record, creating it on chainID
+// the first time the bot is used in the channel.
+func EnsureDiscordChannel(db *gorm.DB, channelID, guildID int64, chainID string) (DiscordChannel, error) {
+	ch := DiscordChannel{ChannelID: channelID}
+	err := db.Where(DiscordChannel{ChannelID: channelID}).
+		Attrs(DiscordChannel{GuildID: guildID, ChainID: chainID}).
+		FirstOrCreate(&ch).Error
+	if err != nil {
+		return DiscordChannel{}, fmt.Errorf("EnsureDiscordChannel: %w", err)
+	}
+	return ch, nil
+}
+
+// UpdateDiscordChannelChain switches the active chain of a channel.
+func UpdateDiscordChannelChain(db *gorm.DB, channelID int64, chainID string) error {
+	return db.Model(&DiscordChannel{}).
+		Where("channel_id = ?", channelID).
+		Update("chain_id", chainID).Error
+}
+
+// GetDiscordChannelIDsForChain returns the channels whose active chain is
+// chainID (chain-wide alerts).
+func GetDiscordChannelIDsForChain(db *gorm.DB, chainID string) ([]int64, error) {
+	var ids []int64
+	err := db.Model(&DiscordChannel{}).
+		Where("chain_id = ?", chainID).
+		Pluck("channel_id", &ids).Error
+	return ids, err
+}
+
+// ============================ Discord subscriptions ===============================
+
+// SetDiscordValidatorSub turns a channel's alert subscription to addr on or
+// off. Turning off a subscription that does not exist is a no-op.
+func SetDiscordValidatorSub(db *gorm.DB, channelID int64, chainID, addr, moniker string, activate bool) error {
+	if !activate {
+		return db.Model(&DiscordValidatorSub{}).
+			Where("channel_id = ? AND chain_id = ? AND addr = ?", channelID, chainID, addr).
+			Update("activate", false).Error
+	}
+	sub := DiscordValidatorSub{ChannelID: channelID, ChainID: chainID, Addr: addr, Moniker: moniker, Activate: true}
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chain_id"}, {Name: "addr"}, {Name: "channel_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"activate", "moniker"}),
+	}).Create(&sub).Error
+	if err != nil {
+		return fmt.Errorf("SetDiscordValidatorSub: %w", err)
+	}
+	return nil
+}
+
+// GetDiscordValidatorSubs returns a channel's subscriptions on chainID.
+func GetDiscordValidatorSubs(db *gorm.DB, channelID int64, chainID string, onlyActive bool) ([]DiscordValidatorSub, error) {
+	q := db.Where("channel_id = ? AND chain_id = ?", channelID, chainID)
+	if onlyActive {
+		q = q.Where("activate = ?", true)
+	}
+	var subs []DiscordValidatorSub
+	err := q.Order("moniker asc").Find(&subs).Error
+	return subs, err
+}
+
+// GetDiscordValidatorStatusList is GetValidatorStatusList for a Discord
+// channel.
+func GetDiscordValidatorStatusList(db *gorm.DB, channelID int64, chainID string) ([]ValidatorStatus, error) {
+	return validatorStatusList(db, "discord_validator_subs", "channel_id", channelID, chainID)
+}
+
+// GetDiscordAlertChannelIDs returns the channels with an active
+// subscription to addr on chainID.
+func GetDiscordAlertChannelIDs(db *gorm.DB, chainID, addr string) ([]int64, error) {
+	var ids []int64
+	err := db.Model(&DiscordValidatorSub{}).
+		Where("chain_id = ? AND addr = ? AND activate = ?", chainID, addr, true).
+		Pluck("channel_id", &ids).Error
+	return ids, err
+}
+
+// ============================ Discord daily report ================================
+
+// GetDiscordHourReport returns the report schedule of a channel for chainID,
+// creating the (inactive) default one when there is none.
+func GetDiscordHourReport(db *gorm.DB, channelID int64, chainID string) (*DiscordHourReport, error) {
+	hr := DiscordHourReport{ChannelID: channelID, ChainID: chainID}
+	if err := db.Where(DiscordHourReport{ChannelID: channelID, ChainID: chainID}).FirstOrCreate(&hr).Error; err != nil {
+		return nil, fmt.Errorf("GetDiscordHourReport: %w", err)
+	}
+	return &hr, nil
+}
+
+// UpdateDiscordHourReport saves a channel's report schedule and activation.
+func UpdateDiscordHourReport(db *gorm.DB, channelID int64, chainID string, hour, minute int, timezone string, activate bool) error {
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", timezone, err)
+	}
+	return db.Model(&DiscordHourReport{}).
+		Where("channel_id = ? AND chain_id = ?", channelID, chainID).
+		Updates(map[string]interface{}{
+			"daily_report_hour":   hour,
+			"daily_report_minute": minute,
+			"timezone":            timezone,
+			"activate":            activate,
+		}).Error
+}
+
+// ListActiveDiscordHourReports returns every activated Discord report
+// schedule, for the scheduler.
+func ListActiveDiscordHourReports(db *gorm.DB) ([]DiscordHourReport, error) {
+	var rows []DiscordHourReport
+	err := db.Where("activate = ?", true).Find(&rows).Error
+	return rows, err
+}
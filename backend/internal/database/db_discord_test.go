@@ -0,0 +1,81 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/testoutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEnsureDiscordChannel_KeepsChosenChain verifies that a channel is
+// registered on the default chain once and keeps the chain picked with
+// /setchain afterwards.
+func TestEnsureDiscordChannel_KeepsChosenChain(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+
+	ch, err := database.EnsureDiscordChannel(db, 501, 9, "test12")
+	require.NoError(t, err)
+	assert.Equal(t, "test12", ch.ChainID)
+	assert.Equal(t, int64(9), ch.GuildID)
+
+	require.NoError(t, database.UpdateDiscordChannelChain(db, 501, "gnoland1"))
+	ch, err = database.EnsureDiscordChannel(db, 501, 9, "test12")
+	require.NoError(t, err)
+	assert.Equal(t, "gnoland1", ch.ChainID)
+
+	ids, err := database.GetDiscordChannelIDsForChain(db, "gnoland1")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{501}, ids)
+}
+
+// TestDiscordValidatorSub_OnOff verifies subscribing, resubscribing and
+// unsubscribing a channel, and that only active subscriptions receive
+// alerts.
+func TestDiscordValidatorSub_OnOff(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+
+	require.NoError(t, database.SetDiscordValidatorSub(db, 501, "test12", "g1abc", "val-1", true))
+	require.NoError(t, database.SetDiscordValidatorSub(db, 501, "test12", "g1abc", "val-1b", true))
+	require.NoError(t, database.SetDiscordValidatorSub(db, 502, "test12", "g1abc", "val-1", true))
+	require.NoError(t, database.SetDiscordValidatorSub(db, 503, "test12", "g1zzz", "val-2", false),
+		"unsubscribing without a subscription is a no-op")
+
+	subs, err := database.GetDiscordValidatorSubs(db, 501, "test12", false)
+	require.NoError(t, err)
+	require.Len(t, subs, 1)
+	assert.Equal(t, "val-1b", subs[0].Moniker)
+
+	require.NoError(t, database.SetDiscordValidatorSub(db, 502, "test12", "g1abc", "val-1", false))
+	ids, err := database.GetDiscordAlertChannelIDs(db, "test12", "g1abc")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{501}, ids)
+
+	ids, err = database.GetDiscordAlertChannelIDs(db, "gnoland1", "g1abc")
+	require.NoError(t, err)
+	assert.Empty(t, ids, "subscriptions are scoped to their chain")
+}
+
+// TestDiscordHourReport_OptIn verifies that a channel's daily report is off
+// until it is activated, and that only activated schedules are listed.
+func TestDiscordHourReport_OptIn(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+
+	hr, err := database.GetDiscordHourReport(db, 501, "test12")
+	require.NoError(t, err)
+	assert.False(t, hr.Activate)
+	assert.Equal(t, 9, hr.DailyReportHour)
+
+	active, err := database.ListActiveDiscordHourReports(db)
+	require.NoError(t, err)
+	assert.Empty(t, active)
+
+	require.NoError(t, database.UpdateDiscordHourReport(db, 501, "test12", 7, 30, "UTC", true))
+	assert.Error(t, database.UpdateDiscordHourReport(db, 501, "test12", 7, 30, "Mars/Olympus", true))
+
+	active, err = database.ListActiveDiscordHourReports(db)
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	assert.Equal(t, database.DiscordHourReport{ChannelID: 501, ChainID: "test12", DailyReportHour: 7, DailyReportMinute: 30, Activate: true, Timezone: "UTC"}, active[0])
+}
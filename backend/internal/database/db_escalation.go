@@ -0,0 +1,186 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ====================================== ESCALATIONS ======================================
+// escalation policies notify more people when a CRITICAL missed-block
+// incident is left unanswered. The evaluator (gnovalidator.WatchEscalations)
+// reads OpenCriticalIncidents and records each tier it fires with
+// MarkEscalated.
+
+// Validate checks that p has at least one tier, that every enabled tier has
+// a target and that tier 3 comes after tier 2.
+func (p *EscalationPolicy) Validate() error {
+	if p.Tier2After < 0 || p.Tier3After < 0 {
+		return errors.New("tier delays cannot be negative")
+	}
+	if p.Tier2After == 0 && p.Tier3After == 0 {
+		return errors.New("at least one of tier2_after_minutes and tier3_after_minutes is required")
+	}
+	if p.Tier2After > 0 && len(p.Tier2Webhooks)+len(p.Tier2Contacts) == 0 {
+		return errors.New("tier 2 needs at least one webhook or contact")
+	}
+	if p.Tier3After > 0 && len(p.Tier3Webhooks)+len(p.Tier3Contacts) == 0 {
+		return errors.New("tier 3 needs at least one webhook or contact")
+	}
+	if p.Tier2After > 0 && p.Tier3After > 0 && p.Tier3After <= p.Tier2After {
+		return errors.New("tier3_after_minutes must be greater than tier2_after_minutes")
+	}
+	return nil
+}
+
+// Tier returns the delay and targets of tier (2 or 3). A zero delay means
+// the tier is disabled.
+func (p EscalationPolicy) Tier(tier int) (after time.Duration, webhooks, contacts []int) {
+	switch tier {
+	case 2:
+		return time.Duration(p.Tier2After) * time.Minute, p.Tier2Webhooks, p.Tier2Contacts
+	case 3:
+		return time.Duration(p.Tier3After) * time.Minute, p.Tier3Webhooks, p.Tier3Contacts
+	}
+	return 0, nil, nil
+}
+
+// CreateEscalationPolicy validates and inserts p.
+func CreateEscalationPolicy(db *gorm.DB, p *EscalationPolicy) error {
+	if p.UserID == "" {
+		return errors.New("user_id is required")
+	}
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if err := db.Create(p).Error; err != nil {
+		return fmt.Errorf("CreateEscalationPolicy: %w", err)
+	}
+	return nil
+}
+
+// ListEscalationPolicies returns userID's policies, oldest first.
+func ListEscalationPolicies(db *gorm.DB, userID string) ([]EscalationPolicy, error) {
+	var rows []EscalationPolicy
+	err := db.Where("user_id = ?", userID).Order("id asc").Find(&rows).Error
+	return rows, err
+}
+
+// UpdateEscalationPolicy validates p and overwrites the policy p.ID of
+// p.UserID. Returns gorm.ErrRecordNotFound when the user has no such policy.
+func UpdateEscalationPolicy(db *gorm.DB, p *EscalationPolicy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	res := db.Model(&EscalationPolicy{}).
+		Where("id = ? AND user_id = ?", p.ID, p.UserID).
+		Select("chain_id", "validators", "tier2_after_minutes", "tier2_webhooks", "tier2_contacts",
+			"tier3_after_minutes", "tier3_webhooks", "tier3_contacts").
+		Updates(p)
+	if res.Error != nil {
+		return fmt.Errorf("UpdateEscalationPolicy: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteEscalationPolicy deletes userID's policy id and its escalation
+// history. Returns gorm.ErrRecordNotFound when the user has no such policy.
+func DeleteEscalationPolicy(db *gorm.DB, id uint, userID string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("id = ? AND user_id = ?", id, userID).Delete(&EscalationPolicy{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("policy_id = ?", id).Delete(&AlertEscalation{}).Error
+	})
+}
+
+// Covers reports whether p escalates the incidents of addr (or its
+// moniker) on chainID: addr is named in p.Validators or, when that list is
+// empty, one of the owner's validator webhooks on chainID routes it.
+func (p EscalationPolicy) Covers(db *gorm.DB, chainID, addr, moniker string) (bool, error) {
+	if len(p.Validators) > 0 {
+		return namesValidator(p.Validators, addr, moniker), nil
+	}
+	return UserRoutesValidator(db, p.UserID, chainID, addr, moniker)
+}
+
+// EscalationPoliciesForChain returns every user's policies that apply to
+// chainID (scoped to it or to every chain).
+func EscalationPoliciesForChain(db *gorm.DB, chainID string) ([]EscalationPolicy, error) {
+	var rows []EscalationPolicy
+	err := db.Where("chain_id = ? OR chain_id IS NULL", chainID).Order("id asc").Find(&rows).Error
+	return rows, err
+}
+
+// OpenIncident is a validator's CRITICAL missed-block incident that is
+// neither RESOLVED nor acknowledged. AlertLogID and OpenedAt are those of
+// its first notified CRITICAL; Moniker, StartHeight and EndHeight those of
+// its latest.
+type OpenIncident struct {
+	AlertLogID  uint
+	Addr        string
+	Moniker     string
+	StartHeight int64
+	EndHeight   int64
+	OpenedAt    time.Time
+}
+
+// OpenCriticalIncidents returns the open CRITICAL incidents of chainID.
+// Alerts muted by a silence do not open an incident: nobody was told about
+// them. A RESOLVED closes the alerts it covers the same way it does for
+// SendResolveAlerts (end_height), and acknowledging any CRITICAL of the
+// incident stops its escalation.
+func OpenCriticalIncidents(db *gorm.DB, chainID string) ([]OpenIncident, error) {
+	var rows []OpenIncident
+	err := db.Raw(`
+		SELECT al.addr,
+		       MIN(al.id) AS alert_log_id,
+		       (array_agg(al.moniker ORDER BY al.end_height DESC))[1] AS moniker,
+		       (array_agg(al.start_height ORDER BY al.end_height DESC))[1] AS start_height,
+		       MAX(al.end_height) AS end_height,
+		       MIN(al.sent_at) AS opened_at
+		FROM alert_logs al
+		WHERE al.chain_id = ?
+		  AND al.level = 'CRITICAL'
+		  AND al.skipped = true
+		  AND al.silence_id IS NULL
+		  AND NOT EXISTS (
+		      SELECT 1 FROM alert_logs r
+		      WHERE r.chain_id   = al.chain_id
+		        AND r.addr       = al.addr
+		        AND r.level      = 'RESOLVED'
+		        AND r.end_height >= al.end_height
+		  )
+		GROUP BY al.addr
+		HAVING bool_and(al.acked_at IS NULL)
+		ORDER BY al.addr
+	`, chainID).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("OpenCriticalIncidents: %w", err)
+	}
+	return rows, nil
+}
+
+// MarkEscalated records that tier of policyID fired for the incident opened
+// by alertLogID. It reports false when it had already been recorded, so
+// concurrent or restarted evaluators notify each tier once.
+func MarkEscalated(db *gorm.DB, chainID string, alertLogID, policyID uint, tier int) (bool, error) {
+	res := db.Exec(`
+		INSERT INTO alert_escalations (chain_id, alert_log_id, policy_id, tier, sent_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (alert_log_id, policy_id, tier) DO NOTHING
+	`, chainID, alertLogID, policyID, tier, time.Now())
+	if res.Error != nil {
+		return false, fmt.Errorf("MarkEscalated: %w", res.Error)
+	}
+	return res.RowsAffected == 1, nil
+}
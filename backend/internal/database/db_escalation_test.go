@@ -0,0 +1,113 @@
+package database_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/testoutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscalationPolicy_Validate(t *testing.T) {
+	cases := []struct {
+		name string
+		p    database.EscalationPolicy
+		ok   bool
+	}{
+		{"tier 2 only", database.EscalationPolicy{Tier2After: 15, Tier2Webhooks: database.IntList{1}}, true},
+		{"tier 3 only", database.EscalationPolicy{Tier3After: 60, Tier3Contacts: database.IntList{4}}, true},
+		{"both tiers", database.EscalationPolicy{Tier2After: 15, Tier2Webhooks: database.IntList{1}, Tier3After: 60, Tier3Contacts: database.IntList{4}}, true},
+		{"no tier", database.EscalationPolicy{}, false},
+		{"tier without target", database.EscalationPolicy{Tier2After: 15}, false},
+		{"tier 3 before tier 2", database.EscalationPolicy{Tier2After: 30, Tier2Webhooks: database.IntList{1}, Tier3After: 30, Tier3Webhooks: database.IntList{2}}, false},
+		{"negative delay", database.EscalationPolicy{Tier2After: -5, Tier2Webhooks: database.IntList{1}}, false},
+	}
+	for _, tc := range cases {
+		err := tc.p.Validate()
+		if tc.ok {
+			assert.NoError(t, err, tc.name)
+		} else {
+			assert.Error(t, err, tc.name)
+		}
+	}
+}
+
+// TestEscalationPolicy_Covers checks that a policy covers the validators it
+// names or, without a list, those its owner's webhooks route.
+func TestEscalationPolicy_Covers(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+	const chain = "test12"
+	require.NoError(t, db.Create(&database.WebhookValidator{
+		UserID: "u1", URL: "https://example.com/a", Type: "discord",
+		Filter: database.WebhookFilter{IncludeValidators: database.StringList{"g1mine"}},
+	}).Error)
+
+	cases := []struct {
+		name   string
+		p      database.EscalationPolicy
+		addr   string
+		covers bool
+	}{
+		{"named validator", database.EscalationPolicy{UserID: "u2", Validators: database.StringList{"g1listed"}}, "g1listed", true},
+		{"named moniker", database.EscalationPolicy{UserID: "u2", Validators: database.StringList{"Listed"}}, "g1listed", true},
+		{"not named", database.EscalationPolicy{UserID: "u1", Validators: database.StringList{"g1listed"}}, "g1mine", false},
+		{"routed by a webhook", database.EscalationPolicy{UserID: "u1"}, "g1mine", true},
+		{"filtered out", database.EscalationPolicy{UserID: "u1"}, "g1stranger", false},
+		{"no webhook", database.EscalationPolicy{UserID: "u2"}, "g1mine", false},
+	}
+	for _, tc := range cases {
+		covers, err := tc.p.Covers(db, chain, tc.addr, "listed")
+		require.NoError(t, err, tc.name)
+		assert.Equal(t, tc.covers, covers, tc.name)
+	}
+}
+
+// TestOpenCriticalIncidents checks that an incident is reported from its
+// first CRITICAL until it is acknowledged or RESOLVED, and that silenced
+// alerts and WARNINGs do not open one.
+func TestOpenCriticalIncidents(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+	const chain = "test12"
+	opened := time.Now().Add(-40 * time.Minute)
+
+	firstID, err := database.InsertAlertlog(db, chain, "g1down", "down", "CRITICAL", 100, 130, true, opened, "")
+	require.NoError(t, err)
+	_, err = database.InsertAlertlog(db, chain, "g1down", "down", "CRITICAL", 100, 160, true, opened.Add(20*time.Minute), "")
+	require.NoError(t, err)
+	_, err = database.InsertAlertlog(db, chain, "g1warn", "warn", "WARNING", 100, 110, true, opened, "")
+	require.NoError(t, err)
+	_, err = database.InsertSilencedAlertlog(db, 1, chain, "g1muted", "muted", "CRITICAL", 100, 130, true, opened, "")
+	require.NoError(t, err)
+
+	incidents, err := database.OpenCriticalIncidents(db, chain)
+	require.NoError(t, err)
+	require.Len(t, incidents, 1)
+	inc := incidents[0]
+	assert.Equal(t, "g1down", inc.Addr)
+	assert.Equal(t, firstID, inc.AlertLogID, "the incident is keyed on its first CRITICAL")
+	assert.Equal(t, int64(160), inc.EndHeight)
+	assert.WithinDuration(t, opened, inc.OpenedAt, time.Second)
+
+	fresh, err := database.MarkEscalated(db, chain, inc.AlertLogID, 7, 2)
+	require.NoError(t, err)
+	assert.True(t, fresh)
+	fresh, err = database.MarkEscalated(db, chain, inc.AlertLogID, 7, 2)
+	require.NoError(t, err)
+	assert.False(t, fresh, "a tier fires once per incident")
+
+	_, err = database.AckAlert(db, firstID, "user_1")
+	require.NoError(t, err)
+	incidents, err = database.OpenCriticalIncidents(db, chain)
+	require.NoError(t, err)
+	assert.Empty(t, incidents, "an acknowledged incident is not escalated")
+
+	_, err = database.InsertAlertlog(db, chain, "g1other", "other", "CRITICAL", 200, 230, true, opened, "")
+	require.NoError(t, err)
+	_, err = database.InsertAlertlog(db, chain, "g1other", "other", "RESOLVED", 200, 230, false, time.Now(), "")
+	require.NoError(t, err)
+	incidents, err = database.OpenCriticalIncidents(db, chain)
+	require.NoError(t, err)
+	assert.Empty(t, incidents, "a RESOLVED incident is not escalated")
+}
@@ -2,6 +2,9 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"log"
 	"strings"
@@ -44,6 +47,60 @@ type TelegramValidatorSub struct {
 	Activate  bool      `gorm:"default:true;index"                                                                         json:"activate"`
 	CreatedAt time.Time `gorm:"autoCreateTime"                                                                             json:"-"`
 }
+
+// DiscordChannel is a Discord channel the validator bot was used in, with
+// its active chain (the Discord counterpart of Telegram). Channel and guild
+// snowflakes are stored as integers.
+type DiscordChannel struct {
+	ChannelID int64     `gorm:"primaryKey;column:channel_id"       json:"channel_id"`
+	GuildID   int64     `gorm:"column:guild_id;not null;default:0" json:"guild_id"`
+	ChainID   string    `gorm:"column:chain_id;not null"           json:"chain_id"`
+	CreatedAt time.Time `gorm:"autoCreateTime"                     json:"created_at"`
+}
+
+// DiscordHourReport is the daily report schedule of a Discord channel for a
+// chain. Unlike the Telegram one it is opt-in (/report activate:true).
+type DiscordHourReport struct {
+	ChannelID         int64  `gorm:"primaryKey;column:channel_id"           json:"channel_id"`
+	ChainID           string `gorm:"primaryKey;column:chain_id;not null"     json:"chain_id"`
+	DailyReportHour   int    `gorm:"column:daily_report_hour;default:9"     json:"daily_report_hour"`
+	DailyReportMinute int    `gorm:"column:daily_report_minute;default:0"   json:"daily_report_minute"`
+	Activate          bool   `gorm:"column:activate;default:false"          json:"activate"`
+	Timezone          string `gorm:"column:timezone;default:'Europe/Paris'" json:"timezone"`
+}
+
+// DiscordValidatorSub is a Discord channel's alert subscription to a
+// validator, like TelegramValidatorSub.
+type DiscordValidatorSub struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"                                                       json:"ID"`
+	ChannelID int64     `gorm:"index:idx_dvs_chain_addr_channel,unique,priority:3;not null"                    json:"channel_id"`
+	ChainID   string    `gorm:"column:chain_id;not null;index:idx_dvs_chain_addr_channel,unique,priority:1"    json:"chain_id"`
+	Moniker   string    `gorm:"size:64"                                                                        json:"moniker"`
+	Addr      string    `gorm:"index:idx_dvs_chain_addr_channel,unique,priority:2;not null"                    json:"addr"`
+	Activate  bool      `gorm:"default:true;index"                                                             json:"activate"`
+	CreatedAt time.Time `gorm:"autoCreateTime"                                                                 json:"-"`
+}
+
+// SlackChannel is a Slack channel the app was used in, with its active
+// chain, like DiscordChannel.
+type SlackChannel struct {
+	ChannelID string    `gorm:"primaryKey;column:channel_id"        json:"channel_id"`
+	TeamID    string    `gorm:"column:team_id;not null;default:''" json:"team_id"`
+	ChainID   string    `gorm:"column:chain_id;not null"            json:"chain_id"`
+	CreatedAt time.Time `gorm:"autoCreateTime"                      json:"created_at"`
+}
+
+// SlackValidatorSub is a Slack channel's alert subscription to a validator,
+// like TelegramValidatorSub.
+type SlackValidatorSub struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"                                                       json:"ID"`
+	ChannelID string    `gorm:"index:idx_svs_chain_addr_channel,unique,priority:3;not null"                    json:"channel_id"`
+	ChainID   string    `gorm:"column:chain_id;not null;index:idx_svs_chain_addr_channel,unique,priority:1"    json:"chain_id"`
+	Moniker   string    `gorm:"size:64"                                                                        json:"moniker"`
+	Addr      string    `gorm:"index:idx_svs_chain_addr_channel,unique,priority:2;not null"                    json:"addr"`
+	Activate  bool      `gorm:"default:true;index"                                                             json:"activate"`
+	CreatedAt time.Time `gorm:"autoCreateTime"                                                                 json:"-"`
+}
 type ParticipationRate struct {
 	Addr              string  `json:"addr"`
 	Moniker           string  `json:"moniker"`
@@ -55,6 +112,10 @@ type User struct {
 	Name      string    `gorm:"column:nameuser;not null" json:"name"`
 	Email     string    `gorm:"uniqueIndex;not null" json:"email"`
 	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	// EmailAlerts and EmailReports opt the user in to validator alerts and
+	// the daily report by email (needs the smtp config section).
+	EmailAlerts  bool `gorm:"column:email_alerts;not null;default:false" json:"email_alerts"`
+	EmailReports bool `gorm:"column:email_reports;not null;default:false" json:"email_reports"`
 }
 type HourReport struct {
 	UserID            string `gorm:"primaryKey;column:user_id;not null" `
@@ -79,9 +140,15 @@ type WebhookGovDAO struct {
 	Description   string    `gorm:"column:description" `
 	UserID        string    `gorm:"column:user_id;not null;index:idx_webhooks_govdao_user" `
 	URL           string    `gorm:"column:url;not null" `
-	Type          string    `gorm:"column:type;not null;check:type IN ('discord','slack')" `
+	Type          string    `gorm:"column:type;not null;check:type IN ('discord','slack','generic','pagerduty','opsgenie','matrix','mattermost')" `
 	LastCheckedID int       `gorm:"column:last_checked_id;not null;default:-1" `
 	ChainID       *string   `gorm:"column:chain_id;default:null" json:"chain_id"`
+	// Secret is the HMAC key of a "generic" webhook, or the routing/API key
+	// of a "pagerduty"/"opsgenie" one (NULL for other types).
+	Secret *string `gorm:"column:secret;default:null" json:"secret,omitempty"`
+	// HasSecret stands in for Secret in list responses, which never return
+	// the secret itself.
+	HasSecret bool `gorm:"-" json:"has_secret"`
 }
 type WebhookValidator struct {
 	ID          int       `gorm:"primaryKey;autoIncrement;column:id"`
@@ -89,9 +156,101 @@ type WebhookValidator struct {
 	Description string    `gorm:"column:description" `
 	UserID      string    `gorm:"column:user_id;not null;index:idx_webhooks_validator_user" `
 	URL         string    `gorm:"column:url;not null" `
-	Type        string    `gorm:"column:type;not null;check:type IN ('discord','slack')" `
+	Type        string    `gorm:"column:type;not null;check:type IN ('discord','slack','generic','pagerduty','opsgenie','matrix','mattermost')" `
 	ChainID     *string   `gorm:"column:chain_id;default:null" json:"chain_id"`
+	// Secret is the HMAC key of a "generic" webhook, or the routing/API key
+	// of a "pagerduty"/"opsgenie" one (NULL for other types).
+	Secret *string       `gorm:"column:secret;default:null" json:"secret,omitempty"`
+	Filter WebhookFilter `gorm:"embedded"                   json:"filter"`
+	// HasSecret stands in for Secret in list responses, which never return
+	// the secret itself.
+	HasSecret bool `gorm:"-" json:"has_secret"`
+}
+
+// WebhookFilter narrows which alerts a validator webhook receives. Every
+// empty field means "no restriction", so a zero filter receives everything.
+type WebhookFilter struct {
+	// MinLevel drops alerts below it: "INFO", "WARNING" or "CRITICAL".
+	MinLevel string `gorm:"column:min_level;not null;default:''" json:"min_level"`
+	// IncludeValidators / ExcludeValidators hold validator addresses or
+	// monikers. They only apply to alerts about one validator.
+	IncludeValidators StringList `gorm:"column:include_validators;type:text" json:"include_validators"`
+	ExcludeValidators StringList `gorm:"column:exclude_validators;type:text" json:"exclude_validators"`
+	// AlertKinds lists the alert kinds wanted ("missed_blocks",
+	// "stagnation", "valset_change", "rpc_error").
+	AlertKinds StringList `gorm:"column:alert_kinds;type:text" json:"alert_kinds"`
+}
+
+// StringList is a []string stored as a JSON array in a TEXT column (NULL
+// when empty).
+type StringList []string
+
+func (l StringList) Value() (driver.Value, error) {
+	if len(l) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal([]string(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (l *StringList) Scan(src any) error {
+	var raw []byte
+	switch v := src.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return fmt.Errorf("StringList: unsupported type %T", src)
+	}
+	if len(raw) == 0 {
+		*l = nil
+		return nil
+	}
+	return json.Unmarshal(raw, (*[]string)(l))
+}
+
+// IntList is a []int stored as a JSON array in a TEXT column, like
+// StringList.
+type IntList []int
+
+func (l IntList) Value() (driver.Value, error) {
+	if len(l) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal([]int(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (l *IntList) Scan(src any) error {
+	var raw []byte
+	switch v := src.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return fmt.Errorf("IntList: unsupported type %T", src)
+	}
+	if len(raw) == 0 {
+		*l = nil
+		return nil
+	}
+	return json.Unmarshal(raw, (*[]int)(l))
 }
+
 type DailyParticipation struct {
 	ID             uint64    `gorm:"column:id;primaryKey;autoIncrement"`
 	Date           time.Time `gorm:"column:date"`
@@ -119,18 +278,254 @@ type DailyParticipationAgrega struct {
 }
 
 type AlertLog struct {
-	ID          uint      `gorm:"primaryKey;autoIncrement;column:id"                              json:"ID"`
-	ChainID     string    `gorm:"column:chain_id;not null;default:'betanet';index:idx_al_chain_addr,priority:1" json:"chain_id"`
-	Addr        string    `gorm:"column:addr;not null;index:idx_al_chain_addr,priority:2"         json:"addr"`
-	Moniker     string    `gorm:"column:moniker;not null"                                         json:"moniker"`
-	Level       string    `gorm:"column:level;not null"                                           json:"level"`
-	StartHeight int64     `gorm:"column:start_height;not null"                                    json:"start_height"`
-	EndHeight   int64     `gorm:"column:end_height;not null"                                      json:"end_height"`
-	Skipped     bool      `gorm:"column:skipped;not null"                                         json:"skipped"`
-	Msg         string    `gorm:"column:msg"                                                      json:"msg"`
-	SentAt      time.Time `gorm:"column:sent_at;autoCreateTime"                                   json:"sent_at"`
+	ID          uint       `gorm:"primaryKey;autoIncrement;column:id"                              json:"ID"`
+	ChainID     string     `gorm:"column:chain_id;not null;default:'betanet';index:idx_al_chain_addr,priority:1" json:"chain_id"`
+	Addr        string     `gorm:"column:addr;not null;index:idx_al_chain_addr,priority:2"         json:"addr"`
+	Moniker     string     `gorm:"column:moniker;not null"                                         json:"moniker"`
+	Level       string     `gorm:"column:level;not null"                                           json:"level"`
+	StartHeight int64      `gorm:"column:start_height;not null"                                    json:"start_height"`
+	EndHeight   int64      `gorm:"column:end_height;not null"                                      json:"end_height"`
+	Skipped     bool       `gorm:"column:skipped;not null"                                         json:"skipped"`
+	Msg         string     `gorm:"column:msg"                                                      json:"msg"`
+	SentAt      time.Time  `gorm:"column:sent_at;autoCreateTime"                                   json:"sent_at"`
+	SilenceID   *uint      `gorm:"column:silence_id;index"                                         json:"silence_id,omitempty"` // set when a silence muted the notification
+	AckedBy     string     `gorm:"column:acked_by;not null;default:''"                             json:"acked_by,omitempty"`   // Clerk user ID or "telegram:@username"
+	AckedAt     *time.Time `gorm:"column:acked_at"                                                json:"acked_at,omitempty"`
+}
+
+// Silence mutes the alert notifications of one validator (Addr) or of a whole
+// chain (Addr "all") between StartAt and EndAt, e.g. during a node upgrade.
+// Alerts raised meanwhile are still written to alert_logs, tagged with the
+// silence's ID.
+type Silence struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement;column:id"                         json:"id"`
+	ChainID   string    `gorm:"column:chain_id;not null;index:idx_silence_chain_addr,priority:1" json:"chain_id"`
+	Addr      string    `gorm:"column:addr;not null;index:idx_silence_chain_addr,priority:2"     json:"addr"` // validator address or "all"
+	StartAt   time.Time `gorm:"column:start_at;not null"                                   json:"start_at"`
+	EndAt     time.Time `gorm:"column:end_at;not null;index"                               json:"end_at"`
+	Reason    string    `gorm:"column:reason"                                              json:"reason"`
+	CreatedBy string    `gorm:"column:created_by;not null"                                 json:"created_by"` // Clerk user ID, "admin:<id>" or "telegram:<chat_id>"
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"                           json:"created_at"`
+}
+
+// EscalationPolicy escalates a user's unanswered CRITICAL missed-block
+// incidents on ChainID (NULL = every chain), for the validators named in
+// Validators (addresses or monikers) or, when it is empty, the validators
+// the user's own webhooks receive alerts about. When an incident stays
+// unresolved and unacknowledged for Tier2After minutes, the tier-2 webhooks
+// and alert contacts are notified; after Tier3After minutes, the tier-3
+// ones. A zero delay disables the tier. Contacts are reached through their
+// webhook, with their mention tag.
+type EscalationPolicy struct {
+	ID            uint       `gorm:"primaryKey;autoIncrement;column:id"          json:"id"`
+	UserID        string     `gorm:"column:user_id;not null;index"               json:"-"`
+	ChainID       *string    `gorm:"column:chain_id;default:null"                json:"chain_id"`
+	Validators    StringList `gorm:"column:validators;type:text"                 json:"validators"`
+	Tier2After    int        `gorm:"column:tier2_after_minutes;not null;default:0" json:"tier2_after_minutes"`
+	Tier2Webhooks IntList    `gorm:"column:tier2_webhooks;type:text"             json:"tier2_webhooks"`
+	Tier2Contacts IntList    `gorm:"column:tier2_contacts;type:text"             json:"tier2_contacts"`
+	Tier3After    int        `gorm:"column:tier3_after_minutes;not null;default:0" json:"tier3_after_minutes"`
+	Tier3Webhooks IntList    `gorm:"column:tier3_webhooks;type:text"             json:"tier3_webhooks"`
+	Tier3Contacts IntList    `gorm:"column:tier3_contacts;type:text"             json:"tier3_contacts"`
+	CreatedAt     time.Time  `gorm:"column:created_at;autoCreateTime"            json:"created_at"`
+}
+
+// AlertEscalation records that tier Tier of policy PolicyID was notified for
+// the incident opened by the CRITICAL alert_logs row AlertLogID, so each
+// tier fires once per incident, across restarts.
+type AlertEscalation struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement;column:id"                  json:"id"`
+	ChainID    string    `gorm:"column:chain_id;not null;index"                      json:"chain_id"`
+	AlertLogID uint      `gorm:"column:alert_log_id;not null;uniqueIndex:uniq_alert_escalation,priority:1" json:"alert_log_id"`
+	PolicyID   uint      `gorm:"column:policy_id;not null;uniqueIndex:uniq_alert_escalation,priority:2"    json:"policy_id"`
+	Tier       int       `gorm:"column:tier;not null;uniqueIndex:uniq_alert_escalation,priority:3"         json:"tier"`
+	SentAt     time.Time `gorm:"column:sent_at;autoCreateTime"                       json:"sent_at"`
 }
 
+// MissedProposal records that Addr was the proposer of round Round at
+// Height and the round ended without a commit, so the chain moved on to the
+// next proposer. Participation only flags the proposer of the committed
+// round, so these are the only trace of a skipped proposer slot.
+type MissedProposal struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement;column:id"                                      json:"id"`
+	ChainID     string    `gorm:"column:chain_id;not null;uniqueIndex:uniq_missed_proposal,priority:1;index:idx_mp_chain_addr,priority:1" json:"chain_id"`
+	Height      int64     `gorm:"column:height;not null;uniqueIndex:uniq_missed_proposal,priority:2"      json:"height"`
+	Round       int       `gorm:"column:round;not null;uniqueIndex:uniq_missed_proposal,priority:3"       json:"round"`
+	Addr        string    `gorm:"column:addr;not null;index:idx_mp_chain_addr,priority:2"                 json:"addr"`
+	Moniker     string    `gorm:"column:moniker"                                                          json:"moniker"`
+	CommitRound int       `gorm:"column:commit_round;not null"                                            json:"commit_round"` // round Height was finally committed in
+	CommittedAt time.Time `gorm:"column:committed_at;not null;index"                                      json:"committed_at"` // BFT time of the block carrying the commit
+}
+
+// Block holds the chain-level facts of one block; daily_participations only
+// keeps what each validator did in it. LastCommitRound is read from the
+// block's LastCommit, so it is the round Height-1 was committed in. Gas is
+// that of the block's transactions, as reported by the block results.
+type Block struct {
+	ChainID         string    `gorm:"column:chain_id;not null;primaryKey"                   json:"chain_id"`
+	Height          int64     `gorm:"column:height;not null;primaryKey;autoIncrement:false" json:"height"`
+	Time            time.Time `gorm:"column:time;not null;index"                            json:"time"`
+	ProposerAddr    string    `gorm:"column:proposer_addr;not null"                         json:"proposer_addr"`
+	TxCount         int       `gorm:"column:tx_count;not null"                              json:"tx_count"`
+	Size            int       `gorm:"column:size;not null"                                  json:"size"` // amino-encoded block, in bytes
+	GasWanted       int64     `gorm:"column:gas_wanted;not null"                            json:"gas_wanted"`
+	GasUsed         int64     `gorm:"column:gas_used;not null"                              json:"gas_used"`
+	LastCommitRound int       `gorm:"column:last_commit_round;not null"                     json:"last_commit_round"`
+}
+
+// BlockDailyAgrega is the daily rollup of blocks, kept after the raw rows
+// are pruned. AvgBlockTime is in seconds.
+type BlockDailyAgrega struct {
+	ChainID      string  `gorm:"column:chain_id;not null;primaryKey"   json:"chain_id"`
+	BlockDate    string  `gorm:"column:block_date;not null;primaryKey" json:"block_date"` // DATE string YYYY-MM-DD
+	BlockCount   int     `gorm:"column:block_count;not null"           json:"block_count"`
+	FirstHeight  int64   `gorm:"column:first_height;not null"          json:"first_height"`
+	LastHeight   int64   `gorm:"column:last_height;not null"           json:"last_height"`
+	AvgBlockTime float64 `gorm:"column:avg_block_time;not null"        json:"avg_block_time"`
+	MaxBlockTime float64 `gorm:"column:max_block_time;not null"        json:"max_block_time"`
+	TxCount      int64   `gorm:"column:tx_count;not null"              json:"tx_count"`
+	GasUsed      int64   `gorm:"column:gas_used;not null"              json:"gas_used"`
+	Size         int64   `gorm:"column:size;not null"                  json:"size"`
+	LateRounds   int     `gorm:"column:late_rounds;not null"           json:"late_rounds"` // commits after round 0
+	Proposers    int     `gorm:"column:proposers;not null"             json:"proposers"`   // distinct block proposers
+}
+
+// NodeVersion records that node NodeID of ChainID ran Version from
+// FirstSeen to LastSeen: one row per version a node was seen with, so the
+// rows of a node are its upgrade history. Addr is the validator the node
+// signs for, empty when it is not known to be a validator.
+type NodeVersion struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement;column:id"                                 json:"-"`
+	ChainID   string    `gorm:"column:chain_id;not null;uniqueIndex:uniq_node_version,priority:1" json:"chain_id"`
+	NodeID    string    `gorm:"column:node_id;not null;uniqueIndex:uniq_node_version,priority:2"  json:"node_id"`
+	Version   string    `gorm:"column:version;not null;uniqueIndex:uniq_node_version,priority:3"  json:"version"`
+	Moniker   string    `gorm:"column:moniker;not null"                                           json:"moniker"` // node moniker, as advertised to its peers
+	Addr      string    `gorm:"column:addr;not null;index"                                        json:"addr"`
+	RemoteIP  string    `gorm:"column:remote_ip;not null"                                         json:"remote_ip"`
+	FirstSeen time.Time `gorm:"column:first_seen;not null"                                        json:"first_seen"`
+	LastSeen  time.Time `gorm:"column:last_seen;not null;index"                                   json:"last_seen"`
+}
+
+// NetPeer is one peer of the RPC node of ChainID in the NetInfo sampled at
+// SeenAt: each sample stores the whole peer list. Rates are the current
+// bytes per second of the connection.
+type NetPeer struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement;column:id"                                json:"-"`
+	ChainID   string    `gorm:"column:chain_id;not null;index:idx_net_peer_chain_seen,priority:1" json:"chain_id"`
+	SeenAt    time.Time `gorm:"column:seen_at;not null;index:idx_net_peer_chain_seen,priority:2"  json:"seen_at"`
+	NodeID    string    `gorm:"column:node_id;not null"                                           json:"node_id"`
+	Moniker   string    `gorm:"column:moniker;not null"                                           json:"moniker"`
+	RemoteIP  string    `gorm:"column:remote_ip;not null"                                         json:"remote_ip"`
+	Direction string    `gorm:"column:direction;not null"                                         json:"direction"` // "inbound" or "outbound"
+	SendRate  int64     `gorm:"column:send_rate;not null"                                         json:"send_rate"`
+	RecvRate  int64     `gorm:"column:recv_rate;not null"                                         json:"recv_rate"`
+}
+
+// NetPeerSample is one sample of the peer list of the chain's RPC node: how
+// many peers it had, and how many of them it dialed. It is stored even when
+// the node had no peer, which leaves no net_peers row.
+type NetPeerSample struct {
+	ChainID  string    `gorm:"column:chain_id;primaryKey" json:"chain_id"`
+	SeenAt   time.Time `gorm:"column:seen_at;primaryKey"  json:"seen_at"`
+	Peers    int       `gorm:"column:peers;not null"      json:"peers"`
+	Outbound int       `gorm:"column:outbound;not null"   json:"outbound"`
+}
+
+// PlannedHalt is a halt height registered ahead of a coordinated chain
+// upgrade. gnovalidator.WatchPlannedHalts counts down to HaltHeight and
+// fills in the rest as the chain stops and restarts; CollectParticipation
+// holds the stagnation alert back for WindowMinutes once the chain is stuck
+// at HaltHeight.
+type PlannedHalt struct {
+	ID            uint       `gorm:"primaryKey;autoIncrement;column:id"                                   json:"id"`
+	ChainID       string     `gorm:"column:chain_id;not null;uniqueIndex:uniq_planned_halt,priority:1"    json:"chain_id"`
+	HaltHeight    int64      `gorm:"column:halt_height;not null;uniqueIndex:uniq_planned_halt,priority:2" json:"halt_height"`
+	WindowMinutes int        `gorm:"column:window_minutes;not null"                                       json:"window_minutes"`
+	Reason        string     `gorm:"column:reason"                                                        json:"reason"`
+	CreatedBy     string     `gorm:"column:created_by;not null"                                           json:"created_by"`
+	CreatedAt     time.Time  `gorm:"column:created_at;autoCreateTime"                                     json:"created_at"`
+	NotifiedStage int        `gorm:"column:notified_stage;not null;default:0"                             json:"notified_stage"` // last countdown notification sent
+	HaltedAt      *time.Time `gorm:"column:halted_at"                                                     json:"halted_at"`      // time of block HaltHeight
+	ResumedAt     *time.Time `gorm:"column:resumed_at"                                                    json:"resumed_at"`     // time of the first block after it
+	ReportedAt    *time.Time `gorm:"column:reported_at"                                                   json:"reported_at"`    // when the rejoin report was sent
+}
+
+// MonitoredAccount is an address a user follows the GNOT balance of on
+// ChainID, besides the valoper operator addresses every chain follows.
+// gnovalidator.WatchAccountBalances alerts the user's webhooks when the
+// balance falls below MinBalance.
+type MonitoredAccount struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement;column:id"                                     json:"id"`
+	UserID     string    `gorm:"column:user_id;not null;uniqueIndex:uniq_monitored_account,priority:1"  json:"-"`
+	ChainID    string    `gorm:"column:chain_id;not null;uniqueIndex:uniq_monitored_account,priority:2" json:"chain_id"`
+	Addr       string    `gorm:"column:addr;not null;uniqueIndex:uniq_monitored_account,priority:3"     json:"addr"`
+	Label      string    `gorm:"column:label"                                                           json:"label"`
+	MinBalance int64     `gorm:"column:min_balance;not null;default:0"                                  json:"min_balance"` // ugnot, 0: low_balance_ugnot
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime"                                       json:"created_at"`
+}
+
+// AccountBalance is the GNOT balance of Addr on ChainID sampled at Time by
+// gnovalidator.WatchAccountBalances.
+type AccountBalance struct {
+	ID      uint64    `gorm:"primaryKey;autoIncrement;column:id"                                            json:"-"`
+	ChainID string    `gorm:"column:chain_id;not null;index:idx_account_balance_chain_addr_time,priority:1" json:"chain_id"`
+	Addr    string    `gorm:"column:addr;not null;index:idx_account_balance_chain_addr_time,priority:2"     json:"addr"`
+	Time    time.Time `gorm:"column:time;not null;index:idx_account_balance_chain_addr_time,priority:3"     json:"time"`
+	Balance int64     `gorm:"column:balance;not null"                                                       json:"balance"` // ugnot
+}
+
+// TxWatch follows the transactions touching Target on ChainID: calls to a
+// realm or package path, or messages sent by or to an address.
+// govdao.WatchTransactions notifies its owner of every one of them, failed
+// ones included.
+type TxWatch struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement;column:id"                              json:"id"`
+	ChainID   string    `gorm:"column:chain_id;not null;uniqueIndex:uniq_tx_watch,priority:1"   json:"chain_id"`
+	Target    string    `gorm:"column:target;not null;uniqueIndex:uniq_tx_watch,priority:2"     json:"target"` // realm/package path or address
+	CreatedBy string    `gorm:"column:created_by;not null;uniqueIndex:uniq_tx_watch,priority:3" json:"-"` // Clerk user ID or "telegram:<chat_id>"
+	Label     string    `gorm:"column:label"                                                    json:"label"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"                                json:"created_at"`
+}
+
+// TxWatchCursor is the last block height govdao.WatchTransactions read on
+// ChainID, so a restart resumes where it stopped instead of at the head.
+type TxWatchCursor struct {
+	ChainID    string `gorm:"column:chain_id;primaryKey"`
+	LastHeight int64  `gorm:"column:last_height;not null"`
+}
+
+// AlertDelivery is one queued notification for one destination (a webhook
+// or a Telegram chat): the outbox drained by the delivery worker in
+// internal/outbox.go. Payload is the already-rendered channel body;
+// webhook secrets and bot tokens are looked up at send time and never stored.
+type AlertDelivery struct {
+	ID            uint       `gorm:"primaryKey;autoIncrement;column:id"                json:"id"`
+	AlertLogID    *uint      `gorm:"column:alert_log_id;index"                         json:"alert_log_id"`
+	ChainID       string     `gorm:"column:chain_id;not null;index"                    json:"chain_id"`
+	DestKey       string     `gorm:"column:dest_key;not null;index"                    json:"dest_key"` // e.g. "webhook_validators/12", "telegram/validator/-100123"
+	DestType      string     `gorm:"column:dest_type;not null"                         json:"dest_type"`
+	WebhookTable  string     `gorm:"column:webhook_table"                              json:"webhook_table,omitempty"`
+	WebhookID     int        `gorm:"column:webhook_id"                                 json:"webhook_id,omitempty"`
+	UserID        string     `gorm:"column:user_id"                                    json:"user_id,omitempty"`
+	ChatID        int64      `gorm:"column:chat_id"                                    json:"chat_id,omitempty"`
+	Channel       string     `gorm:"column:channel"                                    json:"channel,omitempty"` // Slack channel ID
+	Bot           string     `gorm:"column:bot"                                        json:"bot,omitempty"`
+	Title         string     `gorm:"column:title"                                      json:"title"`
+	Payload       string     `gorm:"column:payload;type:text;not null"                 json:"payload"`
+	Status        string     `gorm:"column:status;not null;default:'pending';index"    json:"status"` // pending | sent | dead
+	Attempts      int        `gorm:"column:attempts;not null;default:0"                json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"column:next_attempt_at;not null;index"             json:"next_attempt_at"`
+	LastError     string     `gorm:"column:last_error"                                 json:"last_error,omitempty"`
+	CreatedAt     time.Time  `gorm:"column:created_at;autoCreateTime"                  json:"created_at"`
+	SentAt        *time.Time `gorm:"column:sent_at"                                    json:"sent_at,omitempty"`
+}
+
+const (
+	DeliveryPending = "pending"
+	DeliverySent    = "sent"
+	DeliveryDead    = "dead"
+)
+
 type AddrMoniker struct {
 	ID               uint   `gorm:"primaryKey;autoIncrement;column:id"                                     json:"ID"`
 	ChainID          string `gorm:"column:chain_id;not null;default:'betanet';uniqueIndex:uniq_chain_addr,priority:1" json:"chain_id"`
@@ -140,13 +535,17 @@ type AddrMoniker struct {
 	VotingPower      int64  `gorm:"column:voting_power;not null;default:0"                                 json:"voting_power"`
 }
 type AlertSummary struct {
-	Moniker     string    `json:"moniker"`
-	Addr        string    `json:"addr"`
-	Level       string    `json:"level"`
-	StartHeight int64     `json:"startHeight"`
-	EndHeight   int64     `json:"endHeight"`
-	Msg         string    `json:"msg"`
-	SentAt      time.Time `json:"sentAt"`
+	Moniker     string     `json:"moniker"`
+	Addr        string     `json:"addr"`
+	Level       string     `json:"level"`
+	StartHeight int64      `json:"startHeight"`
+	EndHeight   int64      `json:"endHeight"`
+	Msg         string     `json:"msg"`
+	SentAt      time.Time  `json:"sentAt"`
+	Silenced    bool       `json:"silenced"`
+	ID          uint       `json:"id"`
+	AckedBy     string     `json:"ackedBy,omitempty"`
+	AckedAt     *time.Time `json:"ackedAt,omitempty"`
 }
 type UptimeMetrics struct {
 	Moniker string  `json:"moniker"`
@@ -256,6 +655,21 @@ func ApplyTelegramChainIDMigration(db *gorm.DB) error {
 	return nil
 }
 
+// ApplyNetPeerSampleBackfill creates the net_peer_samples rows of the
+// net_peers samples stored before the sample counts were recorded.
+// Idempotent: samples already counted are left alone.
+func ApplyNetPeerSampleBackfill(db *gorm.DB) error {
+	return db.Exec(`
+		INSERT INTO net_peer_samples (chain_id, seen_at, peers, outbound)
+		SELECT chain_id, seen_at,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE direction = 'outbound')
+		FROM net_peers
+		GROUP BY chain_id, seen_at
+		ON CONFLICT (chain_id, seen_at) DO NOTHING
+	`).Error
+}
+
 // ApplyGovdaoCompositePrimaryKeyMigration changes the govdaos primary key from
 // id alone to the composite (chain_id, id). Proposal IDs are per-chain
 // sequential counters, so two chains inevitably reuse the same integer id;
@@ -309,6 +723,59 @@ func ApplyGovdaoCompositePrimaryKeyMigration(db *gorm.DB) error {
 	return nil
 }
 
+// WebhookTypes lists every accepted webhook_validators/webhook_gov_daos
+// type. Keep it in sync with the check tag on both models: AutoMigrate only
+// creates a check constraint when it is missing, so existing databases pick
+// up a new type through ApplyWebhookTypeCheckMigration instead.
+var WebhookTypes = []string{"discord", "slack", "generic", "pagerduty", "opsgenie", "matrix", "mattermost"}
+
+// ApplyWebhookTypeCheckMigration rewrites the type check constraint of both
+// webhook tables so it accepts every entry of WebhookTypes. Idempotent: a
+// constraint whose definition already names every type is left untouched.
+func ApplyWebhookTypeCheckMigration(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("ApplyWebhookTypeCheckMigration: get sql.DB: %w", err)
+	}
+
+	quoted := make([]string, len(WebhookTypes))
+	for i, t := range WebhookTypes {
+		quoted[i] = "'" + t + "'"
+	}
+	check := fmt.Sprintf("type IN (%s)", strings.Join(quoted, ","))
+
+	for _, table := range []string{"webhook_validators", "webhook_gov_daos"} {
+		name := "chk_" + table + "_type"
+
+		var def string
+		err := sqlDB.QueryRow(
+			`SELECT pg_get_constraintdef(oid) FROM pg_constraint
+			WHERE conrelid = $1::regclass AND conname = $2`, table, name,
+		).Scan(&def)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("ApplyWebhookTypeCheckMigration: read %s: %w", name, err)
+		}
+		upToDate := err == nil
+		for _, q := range quoted {
+			if !strings.Contains(def, q) {
+				upToDate = false
+				break
+			}
+		}
+		if upToDate {
+			continue
+		}
+
+		if _, err := sqlDB.Exec(fmt.Sprintf(`ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s`, table, name)); err != nil {
+			return fmt.Errorf("ApplyWebhookTypeCheckMigration: drop %s: %w", name, err)
+		}
+		if _, err := sqlDB.Exec(fmt.Sprintf(`ALTER TABLE %s ADD CONSTRAINT %s CHECK (%s)`, table, name, check)); err != nil {
+			return fmt.Errorf("ApplyWebhookTypeCheckMigration: add %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
 // CreateOrReplaceIndexes drops legacy single-chain indexes and creates new
 // compound (chain_id, …) indexes suited for multi-chain queries.
 func CreateOrReplaceIndexes(db *gorm.DB) error {
@@ -467,7 +934,11 @@ func InitDB(dsn string) (*gorm.DB, error) {
 		&User{}, &AlertContact{}, &WebhookValidator{},
 		&WebhookGovDAO{}, &HourReport{},
 		&DailyParticipation{}, &DailyParticipationAgrega{}, &AlertLog{}, &AddrMoniker{}, &Govdao{}, &Telegram{}, &TelegramHourReport{}, &TelegramValidatorSub{},
-		&AdminConfig{},
+		&AdminConfig{}, &AlertDelivery{}, &Silence{}, &EscalationPolicy{}, &AlertEscalation{},
+		&DiscordChannel{}, &DiscordHourReport{}, &DiscordValidatorSub{},
+		&SlackChannel{}, &SlackValidatorSub{},
+		&MissedProposal{}, &Block{}, &BlockDailyAgrega{}, &NodeVersion{}, &NetPeer{}, &NetPeerSample{}, &PlannedHalt{},
+		&MonitoredAccount{}, &AccountBalance{}, &TxWatch{}, &TxWatchCursor{},
 	)
 	if err != nil {
 		return nil, err
@@ -485,6 +956,14 @@ func InitDB(dsn string) (*gorm.DB, error) {
 		return nil, fmt.Errorf("ApplyGovdaoCompositePrimaryKeyMigration: %w", err)
 	}
 
+	if err := ApplyWebhookTypeCheckMigration(db); err != nil {
+		return nil, fmt.Errorf("ApplyWebhookTypeCheckMigration: %w", err)
+	}
+
+	if err := ApplyNetPeerSampleBackfill(db); err != nil {
+		return nil, fmt.Errorf("ApplyNetPeerSampleBackfill: %w", err)
+	}
+
 	if err := CreateOrReplaceIndexes(db); err != nil {
 		return nil, fmt.Errorf("CreateOrReplaceIndexes: %w", err)
 	}
@@ -536,6 +1015,16 @@ func SeedAdminConfig(db *gorm.DB) error {
 		"alert_check_interval_seconds":     "30",
 		"raw_retention_days":               "7",
 		"aggregator_period_minutes":        "60",
+		"delivery_max_attempts":            "8",
+		"consensus_round_warning":          "3",
+		"endpoint_lag_blocks":              "10",
+		"block_time_window":                "100",
+		"block_time_warning_multiple":      "3",
+		"block_time_critical_multiple":     "10",
+		"block_time_baseline_ms":           "0",
+		"peer_count_min":                   "3",
+		"sentry_missing_minutes":           "10",
+		"low_balance_ugnot":                "1000000",
 	}
 	for key, value := range defaults {
 		row := AdminConfig{Key: key, Value: value}
@@ -23,3 +23,27 @@ func TestEnsureUTCTimeZone_CaseInsensitiveDetection(t *testing.T) {
 		t.Fatalf("got %q, want unchanged %q (lowercase timezone= must still be detected)", got, dsn)
 	}
 }
+
+func TestStringList_RoundTrip(t *testing.T) {
+	v, err := StringList{"g1abc", "my moniker"}.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != `["g1abc","my moniker"]` {
+		t.Fatalf("Value() = %v", v)
+	}
+	var got StringList
+	if err := got.Scan(v); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[1] != "my moniker" {
+		t.Fatalf("Scan() = %v", got)
+	}
+
+	if v, _ := StringList(nil).Value(); v != nil {
+		t.Fatalf("empty list should be stored as NULL, got %v", v)
+	}
+	if err := got.Scan(nil); err != nil || got != nil {
+		t.Fatalf("Scan(nil) = %v, %v", got, err)
+	}
+}
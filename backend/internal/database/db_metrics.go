@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -11,7 +12,9 @@ import (
 )
 
 // ====================================== ALERT LOG ======================================
-func InsertAlertlog(db *gorm.DB, chainID, addr, moniker, level string, startheight, endheight int64, skipped bool, sent time.Time, msg string) error {
+// InsertAlertlog records an alert and returns the new row ID, which links
+// the alert's queued deliveries (alert_deliveries.alert_log_id) to it.
+func InsertAlertlog(db *gorm.DB, chainID, addr, moniker, level string, startheight, endheight int64, skipped bool, sent time.Time, msg string) (uint, error) {
 	alert := AlertLog{
 		ChainID:     chainID,
 		Addr:        addr,
@@ -23,7 +26,147 @@ func InsertAlertlog(db *gorm.DB, chainID, addr, moniker, level string, startheig
 		Msg:         msg,
 		SentAt:      sent,
 	}
-	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&alert).Error
+	err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&alert).Error
+	return alert.ID, err
+}
+
+// InsertSilencedAlertlog records an alert whose notification was muted by
+// silence silenceID, so reports still show it. Same arguments as
+// InsertAlertlog.
+func InsertSilencedAlertlog(db *gorm.DB, silenceID uint, chainID, addr, moniker, level string, startheight, endheight int64, skipped bool, sent time.Time, msg string) (uint, error) {
+	alert := AlertLog{
+		ChainID:     chainID,
+		Addr:        addr,
+		Moniker:     moniker,
+		Level:       level,
+		StartHeight: startheight,
+		EndHeight:   endheight,
+		Skipped:     skipped,
+		Msg:         msg,
+		SentAt:      sent,
+		SilenceID:   &silenceID,
+	}
+	err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&alert).Error
+	return alert.ID, err
+}
+
+// AckAlert records that by acknowledged WARNING/CRITICAL alert id and
+// returns the row. Acknowledging an already acknowledged alert keeps the
+// first ack. Returns gorm.ErrRecordNotFound for unknown IDs and for alerts
+// that cannot be acknowledged (RESOLVED, INFO).
+func AckAlert(db *gorm.DB, id uint, by string) (AlertLog, error) {
+	var alert AlertLog
+	err := db.Where("id = ? AND level IN ('WARNING', 'CRITICAL')", id).First(&alert).Error
+	if err != nil {
+		return alert, err
+	}
+	if alert.AckedAt != nil {
+		return alert, nil
+	}
+	now := time.Now()
+	res := db.Model(&AlertLog{}).
+		Where("id = ? AND acked_at IS NULL", id).
+		Updates(map[string]any{"acked_by": by, "acked_at": now})
+	if res.Error != nil {
+		return alert, res.Error
+	}
+	if res.RowsAffected == 0 {
+		// Acknowledged concurrently: report the ack that won.
+		err := db.First(&alert, id).Error
+		return alert, err
+	}
+	alert.AckedBy = by
+	alert.AckedAt = &now
+	return alert, nil
+}
+
+// UserWatchesAlert reports whether userID is notified about alert id: one of
+// their validator webhooks routes its validator on its chain, or one of
+// their escalation policies covers it. An ack pauses resends and
+// escalations for everyone, so only these users may give it. Returns
+// gorm.ErrRecordNotFound for unknown IDs.
+func UserWatchesAlert(db *gorm.DB, id uint, userID string) (bool, error) {
+	var alert AlertLog
+	if err := db.First(&alert, id).Error; err != nil {
+		return false, err
+	}
+	routed, err := UserRoutesValidator(db, userID, alert.ChainID, alert.Addr, alert.Moniker)
+	if err != nil || routed {
+		return routed, err
+	}
+	var policies []EscalationPolicy
+	if err := db.Where("user_id = ? AND (chain_id = ? OR chain_id IS NULL)", userID, alert.ChainID).
+		Find(&policies).Error; err != nil {
+		return false, err
+	}
+	for _, p := range policies {
+		if namesValidator(p.Validators, alert.Addr, alert.Moniker) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// UserRoutesValidator reports whether one of userID's validator webhooks on
+// chainID lets through the alerts about addr (or its moniker), whatever
+// their level and kind. Chain-level alerts (addr "all") pass every filter.
+func UserRoutesValidator(db *gorm.DB, userID, chainID, addr, moniker string) (bool, error) {
+	var hooks []WebhookValidator
+	if err := db.Where("user_id = ? AND (chain_id = ? OR chain_id IS NULL)", userID, chainID).
+		Find(&hooks).Error; err != nil {
+		return false, err
+	}
+	for _, h := range hooks {
+		if addr == "all" {
+			return true, nil
+		}
+		if len(h.Filter.IncludeValidators) > 0 && !namesValidator(h.Filter.IncludeValidators, addr, moniker) {
+			continue
+		}
+		if !namesValidator(h.Filter.ExcludeValidators, addr, moniker) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// namesValidator reports whether list names addr or (case-insensitively)
+// moniker.
+func namesValidator(list StringList, addr, moniker string) bool {
+	for _, v := range list {
+		if v == addr || (moniker != "" && strings.EqualFold(v, moniker)) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsIncidentAcked reports whether the ongoing incident of addr on chainID
+// (addr "all" for chain-level alerts) was acknowledged at level or above: an
+// acked alert with no RESOLVED logged after it. A CRITICAL ack covers later
+// WARNINGs, but a WARNING ack does not cover an escalation to CRITICAL.
+func IsIncidentAcked(db *gorm.DB, chainID, addr, level string) (bool, error) {
+	levels := []string{"CRITICAL"}
+	if level == "WARNING" {
+		levels = append(levels, "WARNING")
+	}
+	var acked bool
+	err := db.Raw(`
+		SELECT EXISTS (
+			SELECT 1 FROM alert_logs al
+			WHERE al.chain_id = ? AND al.addr = ?
+			  AND al.level IN ?
+			  AND al.acked_at IS NOT NULL
+			  AND NOT EXISTS (
+			      SELECT 1 FROM alert_logs r
+			      WHERE r.chain_id = al.chain_id
+			        AND r.addr     = al.addr
+			        AND r.level    = 'RESOLVED'
+			        AND r.sent_at  > al.sent_at
+			  )
+		)
+	`, chainID, addr, levels).Scan(&acked).Error
+	return acked, err
 }
 
 func GetAlertLog(db *gorm.DB, chainID, period string) ([]AlertSummary, error) {
@@ -84,7 +227,9 @@ func GetAlertLog(db *gorm.DB, chainID, period string) ([]AlertSummary, error) {
 	err := db.Raw(`
 		SELECT DISTINCT
 		       COALESCE(NULLIF(am.moniker, 'unknown'), al.moniker, '') AS moniker,
-		       al.level, al.addr, al.start_height, al.end_height, al.msg, al.sent_at
+		       al.level, al.addr, al.start_height, al.end_height, al.msg, al.sent_at,
+		       al.silence_id IS NOT NULL AS silenced,
+		       al.id, al.acked_by, al.acked_at
 		FROM alert_logs al
 		LEFT JOIN addr_monikers am ON am.chain_id = al.chain_id AND am.addr = al.addr
 		WHERE al.chain_id = ? AND al.sent_at BETWEEN ? AND ?
@@ -779,6 +924,28 @@ func GetCurrentChainHeight(db *gorm.DB, chainID string) (int64, error) {
 	return height.Int64, nil
 }
 
+// GetMissingHeights returns the heights in [from, to] with no
+// daily_participations row for chainID, in ascending order.
+func GetMissingHeights(db *gorm.DB, chainID string, from, to int64) ([]int64, error) {
+	if from > to {
+		return nil, nil
+	}
+	var heights []int64
+	err := db.Raw(`
+		SELECT h.height
+		FROM generate_series(?::bigint, ?::bigint) AS h(height)
+		WHERE NOT EXISTS (
+			SELECT 1 FROM daily_participations dp
+			WHERE dp.chain_id = ? AND dp.block_height = h.height
+		)
+		ORDER BY h.height
+	`, from, to, chainID).Scan(&heights).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetMissingHeights: %w", err)
+	}
+	return heights, nil
+}
+
 // ====================================== ALERT METRICS ==============================
 
 // GetActiveAlertCount returns the count of currently active alerts (unresolved)
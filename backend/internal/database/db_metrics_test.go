@@ -8,6 +8,7 @@ import (
 	"github.com/samouraiworld/gnomonitoring/backend/internal/testoutils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 )
 
 // TestGetAlertLog_CrossChainIsolation verifies that alert logs are properly isolated by chain_id
@@ -690,3 +691,102 @@ func TestUptimeAndMissingBlock_MonikerFrozenFallback(t *testing.T) {
 	require.Len(t, missing, 1)
 	assert.Equal(t, frozen, missing[0].Moniker, "missing-block must use the frozen moniker when addr_monikers is empty")
 }
+
+// TestAckAlert_SuppressesUntilResolved acknowledges a WARNING and checks the
+// incident counts as acked for WARNING but not for an escalation to
+// CRITICAL, and stops counting once a RESOLVED is logged.
+func TestAckAlert_SuppressesUntilResolved(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+	const chain, addr = "test12", "g1acked"
+
+	warnID, err := database.InsertAlertlog(db, chain, addr, "val", "WARNING", 100, 110, true, time.Now().Add(-time.Minute), "")
+	require.NoError(t, err)
+
+	alert, err := database.AckAlert(db, warnID, "user_1")
+	require.NoError(t, err)
+	assert.Equal(t, "user_1", alert.AckedBy)
+	require.NotNil(t, alert.AckedAt)
+
+	again, err := database.AckAlert(db, warnID, "user_2")
+	require.NoError(t, err)
+	assert.Equal(t, "user_1", again.AckedBy, "the first ack is kept")
+
+	acked, err := database.IsIncidentAcked(db, chain, addr, "WARNING")
+	require.NoError(t, err)
+	assert.True(t, acked)
+	acked, err = database.IsIncidentAcked(db, chain, addr, "CRITICAL")
+	require.NoError(t, err)
+	assert.False(t, acked, "a WARNING ack does not cover an escalation")
+
+	resolvedID, err := database.InsertAlertlog(db, chain, addr, "val", "RESOLVED", 100, 110, false, time.Now(), "")
+	require.NoError(t, err)
+	acked, err = database.IsIncidentAcked(db, chain, addr, "WARNING")
+	require.NoError(t, err)
+	assert.False(t, acked, "a new incident after the RESOLVED is not acked")
+
+	_, err = database.AckAlert(db, resolvedID, "user_1")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "RESOLVED alerts cannot be acknowledged")
+}
+
+// TestUserWatchesAlert checks that only users whose webhooks route the
+// alert's validator, or whose escalation policies name it, watch it.
+func TestUserWatchesAlert(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+	const chain = "test12"
+	chainID, other := chain, "other"
+
+	alertID, err := database.InsertAlertlog(db, chain, "g1watched", "Watched", "CRITICAL", 100, 130, true, time.Now(), "")
+	require.NoError(t, err)
+	require.NoError(t, db.Create(&[]database.WebhookValidator{
+		{UserID: "all_chains", URL: "https://example.com/a", Type: "discord"},
+		{UserID: "by_moniker", URL: "https://example.com/b", Type: "discord", ChainID: &chainID,
+			Filter: database.WebhookFilter{IncludeValidators: database.StringList{"watched"}}},
+		{UserID: "excluded", URL: "https://example.com/c", Type: "discord",
+			Filter: database.WebhookFilter{ExcludeValidators: database.StringList{"g1watched"}}},
+		{UserID: "other_chain", URL: "https://example.com/d", Type: "discord", ChainID: &other},
+	}).Error)
+	require.NoError(t, db.Create(&[]database.EscalationPolicy{
+		{UserID: "escalates", Validators: database.StringList{"g1watched"}, Tier2After: 10},
+		{UserID: "escalates_other", Validators: database.StringList{"g1other"}, Tier2After: 10},
+	}).Error)
+
+	for user, want := range map[string]bool{
+		"all_chains":      true,
+		"by_moniker":      true,
+		"escalates":       true,
+		"escalates_other": false,
+		"excluded":        false,
+		"other_chain":     false,
+		"stranger":        false,
+	} {
+		got, err := database.UserWatchesAlert(db, alertID, user)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, user)
+	}
+
+	_, err = database.UserWatchesAlert(db, alertID+1000, "all_chains")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestGetMissingHeights(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+	now := time.Now().UTC()
+
+	var data []database.DailyParticipation
+	for _, h := range []int64{10, 11, 14, 16} {
+		data = append(data,
+			database.DailyParticipation{ChainID: "betanet", Addr: "g1a", Moniker: "A", BlockHeight: h, Date: now, Participated: true},
+			database.DailyParticipation{ChainID: "betanet", Addr: "g1b", Moniker: "B", BlockHeight: h, Date: now, Participated: false},
+		)
+	}
+	data = append(data, database.DailyParticipation{ChainID: "gnoland1", Addr: "g1a", Moniker: "A", BlockHeight: 12, Date: now})
+	require.NoError(t, db.Create(&data).Error)
+
+	missing, err := database.GetMissingHeights(db, "betanet", 10, 16)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{12, 13, 15}, missing, "rows of another chain do not fill a gap")
+
+	missing, err = database.GetMissingHeights(db, "betanet", 16, 10)
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+}
@@ -0,0 +1,80 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ====================================== NET PEERS ======================================
+// net peers are written by gnovalidator.WatchPeers, one sample of the peer
+// list of the chain's RPC node at a time, and pruned with the raw
+// participation rows. Every sample also gets a net_peer_samples row with its
+// peer count, so a sample with no peer is recorded too.
+
+// InsertNetPeers stores peers, the sample of the peer list of chainID taken
+// at seenAt, and its peer count. An empty sample stores its count only.
+func InsertNetPeers(db *gorm.DB, chainID string, seenAt time.Time, peers []NetPeer) error {
+	sample := NetPeerSample{ChainID: chainID, SeenAt: seenAt, Peers: len(peers)}
+	for i := range peers {
+		peers[i].ChainID, peers[i].SeenAt = chainID, seenAt
+		if peers[i].Direction == "outbound" {
+			sample.Outbound++
+		}
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&sample).Error; err != nil {
+			return err
+		}
+		if len(peers) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(&peers, 500).Error
+	})
+	if err != nil {
+		return fmt.Errorf("InsertNetPeers: %w", err)
+	}
+	return nil
+}
+
+// GetLatestNetPeers returns the peers of the last sample of chainID stored
+// at or after since, by node ID. It is empty when there is none, or when
+// the node had no peer at all then.
+func GetLatestNetPeers(db *gorm.DB, chainID string, since time.Time) ([]NetPeer, error) {
+	var peers []NetPeer
+	err := db.Raw(`
+		SELECT *
+		FROM net_peers
+		WHERE chain_id = ?
+		  AND seen_at = (SELECT MAX(seen_at) FROM net_peer_samples WHERE chain_id = ? AND seen_at >= ?)
+		ORDER BY node_id
+	`, chainID, chainID, since).Scan(&peers).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetLatestNetPeers: %w", err)
+	}
+	return peers, nil
+}
+
+// PeerCount is how many peers a sample of the peer list had, and how many
+// of them the node dialed.
+type PeerCount struct {
+	SeenAt   time.Time `json:"seen_at"`
+	Peers    int       `json:"peers"`
+	Outbound int       `json:"outbound"`
+}
+
+// GetPeerCounts returns the peer count of every sample of chainID stored at
+// or after since, oldest first, samples with no peer included.
+func GetPeerCounts(db *gorm.DB, chainID string, since time.Time) ([]PeerCount, error) {
+	var counts []PeerCount
+	err := db.Model(&NetPeerSample{}).
+		Select("seen_at, peers, outbound").
+		Where("chain_id = ? AND seen_at >= ?", chainID, since).
+		Order("seen_at ASC").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetPeerCounts: %w", err)
+	}
+	return counts, nil
+}
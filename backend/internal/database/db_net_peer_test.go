@@ -0,0 +1,69 @@
+package database_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/testoutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetPeers(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+	const chain = "test12"
+	now := time.Now().UTC().Truncate(time.Second)
+	before := now.Add(-time.Minute)
+
+	require.NoError(t, database.InsertNetPeers(db, chain, before, []database.NetPeer{
+		{NodeID: "a", Direction: "outbound"},
+		{NodeID: "b", Direction: "inbound"},
+		{NodeID: "c", Direction: "inbound"},
+	}))
+	require.NoError(t, database.InsertNetPeers(db, chain, now, []database.NetPeer{
+		{NodeID: "b", Direction: "inbound", SendRate: 10, RecvRate: 20},
+		{NodeID: "a", Direction: "outbound"},
+	}))
+	require.NoError(t, database.InsertNetPeers(db, "other", now.Add(time.Minute), []database.NetPeer{
+		{NodeID: "z", Direction: "inbound"},
+	}))
+
+	peers, err := database.GetLatestNetPeers(db, chain, now.Add(-5*time.Minute))
+	require.NoError(t, err)
+	require.Len(t, peers, 2, "last sample only")
+	assert.Equal(t, "a", peers[0].NodeID)
+	assert.Equal(t, int64(20), peers[1].RecvRate)
+
+	peers, err = database.GetLatestNetPeers(db, chain, now.Add(time.Second))
+	require.NoError(t, err)
+	assert.Empty(t, peers, "no recent sample")
+
+	counts, err := database.GetPeerCounts(db, chain, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, counts, 2)
+	assert.Equal(t, 3, counts[0].Peers, "oldest first")
+	assert.Equal(t, 1, counts[0].Outbound)
+	assert.Equal(t, 2, counts[1].Peers)
+}
+
+func TestNetPeers_EmptySample(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+	const chain = "test12"
+	now := time.Now().UTC().Truncate(time.Second)
+
+	require.NoError(t, database.InsertNetPeers(db, chain, now.Add(-time.Minute), []database.NetPeer{
+		{NodeID: "a", Direction: "outbound"},
+	}))
+	require.NoError(t, database.InsertNetPeers(db, chain, now, nil))
+
+	peers, err := database.GetLatestNetPeers(db, chain, now.Add(-5*time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, peers, "the last sample had no peer, not the one before")
+
+	counts, err := database.GetPeerCounts(db, chain, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, counts, 2)
+	assert.Equal(t, 1, counts[0].Peers)
+	assert.Equal(t, 0, counts[1].Peers, "a sample with no peer is charted")
+}
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ====================================== NODE VERSIONS ======================================
+// node versions are written by gnovalidator.WatchNodeVersions from the peer
+// list of the chain's RPC node and the Status of the peers that serve RPC.
+
+// UpsertNodeVersions records nodes as seen now with their versions. A node
+// seen again with the same version only moves its last_seen.
+func UpsertNodeVersions(db *gorm.DB, nodes []NodeVersion) error {
+	if len(nodes) == 0 {
+		return nil
+	}
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chain_id"}, {Name: "node_id"}, {Name: "version"}},
+		DoUpdates: clause.AssignmentColumns([]string{"moniker", "addr", "remote_ip", "last_seen"}),
+	}).Create(&nodes).Error
+	if err != nil {
+		return fmt.Errorf("UpsertNodeVersions: %w", err)
+	}
+	return nil
+}
+
+// GetCurrentNodeVersions returns, for each node of chainID seen at or after
+// since, the version it was last seen with, by node ID.
+func GetCurrentNodeVersions(db *gorm.DB, chainID string, since time.Time) ([]NodeVersion, error) {
+	var nodes []NodeVersion
+	err := db.Raw(`
+		SELECT DISTINCT ON (node_id) *
+		FROM node_versions
+		WHERE chain_id = ? AND last_seen >= ?
+		ORDER BY node_id, last_seen DESC
+	`, chainID, since).Scan(&nodes).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetCurrentNodeVersions: %w", err)
+	}
+	return nodes, nil
+}
+
+// GetNodeVersionHistory returns the versions node nodeID of chainID was
+// seen with, oldest first.
+func GetNodeVersionHistory(db *gorm.DB, chainID, nodeID string) ([]NodeVersion, error) {
+	var nodes []NodeVersion
+	err := db.Where("chain_id = ? AND node_id = ?", chainID, nodeID).
+		Order("first_seen ASC").
+		Find(&nodes).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetNodeVersionHistory: %w", err)
+	}
+	return nodes, nil
+}
@@ -0,0 +1,46 @@
+package database_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/testoutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeVersions(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+	const chain = "test12"
+	now := time.Now().UTC().Truncate(time.Second)
+	dayAgo := now.Add(-24 * time.Hour)
+
+	require.NoError(t, database.UpsertNodeVersions(db, []database.NodeVersion{
+		{ChainID: chain, NodeID: "a", Version: "v1", FirstSeen: dayAgo, LastSeen: dayAgo},
+		{ChainID: chain, NodeID: "b", Version: "v1", FirstSeen: dayAgo, LastSeen: dayAgo},
+		{ChainID: chain, NodeID: "old", Version: "v0", FirstSeen: now.AddDate(0, 0, -7), LastSeen: now.AddDate(0, 0, -7)},
+		{ChainID: "other", NodeID: "a", Version: "v9", FirstSeen: now, LastSeen: now},
+	}))
+	// Node a upgrades, node b is seen again on the same version.
+	require.NoError(t, database.UpsertNodeVersions(db, []database.NodeVersion{
+		{ChainID: chain, NodeID: "a", Version: "v2", Addr: "g1a", FirstSeen: now, LastSeen: now},
+		{ChainID: chain, NodeID: "b", Version: "v1", Moniker: "bee", FirstSeen: now, LastSeen: now},
+	}))
+
+	nodes, err := database.GetCurrentNodeVersions(db, chain, now.Add(-48*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, nodes, 2, "stale nodes are left out")
+	assert.Equal(t, "a", nodes[0].NodeID)
+	assert.Equal(t, "v2", nodes[0].Version, "latest version of a node")
+	assert.Equal(t, "g1a", nodes[0].Addr)
+	assert.Equal(t, "bee", nodes[1].Moniker)
+	assert.True(t, nodes[1].FirstSeen.Equal(dayAgo), "first_seen kept")
+	assert.True(t, nodes[1].LastSeen.Equal(now), "last_seen moved")
+
+	history, err := database.GetNodeVersionHistory(db, chain, "a")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "v1", history[0].Version, "oldest first")
+	assert.Equal(t, "v2", history[1].Version)
+}
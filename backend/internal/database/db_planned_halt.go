@@ -0,0 +1,155 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ====================================== PLANNED HALTS ======================================
+// planned halts are registered by admins ahead of a coordinated upgrade and
+// followed by gnovalidator.WatchPlannedHalts until the rejoin report of the
+// validators is sent.
+
+// CreatePlannedHalt validates and inserts h.
+func CreatePlannedHalt(db *gorm.DB, h *PlannedHalt) error {
+	if h.ChainID == "" {
+		return errors.New("chain_id is required")
+	}
+	if h.HaltHeight <= 0 {
+		return errors.New("halt_height must be positive")
+	}
+	if h.WindowMinutes <= 0 {
+		return errors.New("window_minutes must be positive")
+	}
+	if err := db.Create(h).Error; err != nil {
+		return fmt.Errorf("CreatePlannedHalt: %w", err)
+	}
+	return nil
+}
+
+// ListPlannedHalts returns the planned halts of chainID, highest halt height
+// first. Pass empty chainID to list every chain.
+func ListPlannedHalts(db *gorm.DB, chainID string) ([]PlannedHalt, error) {
+	q := db.Model(&PlannedHalt{}).Order("halt_height desc")
+	if chainID != "" {
+		q = q.Where("chain_id = ?", chainID)
+	}
+	var rows []PlannedHalt
+	if err := q.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("ListPlannedHalts: %w", err)
+	}
+	return rows, nil
+}
+
+// GetOpenPlannedHalts returns the planned halts of chainID whose rejoin
+// report has not been sent yet, lowest halt height first.
+func GetOpenPlannedHalts(db *gorm.DB, chainID string) ([]PlannedHalt, error) {
+	var rows []PlannedHalt
+	err := db.Where("chain_id = ? AND reported_at IS NULL", chainID).
+		Order("halt_height asc").
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetOpenPlannedHalts: %w", err)
+	}
+	return rows, nil
+}
+
+// GetPlannedHaltAt returns the planned halt of chainID a chain stuck at
+// height is stopped for, or nil when there is none. A chain may stop
+// after committing the halt height or just before it, so both count.
+func GetPlannedHaltAt(db *gorm.DB, chainID string, height int64) (*PlannedHalt, error) {
+	var rows []PlannedHalt
+	err := db.Where("chain_id = ? AND halt_height BETWEEN ? AND ? AND resumed_at IS NULL", chainID, height, height+1).
+		Limit(1).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetPlannedHaltAt: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return &rows[0], nil
+}
+
+// GetLastResumedHalt returns the planned halt of chainID the chain last
+// restarted from at or after since, or nil when there is none.
+func GetLastResumedHalt(db *gorm.DB, chainID string, since time.Time) (*PlannedHalt, error) {
+	var rows []PlannedHalt
+	err := db.Where("chain_id = ? AND resumed_at >= ?", chainID, since).
+		Order("resumed_at desc").
+		Limit(1).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetLastResumedHalt: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return &rows[0], nil
+}
+
+// UpdatePlannedHaltProgress stores the countdown and restart fields of h.
+func UpdatePlannedHaltProgress(db *gorm.DB, h *PlannedHalt) error {
+	err := db.Model(&PlannedHalt{}).Where("id = ?", h.ID).Updates(map[string]any{
+		"notified_stage": h.NotifiedStage,
+		"halted_at":      h.HaltedAt,
+		"resumed_at":     h.ResumedAt,
+		"reported_at":    h.ReportedAt,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("UpdatePlannedHaltProgress: %w", err)
+	}
+	return nil
+}
+
+// DeletePlannedHalt cancels planned halt id. Returns gorm.ErrRecordNotFound
+// when there is no such halt.
+func DeletePlannedHalt(db *gorm.DB, id uint) error {
+	res := db.Delete(&PlannedHalt{}, id)
+	if res.Error != nil {
+		return fmt.Errorf("DeletePlannedHalt: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ValidatorRejoin is when a validator of the set at a planned halt signed
+// again after the chain restarted. FirstHeight and FirstSigned are nil
+// while it has not.
+type ValidatorRejoin struct {
+	Addr        string     `json:"addr"`
+	Moniker     string     `json:"moniker"`
+	FirstHeight *int64     `json:"first_height"`
+	FirstSigned *time.Time `json:"first_signed"`
+}
+
+// GetValidatorRejoins returns, for each validator of chainID at the last
+// stored height up to haltHeight, the first block after it the validator
+// signed. Validators that signed again come first, fastest first.
+func GetValidatorRejoins(db *gorm.DB, chainID string, haltHeight int64) ([]ValidatorRejoin, error) {
+	var rows []ValidatorRejoin
+	err := db.Raw(`
+		SELECT v.addr,
+			COALESCE(MAX(am.moniker), MAX(v.moniker), '') AS moniker,
+			MIN(dp.block_height) AS first_height,
+			MIN(dp.date) AS first_signed
+		FROM daily_participations v
+		LEFT JOIN daily_participations dp
+			ON dp.chain_id = v.chain_id AND dp.addr = v.addr
+			AND dp.block_height > v.block_height AND dp.participated
+		LEFT JOIN addr_monikers am ON am.chain_id = v.chain_id AND am.addr = v.addr
+		WHERE v.chain_id = ?
+		  AND v.block_height = (SELECT MAX(block_height) FROM daily_participations WHERE chain_id = ? AND block_height <= ?)
+		GROUP BY v.addr
+		ORDER BY first_height ASC NULLS LAST, v.addr ASC
+	`, chainID, chainID, haltHeight).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetValidatorRejoins: %w", err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,103 @@
+package database_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/testoutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestPlannedHalts(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+	const chain = "test12"
+
+	require.Error(t, database.CreatePlannedHalt(db, &database.PlannedHalt{ChainID: chain, WindowMinutes: 60}), "no height")
+	require.Error(t, database.CreatePlannedHalt(db, &database.PlannedHalt{ChainID: chain, HaltHeight: 500}), "no window")
+
+	first := database.PlannedHalt{ChainID: chain, HaltHeight: 500, WindowMinutes: 60, CreatedBy: "admin:u1"}
+	second := database.PlannedHalt{ChainID: chain, HaltHeight: 900, WindowMinutes: 60, CreatedBy: "admin:u1"}
+	require.NoError(t, database.CreatePlannedHalt(db, &first))
+	require.NoError(t, database.CreatePlannedHalt(db, &second))
+	require.NoError(t, database.CreatePlannedHalt(db, &database.PlannedHalt{ChainID: "other", HaltHeight: 500, WindowMinutes: 60, CreatedBy: "admin:u1"}))
+	require.Error(t, database.CreatePlannedHalt(db, &database.PlannedHalt{ChainID: chain, HaltHeight: 500, WindowMinutes: 60, CreatedBy: "admin:u1"}), "one halt per height")
+
+	halts, err := database.ListPlannedHalts(db, chain)
+	require.NoError(t, err)
+	require.Len(t, halts, 2)
+	assert.Equal(t, int64(900), halts[0].HaltHeight, "highest first")
+	all, err := database.ListPlannedHalts(db, "")
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+
+	// A chain stopped after or just before committing the halt height.
+	for height, want := range map[int64]bool{498: false, 499: true, 500: true, 501: false} {
+		h, err := database.GetPlannedHaltAt(db, chain, height)
+		require.NoError(t, err)
+		assert.Equal(t, want, h != nil, "height %d", height)
+	}
+
+	resumed := time.Now().UTC().Truncate(time.Second)
+	first.NotifiedStage = 4
+	first.HaltedAt = &resumed
+	first.ResumedAt = &resumed
+	require.NoError(t, database.UpdatePlannedHaltProgress(db, &first))
+	h, err := database.GetPlannedHaltAt(db, chain, 500)
+	require.NoError(t, err)
+	assert.Nil(t, h, "resumed")
+
+	last, err := database.GetLastResumedHalt(db, chain, resumed.Add(-time.Hour))
+	require.NoError(t, err)
+	require.NotNil(t, last)
+	assert.Equal(t, first.ID, last.ID)
+	assert.Equal(t, 4, last.NotifiedStage)
+
+	first.ReportedAt = &resumed
+	require.NoError(t, database.UpdatePlannedHaltProgress(db, &first))
+	open, err := database.GetOpenPlannedHalts(db, chain)
+	require.NoError(t, err)
+	require.Len(t, open, 1)
+	assert.Equal(t, second.ID, open[0].ID)
+
+	require.NoError(t, database.DeletePlannedHalt(db, second.ID))
+	assert.True(t, errors.Is(database.DeletePlannedHalt(db, second.ID), gorm.ErrRecordNotFound))
+}
+
+func TestGetValidatorRejoins(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+	const chain = "test12"
+	resumed := time.Now().UTC().Truncate(time.Second)
+
+	var rows []database.DailyParticipation
+	add := func(addr string, height int64, participated bool, at time.Time) {
+		rows = append(rows, database.DailyParticipation{ChainID: chain, Addr: addr, BlockHeight: height, Participated: participated, Date: at, Moniker: addr + "-moniker"})
+	}
+	// The chain stopped at 100; g1c joined the set after the restart.
+	for _, addr := range []string{"g1a", "g1b", "g1d"} {
+		add(addr, 100, true, resumed.Add(-time.Hour))
+	}
+	add("g1a", 101, true, resumed)
+	add("g1b", 101, false, resumed)
+	add("g1d", 101, false, resumed)
+	add("g1b", 102, false, resumed.Add(2*time.Second))
+	add("g1b", 103, true, resumed.Add(4*time.Second))
+	add("g1c", 103, true, resumed.Add(4*time.Second))
+	require.NoError(t, db.Create(&rows).Error)
+
+	rejoins, err := database.GetValidatorRejoins(db, chain, 100)
+	require.NoError(t, err)
+	require.Len(t, rejoins, 3)
+	assert.Equal(t, "g1a", rejoins[0].Addr)
+	require.NotNil(t, rejoins[0].FirstHeight)
+	assert.Equal(t, int64(101), *rejoins[0].FirstHeight)
+	assert.Equal(t, "g1b", rejoins[1].Addr)
+	require.NotNil(t, rejoins[1].FirstSigned)
+	assert.True(t, rejoins[1].FirstSigned.Equal(resumed.Add(4*time.Second)))
+	assert.Equal(t, "g1d", rejoins[2].Addr)
+	assert.Nil(t, rejoins[2].FirstHeight, "not signing yet")
+	assert.Equal(t, "g1d-moniker", rejoins[2].Moniker)
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ====================================== MISSED PROPOSALS ======================================
+// missed proposals are the rounds a height went through before its commit,
+// attributed to the validator whose turn it was to propose. They are written
+// by the realtime loop (gnovalidator.CollectParticipation) from the commit
+// round of each block.
+
+// MissedProposalCount is the number of missed proposer slots of a validator.
+type MissedProposalCount struct {
+	Addr       string    `json:"addr"`
+	Moniker    string    `json:"moniker"`
+	Count      int64     `json:"count"`
+	LastHeight int64     `json:"last_height"`
+	LastTime   time.Time `json:"last_time"`
+}
+
+// InsertMissedProposals stores mps. Rounds already recorded for a height
+// are left as they are, so a re-processed block is harmless.
+func InsertMissedProposals(db *gorm.DB, mps []MissedProposal) error {
+	if len(mps) == 0 {
+		return nil
+	}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&mps).Error; err != nil {
+		return fmt.Errorf("InsertMissedProposals: %w", err)
+	}
+	return nil
+}
+
+// GetMissedProposals returns the missed proposals of chainID committed at
+// or after since, newest first. addr, when not empty, keeps only that
+// validator's; limit <= 0 means no limit.
+func GetMissedProposals(db *gorm.DB, chainID, addr string, since time.Time, limit int) ([]MissedProposal, error) {
+	q := db.Where("chain_id = ? AND committed_at >= ?", chainID, since)
+	if addr != "" {
+		q = q.Where("addr = ?", addr)
+	}
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	var mps []MissedProposal
+	if err := q.Order("height DESC, round DESC").Find(&mps).Error; err != nil {
+		return nil, fmt.Errorf("GetMissedProposals: %w", err)
+	}
+	return mps, nil
+}
+
+// CountMissedProposals returns, per validator, the missed proposals of
+// chainID committed at or after since, most missed first.
+func CountMissedProposals(db *gorm.DB, chainID string, since time.Time) ([]MissedProposalCount, error) {
+	var counts []MissedProposalCount
+	err := db.Raw(`
+		SELECT mp.addr,
+		       COALESCE(NULLIF(MAX(am.moniker), ''), MAX(mp.moniker), '') AS moniker,
+		       COUNT(*)             AS count,
+		       MAX(mp.height)       AS last_height,
+		       MAX(mp.committed_at) AS last_time
+		FROM missed_proposals mp
+		LEFT JOIN addr_monikers am ON am.chain_id = mp.chain_id AND am.addr = mp.addr
+		WHERE mp.chain_id = ? AND mp.committed_at >= ?
+		GROUP BY mp.addr
+		ORDER BY count DESC, mp.addr
+	`, chainID, since).Scan(&counts).Error
+	if err != nil {
+		return nil, fmt.Errorf("CountMissedProposals: %w", err)
+	}
+	return counts, nil
+}
@@ -0,0 +1,50 @@
+package database_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/testoutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMissedProposals(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+	const chain = "test12"
+	now := time.Now().UTC().Truncate(time.Second)
+
+	require.NoError(t, database.InsertMissedProposals(db, []database.MissedProposal{
+		{ChainID: chain, Height: 100, Round: 0, Addr: "g1slow", Moniker: "slow", CommitRound: 2, CommittedAt: now.Add(-time.Hour)},
+		{ChainID: chain, Height: 100, Round: 1, Addr: "g1down", Moniker: "down", CommitRound: 2, CommittedAt: now.Add(-time.Hour)},
+		{ChainID: chain, Height: 120, Round: 0, Addr: "g1down", Moniker: "down", CommitRound: 1, CommittedAt: now},
+		{ChainID: "other", Height: 120, Round: 0, Addr: "g1down", Moniker: "down", CommitRound: 1, CommittedAt: now},
+	}))
+	// Re-processing a block keeps the rows it already wrote.
+	require.NoError(t, database.InsertMissedProposals(db, []database.MissedProposal{
+		{ChainID: chain, Height: 120, Round: 0, Addr: "g1down", Moniker: "down", CommitRound: 1, CommittedAt: now},
+	}))
+
+	mps, err := database.GetMissedProposals(db, chain, "", now.Add(-24*time.Hour), 0)
+	require.NoError(t, err)
+	require.Len(t, mps, 3)
+	assert.Equal(t, int64(120), mps[0].Height)
+	assert.Equal(t, 1, mps[1].Round, "rounds of a height come newest first")
+
+	mps, err = database.GetMissedProposals(db, chain, "g1slow", now.Add(-24*time.Hour), 0)
+	require.NoError(t, err)
+	require.Len(t, mps, 1)
+
+	mps, err = database.GetMissedProposals(db, chain, "", now.Add(-time.Minute), 0)
+	require.NoError(t, err)
+	assert.Len(t, mps, 1)
+
+	counts, err := database.CountMissedProposals(db, chain, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, counts, 2)
+	assert.Equal(t, "g1down", counts[0].Addr)
+	assert.Equal(t, int64(2), counts[0].Count)
+	assert.Equal(t, int64(120), counts[0].LastHeight)
+	assert.Equal(t, "slow", counts[1].Moniker)
+}
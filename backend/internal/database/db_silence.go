@@ -0,0 +1,126 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ====================================== SILENCES ======================================
+// silences mute alert notifications for a validator or a whole chain during a
+// maintenance window. Silences created by admins are global: the alert loops
+// consult ActiveSilence before sending. Any other silence is personal: it
+// only mutes the destinations of its creator, which EnqueueAlert checks
+// through PersonalSilences.
+
+// AdminSilencePrefix starts the CreatedBy of the silences admins create,
+// the only global ones.
+const AdminSilencePrefix = "admin:"
+
+// MaxSilenceDuration caps silences created through the user API and Telegram;
+// admins may create longer ones.
+const MaxSilenceDuration = 7 * 24 * time.Hour
+
+// CreateSilence validates and inserts s. A zero StartAt means "now".
+func CreateSilence(db *gorm.DB, s *Silence) error {
+	if s.ChainID == "" || s.Addr == "" {
+		return errors.New("chain_id and addr are required")
+	}
+	if s.StartAt.IsZero() {
+		s.StartAt = time.Now()
+	}
+	if !s.EndAt.After(s.StartAt) {
+		return errors.New("end_at must be after start_at")
+	}
+	if !s.EndAt.After(time.Now()) {
+		return errors.New("end_at must be in the future")
+	}
+	if err := db.Create(s).Error; err != nil {
+		return fmt.Errorf("CreateSilence: %w", err)
+	}
+	return nil
+}
+
+// ListSilences returns silences that have not ended at now (active and
+// upcoming), soonest end first. Pass empty chainID to list every chain.
+// When createdBy is not empty, only the global silences and those of
+// createdBy are listed.
+func ListSilences(db *gorm.DB, chainID, createdBy string, now time.Time) ([]Silence, error) {
+	q := db.Model(&Silence{}).Where("end_at > ?", now).Order("end_at asc")
+	if chainID != "" {
+		q = q.Where("chain_id = ?", chainID)
+	}
+	if createdBy != "" {
+		q = q.Where("created_by LIKE ? OR created_by = ?", AdminSilencePrefix+"%", createdBy)
+	}
+	var rows []Silence
+	if err := q.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ActiveSilence returns the global silence covering addr on chainID at time
+// at, or nil when there is none. A chain-wide silence (addr "all") covers
+// every validator of the chain; a validator silence never covers
+// chain-level alerts (addr "all"). When several match, the one ending last
+// wins.
+func ActiveSilence(db *gorm.DB, chainID, addr string, at time.Time) (*Silence, error) {
+	var rows []Silence
+	err := db.Where("chain_id = ? AND addr IN (?, 'all') AND start_at <= ? AND end_at > ? AND created_by LIKE ?",
+		chainID, addr, at, at, AdminSilencePrefix+"%").
+		Order("end_at desc").
+		Limit(1).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("ActiveSilence: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return &rows[0], nil
+}
+
+// PersonalSilences returns, by creator, the ID of the personal silence
+// covering addr on chainID at time at, with the same matching rules as
+// ActiveSilence.
+func PersonalSilences(db *gorm.DB, chainID, addr string, at time.Time) (map[string]uint, error) {
+	var rows []Silence
+	err := db.Where("chain_id = ? AND addr IN (?, 'all') AND start_at <= ? AND end_at > ? AND created_by NOT LIKE ?",
+		chainID, addr, at, at, AdminSilencePrefix+"%").
+		Order("end_at asc").
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("PersonalSilences: %w", err)
+	}
+	out := make(map[string]uint, len(rows))
+	for _, r := range rows {
+		out[r.CreatedBy] = r.ID
+	}
+	return out, nil
+}
+
+// EndSilence ends silence id now; an upcoming silence is ended before it
+// starts. Rows are kept so alert_logs.silence_id stays resolvable. When
+// createdBy is not empty only a silence created by it can be ended.
+// Returns gorm.ErrRecordNotFound when no matching silence is still running.
+func EndSilence(db *gorm.DB, id uint, createdBy string) error {
+	now := time.Now()
+	q := db.Model(&Silence{}).Where("id = ? AND end_at > ?", id, now)
+	if createdBy != "" {
+		q = q.Where("created_by = ?", createdBy)
+	}
+	res := q.Updates(map[string]any{
+		"end_at":   now,
+		"start_at": gorm.Expr("LEAST(start_at, ?)", now),
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
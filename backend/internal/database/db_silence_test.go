@@ -0,0 +1,96 @@
+package database_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/testoutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// TestActiveSilence checks that a chain-wide silence covers validators, a
+// validator silence does not cover chain-level alerts, and ended silences no
+// longer match.
+func TestActiveSilence(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+	now := time.Now()
+
+	chain := database.Silence{ChainID: "test12", Addr: "all", EndAt: now.Add(time.Hour), CreatedBy: "admin:u1"}
+	val := database.Silence{ChainID: "test12", Addr: "g1val", EndAt: now.Add(2 * time.Hour), CreatedBy: "admin:u2"}
+	require.NoError(t, database.CreateSilence(db, &chain))
+	require.NoError(t, database.CreateSilence(db, &val))
+
+	s, err := database.ActiveSilence(db, "test12", "g1val", now.Add(time.Minute))
+	require.NoError(t, err)
+	require.NotNil(t, s)
+	assert.Equal(t, val.ID, s.ID, "the silence ending last wins")
+
+	s, err = database.ActiveSilence(db, "test12", "all", now.Add(time.Minute))
+	require.NoError(t, err)
+	require.NotNil(t, s)
+	assert.Equal(t, chain.ID, s.ID, "a validator silence never covers chain-level alerts")
+
+	s, err = database.ActiveSilence(db, "othernet", "g1val", now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Nil(t, s)
+
+	assert.ErrorIs(t, database.EndSilence(db, val.ID, "admin:u1"), gorm.ErrRecordNotFound, "only the creator can end it")
+	require.NoError(t, database.EndSilence(db, val.ID, "admin:u2"))
+	require.NoError(t, database.EndSilence(db, chain.ID, ""))
+
+	s, err = database.ActiveSilence(db, "test12", "g1val", time.Now().Add(time.Second))
+	require.NoError(t, err)
+	assert.Nil(t, s)
+
+	silences, err := database.ListSilences(db, "test12", "", time.Now().Add(time.Second))
+	require.NoError(t, err)
+	assert.Empty(t, silences)
+}
+
+// TestPersonalSilences checks that silences created by users and chats only
+// mute their creator: they are not global, are listed to their creator only,
+// and PersonalSilences reports them by creator.
+func TestPersonalSilences(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+	now := time.Now()
+
+	mine := database.Silence{ChainID: "test12", Addr: "all", EndAt: now.Add(time.Hour), CreatedBy: "u1"}
+	chat := database.Silence{ChainID: "test12", Addr: "g1val", EndAt: now.Add(time.Hour), CreatedBy: "telegram:42"}
+	global := database.Silence{ChainID: "test12", Addr: "g1other", EndAt: now.Add(time.Hour), CreatedBy: "admin:root"}
+	for _, s := range []*database.Silence{&mine, &chat, &global} {
+		require.NoError(t, database.CreateSilence(db, s))
+	}
+
+	s, err := database.ActiveSilence(db, "test12", "g1val", now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Nil(t, s, "a personal silence is not global")
+
+	muted, err := database.PersonalSilences(db, "test12", "g1val", now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]uint{"u1": mine.ID, "telegram:42": chat.ID}, muted)
+
+	muted, err = database.PersonalSilences(db, "test12", "all", now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]uint{"u1": mine.ID}, muted, "a validator silence never covers chain-level alerts")
+
+	listed, err := database.ListSilences(db, "test12", "telegram:42", now)
+	require.NoError(t, err)
+	var ids []uint
+	for _, l := range listed {
+		ids = append(ids, l.ID)
+	}
+	assert.ElementsMatch(t, []uint{chat.ID, global.ID}, ids, "own and global silences only")
+}
+
+func TestCreateSilence_Validation(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+
+	past := database.Silence{ChainID: "test12", Addr: "all", EndAt: time.Now().Add(-time.Minute), CreatedBy: "u1"}
+	assert.Error(t, database.CreateSilence(db, &past))
+
+	missingAddr := database.Silence{ChainID: "test12", EndAt: time.Now().Add(time.Hour), CreatedBy: "u1"}
+	assert.Error(t, database.CreateSilence(db, &missingAddr))
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ============================ Slack ===============================================
+// The Slack app keeps the same per-channel state as the Discord bot: the
+// active chain and validator subscriptions.
+
+// EnsureSlackChannel returns the channel record, creating it on chainID the
+// first time the app is used in the channel.
+func EnsureSlackChannel(db *gorm.DB, channelID, teamID, chainID string) (SlackChannel, error) {
+	ch := SlackChannel{ChannelID: channelID}
+	err := db.Where(SlackChannel{ChannelID: channelID}).
+		Attrs(SlackChannel{TeamID: teamID, ChainID: chainID}).
+		FirstOrCreate(&ch).Error
+	if err != nil {
+		return SlackChannel{}, fmt.Errorf("EnsureSlackChannel: %w", err)
+	}
+	return ch, nil
+}
+
+// UpdateSlackChannelChain switches the active chain of a channel.
+func UpdateSlackChannelChain(db *gorm.DB, channelID, chainID string) error {
+	return db.Model(&SlackChannel{}).
+		Where("channel_id = ?", channelID).
+		Update("chain_id", chainID).Error
+}
+
+// GetSlackChannelIDsForChain returns the channels whose active chain is
+// chainID (chain-wide alerts).
+func GetSlackChannelIDsForChain(db *gorm.DB, chainID string) ([]string, error) {
+	var ids []string
+	err := db.Model(&SlackChannel{}).
+		Where("chain_id = ?", chainID).
+		Pluck("channel_id", &ids).Error
+	return ids, err
+}
+
+// ============================ Slack subscriptions =================================
+
+// SetSlackValidatorSub turns a channel's alert subscription to addr on or
+// off. Turning off a subscription that does not exist is a no-op.
+func SetSlackValidatorSub(db *gorm.DB, channelID, chainID, addr, moniker string, activate bool) error {
+	if !activate {
+		return db.Model(&SlackValidatorSub{}).
+			Where("channel_id = ? AND chain_id = ? AND addr = ?", channelID, chainID, addr).
+			Update("activate", false).Error
+	}
+	sub := SlackValidatorSub{ChannelID: channelID, ChainID: chainID, Addr: addr, Moniker: moniker, Activate: true}
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chain_id"}, {Name: "addr"}, {Name: "channel_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"activate", "moniker"}),
+	}).Create(&sub).Error
+	if err != nil {
+		return fmt.Errorf("SetSlackValidatorSub: %w", err)
+	}
+	return nil
+}
+
+// GetSlackValidatorSubs returns a channel's subscriptions on chainID.
+func GetSlackValidatorSubs(db *gorm.DB, channelID, chainID string, onlyActive bool) ([]SlackValidatorSub, error) {
+	q := db.Where("channel_id = ? AND chain_id = ?", channelID, chainID)
+	if onlyActive {
+		q = q.Where("activate = ?", true)
+	}
+	var subs []SlackValidatorSub
+	err := q.Order("moniker asc").Find(&subs).Error
+	return subs, err
+}
+
+// GetSlackValidatorStatusList is GetValidatorStatusList for a Slack channel.
+func GetSlackValidatorStatusList(db *gorm.DB, channelID, chainID string) ([]ValidatorStatus, error) {
+	return validatorStatusList(db, "slack_validator_subs", "channel_id", channelID, chainID)
+}
+
+// GetSlackAlertChannelIDs returns the channels with an active subscription
+// to addr on chainID.
+func GetSlackAlertChannelIDs(db *gorm.DB, chainID, addr string) ([]string, error) {
+	var ids []string
+	err := db.Model(&SlackValidatorSub{}).
+		Where("chain_id = ? AND addr = ? AND activate = ?", chainID, addr, true).
+		Pluck("channel_id", &ids).Error
+	return ids, err
+}
@@ -0,0 +1,38 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/testoutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSlackChannelAndSubs verifies that a Slack channel keeps the chain
+// picked with setchain and that only its active subscriptions receive
+// alerts.
+func TestSlackChannelAndSubs(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+
+	ch, err := database.EnsureSlackChannel(db, "C0123", "T01", "test12")
+	require.NoError(t, err)
+	assert.Equal(t, "test12", ch.ChainID)
+	require.NoError(t, database.UpdateSlackChannelChain(db, "C0123", "gnoland1"))
+	ch, err = database.EnsureSlackChannel(db, "C0123", "T01", "test12")
+	require.NoError(t, err)
+	assert.Equal(t, "gnoland1", ch.ChainID)
+
+	require.NoError(t, database.SetSlackValidatorSub(db, "C0123", "gnoland1", "g1abc", "val-1", true))
+	require.NoError(t, database.SetSlackValidatorSub(db, "C0456", "gnoland1", "g1abc", "val-1", true))
+	require.NoError(t, database.SetSlackValidatorSub(db, "C0456", "gnoland1", "g1abc", "val-1", false))
+
+	ids, err := database.GetSlackAlertChannelIDs(db, "gnoland1", "g1abc")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"C0123"}, ids)
+
+	subs, err := database.GetSlackValidatorSubs(db, "C0456", "gnoland1", false)
+	require.NoError(t, err)
+	require.Len(t, subs, 1)
+	assert.False(t, subs[0].Activate)
+}
@@ -246,12 +246,20 @@ func GetTelegramValidatorSub(db *gorm.DB, chatID int64, chainID string, onlyActi
 }
 
 func GetValidatorStatusList(db *gorm.DB, chatID int64, chainID string) ([]ValidatorStatus, error) {
+	return validatorStatusList(db, "telegram_validator_subs", "chat_id", chatID, chainID)
+}
+
+// validatorStatusList lists every validator of chainID with the on/off status
+// of its subscription in subsTable (telegram_validator_subs,
+// discord_validator_subs or slack_validator_subs) for the chat or channel
+// subject in column.
+func validatorStatusList(db *gorm.DB, subsTable, column string, subject any, chainID string) ([]ValidatorStatus, error) {
 
 	var results []ValidatorStatus
 
 	// UNION raw (last 7 days) + agrega (all history) to keep validators visible
 	// even after raw data is pruned beyond the retention window.
-	query := `
+	query := fmt.Sprintf(`
 		WITH v AS (
 			SELECT DISTINCT addr, COALESCE(
 				(SELECT moniker FROM addr_monikers am WHERE am.chain_id = ? AND am.addr = all_addrs.addr),
@@ -271,14 +279,14 @@ func GetValidatorStatusList(db *gorm.DB, chatID int64, chainID string) ([]Valida
 				ELSE 'off'
 			END AS status
 		FROM v
-		LEFT JOIN telegram_validator_subs s
+		LEFT JOIN %s s
 			ON s.addr = v.addr
 			AND s.chain_id = ?
-			AND s.chat_id = ?
+			AND s.%s = ?
 		ORDER BY status DESC;
-	`
+	`, subsTable, column)
 
-	err := db.Raw(query, chainID, chainID, chainID, chainID, chatID).Scan(&results).Error
+	err := db.Raw(query, chainID, chainID, chainID, chainID, subject).Scan(&results).Error
 	if err != nil {
 		return nil, err
 	}
@@ -323,6 +331,18 @@ func ResolveAddrs(db *gorm.DB, chainID string, addrs []string) ([]AddrMoniker, e
 	return results, nil
 }
 
+// ResolveValidator finds the validator of chainID whose address, or failing
+// that moniker (case-insensitive), is addrOrMoniker. Returns
+// gorm.ErrRecordNotFound when none matches.
+func ResolveValidator(db *gorm.DB, chainID, addrOrMoniker string) (AddrMoniker, error) {
+	var am AddrMoniker
+	err := db.Where("chain_id = ? AND addr = ?", chainID, addrOrMoniker).First(&am).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = db.Where("chain_id = ? AND LOWER(moniker) = LOWER(?)", chainID, addrOrMoniker).First(&am).Error
+	}
+	return am, err
+}
+
 func DeleteTelegramValidatorSub(db *gorm.DB, chatID int64, chainID, addr string) error {
 	return db.
 		Where("chat_id = ? AND chain_id = ? AND addr = ?", chatID, chainID, addr).
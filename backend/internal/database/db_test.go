@@ -65,3 +65,43 @@ func TestUpdateUser(t *testing.T) {
 		t.Errorf("User not updated correctly: %+v", user)
 	}
 }
+
+// TestListEmailAlertUsers checks that alert emails follow the opt-in and go
+// to the chains of the user's webhooks, or the default chain without any.
+func TestListEmailAlertUsers(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+	on := true
+
+	for _, id := range []string{"scoped", "unscoped", "optout"} {
+		if err := database.InsertUser(id, id+"@example.com", id, db); err != nil {
+			t.Fatalf("InsertUser failed: %v", err)
+		}
+	}
+	for _, id := range []string{"scoped", "unscoped"} {
+		if err := database.SetUserEmailOptIn(db, id, &on, nil); err != nil {
+			t.Fatalf("SetUserEmailOptIn failed: %v", err)
+		}
+	}
+	chain := "test11"
+	if err := db.Create(&database.WebhookValidator{UserID: "scoped", URL: "https://discord.com/api/webhooks/1/a", Type: "discord", ChainID: &chain}).Error; err != nil {
+		t.Fatalf("create webhook failed: %v", err)
+	}
+
+	ids := func(chainID string) []string {
+		users, err := database.ListEmailAlertUsers(db, chainID, "test12")
+		if err != nil {
+			t.Fatalf("ListEmailAlertUsers failed: %v", err)
+		}
+		var out []string
+		for _, u := range users {
+			out = append(out, u.UserID)
+		}
+		return out
+	}
+	if got := ids("test11"); len(got) != 1 || got[0] != "scoped" {
+		t.Fatalf("test11 recipients = %v, want [scoped]", got)
+	}
+	if got := ids("test12"); len(got) != 1 || got[0] != "unscoped" {
+		t.Fatalf("test12 (default chain) recipients = %v, want [unscoped]", got)
+	}
+}
@@ -0,0 +1,110 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ====================================== TX WATCHES ======================================
+// tx watches are registered by users from the API or from a Telegram chat,
+// and matched by govdao.WatchTransactions against the transactions of the
+// GraphQL indexer.
+
+// NormalizeWatchTarget trims a watch target and checks it is a bech32
+// address (g1...) or a realm or package path (gno.land/...).
+func NormalizeWatchTarget(target string) (string, error) {
+	target = strings.TrimSuffix(strings.TrimSpace(target), "/")
+	switch {
+	case strings.HasPrefix(target, "g1") && !strings.Contains(target, "/"):
+		if err := validateAddress(target); err != nil {
+			return "", err
+		}
+		return target, nil
+	case strings.HasPrefix(target, "gno.land/r/"), strings.HasPrefix(target, "gno.land/p/"):
+		return target, nil
+	}
+	return "", errors.New("target must be an address (g1...) or a realm or package path (gno.land/r/..., gno.land/p/...)")
+}
+
+// CreateTxWatch validates and inserts w.
+func CreateTxWatch(db *gorm.DB, w *TxWatch) error {
+	if w.CreatedBy == "" {
+		return errors.New("created_by is required")
+	}
+	if w.ChainID == "" {
+		return errors.New("chain_id is required")
+	}
+	target, err := NormalizeWatchTarget(w.Target)
+	if err != nil {
+		return err
+	}
+	w.Target = target
+	if err := db.Create(w).Error; err != nil {
+		return fmt.Errorf("CreateTxWatch: %w", err)
+	}
+	return nil
+}
+
+// ListTxWatches returns the watches createdBy registered, oldest first. Pass
+// empty chainID to list every chain.
+func ListTxWatches(db *gorm.DB, createdBy, chainID string) ([]TxWatch, error) {
+	q := db.Where("created_by = ?", createdBy)
+	if chainID != "" {
+		q = q.Where("chain_id = ?", chainID)
+	}
+	var rows []TxWatch
+	if err := q.Order("id asc").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("ListTxWatches: %w", err)
+	}
+	return rows, nil
+}
+
+// TxWatchesForChain returns every watch of chainID.
+func TxWatchesForChain(db *gorm.DB, chainID string) ([]TxWatch, error) {
+	var rows []TxWatch
+	if err := db.Where("chain_id = ?", chainID).Order("id asc").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("TxWatchesForChain: %w", err)
+	}
+	return rows, nil
+}
+
+// DeleteTxWatch removes watch id of createdBy. Returns gorm.ErrRecordNotFound
+// when createdBy has no such watch.
+func DeleteTxWatch(db *gorm.DB, id uint, createdBy string) error {
+	res := db.Where("id = ? AND created_by = ?", id, createdBy).Delete(&TxWatch{})
+	if res.Error != nil {
+		return fmt.Errorf("DeleteTxWatch: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetTxWatchCursor returns the last block height WatchTransactions read on
+// chainID, or 0 when it has not read any yet.
+func GetTxWatchCursor(db *gorm.DB, chainID string) (int64, error) {
+	var c TxWatchCursor
+	if err := db.Where("chain_id = ?", chainID).Limit(1).Find(&c).Error; err != nil {
+		return 0, fmt.Errorf("GetTxWatchCursor: %w", err)
+	}
+	return c.LastHeight, nil
+}
+
+// SetTxWatchCursor stores height as the last block height WatchTransactions
+// read on chainID.
+func SetTxWatchCursor(db *gorm.DB, chainID string, height int64) error {
+	c := TxWatchCursor{ChainID: chainID, LastHeight: height}
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chain_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"last_height"}),
+	}).Create(&c).Error
+	if err != nil {
+		return fmt.Errorf("SetTxWatchCursor: %w", err)
+	}
+	return nil
+}
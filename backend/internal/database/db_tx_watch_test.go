@@ -0,0 +1,84 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/testoutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestNormalizeWatchTarget(t *testing.T) {
+	for in, want := range map[string]string{
+		" gno.land/r/gov/dao/ ":                    "gno.land/r/gov/dao",
+		"gno.land/r/sys/validators/v2":             "gno.land/r/sys/validators/v2",
+		"gno.land/p/demo/avl":                      "gno.land/p/demo/avl",
+		"g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5": "g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5",
+	} {
+		got, err := database.NormalizeWatchTarget(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got)
+	}
+	for _, bad := range []string{"", "gov/dao", "gno.land/x/foo", "g1abc/def", "g1abc", "https://gno.land/r/gov/dao"} {
+		_, err := database.NormalizeWatchTarget(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestTxWatches(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+
+	for _, bad := range []database.TxWatch{
+		{ChainID: "test12", Target: "gno.land/r/gov/dao"},
+		{CreatedBy: "u1", Target: "gno.land/r/gov/dao"},
+		{CreatedBy: "u1", ChainID: "test12", Target: "nope"},
+	} {
+		assert.Error(t, database.CreateTxWatch(db, &bad))
+	}
+
+	w := database.TxWatch{CreatedBy: "u1", ChainID: "test12", Target: "gno.land/r/gov/dao/", Label: "dao"}
+	require.NoError(t, database.CreateTxWatch(db, &w))
+	assert.Equal(t, "gno.land/r/gov/dao", w.Target)
+	require.NoError(t, database.CreateTxWatch(db, &database.TxWatch{CreatedBy: "telegram:42", ChainID: "test12", Target: "gno.land/r/gov/dao"}))
+	require.NoError(t, database.CreateTxWatch(db, &database.TxWatch{CreatedBy: "u1", ChainID: "other", Target: testAddr1}))
+	assert.Error(t, database.CreateTxWatch(db, &database.TxWatch{CreatedBy: "u1", ChainID: "test12", Target: "gno.land/r/gov/dao"}), "duplicate")
+
+	mine, err := database.ListTxWatches(db, "u1", "")
+	require.NoError(t, err)
+	require.Len(t, mine, 2)
+	assert.Equal(t, "dao", mine[0].Label)
+
+	mine, err = database.ListTxWatches(db, "u1", "other")
+	require.NoError(t, err)
+	require.Len(t, mine, 1)
+	assert.Equal(t, testAddr1, mine[0].Target)
+
+	onChain, err := database.TxWatchesForChain(db, "test12")
+	require.NoError(t, err)
+	assert.Len(t, onChain, 2)
+
+	assert.ErrorIs(t, database.DeleteTxWatch(db, w.ID, "telegram:42"), gorm.ErrRecordNotFound, "not the owner")
+	require.NoError(t, database.DeleteTxWatch(db, w.ID, "u1"))
+	assert.ErrorIs(t, database.DeleteTxWatch(db, w.ID, "u1"), gorm.ErrRecordNotFound)
+}
+
+func TestTxWatchCursor(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+
+	last, err := database.GetTxWatchCursor(db, "test12")
+	require.NoError(t, err)
+	assert.Zero(t, last, "no cursor yet")
+
+	require.NoError(t, database.SetTxWatchCursor(db, "test12", 1200))
+	require.NoError(t, database.SetTxWatchCursor(db, "other", 7))
+	require.NoError(t, database.SetTxWatchCursor(db, "test12", 1400))
+
+	last, err = database.GetTxWatchCursor(db, "test12")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1400), last)
+	last, err = database.GetTxWatchCursor(db, "other")
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), last, "one cursor per chain")
+}
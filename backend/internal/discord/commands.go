@@ -0,0 +1,361 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/telegram"
+	"gorm.io/gorm"
+)
+
+// Application command option types.
+const (
+	optionSubCommand = 1
+	optionString     = 3
+	optionInteger    = 4
+	optionBoolean    = 5
+)
+
+// permManageChannels is the default_member_permissions of the commands that
+// change a channel's state (/subscribe, /report, /setchain): only members
+// who can manage the channel see them until a server admin says otherwise.
+const permManageChannels = "16"
+
+// Command is a slash command definition, as registered with Discord.
+type Command struct {
+	Name                     string          `json:"name"`
+	Description              string          `json:"description"`
+	Options                  []CommandOption `json:"options,omitempty"`
+	DefaultMemberPermissions *string         `json:"default_member_permissions,omitempty"`
+}
+
+// CommandOption is an option (or subcommand) of a slash command.
+type CommandOption struct {
+	Type        int             `json:"type"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Required    bool            `json:"required,omitempty"`
+	Choices     []OptionChoice  `json:"choices,omitempty"`
+	Options     []CommandOption `json:"options,omitempty"`
+	MinValue    *int            `json:"min_value,omitempty"`
+	MaxValue    *int            `json:"max_value,omitempty"`
+}
+
+// OptionChoice is one fixed value of a string option.
+type OptionChoice struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func intPtr(v int) *int { return &v }
+
+// maxOptionChoices is Discord's limit on the choices of an option.
+const maxOptionChoices = 25
+
+// Commands returns the slash commands of the bot. They mirror the Telegram
+// validator commands; the key=value arguments become typed options, which
+// also replaces the interactive /cmd menu.
+func Commands(enabledChains []string) []Command {
+	manage := permManageChannels
+	filter := CommandOption{Type: optionString, Name: "filter", Description: "Only validators whose moniker or address contains this"}
+	limit := CommandOption{Type: optionInteger, Name: "limit", Description: "Validators per page", MinValue: intPtr(1), MaxValue: intPtr(50)}
+	page := CommandOption{Type: optionInteger, Name: "page", Description: "Page to show", MinValue: intPtr(1)}
+	sortOpt := CommandOption{Type: optionString, Name: "sort", Description: "Sort order", Choices: []OptionChoice{
+		{Name: "descending", Value: "desc"}, {Name: "ascending", Value: "asc"},
+	}}
+	period := CommandOption{Type: optionString, Name: "period", Description: "Period (default: current month)", Choices: []OptionChoice{
+		{Name: "current week", Value: "current_week"},
+		{Name: "current month", Value: "current_month"},
+		{Name: "current year", Value: "current_year"},
+		{Name: "all time", Value: "all_time"},
+	}}
+	validators := CommandOption{Type: optionString, Name: "validators", Required: true,
+		Description: "Space-separated addresses or monikers, or all"}
+
+	var chains []OptionChoice
+	for _, id := range enabledChains {
+		if len(chains) == maxOptionChoices {
+			break
+		}
+		chains = append(chains, OptionChoice{Name: id, Value: id})
+	}
+
+	return []Command{
+		{Name: "status", Description: "Chain health and validators with missed blocks", Options: []CommandOption{filter, page, limit}},
+		{Name: "rate", Description: "Participation rate of the validators", Options: []CommandOption{period, filter, sortOpt, limit}},
+		{Name: "uptime", Description: "Uptime of the validators", Options: []CommandOption{filter, sortOpt, limit}},
+		{Name: "operation_time", Description: "Time since the validators last went down", Options: []CommandOption{filter, limit}},
+		{Name: "tx_contrib", Description: "Share of the transactions included by each validator", Options: []CommandOption{period, filter, sortOpt, limit}},
+		{Name: "missing", Description: "Missed blocks per validator", Options: []CommandOption{period, filter, limit}},
+		{Name: "subscribe", Description: "Validator alerts posted in this channel", DefaultMemberPermissions: &manage, Options: []CommandOption{
+			{Type: optionSubCommand, Name: "list", Description: "Show the subscriptions of this channel"},
+			{Type: optionSubCommand, Name: "on", Description: "Post the alerts of these validators here", Options: []CommandOption{validators}},
+			{Type: optionSubCommand, Name: "off", Description: "Stop posting the alerts of these validators here", Options: []CommandOption{validators}},
+		}},
+		{Name: "report", Description: "Daily report posted in this channel", DefaultMemberPermissions: &manage, Options: []CommandOption{
+			{Type: optionBoolean, Name: "activate", Description: "Turn the daily report on or off"},
+			{Type: optionInteger, Name: "hour", Description: "Hour of the report", MinValue: intPtr(0), MaxValue: intPtr(23)},
+			{Type: optionInteger, Name: "minute", Description: "Minute of the report", MinValue: intPtr(0), MaxValue: intPtr(59)},
+			{Type: optionString, Name: "timezone", Description: "IANA timezone, e.g. Europe/Paris"},
+		}},
+		{Name: "chain", Description: "Show the chain this channel follows"},
+		{Name: "setchain", Description: "Switch the chain this channel follows", DefaultMemberPermissions: &manage, Options: []CommandOption{
+			{Type: optionString, Name: "chain", Description: "Chain ID", Required: true, Choices: chains},
+		}},
+		{Name: "help", Description: "List the commands"},
+	}
+}
+
+// pageCommands maps the paginated slash commands to their page key.
+var pageCommands = map[string]string{
+	"status":         "health",
+	"rate":           "rate",
+	"uptime":         "uptime",
+	"operation_time": "operation_time",
+	"tx_contrib":     "tx_contrib",
+	"missing":        "missing",
+}
+
+// commandParams flattens the options of a command to the key=value params
+// of the Telegram commands. The name of a subcommand is under "subcommand".
+func commandParams(opts []InteractionOption) map[string]string {
+	params := map[string]string{}
+	for _, o := range opts {
+		if o.Type == optionSubCommand {
+			params["subcommand"] = o.Name
+			for k, v := range commandParams(o.Options) {
+				params[k] = v
+			}
+			continue
+		}
+		switch v := o.Value.(type) {
+		case string:
+			params[o.Name] = v
+		case float64:
+			params[o.Name] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			params[o.Name] = strconv.FormatBool(v)
+		}
+	}
+	return params
+}
+
+// runCommand answers a slash command run in channel ch.
+func (b *Bot) runCommand(ch database.DiscordChannel, name string, params map[string]string) Message {
+	if cmdKey, ok := pageCommands[name]; ok {
+		return b.page(ch.ChainID, telegram.NewPageRequest(cmdKey, params))
+	}
+	switch name {
+	case "subscribe":
+		return textMessage(b.subscribe(ch, params))
+	case "report":
+		return textMessage(b.report(ch, params))
+	case "chain":
+		return textMessage(b.formatChains(ch.ChainID))
+	case "setchain":
+		return textMessage(b.setChain(ch, params["chain"]))
+	case "help":
+		return textMessage(formatHelp())
+	}
+	return textMessage("Unknown command ❓ try `/help`")
+}
+
+// page renders one page of a paginated command.
+func (b *Bot) page(chainID string, r telegram.PageRequest) Message {
+	text, markup, err := telegram.BuildPage(b.db, chainID, r)
+	if err != nil {
+		log.Printf("[discord] paginated response error cmd=%s: %v", r.Cmd, err)
+		return textMessage("⚠️ Unable to fetch data.")
+	}
+	return pageMessage(text, markup)
+}
+
+func (b *Bot) subscribe(ch database.DiscordChannel, params map[string]string) string {
+	chainID := ch.ChainID
+	switch params["subcommand"] {
+	case "list":
+		subs, err := database.GetDiscordValidatorStatusList(b.db, ch.ChannelID, chainID)
+		if err != nil {
+			log.Printf("[discord] subscribe list failed: %v", err)
+			return "⚠️ Unable to fetch list of validators."
+		}
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("🧾 **Subscriptions of this channel** (chain: `%s`)\n", chainID))
+		for _, s := range subs {
+			sb.WriteString(fmt.Sprintf("• %s (`%s`) **%s**\n", markdownEscaper.Replace(s.Moniker), s.Addr, s.Status))
+		}
+		return sb.String()
+
+	case "on", "off":
+		activate := params["subcommand"] == "on"
+		targets := strings.Fields(params["validators"])
+		var vals []database.AddrMoniker
+		var err error
+		switch {
+		case len(targets) == 1 && strings.EqualFold(targets[0], "all") && activate:
+			vals, err = database.GetAllValidators(b.db, chainID)
+		case len(targets) == 1 && strings.EqualFold(targets[0], "all"):
+			var subs []database.DiscordValidatorSub
+			subs, err = database.GetDiscordValidatorSubs(b.db, ch.ChannelID, chainID, true)
+			for _, s := range subs {
+				vals = append(vals, database.AddrMoniker{Addr: s.Addr, Moniker: s.Moniker})
+			}
+		default:
+			vals, err = database.ResolveAddrs(b.db, chainID, targets)
+		}
+		if err != nil {
+			log.Printf("[discord] subscribe %s failed: %v", params["subcommand"], err)
+			return "⚠️ Unable to fetch validator list."
+		}
+		if len(vals) == 0 {
+			return "No valid validators found."
+		}
+		var names []string
+		for _, v := range vals {
+			if err := database.SetDiscordValidatorSub(b.db, ch.ChannelID, chainID, v.Addr, v.Moniker, activate); err != nil {
+				log.Printf("[discord] SetDiscordValidatorSub channel=%d addr=%s: %v", ch.ChannelID, v.Addr, err)
+				continue
+			}
+			names = append(names, markdownEscaper.Replace(v.Moniker))
+		}
+		if activate {
+			return fmt.Sprintf("✅ Enabled alerts for **%d** validators (chain: `%s`): %s", len(names), chainID, strings.Join(names, ", "))
+		}
+		return fmt.Sprintf("🛑 Disabled alerts for **%d** validators (chain: `%s`): %s", len(names), chainID, strings.Join(names, ", "))
+	}
+	return "Usage: `/subscribe list`, `/subscribe on validators:<addr|moniker ...|all>`, `/subscribe off validators:<addr|moniker ...|all>`"
+}
+
+// report shows or changes the daily report of the channel. Without options
+// it shows the current schedule.
+func (b *Bot) report(ch database.DiscordChannel, params map[string]string) string {
+	hr, err := database.GetDiscordHourReport(b.db, ch.ChannelID, ch.ChainID)
+	if err != nil {
+		log.Printf("[discord] %v", err)
+		return "⚠️ Unable to read the report schedule."
+	}
+	hour, minute, tz, activate := hr.DailyReportHour, hr.DailyReportMinute, hr.Timezone, hr.Activate
+
+	changed := false
+	if v := params["activate"]; v != "" {
+		activate, changed = v == "true", true
+	}
+	if v := params["hour"]; v != "" {
+		h, err := strconv.Atoi(v)
+		if err != nil || h < 0 || h > 23 {
+			return "⚠️ Invalid hour. Must be an integer between 0 and 23."
+		}
+		hour, changed = h, true
+	}
+	if v := params["minute"]; v != "" {
+		m, err := strconv.Atoi(v)
+		if err != nil || m < 0 || m > 59 {
+			return "⚠️ Invalid minute. Must be an integer between 0 and 59."
+		}
+		minute, changed = m, true
+	}
+	if v := params["timezone"]; v != "" {
+		if _, err := time.LoadLocation(v); err != nil {
+			return fmt.Sprintf("⚠️ Unknown timezone `%s`. Use IANA format, e.g. `Europe/Paris`.", v)
+		}
+		tz, changed = v, true
+	}
+
+	if changed {
+		if err := database.UpdateDiscordHourReport(b.db, ch.ChannelID, ch.ChainID, hour, minute, tz, activate); err != nil {
+			log.Printf("[discord] UpdateDiscordHourReport channel=%d chain=%s: %v", ch.ChannelID, ch.ChainID, err)
+			return "❌ Failed to update the daily report. Please try again."
+		}
+		if SchedulerInstance != nil {
+			if err := SchedulerInstance.ReloadForDiscord(ch.ChannelID, ch.ChainID, b.db); err != nil {
+				log.Printf("[discord] scheduler reload failed (channel %d chain %s): %v", ch.ChannelID, ch.ChainID, err)
+			}
+		}
+	}
+
+	state := "disabled ❌"
+	if activate {
+		state = "activated ✅"
+	}
+	return fmt.Sprintf("📝 The daily report is %s (chain: `%s`)\nTime: **%02d:%02d** — Timezone: `%s`", state, ch.ChainID, hour, minute, tz)
+}
+
+func (b *Bot) formatChains(current string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Current chain: `%s`\n\nAvailable chains:\n", current))
+	for _, id := range b.enabledChains {
+		sb.WriteString(fmt.Sprintf("• `%s`\n", id))
+	}
+	sb.WriteString("\nUse `/setchain` to switch.")
+	return sb.String()
+}
+
+func (b *Bot) setChain(ch database.DiscordChannel, requested string) string {
+	requested = strings.TrimSpace(requested)
+	valid := false
+	for _, id := range b.enabledChains {
+		if id == requested {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Sprintf("Unknown chain `%s`.\n\n%s", requested, b.formatChains(ch.ChainID))
+	}
+	if err := database.UpdateDiscordChannelChain(b.db, ch.ChannelID, requested); err != nil {
+		log.Printf("[discord] UpdateDiscordChannelChain channel=%d: %v", ch.ChannelID, err)
+		return "❌ Failed to switch chain. Please try again."
+	}
+	return fmt.Sprintf("Chain set to `%s`.", requested)
+}
+
+// ack acknowledges alert id from its Acknowledge button.
+func (b *Bot) ack(id uint, user string) (Message, error) {
+	a, err := database.AckAlert(b.db, id, "discord:"+user)
+	if err != nil {
+		return Message{}, err
+	}
+	who := a.AckedBy
+	if i := strings.Index(who, ":"); i >= 0 {
+		who = who[i+1:]
+	}
+	target := a.Moniker
+	if a.Addr == "all" {
+		target = "chain"
+	}
+	return Message{Content: fmt.Sprintf("👀 %s alert for **%s** on `%s` acknowledged by %s. Resends are paused until it is resolved.",
+		a.Level, markdownEscaper.Replace(target), a.ChainID, markdownEscaper.Replace(who))}, nil
+}
+
+func formatHelp() string {
+	return `🤖 **Gnomonitoring validator bot**
+
+**Validators**
+` + "`/status`" + ` — chain health and validators with missed blocks
+` + "`/rate`" + ` — participation rate (period, filter, sort, limit)
+` + "`/uptime`" + ` — uptime (filter, sort, limit)
+` + "`/operation_time`" + ` — time since the validators last went down
+` + "`/tx_contrib`" + ` — share of included transactions (period, filter, sort, limit)
+` + "`/missing`" + ` — missed blocks (period, filter, limit)
+
+**Channel**
+` + "`/subscribe list|on|off`" + ` — validator alerts posted in this channel
+` + "`/report`" + ` — daily report (activate, hour, minute, timezone)
+` + "`/chain`" + `, ` + "`/setchain`" + ` — chain followed by this channel
+
+Use the buttons under a list to page through it, sort it or search it.`
+}
+
+// schedulerReloader is a narrow interface so discord does not need to import
+// the scheduler package (which would create an import cycle).
+type schedulerReloader interface {
+	ReloadForDiscord(channelID int64, chainID string, db *gorm.DB) error
+}
+
+// SchedulerInstance is set by scheduler.InitScheduler. It stays nil when the
+// report scheduler is disabled; schedule changes are then only stored.
+var SchedulerInstance schedulerReloader
@@ -0,0 +1,166 @@
+// Package discord is the Discord counterpart of the Telegram validator bot.
+// Discord posts slash commands and button clicks to an HTTP interactions
+// endpoint (Bot, see interactions.go); the same formatters and paginated
+// pages as the Telegram bot answer them. The REST helpers here post alerts
+// and daily reports in channels and register the slash commands.
+package discord
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var discordHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// apiBaseURL is the Discord REST API base URL. It is a package variable (not
+// a const) so tests can point it at an httptest server.
+var apiBaseURL = "https://discord.com/api/v10"
+
+// APIError is a failed Discord REST call. RetryAfter is how long Discord
+// asks to wait before retrying (set on HTTP 429 only).
+type APIError struct {
+	StatusCode int
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("discord http %d: %s", e.StatusCode, e.Message)
+}
+
+// Message is the body of a message sent or edited by the bot. Components is
+// always sent so an edit can remove the buttons of the previous version.
+type Message struct {
+	Content    string      `json:"content,omitempty"`
+	Embeds     []Embed     `json:"embeds,omitempty"`
+	Components []Component `json:"components"`
+	Flags      int         `json:"flags,omitempty"`
+}
+
+// flagEphemeral makes a follow-up message visible to the invoking user only.
+const flagEphemeral = 1 << 6
+
+// Embed is the subset of a Discord embed the bot uses.
+type Embed struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Color       int    `json:"color,omitempty"`
+}
+
+// Component types and styles used by the bot.
+const (
+	componentActionRow = 1
+	componentButton    = 2
+	componentTextInput = 4
+
+	buttonSecondary = 2
+	buttonLink      = 5
+
+	textInputShort = 1
+)
+
+// Component is a message component (action row, button) or a modal's text
+// input. Modal submissions carry the inputs back in the same shape.
+type Component struct {
+	Type        int         `json:"type"`
+	Components  []Component `json:"components,omitempty"`
+	Style       int         `json:"style,omitempty"`
+	Label       string      `json:"label,omitempty"`
+	CustomID    string      `json:"custom_id,omitempty"`
+	URL         string      `json:"url,omitempty"`
+	Placeholder string      `json:"placeholder,omitempty"`
+	MaxLength   int         `json:"max_length,omitempty"`
+	Required    bool        `json:"required,omitempty"`
+	Value       string      `json:"value,omitempty"`
+}
+
+// SendChannelMessage posts body (a JSON message) in channelID as the bot
+// whose token is token.
+func SendChannelMessage(token string, channelID int64, body []byte) error {
+	if token == "" {
+		return fmt.Errorf("discord bot token is empty")
+	}
+	url := fmt.Sprintf("%s/channels/%d/messages", apiBaseURL, channelID)
+	return doRequest(http.MethodPost, url, token, body)
+}
+
+// editOriginal replaces the deferred response of an interaction. The
+// interaction token authorizes the call for 15 minutes.
+func editOriginal(appID, interactionToken string, msg Message) error {
+	if msg.Components == nil {
+		msg.Components = []Component{}
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+	url := fmt.Sprintf("%s/webhooks/%s/%s/messages/@original", apiBaseURL, appID, interactionToken)
+	return doRequest(http.MethodPatch, url, "", body)
+}
+
+// createFollowup sends an additional message for an interaction.
+func createFollowup(appID, interactionToken string, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+	url := fmt.Sprintf("%s/webhooks/%s/%s", apiBaseURL, appID, interactionToken)
+	return doRequest(http.MethodPost, url, "", body)
+}
+
+// RegisterCommands overwrites the application's global slash commands with
+// the bot's (see Commands). enabledChains become the choices of /setchain.
+func RegisterCommands(appID, token string, enabledChains []string) error {
+	if appID == "" || token == "" {
+		return fmt.Errorf("discord application_id and token are required to register commands")
+	}
+	body, err := json.Marshal(Commands(enabledChains))
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+	url := fmt.Sprintf("%s/applications/%s/commands", apiBaseURL, appID)
+	return doRequest(http.MethodPut, url, token, body)
+}
+
+// doRequest sends body to url, authenticated as the bot when token is set,
+// and turns a non-2xx answer into an *APIError.
+func doRequest(method, url, token string, body []byte) error {
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bot "+token)
+	}
+
+	resp, err := discordHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 == 2 {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	var res struct {
+		Message    string  `json:"message"`
+		RetryAfter float64 `json:"retry_after"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&res)
+	apiErr := &APIError{StatusCode: resp.StatusCode, Message: res.Message}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		secs := res.RetryAfter
+		if v, err := strconv.ParseFloat(resp.Header.Get("Retry-After"), 64); err == nil && v > secs {
+			secs = v
+		}
+		apiErr.RetryAfter = time.Duration(secs * float64(time.Second))
+	}
+	return apiErr
+}
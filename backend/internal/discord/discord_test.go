@@ -0,0 +1,236 @@
+package discord
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/telegram"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTMLToMarkdown(t *testing.T) {
+	cases := []struct{ in, want string }{
+		{"<b>Participation</b> — <code>test12</code>", "**Participation** — `test12`"},
+		{"• my_val (<code>g1_abc</code>)", "• my\\_val (`g1_abc`)"},
+		{"<i>note</i> &lt;addr&gt; &amp; more", "*note* <addr> & more"},
+		{`<a href="https://gno.land/r/x?a=1&amp;b=2">proposal</a>`, "[proposal](https://gno.land/r/x?a=1&b=2)"},
+		{"<code>a`b</code>", "`a'b`"},
+		{"<pre>line</pre>", "```\nline\n```"},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, HTMLToMarkdown(c.in), c.in)
+	}
+}
+
+func TestButtons_FromTelegramMarkup(t *testing.T) {
+	markup := &telegram.InlineKeyboardMarkup{InlineKeyboard: [][]telegram.InlineKeyboardButton{
+		{{Text: "⬅️ Prev", CallbackData: "c=rt&p=1&l=10"}, {Text: "➡️ Next", CallbackData: "c=rt&p=3&l=10"}},
+		{{Text: "Report", URL: "https://example.com/r"}},
+	}}
+	rows := Buttons(markup)
+	require.Len(t, rows, 2)
+	assert.Equal(t, componentActionRow, rows[0].Type)
+	require.Len(t, rows[0].Components, 2)
+	assert.Equal(t, Component{Type: componentButton, Style: buttonSecondary, Label: "⬅️ Prev", CustomID: "c=rt&p=1&l=10"}, rows[0].Components[0])
+	assert.Equal(t, Component{Type: componentButton, Style: buttonLink, Label: "Report", URL: "https://example.com/r"}, rows[1].Components[0])
+
+	assert.NotNil(t, Buttons(nil), "an empty list clears the buttons of an edited message")
+	assert.Empty(t, Buttons(nil))
+
+	ack := AckButtons(42)
+	require.Len(t, ack, 1)
+	assert.Equal(t, "c=ack&id=42", ack[0].Components[0].CustomID)
+}
+
+func TestCommandParams_FlattensSubcommandsAndValues(t *testing.T) {
+	var opts []InteractionOption
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"name":"on","type":1,"options":[{"name":"validators","type":3,"value":"g1a g1b"}]}
+	]`), &opts))
+	assert.Equal(t, map[string]string{"subcommand": "on", "validators": "g1a g1b"}, commandParams(opts))
+
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"name":"limit","type":4,"value":20},
+		{"name":"activate","type":5,"value":false},
+		{"name":"period","type":3,"value":"all_time"}
+	]`), &opts))
+	assert.Equal(t, map[string]string{"limit": "20", "activate": "false", "period": "all_time"}, commandParams(opts))
+}
+
+func TestCommands_SetchainChoicesAndPermissions(t *testing.T) {
+	chains := make([]string, 30)
+	for i := range chains {
+		chains[i] = "chain" + strconv.Itoa(i)
+	}
+	cmds := Commands(chains)
+	seen := map[string]bool{}
+	for _, c := range cmds {
+		assert.False(t, seen[c.Name], "duplicate command %s", c.Name)
+		seen[c.Name] = true
+		switch c.Name {
+		case "setchain":
+			assert.Len(t, c.Options[0].Choices, maxOptionChoices)
+			fallthrough
+		case "subscribe", "report":
+			require.NotNil(t, c.DefaultMemberPermissions, c.Name)
+		default:
+			assert.Nil(t, c.DefaultMemberPermissions, c.Name)
+		}
+	}
+	for name := range pageCommands {
+		assert.True(t, seen[name], "paginated command %s is not registered", name)
+	}
+}
+
+// signedRequest signs body like Discord does, with timestamp ts.
+func signedRequest(t *testing.T, priv ed25519.PrivateKey, body string, ts time.Time) *http.Request {
+	t.Helper()
+	stamp := strconv.FormatInt(ts.Unix(), 10)
+	req := httptest.NewRequest(http.MethodPost, "/discord/interactions", bytes.NewBufferString(body))
+	req.Header.Set("X-Signature-Timestamp", stamp)
+	req.Header.Set("X-Signature-Ed25519", hex.EncodeToString(ed25519.Sign(priv, []byte(stamp+body))))
+	return req
+}
+
+func newTestBot(t *testing.T) (*Bot, ed25519.PrivateKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	bot, err := NewBot(nil, hex.EncodeToString(pub), "app1", "test12", []string{"test12"})
+	require.NoError(t, err)
+	return bot, priv
+}
+
+func TestNewBot_RejectsInvalidKey(t *testing.T) {
+	_, err := NewBot(nil, "not-hex", "app1", "test12", nil)
+	assert.Error(t, err)
+	_, err = NewBot(nil, "abcd", "app1", "test12", nil)
+	assert.Error(t, err)
+}
+
+func TestServeHTTP_Ping(t *testing.T) {
+	bot, priv := newTestBot(t)
+	rec := httptest.NewRecorder()
+	bot.ServeHTTP(rec, signedRequest(t, priv, `{"type":1}`, time.Now()))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"type":1}`, rec.Body.String())
+}
+
+func TestServeHTTP_RejectsBadSignatures(t *testing.T) {
+	bot, priv := newTestBot(t)
+	_, otherPriv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	tampered := signedRequest(t, priv, `{"type":1}`, time.Now())
+	tampered.Body = io.NopCloser(bytes.NewBufferString(`{"type":2}`))
+
+	for name, req := range map[string]*http.Request{
+		"other key": signedRequest(t, otherPriv, `{"type":1}`, time.Now()),
+		"tampered":  tampered,
+		"stale":     signedRequest(t, priv, `{"type":1}`, time.Now().Add(-time.Hour)),
+		"unsigned":  httptest.NewRequest(http.MethodPost, "/discord/interactions", bytes.NewBufferString(`{"type":1}`)),
+	} {
+		rec := httptest.NewRecorder()
+		bot.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, name)
+	}
+}
+
+func TestServeHTTP_SearchButtonOpensModal(t *testing.T) {
+	bot, priv := newTestBot(t)
+	search := telegram.PageRequest{Cmd: "rate", Page: 2, Limit: 10, Period: "current_week", SortOrder: "asc", Action: "search"}
+	body, err := json.Marshal(map[string]any{
+		"type": interactionMessageComponent, "token": "tok", "channel_id": "123",
+		"data": map[string]any{"custom_id": search.Callback(), "component_type": componentButton},
+	})
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	bot.ServeHTTP(rec, signedRequest(t, priv, string(body), time.Now()))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp struct {
+		Type int   `json:"type"`
+		Data modal `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, responseModal, resp.Type)
+	req, ok := telegram.ParsePageCallback(resp.Data.CustomID)
+	require.True(t, ok)
+	assert.Equal(t, telegram.PageRequest{Cmd: "rate", Page: 1, Limit: 10, Period: "current_week", SortOrder: "asc"}, req,
+		"the modal restarts the list at page 1, keeping the period and sort")
+	assert.Equal(t, searchInputID, resp.Data.Components[0].Components[0].CustomID)
+}
+
+func TestModalValue(t *testing.T) {
+	rows := []Component{{Type: componentActionRow, Components: []Component{{Type: componentTextInput, CustomID: searchInputID, Value: "samourai"}}}}
+	assert.Equal(t, "samourai", modalValue(rows, searchInputID))
+	assert.Equal(t, "", modalValue(rows, "other"))
+}
+
+// fakeDiscordAPI points apiBaseURL at a test server answering status and
+// records the requests it gets.
+func fakeDiscordAPI(t *testing.T, status int, header http.Header) chan *http.Request {
+	t.Helper()
+	reqs := make(chan *http.Request, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		reqs <- r
+		for k, v := range header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(status)
+		if status == http.StatusTooManyRequests {
+			io.WriteString(w, `{"message":"You are being rate limited.","retry_after":1.5}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	old := apiBaseURL
+	apiBaseURL = srv.URL
+	t.Cleanup(func() { apiBaseURL = old })
+	return reqs
+}
+
+func TestEditOriginal_AlwaysSendsComponents(t *testing.T) {
+	reqs := fakeDiscordAPI(t, http.StatusOK, nil)
+	require.NoError(t, editOriginal("app1", "tok", Message{}))
+
+	r := <-reqs
+	assert.Equal(t, http.MethodPatch, r.Method)
+	assert.Equal(t, "/webhooks/app1/tok/messages/@original", r.URL.Path)
+	assert.Empty(t, r.Header.Get("Authorization"), "interaction webhooks are authorized by their token")
+	body, _ := io.ReadAll(r.Body)
+	assert.JSONEq(t, `{"components":[]}`, string(body), "an ack edit removes the buttons and keeps the embed")
+}
+
+func TestSendChannelMessage(t *testing.T) {
+	reqs := fakeDiscordAPI(t, http.StatusOK, nil)
+	require.NoError(t, SendChannelMessage("bot-token", 987, []byte(`{"content":"hi"}`)))
+	r := <-reqs
+	assert.Equal(t, "/channels/987/messages", r.URL.Path)
+	assert.Equal(t, "Bot bot-token", r.Header.Get("Authorization"))
+
+	assert.Error(t, SendChannelMessage("", 987, nil))
+}
+
+func TestSendChannelMessage_RateLimited(t *testing.T) {
+	fakeDiscordAPI(t, http.StatusTooManyRequests, http.Header{"Retry-After": {"2"}})
+	err := SendChannelMessage("bot-token", 987, []byte(`{}`))
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+	assert.Equal(t, "You are being rate limited.", apiErr.Message)
+	assert.Equal(t, 2*time.Second, apiErr.RetryAfter, "the larger of the header and body delays")
+}
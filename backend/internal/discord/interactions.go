@@ -0,0 +1,307 @@
+package discord
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/telegram"
+	"gorm.io/gorm"
+)
+
+// Interaction types sent by Discord.
+const (
+	interactionPing               = 1
+	interactionApplicationCommand = 2
+	interactionMessageComponent   = 3
+	interactionModalSubmit        = 5
+)
+
+// Interaction response types.
+const (
+	responsePong                   = 1
+	responseChannelMessage         = 4
+	responseDeferredChannelMessage = 5
+	responseDeferredUpdateMessage  = 6
+	responseModal                  = 9
+)
+
+// maxInteractionBody bounds the request body read from Discord.
+const maxInteractionBody = 1 << 20
+
+// maxSignatureAge rejects replayed requests whose signed timestamp is older
+// than this.
+const maxSignatureAge = 5 * time.Minute
+
+// searchInputID is the custom_id of the search modal's text input.
+const searchInputID = "filter"
+
+// Interaction is the subset of a Discord interaction the bot reads.
+type Interaction struct {
+	Type      int                  `json:"type"`
+	Token     string               `json:"token"`
+	ChannelID string               `json:"channel_id"`
+	GuildID   string               `json:"guild_id"`
+	Member    *struct{ User User } `json:"member"`
+	User      *User                `json:"user"`
+	Data      InteractionData      `json:"data"`
+}
+
+// User is a Discord user.
+type User struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+// InteractionData is the payload of a command, a component click or a modal
+// submission.
+type InteractionData struct {
+	Name       string              `json:"name"`
+	Options    []InteractionOption `json:"options"`
+	CustomID   string              `json:"custom_id"`
+	Components []Component         `json:"components"`
+}
+
+// InteractionOption is an option value of a slash command.
+type InteractionOption struct {
+	Name    string              `json:"name"`
+	Type    int                 `json:"type"`
+	Value   any                 `json:"value"`
+	Options []InteractionOption `json:"options"`
+}
+
+type interactionResponse struct {
+	Type int `json:"type"`
+	Data any `json:"data,omitempty"`
+}
+
+type modal struct {
+	CustomID   string      `json:"custom_id"`
+	Title      string      `json:"title"`
+	Components []Component `json:"components"`
+}
+
+// user returns the name of whoever triggered in, for acknowledgements.
+func (in *Interaction) user() string {
+	switch {
+	case in.Member != nil && in.Member.User.Username != "":
+		return in.Member.User.Username
+	case in.User != nil:
+		return in.User.Username
+	}
+	return "unknown"
+}
+
+// Bot is the http.Handler of the Discord interactions endpoint. It answers
+// every command and click with a deferred response and edits it once the
+// page is built, so slow queries never hit Discord's 3-second deadline.
+type Bot struct {
+	db             *gorm.DB
+	publicKey      ed25519.PublicKey
+	appID          string
+	defaultChainID string
+	enabledChains  []string
+}
+
+// NewBot returns the interactions handler of the application appID, whose
+// requests are signed with publicKeyHex. Channels follow defaultChainID
+// until /setchain picks one of enabledChains.
+func NewBot(db *gorm.DB, publicKeyHex, appID, defaultChainID string, enabledChains []string) (*Bot, error) {
+	key, err := hex.DecodeString(publicKeyHex)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid discord public key")
+	}
+	return &Bot{
+		db:             db,
+		publicKey:      ed25519.PublicKey(key),
+		appID:          appID,
+		defaultChainID: defaultChainID,
+		enabledChains:  enabledChains,
+	}, nil
+}
+
+// verifySignature checks the Ed25519 signature Discord puts on every
+// interaction request (timestamp + body).
+func verifySignature(pub ed25519.PublicKey, r *http.Request, body []byte, now time.Time) bool {
+	sig, err := hex.DecodeString(r.Header.Get("X-Signature-Ed25519"))
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return false
+	}
+	ts := r.Header.Get("X-Signature-Timestamp")
+	secs, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := now.Sub(time.Unix(secs, 0)); age > maxSignatureAge || age < -maxSignatureAge {
+		return false
+	}
+	return ed25519.Verify(pub, append([]byte(ts), body...), sig)
+}
+
+func (b *Bot) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxInteractionBody))
+	if err != nil {
+		http.Error(w, "Failed to read body", http.StatusBadRequest)
+		return
+	}
+	if !verifySignature(b.publicKey, r, body, time.Now()) {
+		http.Error(w, "invalid request signature", http.StatusUnauthorized)
+		return
+	}
+	var in Interaction
+	if err := json.Unmarshal(body, &in); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	switch in.Type {
+	case interactionPing:
+		writeResponse(w, interactionResponse{Type: responsePong})
+
+	case interactionApplicationCommand:
+		ch, ok := b.channel(&in)
+		if !ok {
+			writeResponse(w, interactionResponse{Type: responseChannelMessage, Data: textMessage("⚠️ Unable to load this channel.")})
+			return
+		}
+		writeResponse(w, interactionResponse{Type: responseDeferredChannelMessage})
+		name, params := in.Data.Name, commandParams(in.Data.Options)
+		go b.edit(&in, func() Message { return b.runCommand(ch, name, params) })
+
+	case interactionMessageComponent:
+		b.handleComponent(w, &in)
+
+	case interactionModalSubmit:
+		req, ok := telegram.ParsePageCallback(in.Data.CustomID)
+		ch, chOK := b.channel(&in)
+		if !ok || !chOK {
+			writeResponse(w, interactionResponse{Type: responseDeferredUpdateMessage})
+			return
+		}
+		req.Filter = modalValue(in.Data.Components, searchInputID)
+		writeResponse(w, interactionResponse{Type: responseDeferredUpdateMessage})
+		go b.edit(&in, func() Message { return b.page(ch.ChainID, req) })
+
+	default:
+		http.Error(w, "unsupported interaction type", http.StatusBadRequest)
+	}
+}
+
+// handleComponent answers a button click: an alert acknowledgement, a search
+// (which opens a modal) or a page change.
+func (b *Bot) handleComponent(w http.ResponseWriter, in *Interaction) {
+	if id, ok := telegram.ParseAckCallback(in.Data.CustomID); ok {
+		writeResponse(w, interactionResponse{Type: responseDeferredUpdateMessage})
+		go b.acknowledge(in, id)
+		return
+	}
+
+	req, ok := telegram.ParsePageCallback(in.Data.CustomID)
+	if !ok {
+		writeResponse(w, interactionResponse{Type: responseDeferredUpdateMessage})
+		return
+	}
+	if req.Action == "search" {
+		req.Action, req.Page, req.Filter = "", 1, ""
+		writeResponse(w, interactionResponse{Type: responseModal, Data: modal{
+			CustomID: req.Callback(),
+			Title:    "Search validators",
+			Components: []Component{{Type: componentActionRow, Components: []Component{{
+				Type:        componentTextInput,
+				CustomID:    searchInputID,
+				Style:       textInputShort,
+				Label:       "Moniker or address",
+				Placeholder: "e.g. samourai",
+				MaxLength:   20,
+			}}}},
+		}})
+		return
+	}
+
+	ch, chOK := b.channel(in)
+	if !chOK {
+		writeResponse(w, interactionResponse{Type: responseDeferredUpdateMessage})
+		return
+	}
+	writeResponse(w, interactionResponse{Type: responseDeferredUpdateMessage})
+	go b.edit(in, func() Message { return b.page(ch.ChainID, req) })
+}
+
+// acknowledge acknowledges alert id, removes the button from the alert and
+// tells the channel who is on it.
+func (b *Bot) acknowledge(in *Interaction, id uint) {
+	msg, err := b.ack(id, in.user())
+	if err != nil {
+		log.Printf("[discord] ack alert %d failed: %v", id, err)
+		if err := createFollowup(b.appID, in.Token, Message{Content: "⚠️ This alert can no longer be acknowledged.", Flags: flagEphemeral}); err != nil {
+			log.Printf("[discord] followup failed: %v", err)
+		}
+		return
+	}
+	if err := editOriginal(b.appID, in.Token, Message{}); err != nil {
+		log.Printf("[discord] remove ack button failed: %v", err)
+	}
+	if err := createFollowup(b.appID, in.Token, msg); err != nil {
+		log.Printf("[discord] followup failed: %v", err)
+	}
+}
+
+// edit replaces the deferred response of in with the message built by
+// build.
+func (b *Bot) edit(in *Interaction, build func() Message) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[discord] interaction panic: %v", r)
+		}
+	}()
+	if err := editOriginal(b.appID, in.Token, build()); err != nil {
+		log.Printf("[discord] edit response failed: %v", err)
+	}
+}
+
+// channel loads the channel of in, registering it on the default chain the
+// first time the bot is used there.
+func (b *Bot) channel(in *Interaction) (database.DiscordChannel, bool) {
+	channelID, err := strconv.ParseInt(in.ChannelID, 10, 64)
+	if err != nil {
+		log.Printf("[discord] invalid channel_id %q", in.ChannelID)
+		return database.DiscordChannel{}, false
+	}
+	guildID, _ := strconv.ParseInt(in.GuildID, 10, 64) // empty in DMs
+	ch, err := database.EnsureDiscordChannel(b.db, channelID, guildID, b.defaultChainID)
+	if err != nil {
+		log.Printf("[discord] %v", err)
+		return database.DiscordChannel{}, false
+	}
+	return ch, true
+}
+
+// modalValue returns the value of the text input id of a submitted modal.
+func modalValue(rows []Component, id string) string {
+	for _, row := range rows {
+		for _, c := range row.Components {
+			if c.CustomID == id {
+				return c.Value
+			}
+		}
+	}
+	return ""
+}
+
+func writeResponse(w http.ResponseWriter, resp interactionResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("[discord] write response: %v", err)
+	}
+}
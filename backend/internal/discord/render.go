@@ -0,0 +1,159 @@
+package discord
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/telegram"
+)
+
+// pageColor is the embed color of command answers (Discord blurple).
+const pageColor = 0x5865F2
+
+// maxEmbedDescription is Discord's limit on an embed description, in
+// characters.
+const maxEmbedDescription = 4096
+
+// Discord caps action rows per message, buttons per row and button labels.
+const (
+	maxActionRows   = 5
+	maxRowButtons   = 5
+	maxButtonLabel  = 80
+	maxCustomIDSize = 100
+)
+
+var htmlTagRe = regexp.MustCompile(`<(/?)(b|strong|i|em|u|s|code|pre|a)(?:\s+href="([^"]*)")?\s*>`)
+
+// markdownEscaper escapes the characters Discord markdown would interpret in
+// plain text (monikers routinely contain underscores).
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "~", `\~`, "`", "\\`", "|", `\|`)
+
+// HTMLToMarkdown converts the Telegram HTML produced by the telegram
+// formatters (b, i, u, s, code, pre, a) to Discord markdown. Other tags are
+// left as text; entities are unescaped.
+func HTMLToMarkdown(s string) string {
+	var b strings.Builder
+	inCode := false
+	var hrefs []string
+
+	writeText := func(t string) {
+		t = html.UnescapeString(t)
+		if inCode {
+			b.WriteString(strings.ReplaceAll(t, "`", "'"))
+			return
+		}
+		b.WriteString(markdownEscaper.Replace(t))
+	}
+
+	last := 0
+	for _, m := range htmlTagRe.FindAllStringSubmatchIndex(s, -1) {
+		writeText(s[last:m[0]])
+		last = m[1]
+		closing := m[3] > m[2]
+		switch tag := s[m[4]:m[5]]; tag {
+		case "b", "strong":
+			b.WriteString("**")
+		case "i", "em":
+			b.WriteString("*")
+		case "u":
+			b.WriteString("__")
+		case "s":
+			b.WriteString("~~")
+		case "code":
+			inCode = !closing
+			b.WriteString("`")
+		case "pre":
+			inCode = !closing
+			if closing {
+				b.WriteString("\n```")
+			} else {
+				b.WriteString("```\n")
+			}
+		case "a":
+			if !closing {
+				href := ""
+				if m[6] >= 0 {
+					href = html.UnescapeString(s[m[6]:m[7]])
+				}
+				hrefs = append(hrefs, href)
+				b.WriteString("[")
+				continue
+			}
+			if len(hrefs) == 0 {
+				continue
+			}
+			href := hrefs[len(hrefs)-1]
+			hrefs = hrefs[:len(hrefs)-1]
+			b.WriteString("](" + href + ")")
+		}
+	}
+	writeText(s[last:])
+	return b.String()
+}
+
+// truncate cuts s to at most max characters, marking the cut.
+func truncate(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max-1]) + "…"
+}
+
+// Buttons converts a Telegram inline keyboard to Discord action rows. The
+// callback data becomes the button's custom_id, so a click carries the same
+// pagination state as a Telegram tap.
+func Buttons(markup *telegram.InlineKeyboardMarkup) []Component {
+	rows := []Component{}
+	if markup == nil {
+		return rows
+	}
+	for _, kbRow := range markup.InlineKeyboard {
+		if len(rows) == maxActionRows {
+			break
+		}
+		row := Component{Type: componentActionRow}
+		for _, btn := range kbRow {
+			if len(row.Components) == maxRowButtons {
+				break
+			}
+			c := Component{Type: componentButton, Label: truncate(btn.Text, maxButtonLabel)}
+			switch {
+			case btn.URL != "":
+				c.Style, c.URL = buttonLink, btn.URL
+			case btn.CallbackData != "" && len(btn.CallbackData) <= maxCustomIDSize:
+				c.Style, c.CustomID = buttonSecondary, btn.CallbackData
+			default:
+				continue
+			}
+			row.Components = append(row.Components, c)
+		}
+		if len(row.Components) > 0 {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// AckButtons is the Acknowledge button of acknowledgeable alerts posted in
+// channels; the click is handled like the Telegram one.
+func AckButtons(alertLogID uint) []Component {
+	return Buttons(telegram.AckMarkup(alertLogID))
+}
+
+// pageMessage wraps Telegram HTML text and its buttons as a Discord message.
+func pageMessage(text string, markup *telegram.InlineKeyboardMarkup) Message {
+	return Message{
+		Embeds:     []Embed{{Description: truncate(HTMLToMarkdown(text), maxEmbedDescription), Color: pageColor}},
+		Components: Buttons(markup),
+	}
+}
+
+// textMessage wraps Discord markdown as a message without buttons.
+func textMessage(md string) Message {
+	return Message{
+		Embeds:     []Embed{{Description: truncate(md, maxEmbedDescription), Color: pageColor}},
+		Components: []Component{},
+	}
+}
@@ -0,0 +1,310 @@
+package internal
+
+import (
+	"math"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Every RPC, GraphQL and gnoweb call records its outcome here, per endpoint
+// URL, and callers pick endpoints in OrderEndpoints order: healthy and up to
+// date first, then slow or lagging ones, then those whose circuit is open.
+// A circuit opens after endpointFailureThreshold failures in a row and stays
+// open for a cooldown that doubles on each failed probe; once it elapses the
+// endpoint may be tried again, and one success closes it. The background
+// probes (gnovalidator.WatchEndpointHealth) keep heights and circuits fresh,
+// so a recovered primary is picked again without waiting for the others to
+// fail.
+
+// Endpoint kinds.
+const (
+	EndpointRPC     = "rpc"
+	EndpointGraphQL = "graphql"
+	EndpointGnoweb  = "gnoweb"
+)
+
+const (
+	// endpointWindow is the number of recent calls the success rate and
+	// latency are computed over.
+	endpointWindow           = 50
+	endpointFailureThreshold = 3
+	endpointMinCooldown      = 30 * time.Second
+	endpointMaxCooldown      = 5 * time.Minute
+	// endpointMinSuccessRate is the success rate below which an endpoint is
+	// ranked after the reliable ones.
+	endpointMinSuccessRate = 0.9
+	// endpointMaxLag is how many blocks an endpoint may be behind the
+	// highest of its list and still count as up to date.
+	endpointMaxLag = 2
+	// endpointLatencyStep is the latency ranking granularity: endpoints
+	// whose p95 latencies fall within the same doubling of it rank as
+	// equally fast and keep their configured order.
+	endpointLatencyStep = 100 * time.Millisecond
+)
+
+// EndpointStats is the health of one endpoint, as served by EndpointHealth.
+type EndpointStats struct {
+	Kind        string        `json:"kind"`
+	Endpoint    string        `json:"endpoint"`
+	Calls       int           `json:"calls"`
+	SuccessRate float64       `json:"success_rate"`
+	P95         time.Duration `json:"p95"`
+	Height      int64         `json:"height"`
+	HeightLag   int64         `json:"height_lag"`
+	CircuitOpen bool          `json:"circuit_open"`
+	Preferred   bool          `json:"preferred"`
+}
+
+type endpointCall struct {
+	ok      bool
+	latency time.Duration
+}
+
+// endpointState is the health record of one endpoint.
+type endpointState struct {
+	calls    [endpointWindow]endpointCall
+	n        int // calls recorded, capped at endpointWindow
+	next     int // ring index of the next call
+	failures int // consecutive failures
+	height   int64
+	openTill time.Time // circuit open until then; zero when closed
+	cooldown time.Duration
+}
+
+func (s *endpointState) record(ok bool, latency time.Duration, now time.Time) {
+	s.calls[s.next] = endpointCall{ok: ok, latency: latency}
+	s.next = (s.next + 1) % endpointWindow
+	if s.n < endpointWindow {
+		s.n++
+	}
+	if ok {
+		s.failures = 0
+		s.openTill = time.Time{}
+		s.cooldown = 0
+		return
+	}
+	s.failures++
+	if s.failures < endpointFailureThreshold {
+		return
+	}
+	// A failure while open (a failed probe) doubles the cooldown.
+	switch {
+	case s.cooldown == 0:
+		s.cooldown = endpointMinCooldown
+	case s.failures > endpointFailureThreshold:
+		s.cooldown = min(2*s.cooldown, endpointMaxCooldown)
+	}
+	s.openTill = now.Add(s.cooldown)
+}
+
+func (s *endpointState) open() bool {
+	return !s.openTill.IsZero()
+}
+
+// probeDue reports whether the endpoint may be called: its circuit is
+// closed, or open past its cooldown.
+func (s *endpointState) probeDue(now time.Time) bool {
+	return !s.open() || !now.Before(s.openTill)
+}
+
+func (s *endpointState) successRate() float64 {
+	if s.n == 0 {
+		return 1
+	}
+	ok := 0
+	for i := 0; i < s.n; i++ {
+		if s.calls[i].ok {
+			ok++
+		}
+	}
+	return float64(ok) / float64(s.n)
+}
+
+// p95 is the 95th percentile latency of the successful calls in the window.
+func (s *endpointState) p95() time.Duration {
+	lat := make([]time.Duration, 0, s.n)
+	for i := 0; i < s.n; i++ {
+		if s.calls[i].ok {
+			lat = append(lat, s.calls[i].latency)
+		}
+	}
+	if len(lat) == 0 {
+		return 0
+	}
+	sort.Slice(lat, func(i, j int) bool { return lat[i] < lat[j] })
+	return lat[int(math.Ceil(0.95*float64(len(lat))))-1]
+}
+
+// endpointRegistry holds the endpointState of every endpoint, keyed by kind
+// and URL: an endpoint shared by two chains has one record.
+type endpointRegistry struct {
+	mu     sync.Mutex
+	states map[string]*endpointState
+}
+
+func newEndpointRegistry() *endpointRegistry {
+	return &endpointRegistry{states: map[string]*endpointState{}}
+}
+
+var endpointStates = newEndpointRegistry()
+
+// state returns the record of kind/endpoint. Must be called with r.mu held.
+func (r *endpointRegistry) state(kind, endpoint string) *endpointState {
+	key := kind + "|" + endpoint
+	s, ok := r.states[key]
+	if !ok {
+		s = &endpointState{}
+		r.states[key] = s
+	}
+	return s
+}
+
+func (r *endpointRegistry) record(kind, endpoint string, latency time.Duration, err error, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.state(kind, endpoint).record(err == nil, latency, now)
+}
+
+func (r *endpointRegistry) recordHeight(kind, endpoint string, height int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.state(kind, endpoint).height = height
+}
+
+func (r *endpointRegistry) probeDue(kind, endpoint string, now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.state(kind, endpoint).probeDue(now)
+}
+
+// order ranks list, best first. Every endpoint is returned: one whose
+// circuit is cooling down comes last but is still worth a try when all the
+// others fail.
+func (r *endpointRegistry) order(kind string, list []string, now time.Time) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var best int64
+	for _, ep := range list {
+		best = max(best, r.state(kind, ep).height)
+	}
+	type ranked struct {
+		ep      string
+		tier    int
+		latency int
+		idx     int
+	}
+	rs := make([]ranked, len(list))
+	for i, ep := range list {
+		s := r.state(kind, ep)
+		tier := 0
+		switch {
+		case s.open() && now.Before(s.openTill):
+			tier = 3
+		case s.open():
+			tier = 2
+		case s.successRate() < endpointMinSuccessRate || (s.height > 0 && best-s.height > endpointMaxLag):
+			tier = 1
+		}
+		rs[i] = ranked{ep: ep, tier: tier, latency: latencyBucket(s.p95()), idx: i}
+	}
+	sort.Slice(rs, func(i, j int) bool {
+		a, b := rs[i], rs[j]
+		if a.tier != b.tier {
+			return a.tier < b.tier
+		}
+		if a.latency != b.latency {
+			return a.latency < b.latency
+		}
+		return a.idx < b.idx
+	})
+	out := make([]string, len(rs))
+	for i, x := range rs {
+		out[i] = x.ep
+	}
+	return out
+}
+
+// latencyBucket is 0 up to endpointLatencyStep, then one more per doubling.
+func latencyBucket(d time.Duration) int {
+	if d <= endpointLatencyStep {
+		return 0
+	}
+	return int(math.Ceil(math.Log2(float64(d) / float64(endpointLatencyStep))))
+}
+
+func (r *endpointRegistry) health(kind string, list []string, now time.Time) []EndpointStats {
+	order := r.order(kind, list, now)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var best int64
+	for _, ep := range list {
+		best = max(best, r.state(kind, ep).height)
+	}
+	stats := make([]EndpointStats, 0, len(list))
+	for _, ep := range list {
+		s := r.state(kind, ep)
+		st := EndpointStats{
+			Kind:        kind,
+			Endpoint:    ep,
+			Calls:       s.n,
+			SuccessRate: s.successRate(),
+			P95:         s.p95(),
+			Height:      s.height,
+			CircuitOpen: s.open(),
+			Preferred:   len(order) > 0 && order[0] == ep,
+		}
+		if s.height > 0 {
+			st.HeightLag = best - s.height
+		}
+		stats = append(stats, st)
+	}
+	return stats
+}
+
+// RecordEndpointCall records the outcome of a call to endpoint.
+func RecordEndpointCall(kind, endpoint string, latency time.Duration, err error) {
+	endpointStates.record(kind, endpoint, latency, err, time.Now())
+}
+
+// RecordEndpointHeight records the latest block height endpoint reported.
+func RecordEndpointHeight(kind, endpoint string, height int64) {
+	endpointStates.recordHeight(kind, endpoint, height)
+}
+
+// EndpointProbeDue reports whether endpoint may be probed: its circuit is
+// closed or its cooldown is over.
+func EndpointProbeDue(kind, endpoint string) bool {
+	return endpointStates.probeDue(kind, endpoint, time.Now())
+}
+
+// OrderEndpoints returns list in the order it should be tried.
+func OrderEndpoints(kind string, list []string) []string {
+	return endpointStates.order(kind, list, time.Now())
+}
+
+// BestEndpoint returns the endpoint of list to use, "" when list is empty.
+func BestEndpoint(kind string, list []string) string {
+	if order := OrderEndpoints(kind, list); len(order) > 0 {
+		return order[0]
+	}
+	return ""
+}
+
+// EndpointHealth returns the health of each endpoint of list, in list order.
+func EndpointHealth(kind string, list []string) []EndpointStats {
+	return endpointStates.health(kind, list, time.Now())
+}
+
+// EndpointLabel is the host of endpoint, as shown in logs, alerts and
+// metrics: endpoint URLs may carry API keys in their path or query.
+func EndpointLabel(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return endpoint
+	}
+	return u.Host
+}
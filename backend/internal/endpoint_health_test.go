@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errDown = errors.New("connection refused")
+
+func TestEndpointOrder_PrefersHealthyAndFresh(t *testing.T) {
+	r := newEndpointRegistry()
+	now := time.Now()
+	list := []string{"a", "b", "c"}
+
+	assert.Equal(t, list, r.order(EndpointRPC, list, now), "no health recorded: configured order")
+
+	// a is behind the others.
+	r.recordHeight(EndpointRPC, "a", 100)
+	r.recordHeight(EndpointRPC, "b", 110)
+	r.recordHeight(EndpointRPC, "c", 109)
+	assert.Equal(t, []string{"b", "c", "a"}, r.order(EndpointRPC, list, now))
+
+	// b is much slower than c.
+	for i := 0; i < 10; i++ {
+		r.record(EndpointRPC, "b", 900*time.Millisecond, nil, now)
+		r.record(EndpointRPC, "c", 50*time.Millisecond, nil, now)
+	}
+	assert.Equal(t, []string{"c", "b", "a"}, r.order(EndpointRPC, list, now))
+}
+
+func TestEndpointOrder_SimilarLatencyKeepsConfiguredOrder(t *testing.T) {
+	r := newEndpointRegistry()
+	now := time.Now()
+	r.record(EndpointGraphQL, "a", 80*time.Millisecond, nil, now)
+	r.record(EndpointGraphQL, "b", 20*time.Millisecond, nil, now)
+	assert.Equal(t, []string{"a", "b"}, r.order(EndpointGraphQL, []string{"a", "b"}, now))
+}
+
+func TestEndpointCircuit_OpensAndFailsBack(t *testing.T) {
+	r := newEndpointRegistry()
+	now := time.Now()
+	list := []string{"primary", "backup"}
+
+	r.record(EndpointRPC, "primary", time.Second, errDown, now)
+	r.record(EndpointRPC, "primary", time.Second, errDown, now)
+	assert.True(t, r.probeDue(EndpointRPC, "primary", now), "two failures do not open the circuit")
+	r.record(EndpointRPC, "primary", time.Second, errDown, now)
+	assert.False(t, r.probeDue(EndpointRPC, "primary", now))
+	assert.Equal(t, []string{"backup", "primary"}, r.order(EndpointRPC, list, now))
+
+	// A failed probe after the cooldown doubles it.
+	later := now.Add(endpointMinCooldown)
+	assert.True(t, r.probeDue(EndpointRPC, "primary", later))
+	r.record(EndpointRPC, "primary", time.Second, errDown, later)
+	assert.False(t, r.probeDue(EndpointRPC, "primary", later.Add(endpointMinCooldown)))
+	assert.True(t, r.probeDue(EndpointRPC, "primary", later.Add(2*endpointMinCooldown)))
+
+	// A successful probe closes the circuit; with a good window again the
+	// primary is preferred.
+	later = later.Add(2 * endpointMinCooldown)
+	for i := 0; i < endpointWindow; i++ {
+		r.record(EndpointRPC, "primary", 10*time.Millisecond, nil, later)
+	}
+	assert.Equal(t, list, r.order(EndpointRPC, list, later))
+}
+
+func TestEndpointState_P95AndSuccessRate(t *testing.T) {
+	var s endpointState
+	now := time.Now()
+	for i := 1; i <= 20; i++ {
+		s.record(true, time.Duration(i)*time.Millisecond, now)
+	}
+	s.record(false, time.Minute, now)
+	assert.Equal(t, 19*time.Millisecond, s.p95(), "failed calls do not count toward latency")
+	assert.InDelta(t, 20.0/21.0, s.successRate(), 1e-9)
+
+	// The window only keeps the latest calls.
+	for i := 0; i < endpointWindow; i++ {
+		s.record(true, time.Millisecond, now)
+	}
+	assert.Equal(t, 1.0, s.successRate())
+	assert.Equal(t, time.Millisecond, s.p95())
+}
+
+func TestEndpointHealth(t *testing.T) {
+	r := newEndpointRegistry()
+	now := time.Now()
+	r.recordHeight(EndpointRPC, "a", 50)
+	r.recordHeight(EndpointRPC, "b", 60)
+	stats := r.health(EndpointRPC, []string{"a", "b", "c"}, now)
+	require.Len(t, stats, 3)
+	assert.Equal(t, int64(10), stats[0].HeightLag)
+	assert.True(t, stats[1].Preferred)
+	assert.Zero(t, stats[2].HeightLag, "no height reported: no lag")
+}
+
+func TestEndpointLabel(t *testing.T) {
+	assert.Equal(t, "rpc.example.com:443", EndpointLabel("https://rpc.example.com:443/apikey/s3cret?token=x"))
+	assert.Equal(t, "not a url", EndpointLabel("not a url"))
+}
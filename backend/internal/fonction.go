@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -30,7 +31,21 @@ type ChainConfig struct {
 	GraphqlEndpoints []string `yaml:"graphqls"`
 	GnowebEndpoints  []string `yaml:"gnowebs"`
 	Enabled          bool     `yaml:"enabled"`
-}
+	// CrossCheckEndpoints compares the blocks served by every RPC endpoint
+	// instead of only failing over between them.
+	CrossCheckEndpoints bool `yaml:"cross_check_endpoints"`
+	// BlockIngestion is how new blocks are noticed: BlockIngestionPoll
+	// (the default) or BlockIngestionWebsocket.
+	BlockIngestion string `yaml:"block_ingestion"`
+}
+
+// Block ingestion modes. tm2 RPC serves no event subscriptions, so the
+// websocket mode subscribes to new blocks on the chain's GraphQL indexer;
+// the blocks themselves are still read over RPC.
+const (
+	BlockIngestionPoll      = "poll"
+	BlockIngestionWebsocket = "websocket"
+)
 
 func (c *ChainConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	var raw struct {
@@ -41,11 +56,20 @@ func (c *ChainConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		GnowebEndpoint   string   `yaml:"gnoweb"`
 		GnowebEndpoints  []string `yaml:"gnowebs"`
 		Enabled          bool     `yaml:"enabled"`
+		CrossCheck       bool     `yaml:"cross_check_endpoints"`
+		BlockIngestion   string   `yaml:"block_ingestion"`
 	}
 	if err := unmarshal(&raw); err != nil {
 		return err
 	}
 	c.Enabled = raw.Enabled
+	c.CrossCheckEndpoints = raw.CrossCheck
+	switch raw.BlockIngestion {
+	case "", BlockIngestionPoll, BlockIngestionWebsocket:
+		c.BlockIngestion = raw.BlockIngestion
+	default:
+		return fmt.Errorf("block_ingestion: unknown mode %q (want %q or %q)", raw.BlockIngestion, BlockIngestionPoll, BlockIngestionWebsocket)
+	}
 	if len(raw.RPCEndpoints) > 0 {
 		c.RPCEndpoints = raw.RPCEndpoints
 	} else if raw.RPCEndpoint != "" {
@@ -103,6 +127,86 @@ func (c DatabaseConfig) DSN() string {
 		c.Host, c.Port, c.User, c.Password, c.DBName, sslmode)
 }
 
+// SMTPConfig is the mail server used for email notifications. Email is
+// disabled while Host or From is empty.
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+	TLS      bool   `yaml:"tls"` // implicit TLS (port 465); otherwise STARTTLS is used when offered
+}
+
+func (c SMTPConfig) Enabled() bool {
+	return c.Host != "" && c.From != ""
+}
+
+func (c SMTPConfig) Addr() string {
+	port := c.Port
+	if port == 0 {
+		port = 587
+		if c.TLS {
+			port = 465
+		}
+	}
+	return net.JoinHostPort(c.Host, strconv.Itoa(port))
+}
+
+// DiscordBotConfig is the Discord application of the validator bot. Discord
+// posts slash commands and button clicks to /discord/interactions, signed
+// with the application's PublicKey; Token is the bot token used to register
+// the slash commands and to post alerts and daily reports in channels. The
+// bot is disabled while PublicKey is empty.
+type DiscordBotConfig struct {
+	ApplicationID string `yaml:"application_id"`
+	PublicKey     string `yaml:"public_key"` // hex Ed25519 key from the developer portal
+	Token         string `yaml:"token"`
+}
+
+func (c DiscordBotConfig) Enabled() bool {
+	return c.PublicKey != ""
+}
+
+// SlackAppConfig is the Slack app of the validator bot. Slack posts the
+// /gnomon command and button clicks to /slack/interactions, signed with
+// SigningSecret; BotToken (xoxb-) posts alerts in the subscribed channels.
+// The app is disabled while SigningSecret is empty.
+type SlackAppConfig struct {
+	SigningSecret string `yaml:"signing_secret"`
+	BotToken      string `yaml:"bot_token"`
+}
+
+func (c SlackAppConfig) Enabled() bool {
+	return c.SigningSecret != ""
+}
+
+// TelegramWebhookConfig switches Telegram bots from getUpdates long-polling
+// to a webhook served by the API on /telegram/webhook/<bot>, so several
+// replicas can run behind a load balancer. Telegram posts to PublicURL plus
+// that path; SecretToken defaults to a value derived from each bot token.
+type TelegramWebhookConfig struct {
+	PublicURL   string `yaml:"public_url"` // external base URL of the API, e.g. https://monitoring.example.com
+	SecretToken string `yaml:"secret_token"`
+	Validator   bool   `yaml:"validator"`
+	Govdao      bool   `yaml:"govdao"`
+}
+
+// Enabled reports whether bot ("validator" or "govdao") runs in webhook
+// mode.
+func (c TelegramWebhookConfig) Enabled(bot string) bool {
+	if c.PublicURL == "" {
+		return false
+	}
+	switch bot {
+	case "validator":
+		return c.Validator
+	case "govdao":
+		return c.Govdao
+	}
+	return false
+}
+
 type config struct {
 	BackendPort            string                  `yaml:"backend_port"`
 	AllowOrigin            string                  `yaml:"allow_origin"`
@@ -114,6 +218,10 @@ type config struct {
 	Chains                 map[string]*ChainConfig `yaml:"chains"`
 	DefaultChain           string                  `yaml:"default_chain"`
 	Database               DatabaseConfig          `yaml:"database"`
+	SMTP                   SMTPConfig              `yaml:"smtp"`
+	DiscordBot             DiscordBotConfig        `yaml:"discord_bot"`
+	SlackApp               SlackAppConfig          `yaml:"slack_app"`
+	TelegramWebhook        TelegramWebhookConfig   `yaml:"telegram_webhook"`
 
 	// Parsed at load time from AllowOrigin (comma-separated).
 	AllowedOrigins []string `yaml:"-"`
@@ -124,11 +232,11 @@ var Config config
 // EnabledChains holds the IDs of all chains with Enabled: true, sorted alphabetically.
 var EnabledChains []string
 
-// isPublicUnicastIP reports whether ip is safe to connect to for an
-// outbound webhook: not loopback, not link-local (this also blocks the
+// IsPublicUnicastIP reports whether ip is safe to connect to for an
+// outbound webhook or peer RPC call: not loopback, not link-local (this also blocks the
 // 169.254.169.254 cloud metadata endpoint), not a private (RFC1918/RFC4193)
 // range, not unspecified, and not multicast.
-func isPublicUnicastIP(ip net.IP) bool {
+func IsPublicUnicastIP(ip net.IP) bool {
 	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
 		ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() {
 		return false
@@ -156,7 +264,7 @@ func guardedDialContext(ctx context.Context, network, addr string) (net.Conn, er
 		return nil, fmt.Errorf("no addresses found for %s", host)
 	}
 	for _, ip := range ips {
-		if !isPublicUnicastIP(ip) {
+		if !IsPublicUnicastIP(ip) {
 			return nil, fmt.Errorf("refusing to dial non-public address %s (resolved from %s)", ip, host)
 		}
 	}
@@ -237,6 +345,11 @@ func LoadConfig() {
 		}
 	}
 
+	if s := Config.TelegramWebhook.SecretToken; s != "" && !telegram.ValidWebhookSecret(s) {
+		log.Fatalf("Config error: telegram_webhook.secret_token must be 1-256 characters among A-Z, a-z, 0-9, _ and -")
+	}
+	Config.TelegramWebhook.PublicURL = strings.TrimRight(Config.TelegramWebhook.PublicURL, "/")
+
 	log.Printf("DevMode value: %v", Config.DevMode)
 	log.Printf("Allowed CORS origins: %v", Config.AllowedOrigins)
 }
@@ -385,8 +498,7 @@ type DiscordEmbedFooter struct {
 
 // SendDiscordEmbed posts a single rich embed to a Discord webhook, used by
 // the daily report for a channel-appropriate rendering instead of a plain
-// text message (see SendDiscordAlert for the plain-text incident-alert path,
-// unchanged).
+// text message (alerts go through discordNotifier).
 func SendDiscordEmbed(embed DiscordEmbed, webhookURL string) error {
 	payload := map[string]any{"embeds": []DiscordEmbed{embed}}
 	body, err := json.Marshal(payload)
@@ -406,66 +518,6 @@ func SendDiscordEmbed(embed DiscordEmbed, webhookURL string) error {
 	return nil
 }
 
-// SendDiscordAlertEmbed posts a single rich embed alongside a plain-text
-// content string to a Discord webhook. content carries @mentions, which
-// Discord does not parse when written inside an embed (see
-// RenderAlertDiscordEmbed); an empty content is omitted from the payload
-// entirely rather than sent as "".
-func SendDiscordAlertEmbed(content string, embed DiscordEmbed, webhookURL string) error {
-	payload := map[string]any{"embeds": []DiscordEmbed{embed}}
-	if content != "" {
-		payload["content"] = content
-	}
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("marshal discord alert embed: %w", err)
-	}
-
-	resp, err := alertHTTPClient.Post(webhookURL, "application/json", bytes.NewBuffer(body))
-	if err != nil {
-		return fmt.Errorf("error sending Discord alert embed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("discord webhook HTTP status: %d", resp.StatusCode)
-	}
-	return nil
-}
-
-func SendDiscordAlert(msg string, webhookURL string) error {
-	payload := map[string]string{"content": msg}
-	body, _ := json.Marshal(payload)
-
-	resp, err := alertHTTPClient.Post(webhookURL, "application/json", bytes.NewBuffer(body))
-	if err != nil {
-		return fmt.Errorf("error sending Discord alert: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("discord webhook HTTP status: %d", resp.StatusCode)
-	}
-	return nil
-}
-
-func SendSlackAlert(msg string, webhookURL string) error {
-	payload := map[string]string{"text": msg}
-	body, _ := json.Marshal(payload)
-
-	resp, err := alertHTTPClient.Post(webhookURL, "application/json", bytes.NewBuffer(body))
-	if err != nil {
-		log.Printf("Erreur sending Slack alert : %v", err)
-		return nil
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("Slack webhook HTTP %d", resp.StatusCode)
-	}
-	return nil
-}
-
 // SlackBlock mirrors the subset of Slack's Block Kit this project uses. See
 // https://api.slack.com/block-kit for the full schema.
 type SlackBlock struct {
@@ -481,9 +533,7 @@ type SlackText struct {
 
 // SendSlackBlocks posts a Block Kit message to a Slack incoming webhook,
 // used by the daily report for a channel-appropriate rendering instead of a
-// plain text message (see SendSlackAlert for the plain-text incident-alert
-// path, unchanged). Unlike SendSlackAlert, this returns a non-nil error on a
-// non-2xx response rather than only logging it.
+// plain text message (alerts go through slackNotifier).
 func SendSlackBlocks(blocks []SlackBlock, webhookURL string) error {
 	payload := map[string]any{"blocks": blocks}
 	body, err := json.Marshal(payload)
@@ -503,19 +553,65 @@ func SendSlackBlocks(blocks []SlackBlock, webhookURL string) error {
 	return nil
 }
 
-func SendAllValidatorAlerts(chainID string, missed int, today, level, addr, moniker string, start_height, end_height int64, db *gorm.DB) error {
-	type Webhook struct {
-		UserID  string
-		URL     string
-		Type    string
-		ID      int
-		ChainID *string
+// MatrixMessage is the payload of a Matrix hookshot generic webhook
+// (https://matrix-org.github.io/matrix-hookshot/latest/setup/webhooks.html):
+// Text becomes the event's plain-text body and HTML its formatted_body.
+type MatrixMessage struct {
+	Text string `json:"text"`
+	HTML string `json:"html,omitempty"`
+}
+
+// SendMatrixMessage posts m to a Matrix hookshot webhook, used by the daily
+// report (alerts go through matrixNotifier).
+func SendMatrixMessage(m MatrixMessage, webhookURL string) error {
+	body, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("marshal matrix message: %w", err)
 	}
-	var webhooks []Webhook
-	if err := db.Model(&database.WebhookValidator{}).
-		Where("chain_id = ? OR chain_id IS NULL", chainID).
-		Find(&webhooks).Error; err != nil {
-		return fmt.Errorf("failed to fetch webhooks: %w", err)
+	return postJSON("matrix webhook", webhookURL, body)
+}
+
+// MattermostMessage mirrors the subset of a Mattermost incoming webhook
+// payload this project uses. Attachments follow Slack's legacy attachment
+// schema, see https://developers.mattermost.com/integrate/reference/message-attachments/.
+type MattermostMessage struct {
+	Text        string                 `json:"text,omitempty"`
+	Attachments []MattermostAttachment `json:"attachments,omitempty"`
+}
+
+type MattermostAttachment struct {
+	Fallback  string            `json:"fallback"`
+	Color     string            `json:"color,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	TitleLink string            `json:"title_link,omitempty"`
+	Text      string            `json:"text,omitempty"`
+	Fields    []MattermostField `json:"fields,omitempty"`
+	Footer    string            `json:"footer,omitempty"`
+}
+
+type MattermostField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short,omitempty"`
+}
+
+// SendMattermostMessage posts m to a Mattermost incoming webhook, used by
+// the daily report (alerts go through mattermostNotifier).
+func SendMattermostMessage(m MattermostMessage, webhookURL string) error {
+	body, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("marshal mattermost message: %w", err)
+	}
+	return postJSON("mattermost webhook", webhookURL, body)
+}
+
+// SendAllValidatorAlerts queues a missed-blocks alert for addr to every
+// validator webhook of chainID and the Telegram chats subscribed to addr.
+// alertLogID is the alert_logs row recorded for it.
+func SendAllValidatorAlerts(chainID string, missed int, today, level, addr, moniker string, start_height, end_height int64, alertLogID uint, db *gorm.DB) error {
+	webhooks, err := validatorWebhookDestinations(db, chainID)
+	if err != nil {
+		return err
 	}
 
 	var alertLevel AlertLevel
@@ -540,95 +636,140 @@ func SendAllValidatorAlerts(chainID string, missed int, today, level, addr, moni
 			{Name: "moniker", Value: moniker},
 			{Name: "missed blocks", Value: fmt.Sprintf("%d (%d -> %d)", missed, start_height, end_height)},
 		},
+		Addr:        addr,
+		Moniker:     moniker,
+		StartHeight: start_height,
+		EndHeight:   end_height,
+		Kind:        AlertKindMissedBlocks,
+	}
+	if alertLevel == AlertCritical {
+		data.Incident = IncidentTrigger
+	}
+
+	dests := append(webhooks, validatorAlertChats(db, chainID, addr)...)
+	dests = append(dests, validatorAlertChannels(db, chainID, addr)...)
+	dests = append(dests, validatorAlertSlackChannels(db, chainID, addr)...)
+	dests = append(dests, emailAlertDestinations(db, chainID, data)...)
+	return EnqueueAlert(db, alertLogID, dests, func(dest Destination) AlertData {
+		if level != "CRITICAL" || dest.WebhookID == 0 {
+			return data
+		}
+		mentions, err := alertMentions(db, dest, moniker)
+		if err != nil {
+			log.Printf("❌ %v", err)
+			return data
+		}
+		whData := data
+		whData.Mentions = mentions
+		return whData
+	})
+}
+
+// SendEscalation queues tier (2 or 3) of policy for the open CRITICAL
+// incident inc of chainID: the tier's webhooks, and its alert contacts'
+// webhooks with their mention tags. The notification is linked to the
+// incident's first CRITICAL alert_logs row, whose ID acknowledges it.
+func SendEscalation(db *gorm.DB, chainID string, policy database.EscalationPolicy, tier int, inc database.OpenIncident) error {
+	after, webhookIDs, contactIDs := policy.Tier(tier)
+	dests, mentions, err := escalationDestinations(db, policy.UserID, webhookIDs, contactIDs)
+	if err != nil {
+		return err
 	}
 
-	for _, wh := range webhooks {
-		whData := data
-		if level == "CRITICAL" && (wh.Type == "discord" || wh.Type == "slack") {
-			type tag struct{ MentionTag string }
-			var res []tag
-			if err := db.Model(&database.AlertContact{}).
-				Select("mention_tag").
-				Where("user_id = ? AND moniker = ? AND id_webhook = ?", wh.UserID, moniker, wh.ID).
-				Find(&res).Error; err != nil {
-				return fmt.Errorf("failed to fetch mentions: %w", err)
-			}
-			for _, r := range res {
-				whData.Mentions = append(whData.Mentions, r.MentionTag)
-			}
+	data := AlertData{
+		ChainID: chainID,
+		Level:   AlertCritical,
+		Emoji:   "📣",
+		Title:   fmt.Sprintf("ESCALATION (tier %d)", tier),
+		Date:    time.Now().Format("2006-01-02"),
+		Fields: []AlertField{
+			{Name: "addr", Value: inc.Addr},
+			{Name: "moniker", Value: inc.Moniker},
+			{Name: "missed blocks", Value: fmt.Sprintf("%d -> %d", inc.StartHeight, inc.EndHeight)},
+			{Name: "unanswered for", Value: fmt.Sprintf("%d min (CRITICAL since %s)", int(after.Minutes()), inc.OpenedAt.UTC().Format("2006-01-02 15:04 UTC"))},
+		},
+		Addr:        inc.Addr,
+		Moniker:     inc.Moniker,
+		StartHeight: inc.StartHeight,
+		EndHeight:   inc.EndHeight,
+		Kind:        AlertKindMissedBlocks,
+		Incident:    IncidentTrigger,
+	}
+	return EnqueueAlert(db, inc.AlertLogID, dests, func(dest Destination) AlertData {
+		if len(mentions[dest.WebhookID]) == 0 {
+			return data
 		}
+		whData := data
+		whData.Mentions = mentions[dest.WebhookID]
+		return whData
+	})
+}
 
-		switch wh.Type {
-		case "discord":
-			content, embed := RenderAlertDiscordEmbed(whData)
-			if err := SendDiscordAlertEmbed(content, embed, wh.URL); err != nil {
-				log.Printf("❌ Failed to send alert to %s (%s): %v", wh.URL, wh.Type, err)
-				continue
-			}
-		case "slack":
-			blocks := RenderAlertSlackBlocks(whData)
-			if err := SendSlackBlocks(blocks, wh.URL); err != nil {
-				log.Printf("❌ Failed to send alert to %s (%s): %v", wh.URL, wh.Type, err)
-				continue
-			}
-		default:
-			continue
-		}
+// SendUserReportAlert queues a plain-text report chunk for userID's webhooks
+// for chainID. It returns the enqueue errors so callers can log which chunk
+// failed.
+func SendUserReportAlert(userID, chainID, msg string, db *gorm.DB) error {
+	var webhooks []database.WebhookValidator
+	if err := db.Where("user_id = ? AND chain_id = ?", userID, chainID).
+		Find(&webhooks).Error; err != nil {
+		return fmt.Errorf("failed to fetch webhooks for user %s: %w", userID, err)
 	}
 
-	text := RenderAlertTelegramHTML(data)
-	if err := telegram.MsgTelegramAlert(text, addr, chainID, Config.TokenTelegramValidator, "validator", db); err != nil {
-		log.Printf("❌ MsgTelegramAlert: %v", err)
+	dests := make([]Destination, 0, len(webhooks))
+	for _, wh := range webhooks {
+		dests = append(dests, ValidatorWebhookDestination(wh))
 	}
 
-	return nil
+	return EnqueueAlert(db, 0, dests, same(AlertData{
+		ChainID:     chainID,
+		Level:       AlertInfo,
+		Emoji:       "📊",
+		Title:       "Validator report",
+		Description: msg,
+	}))
 }
 
-func SendUserReportAlert(userID, chainID, msg string, db *gorm.DB) error {
-	type Webhook struct {
-		URL  string
-		Type string
-	}
-
-	var webhooks []Webhook
-	if err := db.Model(&database.WebhookValidator{}).
-		Select("url", "type").
-		Where("user_id = ? AND chain_id = ?", userID, chainID).
+// SendUserAlert queues data for userID's validator webhooks scoped to
+// chainID, and their unscoped ones: used for what a user registered to be
+// watched, which no one else follows.
+func SendUserAlert(userID, chainID string, data AlertData, db *gorm.DB) error {
+	var webhooks []database.WebhookValidator
+	if err := db.Where("user_id = ? AND (chain_id = ? OR chain_id IS NULL)", userID, chainID).
 		Find(&webhooks).Error; err != nil {
 		return fmt.Errorf("failed to fetch webhooks for user %s: %w", userID, err)
 	}
 
+	dests := make([]Destination, 0, len(webhooks))
 	for _, wh := range webhooks {
-		switch wh.Type {
-		case "discord":
-			if err := SendDiscordAlert(msg, wh.URL); err != nil {
-				return fmt.Errorf("failed to send Discord alert: %w", err)
-			}
-		case "slack":
-			if err := SendSlackAlert(msg, wh.URL); err != nil {
-				return fmt.Errorf("failed to send Slack alert: %w", err)
-			}
-		default:
-			log.Printf("⚠️ Unknown webhook type for user %s: %s", userID, wh.Type)
-		}
+		dests = append(dests, ValidatorWebhookDestination(wh))
 	}
-
-	return nil
+	return EnqueueAlert(db, 0, dests, same(data))
 }
-func SendResolveValidator(chainID, addr, moniker string, resumeHeight int64, db *gorm.DB) error {
-	type Webhook struct {
-		UserID  string
-		URL     string
-		Type    string
-		ID      int
-		ChainID *string
+
+// SendWatchAlert queues data for the owner of a watch: the webhooks of a
+// Clerk user ID (see SendUserAlert), or the validator-bot chat of
+// "telegram:<chat_id>".
+func SendWatchAlert(owner, chainID string, data AlertData, db *gorm.DB) error {
+	chat, ok := strings.CutPrefix(owner, "telegram:")
+	if !ok {
+		return SendUserAlert(owner, chainID, data, db)
 	}
+	chatID, err := strconv.ParseInt(chat, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid watch owner %q", owner)
+	}
+	dests := telegramDestinations("validator", Config.TokenTelegramValidator, []int64{chatID})
+	return EnqueueAlert(db, 0, dests, same(data))
+}
 
-	var webhooks []Webhook
-	if err := db.Model(&database.WebhookValidator{}).
-		Where("chain_id = ? OR chain_id IS NULL", chainID).
-		Find(&webhooks).Error; err != nil {
-		return fmt.Errorf("failed to fetch webhooks: %w", err)
+// SendResolveValidator announces that addr is signing again. level and
+// startHeight are those of the alert being resolved: level drives webhook
+// routing and startHeight ties the resolve to the incident opened for it in
+// PagerDuty/Opsgenie. alertLogID is the RESOLVED alert_logs row.
+func SendResolveValidator(chainID, addr, moniker, level string, startHeight, resumeHeight int64, alertLogID uint, db *gorm.DB) error {
+	webhooks, err := validatorWebhookDestinations(db, chainID)
+	if err != nil {
+		return err
 	}
 
 	data := AlertData{
@@ -640,170 +781,110 @@ func SendResolveValidator(chainID, addr, moniker string, resumeHeight int64, db
 			{Name: "validator", Value: fmt.Sprintf("%s (%s)", moniker, addr)},
 			{Name: "resolved at block", Value: fmt.Sprintf("%d", resumeHeight)},
 		},
+		Addr:          addr,
+		Moniker:       moniker,
+		StartHeight:   startHeight,
+		EndHeight:     resumeHeight,
+		Incident:      IncidentResolve,
+		Kind:          AlertKindMissedBlocks,
+		ResolvedLevel: AlertLevel(level),
+	}
+
+	dests := append(webhooks, validatorAlertChats(db, chainID, addr)...)
+	dests = append(dests, validatorAlertChannels(db, chainID, addr)...)
+	dests = append(dests, validatorAlertSlackChannels(db, chainID, addr)...)
+	dests = append(dests, emailAlertDestinations(db, chainID, data)...)
+	return EnqueueAlert(db, alertLogID, dests, same(data))
+}
+
+// SendValidatorAlert queues data, an alert about the validator data.Addr,
+// for every validator webhook of chainID and the chats subscribed to that
+// validator, like the missed-blocks alerts. alertLogID is the alert_logs
+// row recorded for it, 0 when none is.
+func SendValidatorAlert(chainID string, data AlertData, alertLogID uint, db *gorm.DB) error {
+	webhooks, err := validatorWebhookDestinations(db, chainID)
+	if err != nil {
+		return err
 	}
 
-	for _, wh := range webhooks {
-		switch wh.Type {
-		case "discord":
-			content, embed := RenderAlertDiscordEmbed(data)
-			if err := SendDiscordAlertEmbed(content, embed, wh.URL); err != nil {
-				log.Printf("❌ Failed to send alert to %s (%s): %v", wh.URL, wh.Type, err)
-				continue
-			}
-		case "slack":
-			blocks := RenderAlertSlackBlocks(data)
-			if err := SendSlackBlocks(blocks, wh.URL); err != nil {
-				log.Printf("❌ Failed to send alert to %s (%s): %v", wh.URL, wh.Type, err)
-				continue
-			}
-		}
-	}
-	text := RenderAlertTelegramHTML(data)
-	if err := telegram.MsgTelegramAlert(text, addr, chainID, Config.TokenTelegramValidator, "validator", db); err != nil {
-		log.Printf("❌ MsgTelegramAlert: %v", err)
-	}
-
-	return nil
+	dests := append(webhooks, validatorAlertChats(db, chainID, data.Addr)...)
+	dests = append(dests, validatorAlertChannels(db, chainID, data.Addr)...)
+	dests = append(dests, validatorAlertSlackChannels(db, chainID, data.Addr)...)
+	dests = append(dests, emailAlertDestinations(db, chainID, data)...)
+	return EnqueueAlert(db, alertLogID, dests, same(data))
 }
 
-func SendInfoValidator(chainID string, data AlertData, db *gorm.DB) error {
-	type Webhook struct {
-		UserID  string
-		URL     string
-		Type    string
-		ID      int
-		ChainID *string
+// SendInfoValidator queues a chain-level notification for every validator
+// webhook of chainID and the chats following it. alertLogID is the
+// alert_logs row recorded for it, 0 when none is.
+func SendInfoValidator(chainID string, data AlertData, alertLogID uint, db *gorm.DB) error {
+	webhooks, err := validatorWebhookDestinations(db, chainID)
+	if err != nil {
+		return err
 	}
 
-	var webhooks []Webhook
-	if err := db.Model(&database.WebhookValidator{}).
-		Where("chain_id = ? OR chain_id IS NULL", chainID).
-		Find(&webhooks).Error; err != nil {
-		return fmt.Errorf("failed to fetch webhooks: %w", err)
-	}
-	for _, wh := range webhooks {
-		switch wh.Type {
-		case "discord":
-			content, embed := RenderAlertDiscordEmbed(data)
-			if err := SendDiscordAlertEmbed(content, embed, wh.URL); err != nil {
-				log.Printf("❌ Failed to send alert to %s (%s): %v", wh.URL, wh.Type, err)
-				continue
-			}
-		case "slack":
-			blocks := RenderAlertSlackBlocks(data)
-			if err := SendSlackBlocks(blocks, wh.URL); err != nil {
-				log.Printf("❌ Failed to send alert to %s (%s): %v", wh.URL, wh.Type, err)
-				continue
-			}
-		}
-	}
-	text := RenderAlertTelegramHTML(data)
-	if err := telegram.MsgTelegramChain(text, chainID, Config.TokenTelegramValidator, "validator", db); err != nil {
-		log.Printf("❌ MsgTelegramChain: %v", err)
-	}
-	return nil
+	dests := append(webhooks, validatorChainChats(db, chainID)...)
+	dests = append(dests, validatorChainChannels(db, chainID)...)
+	dests = append(dests, validatorChainSlackChannels(db, chainID)...)
+	dests = append(dests, emailAlertDestinations(db, chainID, data)...)
+	return EnqueueAlert(db, alertLogID, dests, same(data))
 }
 
-func MultiSendReportGovdao(chainID string, id int, title, urlgnoweb, urltx string, db *gorm.DB) error {
+// govdaoVoteURL is the Memba page where a GovDAO proposal can be voted on.
+func govdaoVoteURL(id int) string {
+	return fmt.Sprintf("https://memba.samourai.app/dao/gno.land~r~gov~dao/proposal/%d", id)
+}
 
-	type Webhook struct {
-		UserID        string
-		URL           string
-		Type          string
-		LastCheckedID int
-	}
-	var webhooks []Webhook
-	if err := db.Model(&database.WebhookGovDAO{}).Find(&webhooks).Error; err != nil {
-		return fmt.Errorf("failed to fetch webhooks: %w", err)
-	}
-	for _, wh := range webhooks {
-		if err := SendReportGovdao(chainID, id, title, urlgnoweb, urltx, wh.Type, wh.URL); err != nil {
-			log.Printf("❌ SendReportGovdao: %v", err)
-		}
-	}
-	// build msg for telegram and send to all chatids
-	msg := telegram.FormatTelegramMsg(chainID, id, title, urlgnoweb, urltx)
-	err := telegram.MsgTelegram(msg, Config.TokenTelegramGovdao, "govdao", db)
-	if err != nil {
-		log.Printf("error send govdao telegram  %s", err)
+// GovdaoProposalAlert builds the "new proposal" notification for proposal id.
+// The Tx field is left out when the tx hash could not be resolved (urltx "").
+func GovdaoProposalAlert(chainID string, id int, title, urlgnoweb, urltx string) AlertData {
+	fields := []AlertField{{Name: "🔗 Source", Value: "Gno.land", URL: urlgnoweb}}
+	if urltx != "" {
+		fields = append(fields, AlertField{Name: "🗒️ Tx", Value: "Gnoscan", URL: urltx})
 	}
+	fields = append(fields, AlertField{Name: "🖐️ Interact & Vote", Value: "Open proposal on Memba", URL: govdaoVoteURL(id)})
 
-	return nil
-
+	return AlertData{
+		ChainID:     chainID,
+		Level:       AlertInfo,
+		Emoji:       "🗳️",
+		Title:       fmt.Sprintf("New Proposal N° %d: %s", id, title),
+		Description: "Make sure you're using the appropriate network on Memba",
+		Fields:      fields,
+	}
 }
 
-func SendReportGovdao(chainID string, id int, title, urlgnoweb, urltx, typew string, urlwebhook string) error {
-
-	switch typew {
-	case "discord":
-
-		msg := fmt.Sprintf("--- \n"+
-			"🗳️ ** [%s] New Proposal N° %d: %s ** -  \n"+
-			"🔗source: %s \n"+
-			"🗒️Tx: %s \n"+
-			"🖐️ Interact & Vote: https://memba.samourai.app/dao/gno.land~r~gov~dao/proposal/%d \n"+
-			" ** Make sure you're using the appropriate network on Memba **",
-			chainID, id, title, urlgnoweb, urltx, id)
-		log.Println(msg)
-		sendErr := SendDiscordAlert(msg, urlwebhook)
-		if sendErr != nil {
-			log.Printf("❌ Failed to send alert to %s (%s): %v", urlwebhook, typew, sendErr)
-
-		}
-
-	case "slack":
-		msg := fmt.Sprintf("--- \n"+
-			"🗳️ * [%s] New Proposal N° %d: %s * -  \n"+
-			"🔗source: %s \n"+
-			"🗒️Tx: %s \n"+
-			"🖐️ Interact & Vote: https://memba.samourai.app/dao/gno.land~r~gov~dao/proposal/%d \n"+
-			"* Make sure you're using the appropriate network on Memba *",
-			chainID, id, title, urlgnoweb, urltx, id)
-
-		sendErr := SendSlackAlert(msg, urlwebhook)
-		if sendErr != nil {
-			log.Printf("❌ Failed to send alert to %s (%s): %v", urlwebhook, typew, sendErr)
-
-		}
-
+// GovdaoAcceptedAlert builds the notification sent when proposal id moves to
+// the ACCEPTED status.
+func GovdaoAcceptedAlert(chainID string, id int, title, urlgnoweb string) AlertData {
+	return AlertData{
+		ChainID: chainID,
+		Level:   AlertResolved,
+		Emoji:   "✅",
+		Title:   fmt.Sprintf("Proposal N° %d: %s", id, title),
+		Fields: []AlertField{
+			{Name: "🔗 Source", Value: "Gno.land", URL: urlgnoweb},
+			{Name: "status", Value: "ACCEPTED"},
+		},
 	}
+}
 
-	return nil
-
+func MultiSendReportGovdao(chainID string, id int, title, urlgnoweb, urltx string, db *gorm.DB) error {
+	return SendInfoGovdao(chainID, GovdaoProposalAlert(chainID, id, title, urlgnoweb, urltx), db)
 }
 
-func SendInfoGovdao(chainID string, msg string, db *gorm.DB) error {
-	msg = fmt.Sprintf("[%s] %s", chainID, msg)
-	type Webhook struct {
-		UserID        string
-		URL           string
-		Type          string
-		LastCheckedID int
-	}
+// SendReportGovdao sends the "new proposal" notification to a single webhook
+// (used to post a sample proposal when a GovDAO webhook is registered).
+func SendReportGovdao(chainID string, id int, title, urlgnoweb, urltx string, dest Destination) error {
+	return Deliver([]Destination{dest}, same(GovdaoProposalAlert(chainID, id, title, urlgnoweb, urltx)))
+}
 
-	var webhooks []Webhook
-	if err := db.Model(&database.WebhookGovDAO{}).Find(&webhooks).Error; err != nil {
-		return fmt.Errorf("failed to fetch webhooks: %w", err)
+// SendInfoGovdao queues data for every GovDAO webhook and GovDAO-bot chat.
+func SendInfoGovdao(chainID string, data AlertData, db *gorm.DB) error {
+	webhooks, err := govdaoWebhookDestinations(db)
+	if err != nil {
+		return err
 	}
-	for _, wh := range webhooks {
-		switch wh.Type {
-		case "discord":
-			sendErr := SendDiscordAlert(msg, wh.URL)
-			if sendErr != nil {
-				log.Printf("❌ Failed to send alert to %s (%s): %v", wh.URL, wh.Type, sendErr)
-				continue
-			}
-
-		case "slack":
-			sendErr := SendSlackAlert(msg, wh.URL)
-			if sendErr != nil {
-				log.Printf("❌ Failed to send alert to %s (%s): %v", wh.URL, wh.Type, sendErr)
-				continue
-			}
-
-		}
-		// database.InsertAlertlog(db, wh.addr, "moniker", level, wh.URL, 0, 0, true, msg, time.Now())
 
-	}
-	return nil
+	return EnqueueAlert(db, 0, append(webhooks, govdaoChats(db)...), same(data))
 }
@@ -376,9 +376,9 @@ func TestIsPublicUnicastIP(t *testing.T) {
 			if ip == nil {
 				t.Fatalf("test fixture invalid IP: %s", tc.ip)
 			}
-			got := isPublicUnicastIP(ip)
+			got := IsPublicUnicastIP(ip)
 			if got != tc.want {
-				t.Fatalf("isPublicUnicastIP(%s) = %v, want %v", tc.ip, got, tc.want)
+				t.Fatalf("IsPublicUnicastIP(%s) = %v, want %v", tc.ip, got, tc.want)
 			}
 		})
 	}
@@ -440,45 +440,14 @@ func TestSendDiscordEmbed_PostsEmbedsArray(t *testing.T) {
 	}
 }
 
-func TestSendDiscordAlertEmbed_PostsContentAndEmbeds(t *testing.T) {
-	var captured map[string]any
-	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		json.NewDecoder(r.Body).Decode(&captured)
-		w.WriteHeader(http.StatusNoContent)
-	}))
-	defer srv.Close()
-
-	orig := alertHTTPClient
-	alertHTTPClient = &http.Client{Timeout: 10 * time.Second}
-	defer func() { alertHTTPClient = orig }()
-
-	embed := DiscordEmbed{Title: "t", Color: 0xE74C3C}
-	if err := SendDiscordAlertEmbed("<@111>", embed, srv.URL); err != nil {
+func TestDiscordNotifierRender_OmitsContentWhenEmpty(t *testing.T) {
+	body, err := discordNotifier{}.Render(AlertData{Level: AlertInfo, Title: "t"})
+	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if captured["content"] != "<@111>" {
-		t.Fatalf("content not passed through, got: %+v", captured)
-	}
-	embeds, ok := captured["embeds"].([]any)
-	if !ok || len(embeds) != 1 {
-		t.Fatalf("expected a single-element embeds array, got: %+v", captured)
-	}
-}
-
-func TestSendDiscordAlertEmbed_OmitsContentWhenEmpty(t *testing.T) {
 	var captured map[string]any
-	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		json.NewDecoder(r.Body).Decode(&captured)
-		w.WriteHeader(http.StatusNoContent)
-	}))
-	defer srv.Close()
-
-	orig := alertHTTPClient
-	alertHTTPClient = &http.Client{Timeout: 10 * time.Second}
-	defer func() { alertHTTPClient = orig }()
-
-	if err := SendDiscordAlertEmbed("", DiscordEmbed{Title: "t"}, srv.URL); err != nil {
-		t.Fatalf("unexpected error: %v", err)
+	if err := json.Unmarshal(body, &captured); err != nil {
+		t.Fatalf("invalid payload: %v", err)
 	}
 	if _, present := captured["content"]; present {
 		t.Fatalf("content key must be absent when empty, got: %+v", captured)
@@ -526,3 +495,85 @@ func TestSendSlackBlocks_NonOKStatusIsError(t *testing.T) {
 		t.Fatal("expected an error for a non-200 response, got nil")
 	}
 }
+
+// TestSendEscalation_ReachesTierTargets checks that an escalation tier goes
+// to its webhooks and to its contacts' webhooks with their mention tags, and
+// ignores targets that belong to another user.
+func TestSendEscalation_ReachesTierTargets(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+	chainID := "test12"
+
+	oncall := database.WebhookValidator{UserID: "u1", URL: "https://discord.com/api/webhooks/1/oncall", Type: "discord", ChainID: &chainID}
+	lead := database.WebhookValidator{UserID: "u1", URL: "https://discord.com/api/webhooks/1/lead", Type: "discord", ChainID: &chainID}
+	foreign := database.WebhookValidator{UserID: "u2", URL: "https://discord.com/api/webhooks/2/other", Type: "discord", ChainID: &chainID}
+	require.NoError(t, db.Create(&oncall).Error)
+	require.NoError(t, db.Create(&lead).Error)
+	require.NoError(t, db.Create(&foreign).Error)
+	contact := database.AlertContact{UserID: "u1", Moniker: "any", NameContact: "lead", MentionTag: "424242", IDwebhook: lead.ID}
+	require.NoError(t, db.Create(&contact).Error)
+
+	policy := database.EscalationPolicy{
+		UserID:        "u1",
+		Tier2After:    15,
+		Tier2Webhooks: database.IntList{oncall.ID, foreign.ID},
+		Tier2Contacts: database.IntList{contact.ID},
+	}
+	inc := database.OpenIncident{AlertLogID: 9, Addr: "g1down", Moniker: "down", StartHeight: 100, EndHeight: 160, OpenedAt: time.Now().Add(-20 * time.Minute)}
+	require.NoError(t, SendEscalation(db, chainID, policy, 2, inc))
+
+	rows, err := database.ListAlertDeliveries(db, "", chainID, 0)
+	require.NoError(t, err)
+	require.Len(t, rows, 2, "the foreign webhook is ignored")
+	payloads := map[int]string{}
+	for _, row := range rows {
+		require.NotNil(t, row.AlertLogID)
+		assert.Equal(t, uint(9), *row.AlertLogID)
+		assert.Equal(t, "ESCALATION (tier 2)", row.Title)
+		payloads[row.WebhookID] = row.Payload
+	}
+	assert.Contains(t, payloads[lead.ID], "<@424242>")
+	assert.NotContains(t, payloads[oncall.ID], "<@424242>")
+}
+
+// TestSendValidatorAlert_ReachesValidatorSubscribers checks that an alert
+// about one validator goes to the chats subscribed to it and to the webhooks
+// whose filter accepts it, not to every chat of the chain.
+func TestSendValidatorAlert_ReachesValidatorSubscribers(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+	saved := Config.TokenTelegramValidator
+	defer func() { Config.TokenTelegramValidator = saved }()
+	Config.TokenTelegramValidator = "token"
+	const chainID, addr = "test12", "g1sentry"
+
+	require.NoError(t, db.Create(&[]database.Telegram{
+		{ChatID: 1, Type: "validator", ChainID: chainID},
+		{ChatID: 2, Type: "validator", ChainID: chainID},
+	}).Error)
+	require.NoError(t, db.Create(&[]database.TelegramValidatorSub{
+		{ChatID: 1, ChainID: chainID, Addr: addr, Activate: true},
+		{ChatID: 2, ChainID: chainID, Addr: "g1other", Activate: true},
+	}).Error)
+	wanted := database.WebhookValidator{UserID: "u1", URL: "https://discord.com/api/webhooks/1/a", Type: "discord"}
+	filtered := database.WebhookValidator{UserID: "u2", URL: "https://discord.com/api/webhooks/2/b", Type: "discord",
+		Filter: database.WebhookFilter{ExcludeValidators: database.StringList{addr}}}
+	require.NoError(t, db.Create(&wanted).Error)
+	require.NoError(t, db.Create(&filtered).Error)
+
+	data := AlertData{ChainID: chainID, Level: AlertWarning, Title: "Validator node left the peer list", Addr: addr, Kind: AlertKindSentryMissing}
+	require.NoError(t, SendValidatorAlert(chainID, data, 0, db))
+
+	rows, err := database.ListAlertDeliveries(db, "", chainID, 0)
+	require.NoError(t, err)
+	var chats []int64
+	var hooks []int
+	for _, row := range rows {
+		if row.ChatID != 0 {
+			chats = append(chats, row.ChatID)
+		}
+		if row.WebhookID != 0 {
+			hooks = append(hooks, row.WebhookID)
+		}
+	}
+	assert.Equal(t, []int64{1}, chats, "only the chat subscribed to the validator")
+	assert.Equal(t, []int{wanted.ID}, hooks, "the webhook filter applies")
+}
@@ -175,6 +175,58 @@ var (
 		[]string{"chain"},
 	)
 
+	// Account balances (see WatchAccountBalances)
+	AccountBalance = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gnoland_account_balance",
+			Help: "GNOT balance of each valoper operator address",
+		},
+		[]string{"chain", "address", "name"},
+	)
+
+	// Endpoint health (see WatchEndpointHealth). index is the position of
+	// the endpoint in its configured list: endpoint is the host only, which
+	// two endpoints behind the same host share.
+	EndpointUp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gnoland_endpoint_up",
+			Help: "1 when the endpoint circuit is closed, 0 while it is open",
+		},
+		[]string{"chain", "kind", "index", "endpoint"},
+	)
+
+	EndpointSuccessRatio = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gnoland_endpoint_success_ratio",
+			Help: "Share of the recent calls to the endpoint that succeeded",
+		},
+		[]string{"chain", "kind", "index", "endpoint"},
+	)
+
+	EndpointLatencyP95 = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gnoland_endpoint_latency_p95_seconds",
+			Help: "95th percentile latency of the recent successful calls to the endpoint",
+		},
+		[]string{"chain", "kind", "index", "endpoint"},
+	)
+
+	EndpointHeightLag = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gnoland_endpoint_height_lag",
+			Help: "Blocks the endpoint is behind the highest endpoint of its list",
+		},
+		[]string{"chain", "kind", "index", "endpoint"},
+	)
+
+	EndpointPreferred = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gnoland_endpoint_preferred",
+			Help: "1 for the endpoint calls currently go to first",
+		},
+		[]string{"chain", "kind", "index", "endpoint"},
+	)
+
 	initOnce sync.Once
 )
 
@@ -201,6 +253,14 @@ func Init() {
 		prometheus.MustRegister(ChainPeerCount)
 		prometheus.MustRegister(ChainMempoolTxCount)
 		prometheus.MustRegister(ChainValsetSize)
+		// Account balances
+		prometheus.MustRegister(AccountBalance)
+		// Endpoint health
+		prometheus.MustRegister(EndpointUp)
+		prometheus.MustRegister(EndpointSuccessRatio)
+		prometheus.MustRegister(EndpointLatencyP95)
+		prometheus.MustRegister(EndpointHeightLag)
+		prometheus.MustRegister(EndpointPreferred)
 	})
 }
 
@@ -14,8 +14,8 @@ import (
 
 // StartAggregator runs an immediate aggregation pass then repeats every hour.
 // It processes all enabled chains: aggregates complete past days from
-// daily_participations into daily_participation_agregas, then prunes raw rows
-// older than rawRetentionDays.
+// daily_participations into daily_participation_agregas and from blocks into
+// block_daily_agregas, then prunes raw rows older than rawRetentionDays.
 func StartAggregator(db *gorm.DB) {
 	go func() {
 		for {
@@ -87,12 +87,59 @@ const aggregateDayQuery = `
 	  first_block_height    = excluded.first_block_height,
 	  last_block_height     = excluded.last_block_height`
 
-// aggregateDay upserts one calendar day (format "2006-01-02") for chainID.
+// aggregateBlockDayQuery upserts the block_daily_agregas row of one calendar
+// day, recomputed from the blocks rows of that day, like aggregateDayQuery.
+// Block times are taken between consecutive stored heights; across a height
+// missing from blocks the interval is spread over the heights it spans.
+const aggregateBlockDayQuery = `
+	INSERT INTO block_daily_agregas
+	  (chain_id, block_date, block_count, first_height, last_height,
+	   avg_block_time, max_block_time, tx_count, gas_used, size, late_rounds, proposers)
+	SELECT
+	  chain_id,
+	  day                                                   AS block_date,
+	  COUNT(*)                                              AS block_count,
+	  MIN(height)                                           AS first_height,
+	  MAX(height)                                           AS last_height,
+	  COALESCE(EXTRACT(EPOCH FROM MAX(time) - MIN(time))
+	           / NULLIF(MAX(height) - MIN(height), 0), 0)   AS avg_block_time,
+	  COALESCE(MAX(block_time), 0)                          AS max_block_time,
+	  SUM(tx_count)                                         AS tx_count,
+	  SUM(gas_used)                                         AS gas_used,
+	  SUM(size)                                             AS size,
+	  SUM(CASE WHEN last_commit_round > 0 THEN 1 ELSE 0 END) AS late_rounds,
+	  COUNT(DISTINCT proposer_addr)                         AS proposers
+	FROM (
+	  SELECT *,
+	    time::date AS day,
+	    EXTRACT(EPOCH FROM time - LAG(time) OVER w) / (height - LAG(height) OVER w) AS block_time
+	  FROM blocks
+	  WHERE chain_id = ? AND time::date = ?
+	  WINDOW w AS (ORDER BY height)
+	) b
+	GROUP BY chain_id, day
+	ON CONFLICT(chain_id, block_date) DO UPDATE SET
+	  block_count    = excluded.block_count,
+	  first_height   = excluded.first_height,
+	  last_height    = excluded.last_height,
+	  avg_block_time = excluded.avg_block_time,
+	  max_block_time = excluded.max_block_time,
+	  tx_count       = excluded.tx_count,
+	  gas_used       = excluded.gas_used,
+	  size           = excluded.size,
+	  late_rounds    = excluded.late_rounds,
+	  proposers      = excluded.proposers`
+
+// aggregateDay upserts one calendar day (format "2006-01-02") for chainID,
+// participation and blocks. It returns the participation rows written.
 func aggregateDay(db *gorm.DB, chainID, day string) (int64, error) {
 	result := db.Exec(aggregateDayQuery, chainID, day)
 	if result.Error != nil {
 		return 0, fmt.Errorf("aggregate day %s: %w", day, result.Error)
 	}
+	if err := db.Exec(aggregateBlockDayQuery, chainID, day).Error; err != nil {
+		return 0, fmt.Errorf("aggregate blocks of day %s: %w", day, err)
+	}
 	return result.RowsAffected, nil
 }
 
@@ -204,7 +251,9 @@ const pruneBatchSize = 10_000
 
 // PruneRawData deletes rows from daily_participations older than rawRetentionDays
 // in batches of pruneBatchSize to keep each DELETE transaction short and avoid
-// long-running transactions that could bloat Postgres dead tuples.
+// long-running transactions that could bloat Postgres dead tuples, then the
+// blocks, net_peers, net_peer_samples and account_balances rows older than
+// rawRetentionDays.
 func PruneRawData(db *gorm.DB, chainID string) error {
 	retentionDays := GetThresholds().RawRetentionDays
 	cutoffDays := fmt.Sprintf("%d days", retentionDays) // e.g. "7 days", cast to interval in SQL
@@ -233,5 +282,50 @@ func PruneRawData(db *gorm.DB, chainID string) error {
 	if totalPruned > 0 {
 		log.Printf("[aggregator][%s] pruned %d raw rows (older than %d days)", chainID, totalPruned, retentionDays)
 	}
+
+	// One blocks row per block: small enough for a single DELETE.
+	result := db.Exec(
+		`DELETE FROM blocks WHERE chain_id = ? AND time < NOW() - ?::interval`,
+		chainID, cutoffDays,
+	)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("[aggregator][%s] pruned %d blocks (older than %d days)", chainID, result.RowsAffected, retentionDays)
+	}
+
+	result = db.Exec(
+		`DELETE FROM net_peers WHERE chain_id = ? AND seen_at < NOW() - ?::interval`,
+		chainID, cutoffDays,
+	)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("[aggregator][%s] pruned %d net peers (older than %d days)", chainID, result.RowsAffected, retentionDays)
+	}
+
+	result = db.Exec(
+		`DELETE FROM net_peer_samples WHERE chain_id = ? AND seen_at < NOW() - ?::interval`,
+		chainID, cutoffDays,
+	)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("[aggregator][%s] pruned %d net peer samples (older than %d days)", chainID, result.RowsAffected, retentionDays)
+	}
+
+	result = db.Exec(
+		`DELETE FROM account_balances WHERE chain_id = ? AND time < NOW() - ?::interval`,
+		chainID, cutoffDays,
+	)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("[aggregator][%s] pruned %d account balances (older than %d days)", chainID, result.RowsAffected, retentionDays)
+	}
 	return nil
 }
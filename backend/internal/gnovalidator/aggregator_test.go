@@ -288,3 +288,88 @@ func TestPruneRawData(t *testing.T) {
 	).Scan(&remaining).Error)
 	require.Equal(t, int64(1), remaining, "recent row should be kept")
 }
+
+// TestAggregateChain_Blocks verifies the block rollup of a day: block times
+// over a missing height are spread across the heights they span.
+func TestAggregateChain_Blocks(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+
+	past := time.Now().UTC().AddDate(0, 0, -2)
+	day := time.Date(past.Year(), past.Month(), past.Day(), 12, 0, 0, 0, time.UTC)
+
+	seedRaw(t, db, []database.DailyParticipation{
+		{ChainID: testChain, Addr: "g1aaa", BlockHeight: 10, Date: day, Participated: true, Moniker: "Alice"},
+	})
+	require.NoError(t, database.UpsertBlocks(db, []database.Block{
+		{ChainID: testChain, Height: 10, Time: day, ProposerAddr: "g1aaa", Size: 100},
+		{ChainID: testChain, Height: 11, Time: day.Add(2 * time.Second), ProposerAddr: "g1bbb", TxCount: 2, GasUsed: 500, Size: 300},
+		// 12 is missing: 6s over two heights.
+		{ChainID: testChain, Height: 13, Time: day.Add(8 * time.Second), ProposerAddr: "g1aaa", TxCount: 1, GasUsed: 100, Size: 200, LastCommitRound: 1},
+	}))
+
+	require.NoError(t, gnovalidator.AggregateChain(db, testChain))
+
+	var row database.BlockDailyAgrega
+	require.NoError(t, db.Where("chain_id = ?", testChain).First(&row).Error)
+	require.Equal(t, 3, row.BlockCount)
+	require.Equal(t, int64(10), row.FirstHeight)
+	require.Equal(t, int64(13), row.LastHeight)
+	require.InDelta(t, 8.0/3, row.AvgBlockTime, 1e-9)
+	require.InDelta(t, 3.0, row.MaxBlockTime, 1e-9)
+	require.Equal(t, int64(3), row.TxCount)
+	require.Equal(t, int64(600), row.GasUsed)
+	require.Equal(t, int64(600), row.Size)
+	require.Equal(t, 1, row.LateRounds)
+	require.Equal(t, 2, row.Proposers)
+}
+
+// TestPruneRawData_Blocks verifies that blocks are pruned with the raw
+// participation rows.
+func TestPruneRawData_Blocks(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+
+	now := time.Now().UTC()
+	require.NoError(t, database.UpsertBlocks(db, []database.Block{
+		{ChainID: testChain, Height: 600, Time: now.AddDate(0, 0, -10)},
+		{ChainID: testChain, Height: 700, Time: now.AddDate(0, 0, -2)},
+	}))
+
+	require.NoError(t, gnovalidator.PruneRawData(db, testChain))
+
+	blocks, err := database.GetRecentBlocks(db, testChain, 10)
+	require.NoError(t, err)
+	require.Len(t, blocks, 1)
+	require.Equal(t, int64(700), blocks[0].Height)
+}
+
+func TestPruneRawData_NetPeers(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+
+	now := time.Now().UTC()
+	require.NoError(t, database.InsertNetPeers(db, testChain, now.AddDate(0, 0, -10), []database.NetPeer{{NodeID: "old"}}))
+	require.NoError(t, database.InsertNetPeers(db, testChain, now.AddDate(0, 0, -2), []database.NetPeer{{NodeID: "recent"}}))
+
+	require.NoError(t, gnovalidator.PruneRawData(db, testChain))
+
+	counts, err := database.GetPeerCounts(db, testChain, now.AddDate(0, 0, -30))
+	require.NoError(t, err)
+	require.Len(t, counts, 1)
+	require.Equal(t, 1, counts[0].Peers)
+}
+
+func TestPruneRawData_AccountBalances(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+
+	now := time.Now().UTC()
+	require.NoError(t, database.InsertAccountBalances(db, []database.AccountBalance{
+		{ChainID: testChain, Addr: "g1a", Time: now.AddDate(0, 0, -10), Balance: 1},
+		{ChainID: testChain, Addr: "g1a", Time: now.AddDate(0, 0, -2), Balance: 2},
+	}))
+
+	require.NoError(t, gnovalidator.PruneRawData(db, testChain))
+
+	rows, err := database.GetAccountBalances(db, testChain, "g1a", now.AddDate(0, 0, -30))
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, int64(2), rows[0].Balance)
+}
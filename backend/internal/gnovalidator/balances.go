@@ -0,0 +1,309 @@
+package gnovalidator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/samouraiworld/gnomonitoring/backend/internal"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"gorm.io/gorm"
+)
+
+// balanceCheckInterval is how often WatchAccountBalances samples the
+// balances of the accounts of a chain.
+const balanceCheckInterval = 10 * time.Minute
+
+// valoperMinBalancePrefix prefixes the admin_config keys setting the
+// minimum balance of one valoper operator address, in ugnot, instead of
+// low_balance_ugnot: "low_balance_ugnot:<operator address>".
+const valoperMinBalancePrefix = "low_balance_ugnot:"
+
+// balanceTarget is an account WatchAccountBalances follows: the operator
+// address of a valoper, alerted chain-wide, or an address a user registered,
+// alerted to that user only.
+type balanceTarget struct {
+	Key       string // low balance state key
+	Addr      string
+	Name      string
+	Min       int64  // ugnot
+	UserID    string // "" for a valoper operator
+	Validator string // signing address of the valoper, for silences and filters
+}
+
+// balanceTargets returns the accounts to follow: the operator address of
+// every valoper with its minimum in valoperMins, or the default one, and
+// every account registered by a user with its own minimum, or the default
+// when it has none.
+func balanceTargets(valopers []Valoper, accounts []database.MonitoredAccount, defaultMin int64, valoperMins map[string]int64) []balanceTarget {
+	targets := make([]balanceTarget, 0, len(valopers)+len(accounts))
+	for _, v := range valopers {
+		validator := v.SigningAddress
+		if validator == "" {
+			validator = v.Address
+		}
+		min, ok := valoperMins[v.Address]
+		if !ok {
+			min = defaultMin
+		}
+		targets = append(targets, balanceTarget{
+			Key:       "valoper:" + v.Address,
+			Addr:      v.Address,
+			Name:      v.Name,
+			Min:       min,
+			Validator: validator,
+		})
+	}
+	for _, a := range accounts {
+		min := a.MinBalance
+		if min == 0 {
+			min = defaultMin
+		}
+		name := a.Label
+		if name == "" {
+			name = a.Addr
+		}
+		targets = append(targets, balanceTarget{
+			Key:    fmt.Sprintf("account:%d", a.ID),
+			Addr:   a.Addr,
+			Name:   name,
+			Min:    min,
+			UserID: a.UserID,
+		})
+	}
+	return targets
+}
+
+// valoperMinBalances returns the minimum balances set for single valoper
+// operator addresses, by address. Values that are not a number are skipped.
+func valoperMinBalances(db *gorm.DB) (map[string]int64, error) {
+	values, err := database.GetAdminConfigsWithPrefix(db, valoperMinBalancePrefix)
+	if err != nil {
+		return nil, err
+	}
+	mins := make(map[string]int64, len(values))
+	for addr, v := range values {
+		min, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			log.Printf("[balances] %s%s: invalid minimum %q", valoperMinBalancePrefix, addr, v)
+			continue
+		}
+		mins[addr] = min
+	}
+	return mins, nil
+}
+
+// parseBalance returns the ugnot amount of a bank/balances query answer,
+// the coins of the account as a JSON string (e.g. "1000000ugnot").
+func parseBalance(data []byte) (int64, error) {
+	var coins string
+	if err := json.Unmarshal(data, &coins); err != nil {
+		return 0, fmt.Errorf("decode balance %q: %w", data, err)
+	}
+	parsed, err := std.ParseCoins(coins)
+	if err != nil {
+		return 0, fmt.Errorf("parse balance %q: %w", coins, err)
+	}
+	return parsed.AmountOf("ugnot"), nil
+}
+
+// queryBalance returns the ugnot balance of addr.
+func queryBalance(rpcClient *FallbackRPCClient, addr string) (int64, error) {
+	resp, err := rpcClient.ABCIQuery("bank/balances/"+addr, nil)
+	if err != nil {
+		return 0, err
+	}
+	if resp == nil {
+		return 0, fmt.Errorf("nil response")
+	}
+	if resp.Response.Error != nil {
+		return 0, resp.Response.Error
+	}
+	return parseBalance(resp.Response.Data)
+}
+
+// formatGNOT renders an ugnot amount in GNOT.
+func formatGNOT(ugnot int64) string {
+	sign := ""
+	if ugnot < 0 {
+		sign, ugnot = "-", -ugnot
+	}
+	return fmt.Sprintf("%s%d.%06d GNOT", sign, ugnot/1_000_000, ugnot%1_000_000)
+}
+
+// lowBalanceWatch is the state of the low balance check of
+// WatchAccountBalances: the targets alerted as low, by key.
+type lowBalanceWatch struct {
+	low map[string]bool
+}
+
+// update records the balance of the target key and reports whether it just
+// fell under min, or just got back to it. A min <= 0 disables the check,
+// and ends an ongoing alert.
+func (w *lowBalanceWatch) update(key string, balance, min int64) (dropped, recovered bool) {
+	if w.low == nil {
+		w.low = make(map[string]bool)
+	}
+	low := min > 0 && balance < min
+	dropped, recovered = low && !w.low[key], !low && w.low[key]
+	if low {
+		w.low[key] = true
+	} else {
+		delete(w.low, key)
+	}
+	return dropped, recovered
+}
+
+// forget drops the state of the targets not in keys, so a target no longer
+// followed does not resolve later.
+func (w *lowBalanceWatch) forget(keys map[string]bool) {
+	for key := range w.low {
+		if !keys[key] {
+			delete(w.low, key)
+		}
+	}
+}
+
+// WatchAccountBalances samples the GNOT balance of the valoper operator
+// addresses of chainID, from the last valoper registry snapshot, and of the
+// accounts users registered on it, every checkInterval. Balances are stored
+// in account_balances; the valoper ones are also exported as
+// gnoland_account_balance. A WARNING is raised when a balance falls below
+// its minimum, low_balance_ugnot unless an admin set another for the
+// valoper (low_balance_ugnot:<address>) or the user for the account, and
+// an INFO follows once it is topped up. Valoper alerts are about the
+// valoper's validator and go to the validator webhooks and the chats
+// subscribed to it; registered account alerts go to the webhooks of their
+// user.
+func WatchAccountBalances(ctx context.Context, db *gorm.DB, chainID string, checkInterval time.Duration) {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("[balances][%s] panic recovered: %v", chainID, r)
+			}
+		}()
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+
+		var watch lowBalanceWatch
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			rpcClient, ok := GetChainRPCClient(chainID)
+			if !ok || rpcClient == nil {
+				continue
+			}
+			accounts, err := database.MonitoredAccountsForChain(db, chainID)
+			if err != nil {
+				log.Printf("[balances][%s] %v", chainID, err)
+				continue
+			}
+			valoperMins, err := valoperMinBalances(db)
+			if err != nil {
+				log.Printf("[balances][%s] %v", chainID, err)
+				continue
+			}
+			targets := balanceTargets(getCachedValopers(chainID), accounts, int64(GetThresholds().LowBalanceUgnot), valoperMins)
+
+			now := time.Now()
+			balances := make(map[string]int64)
+			keys := make(map[string]bool, len(targets))
+			for _, t := range targets {
+				keys[t.Key] = true
+				if _, ok := balances[t.Addr]; ok {
+					continue
+				}
+				balance, err := queryBalance(rpcClient, t.Addr)
+				if err != nil {
+					log.Printf("[balances][%s] balance of %s: %v", chainID, t.Addr, err)
+					continue
+				}
+				balances[t.Addr] = balance
+			}
+			watch.forget(keys)
+
+			rows := make([]database.AccountBalance, 0, len(balances))
+			for addr, balance := range balances {
+				rows = append(rows, database.AccountBalance{ChainID: chainID, Addr: addr, Time: now, Balance: balance})
+			}
+			sort.Slice(rows, func(i, j int) bool { return rows[i].Addr < rows[j].Addr })
+			if err := database.InsertAccountBalances(db, rows); err != nil {
+				log.Printf("[balances][%s] %v", chainID, err)
+			}
+			setBalanceMetrics(chainID, targets, balances)
+
+			for _, t := range targets {
+				balance, ok := balances[t.Addr]
+				if !ok {
+					continue
+				}
+				dropped, recovered := watch.update(t.Key, balance, t.Min)
+				if dropped || recovered {
+					sendLowBalanceAlert(db, chainID, t, balance, recovered)
+				}
+			}
+		}
+	}()
+}
+
+// setBalanceMetrics exports the balances of the valoper operators among
+// targets as gnoland_account_balance. The accounts users registered, and
+// their labels, are private and stay off the public /metrics.
+func setBalanceMetrics(chainID string, targets []balanceTarget, balances map[string]int64) {
+	AccountBalance.DeletePartialMatch(prometheus.Labels{"chain": chainID})
+	for _, t := range targets {
+		if t.UserID != "" {
+			continue
+		}
+		if balance, ok := balances[t.Addr]; ok {
+			AccountBalance.WithLabelValues(chainID, t.Addr, t.Name).Set(float64(balance) / 1e6)
+		}
+	}
+}
+
+// sendLowBalanceAlert alerts that the balance of t fell below its minimum,
+// or announces it is back above it.
+func sendLowBalanceAlert(db *gorm.DB, chainID string, t balanceTarget, balance int64, recovered bool) {
+	data := internal.AlertData{
+		ChainID: chainID,
+		Level:   internal.AlertWarning,
+		Emoji:   "⚠️",
+		Title:   "Account balance low",
+		Fields: []internal.AlertField{
+			{Name: "account", Value: fmt.Sprintf("%s (%s)", t.Name, t.Addr)},
+			{Name: "balance", Value: formatGNOT(balance)},
+			{Name: "minimum", Value: formatGNOT(t.Min)},
+		},
+		Addr: "all",
+		Kind: internal.AlertKindLowBalance,
+	}
+	if recovered {
+		data.Level, data.Emoji, data.Title = internal.AlertInfo, "✅", "Account balance topped up"
+		data.ResolvedLevel = internal.AlertWarning
+	}
+	log.Printf("%s [%s] %s: %s %s", data.Emoji, chainID, data.Title, t.Name, formatGNOT(balance))
+
+	if t.UserID != "" {
+		if err := internal.SendUserAlert(t.UserID, chainID, data, db); err != nil {
+			log.Printf("[balances][%s] SendUserAlert error: %v", chainID, err)
+		}
+		return
+	}
+	data.Addr, data.Moniker = t.Validator, t.Name
+	if silenceID := activeSilenceID(db, chainID, t.Validator); silenceID != 0 {
+		log.Printf("[balances][%s] silence #%d: not sending balance alert for %s", chainID, silenceID, t.Addr)
+		return
+	}
+	if err := internal.SendValidatorAlert(chainID, data, 0, db); err != nil {
+		log.Printf("[balances][%s] SendValidatorAlert error: %v", chainID, err)
+	}
+}
@@ -0,0 +1,111 @@
+package gnovalidator
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/testoutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBalance(t *testing.T) {
+	for _, tc := range []struct {
+		data string
+		want int64
+	}{
+		{`"1500000ugnot"`, 1500000},
+		{`"42foo,7ugnot"`, 7},
+		{`""`, 0},
+		{`"42foo"`, 0},
+	} {
+		got, err := parseBalance([]byte(tc.data))
+		require.NoError(t, err, tc.data)
+		assert.Equal(t, tc.want, got, tc.data)
+	}
+	for _, bad := range []string{`1000ugnot`, `"-5ugnot"`} {
+		_, err := parseBalance([]byte(bad))
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestFormatGNOT(t *testing.T) {
+	assert.Equal(t, "1.500000 GNOT", formatGNOT(1500000))
+	assert.Equal(t, "0.000042 GNOT", formatGNOT(42))
+	assert.Equal(t, "0.000000 GNOT", formatGNOT(0))
+}
+
+func TestBalanceTargets(t *testing.T) {
+	valopers := []Valoper{
+		{Name: "alpha", Address: "g1op1", SigningAddress: "g1sign1"},
+		{Name: "beta", Address: "g1op2"},
+	}
+	accounts := []database.MonitoredAccount{
+		{ID: 7, UserID: "u1", Addr: "g1faucet", Label: "faucet", MinBalance: 5000000},
+		{ID: 8, UserID: "u2", Addr: "g1op1"},
+	}
+
+	targets := balanceTargets(valopers, accounts, 1000000, map[string]int64{"g1op2": 3000000})
+	require.Len(t, targets, 4)
+	assert.Equal(t, balanceTarget{Key: "valoper:g1op1", Addr: "g1op1", Name: "alpha", Min: 1000000, Validator: "g1sign1"}, targets[0])
+	assert.Equal(t, "g1op2", targets[1].Validator, "operator address without a signing address")
+	assert.Equal(t, int64(3000000), targets[1].Min, "minimum set for the operator address")
+	assert.Equal(t, balanceTarget{Key: "account:7", Addr: "g1faucet", Name: "faucet", Min: 5000000, UserID: "u1"}, targets[2])
+	assert.Equal(t, int64(1000000), targets[3].Min, "no minimum: the default one")
+	assert.Equal(t, "g1op1", targets[3].Name, "no label: the address")
+}
+
+func TestValoperMinBalances(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+
+	require.NoError(t, database.SetAdminConfigBatch(db, map[string]string{
+		"low_balance_ugnot":           "1000000",
+		"low_balance_ugnot:g1op1":     "5000000",
+		"low_balance_ugnot:g1op2":     "0",
+		"low_balance_ugnot:g1op3":     "lots",
+		"low_balance_ugnotXg1notaddr": "7",
+	}))
+	mins, err := valoperMinBalances(db)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"g1op1": 5000000, "g1op2": 0}, mins, "0 disables the check for that operator")
+}
+
+func TestLowBalanceWatch(t *testing.T) {
+	var w lowBalanceWatch
+	dropped, recovered := w.update("a", 5, 3)
+	assert.False(t, dropped)
+	assert.False(t, recovered)
+
+	dropped, _ = w.update("a", 2, 3)
+	assert.True(t, dropped)
+	dropped, _ = w.update("a", 1, 3)
+	assert.False(t, dropped, "one alert per drop")
+	dropped, _ = w.update("b", 1, 3)
+	assert.True(t, dropped, "state is per target")
+
+	_, recovered = w.update("a", 3, 3)
+	assert.True(t, recovered)
+
+	_, recovered = w.update("b", 1, 0)
+	assert.True(t, recovered, "disabling the check ends the alert")
+
+	w.update("c", 1, 3)
+	w.forget(map[string]bool{"a": true})
+	_, recovered = w.update("c", 5, 3)
+	assert.False(t, recovered, "a forgotten target does not resolve")
+}
+
+func TestSetBalanceMetrics_OnlyValopers(t *testing.T) {
+	const chainID = "metrics-test"
+	defer AccountBalance.DeletePartialMatch(map[string]string{"chain": chainID})
+
+	targets := []balanceTarget{
+		{Key: "valoper:g1op", Addr: "g1op", Name: "op"},
+		{Key: "account:1", Addr: "g1private", Name: "treasury", UserID: "u1"},
+	}
+	setBalanceMetrics(chainID, targets, map[string]int64{"g1op": 2_500_000, "g1private": 9_000_000})
+
+	assert.Equal(t, 2.5, testutil.ToFloat64(AccountBalance.WithLabelValues(chainID, "g1op", "op")))
+	assert.Equal(t, 1, testutil.CollectAndCount(AccountBalance), "registered accounts are not exported")
+}
@@ -0,0 +1,69 @@
+package gnovalidator
+
+import (
+	"log"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoclient"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"gorm.io/gorm"
+)
+
+const (
+	// gapCheckWindow caps the heights one gap check looks at.
+	gapCheckWindow = 1000
+	// gapMaxAttempts is how many times a gap is handed to BackfillRange
+	// before it is given up on.
+	gapMaxAttempts = 3
+)
+
+// gapTracker finds the heights CollectParticipation stored no participation
+// for, e.g. because a block fetch failed, and hands them to BackfillRange.
+type gapTracker struct {
+	checked  int64 // heights up to checked are complete or given up on
+	attempts int   // backfills of the gaps after checked so far
+}
+
+// heightRanges groups ascending heights into ranges of consecutive heights.
+func heightRanges(heights []int64) [][2]int64 {
+	var ranges [][2]int64
+	for _, h := range heights {
+		if n := len(ranges); n > 0 && ranges[n-1][1]+1 == h {
+			ranges[n-1][1] = h
+			continue
+		}
+		ranges = append(ranges, [2]int64{h, h})
+	}
+	return ranges
+}
+
+// check looks for missing heights after the last checked one, up to
+// through, and backfills them. The next check verifies the backfill.
+func (g *gapTracker) check(db *gorm.DB, client gnoclient.Client, chainID string, through int64) {
+	if through <= g.checked {
+		return
+	}
+	to := min(through, g.checked+gapCheckWindow)
+	missing, err := database.GetMissingHeights(db, chainID, g.checked+1, to)
+	if err != nil {
+		log.Printf("[monitor][%s] %v", chainID, err)
+		return
+	}
+	if len(missing) == 0 {
+		g.checked, g.attempts = to, 0
+		return
+	}
+	if g.attempts >= gapMaxAttempts {
+		log.Printf("[monitor][%s] giving up on %d missing heights in [%d..%d] after %d backfills",
+			chainID, len(missing), g.checked+1, to, g.attempts)
+		g.checked, g.attempts = to, 0
+		return
+	}
+	g.attempts++
+	for _, r := range heightRanges(missing) {
+		log.Printf("[monitor][%s] gap [%d..%d], backfilling", chainID, r[0], r[1])
+		// BackfillRange starts after from.
+		if err := BackfillRange(db, client, chainID, r[0]-1, r[1], GetMonikerMap(chainID)); err != nil {
+			log.Printf("[monitor][%s] gap backfill error: %v", chainID, err)
+		}
+	}
+}
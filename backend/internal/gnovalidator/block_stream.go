@@ -0,0 +1,202 @@
+package gnovalidator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/samouraiworld/gnomonitoring/backend/internal"
+)
+
+const (
+	// realtimePollInterval is how often CollectParticipation asks the RPC
+	// for the latest height once it has caught up.
+	realtimePollInterval = 3 * time.Second
+	// blockStreamIdleTimeout is how long CollectParticipation waits for a
+	// pushed block before asking the RPC anyway, so a stalled chain is
+	// still noticed.
+	blockStreamIdleTimeout = 10 * time.Second
+	// blockStreamReadTimeout closes a subscription that stayed silent that
+	// long, so a half-open connection gets redialed.
+	blockStreamReadTimeout = 2 * time.Minute
+)
+
+const newBlocksSubscription = `subscription { getBlocks(where: {}) { height } }`
+
+// blockStream carries the heights of new blocks pushed by the GraphQL
+// indexer of a chain to CollectParticipation. tm2 RPC serves no event
+// subscriptions: the indexer is the only source that pushes blocks.
+type blockStream struct {
+	heights   chan int64 // newest height not read yet
+	connected atomic.Bool
+}
+
+func newBlockStream() *blockStream {
+	return &blockStream{heights: make(chan int64, 1)}
+}
+
+// push offers height, replacing a lower height not read yet.
+func (s *blockStream) push(height int64) {
+	for {
+		select {
+		case s.heights <- height:
+			return
+		default:
+		}
+		select {
+		case old := <-s.heights:
+			height = max(height, old)
+		default:
+		}
+	}
+}
+
+// wait waits for the next block. It returns the height pushed by the
+// stream, or 0 when the RPC must be polled instead: s is nil or
+// disconnected and realtimePollInterval went by, or nothing was pushed for
+// blockStreamIdleTimeout. ok is false once ctx is done.
+func (s *blockStream) wait(ctx context.Context) (height int64, ok bool) {
+	timeout := realtimePollInterval
+	var heights <-chan int64
+	if s != nil && s.connected.Load() {
+		timeout = blockStreamIdleTimeout
+		heights = s.heights
+	}
+	select {
+	case <-ctx.Done():
+		return 0, false
+	case h := <-heights:
+		return h, true
+	case <-time.After(timeout):
+		return 0, true
+	}
+}
+
+// parseBlockStreamMessage decodes a graphql-ws message, returning its type
+// and, for a "data" message, the height of the pushed block.
+func parseBlockStreamMessage(b []byte) (typ string, height int64, err error) {
+	var msg struct {
+		Type    string          `json:"type"`
+		Payload json.RawMessage `json:"payload"`
+	}
+	if err := json.Unmarshal(b, &msg); err != nil {
+		return "", 0, err
+	}
+	if msg.Type != "data" {
+		return msg.Type, 0, nil
+	}
+	var payload struct {
+		Data struct {
+			GetBlocks struct {
+				Height int64 `json:"height"`
+			} `json:"getBlocks"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return msg.Type, 0, err
+	}
+	if len(payload.Errors) > 0 {
+		return msg.Type, 0, fmt.Errorf("graphql: %s", payload.Errors[0].Message)
+	}
+	return msg.Type, payload.Data.GetBlocks.Height, nil
+}
+
+// subscribeNewBlocks subscribes to the new blocks of chainID on its
+// healthiest GraphQL indexer, picked again on every reconnection, until ctx
+// is done. While the subscription is down CollectParticipation polls the
+// RPC as usual.
+func subscribeNewBlocks(ctx context.Context, chainID string, graphqlEndpoints []string) *blockStream {
+	s := newBlockStream()
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("[blockstream][%s] panic recovered: %v", chainID, r)
+			}
+		}()
+		const (
+			backoffMin = 2 * time.Second
+			backoffMax = 60 * time.Second
+		)
+		backoff := backoffMin
+		for ctx.Err() == nil {
+			endpoint := internal.BestEndpoint(internal.EndpointGraphQL, graphqlEndpoints)
+			err := s.run(ctx, chainID, endpoint, func() { backoff = backoffMin })
+			s.connected.Store(false)
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("[blockstream][%s] %s: %v — polling, retrying in %s", chainID, internal.EndpointLabel(endpoint), err, backoff)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(2*backoff, backoffMax)
+		}
+	}()
+	return s
+}
+
+// run holds one subscription on endpoint until it fails, calling onConnect
+// once the indexer acknowledged it.
+func (s *blockStream) run(ctx context.Context, chainID, endpoint string, onConnect func()) error {
+	wsURL := strings.Replace(endpoint, "http", "ws", 1)
+	start := time.Now()
+	c, resp, err := websocket.DefaultDialer.DialContext(ctx, wsURL, nil)
+	internal.RecordEndpointCall(internal.EndpointGraphQL, endpoint, time.Since(start), err)
+	if err != nil {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return fmt.Errorf("dial: %w", err)
+	}
+	defer c.Close()
+	stop := context.AfterFunc(ctx, func() { c.Close() })
+	defer stop()
+
+	if err := c.WriteJSON(map[string]any{"type": "connection_init"}); err != nil {
+		return fmt.Errorf("connection_init: %w", err)
+	}
+	if err := c.WriteJSON(map[string]any{
+		"id":      "1",
+		"type":    "start",
+		"payload": map[string]any{"query": newBlocksSubscription},
+	}); err != nil {
+		return fmt.Errorf("start: %w", err)
+	}
+
+	for {
+		_ = c.SetReadDeadline(time.Now().Add(blockStreamReadTimeout))
+		_, b, err := c.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("read: %w", err)
+		}
+		typ, height, err := parseBlockStreamMessage(b)
+		if err != nil {
+			log.Printf("[blockstream][%s] bad message: %v", chainID, err)
+			continue
+		}
+		switch typ {
+		case "connection_ack":
+			if !s.connected.Swap(true) {
+				log.Printf("[blockstream][%s] subscribed to new blocks on %s", chainID, internal.EndpointLabel(endpoint))
+				onConnect()
+			}
+		case "data":
+			s.connected.Store(true)
+			if height > 0 {
+				s.push(height)
+			}
+		case "error", "connection_error", "complete":
+			return fmt.Errorf("subscription ended (%s): %s", typ, b)
+		}
+	}
+}
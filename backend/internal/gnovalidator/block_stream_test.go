@@ -0,0 +1,83 @@
+package gnovalidator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBlockStreamMessage(t *testing.T) {
+	typ, height, err := parseBlockStreamMessage([]byte(`{"id":"1","type":"data","payload":{"data":{"getBlocks":{"height":1234}}}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "data", typ)
+	assert.Equal(t, int64(1234), height)
+
+	typ, _, err = parseBlockStreamMessage([]byte(`{"type":"ka"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "ka", typ)
+
+	_, _, err = parseBlockStreamMessage([]byte(`{"type":"data","payload":{"errors":[{"message":"bad query"}]}}`))
+	assert.ErrorContains(t, err, "bad query")
+}
+
+func TestBlockStream_PushKeepsNewest(t *testing.T) {
+	s := newBlockStream()
+	s.connected.Store(true)
+	s.push(10)
+	s.push(12)
+	s.push(11)
+	h, ok := s.wait(context.Background())
+	require.True(t, ok)
+	assert.Equal(t, int64(12), h)
+}
+
+func TestBlockStream_WaitStopsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var s *blockStream
+	_, ok := s.wait(ctx)
+	assert.False(t, ok, "a nil stream polls, and stops with ctx")
+}
+
+func TestSubscribeNewBlocks(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		var msg map[string]any
+		_ = c.ReadJSON(&msg) // connection_init
+		_ = c.WriteJSON(map[string]any{"type": "connection_ack"})
+		_ = c.ReadJSON(&msg) // start
+		if msg["type"] != "start" {
+			return
+		}
+		_ = c.WriteJSON(map[string]any{"id": "1", "type": "data", "payload": map[string]any{
+			"data": map[string]any{"getBlocks": map[string]any{"height": 77}},
+		}})
+		// Hold the connection until the client goes away.
+		_, _, _ = c.ReadMessage()
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s := subscribeNewBlocks(ctx, "test", []string{srv.URL + "/graphql/query"})
+	require.Eventually(t, s.connected.Load, 3*time.Second, 10*time.Millisecond)
+	h, ok := s.wait(ctx)
+	require.True(t, ok)
+	assert.Equal(t, int64(77), h)
+}
+
+func TestHeightRanges(t *testing.T) {
+	assert.Equal(t, [][2]int64{{3, 5}, {8, 8}, {10, 11}}, heightRanges([]int64{3, 4, 5, 8, 10, 11}))
+	assert.Empty(t, heightRanges(nil))
+}
@@ -0,0 +1,241 @@
+package gnovalidator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"gorm.io/gorm"
+)
+
+// blockTimeCheckInterval is how often WatchBlockTime compares the recent
+// block time of a chain with its baseline.
+const blockTimeCheckInterval = 30 * time.Second
+
+// blockTimeBaselineDays is how many complete days of block_daily_agregas
+// the baseline block time is averaged over, when block_time_baseline_ms is
+// not set.
+const blockTimeBaselineDays = 7
+
+// averageBlockTime is the average interval between blocks, highest first
+// as served by GetRecentBlocks, and ok is false with fewer than two.
+func averageBlockTime(blocks []database.Block) (avg time.Duration, ok bool) {
+	if len(blocks) < 2 {
+		return 0, false
+	}
+	first, last := blocks[len(blocks)-1], blocks[0]
+	if last.Height <= first.Height {
+		return 0, false
+	}
+	return last.Time.Sub(first.Time) / time.Duration(last.Height-first.Height), true
+}
+
+// trimHaltGap drops from blocks, highest first, the blocks up to and
+// including haltHeight: the gap of a planned halt is not block time, so a
+// window spanning the restart only starts at the first block after it.
+func trimHaltGap(blocks []database.Block, haltHeight int64) []database.Block {
+	for i, b := range blocks {
+		if b.Height <= haltHeight {
+			return blocks[:i]
+		}
+	}
+	return blocks
+}
+
+// recentBlocks returns the last n blocks of chainID, highest first, without
+// those before a planned halt the chain restarted from among them.
+func recentBlocks(db *gorm.DB, chainID string, n int) ([]database.Block, error) {
+	blocks, err := database.GetRecentBlocks(db, chainID, n)
+	if err != nil || len(blocks) == 0 {
+		return blocks, err
+	}
+	halt, err := database.GetLastResumedHalt(db, chainID, blocks[len(blocks)-1].Time)
+	if err != nil {
+		return nil, err
+	}
+	if halt != nil {
+		blocks = trimHaltGap(blocks, halt.HaltHeight)
+	}
+	return blocks, nil
+}
+
+// blockTimeLevel is the alert level of a block time ratio times its
+// baseline: CRITICAL from criticalMultiple, WARNING from warningMultiple,
+// "" below. A multiple <= 0 disables its level.
+func blockTimeLevel(ratio, warningMultiple, criticalMultiple float64) internal.AlertLevel {
+	switch {
+	case criticalMultiple > 0 && ratio >= criticalMultiple:
+		return internal.AlertCritical
+	case warningMultiple > 0 && ratio >= warningMultiple:
+		return internal.AlertWarning
+	}
+	return ""
+}
+
+// blockTimeWatch is the state of WatchBlockTime: the highest level alerted
+// since the block time was last back to normal, and the silence that muted
+// every alert since then, 0 once one of them was delivered.
+type blockTimeWatch struct {
+	level     internal.AlertLevel
+	silenceID uint
+}
+
+// step records the current level and reports the level to raise, if the
+// block time got worse than the last alert, and the level resolved, if it
+// went back below the WARNING multiple. An improvement from CRITICAL to
+// WARNING raises nothing.
+func (w *blockTimeWatch) step(level internal.AlertLevel) (raise, resolved internal.AlertLevel) {
+	switch {
+	case level == "" && w.level != "":
+		resolved, w.level = w.level, ""
+	case level == internal.AlertCritical && w.level != internal.AlertCritical,
+		level == internal.AlertWarning && w.level == "":
+		raise, w.level = level, level
+	}
+	return raise, resolved
+}
+
+// blockTimeBaseline is the block time chainID is compared with:
+// block_time_baseline_ms when set, otherwise the average of its last
+// blockTimeBaselineDays daily rollups. ok is false while there is none.
+func blockTimeBaseline(db *gorm.DB, chainID string, baselineMs int) (time.Duration, bool) {
+	if baselineMs > 0 {
+		return time.Duration(baselineMs) * time.Millisecond, true
+	}
+	secs, err := database.GetAverageBlockTime(db, chainID, time.Now().AddDate(0, 0, -blockTimeBaselineDays))
+	if err != nil {
+		log.Printf("[blocktime][%s] %v", chainID, err)
+		return 0, false
+	}
+	if secs <= 0 {
+		return 0, false
+	}
+	return time.Duration(secs * float64(time.Second)), true
+}
+
+// WatchBlockTime compares the average block time over the last
+// block_time_window blocks of chainID with its baseline, and raises a
+// WARNING from block_time_warning_multiple times the baseline and a
+// CRITICAL from block_time_critical_multiple times. An INFO follows once
+// the average is back below the WARNING multiple. After a planned halt the
+// window restarts with the first block after it (see recentBlocks), so the
+// halt itself never counts as slow blocks. The alerts are
+// chain-level and are not written to alert_logs, so they never touch the
+// "Blockchain stuck" incident, which covers a chain producing no block at
+// all.
+func WatchBlockTime(ctx context.Context, db *gorm.DB, chainID string, checkInterval time.Duration) {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("[blocktime][%s] panic recovered: %v", chainID, r)
+			}
+		}()
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+
+		var w blockTimeWatch
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			// While catching up, the stored blocks are not the chain's
+			// latest.
+			if !isChainSynced(chainID) {
+				continue
+			}
+			t := GetThresholds()
+			if t.BlockTimeWindow <= 0 {
+				continue
+			}
+			baseline, ok := blockTimeBaseline(db, chainID, t.BlockTimeBaselineMs)
+			if !ok {
+				continue
+			}
+			blocks, err := recentBlocks(db, chainID, t.BlockTimeWindow+1)
+			if err != nil {
+				log.Printf("[blocktime][%s] %v", chainID, err)
+				continue
+			}
+			// Right after a planned halt, wait for a full window of blocks
+			// produced since the restart.
+			avg, ok := averageBlockTime(blocks)
+			if !ok || len(blocks) <= t.BlockTimeWindow {
+				continue
+			}
+			ratio := float64(avg) / float64(baseline)
+			first := w.level == ""
+			raise, resolved := w.step(blockTimeLevel(ratio, t.BlockTimeWarningMultiple, t.BlockTimeCriticalMultiple))
+			if resolved != "" {
+				sendBlockTimeResolved(db, chainID, resolved, avg, baseline, w.silenceID)
+				w.silenceID = 0
+			}
+			if raise != "" {
+				silenceID := sendBlockTimeAlert(db, chainID, raise, avg, baseline, ratio, t.BlockTimeWindow, blocks[0].Height)
+				if first || silenceID == 0 {
+					w.silenceID = silenceID
+				}
+			}
+		}
+	}()
+}
+
+// sendBlockTimeAlert alerts that the block time is ratio times its
+// baseline, and returns the ID of the silence that muted the alert, if any.
+func sendBlockTimeAlert(db *gorm.DB, chainID string, level internal.AlertLevel, avg, baseline time.Duration, ratio float64, window int, height int64) uint {
+	emoji := "⚠️"
+	if level == internal.AlertCritical {
+		emoji = "🚨"
+	}
+	log.Printf("%s [%s] %s : average block time %s over the last %d blocks, %.1fx the baseline %s",
+		emoji, chainID, level, avg.Round(time.Millisecond), window, ratio, baseline.Round(time.Millisecond))
+
+	if silenceID := activeSilenceID(db, chainID, "all"); silenceID != 0 {
+		log.Printf("[blocktime][%s] silence #%d: not sending block time alert", chainID, silenceID)
+		return silenceID
+	}
+	data := internal.AlertData{
+		ChainID: chainID,
+		Level:   level,
+		Emoji:   emoji,
+		Title:   "Block time degraded",
+		Fields: []internal.AlertField{
+			{Name: "average block time", Value: fmt.Sprintf("%s over the last %d blocks", avg.Round(time.Millisecond), window)},
+			{Name: "baseline", Value: baseline.Round(time.Millisecond).String()},
+			{Name: "ratio", Value: fmt.Sprintf("%.1fx", ratio)},
+			{Name: "height", Value: fmt.Sprintf("%d", height)},
+		},
+		Addr:        "all",
+		StartHeight: height,
+		Kind:        internal.AlertKindBlockTime,
+	}
+	if err := internal.SendInfoValidator(chainID, data, 0, db); err != nil {
+		log.Printf("[blocktime][%s] SendInfoValidator error: %v", chainID, err)
+	}
+	return 0
+}
+
+// sendBlockTimeResolved announces that the block time is back to normal.
+// It is not sent when every alert it ends was silenced.
+func sendBlockTimeResolved(db *gorm.DB, chainID string, resolved internal.AlertLevel, avg, baseline time.Duration, silenceID uint) {
+	if silenceID != 0 {
+		return
+	}
+	data := internal.AlertData{
+		ChainID:       chainID,
+		Level:         internal.AlertInfo,
+		Emoji:         "✅",
+		Title:         "Block time recovered",
+		Description:   fmt.Sprintf("Average block time is %s, baseline %s.", avg.Round(time.Millisecond), baseline.Round(time.Millisecond)),
+		Addr:          "all",
+		Kind:          internal.AlertKindBlockTime,
+		ResolvedLevel: resolved,
+	}
+	if err := internal.SendInfoValidator(chainID, data, 0, db); err != nil {
+		log.Printf("[blocktime][%s] SendInfoValidator error: %v", chainID, err)
+	}
+}
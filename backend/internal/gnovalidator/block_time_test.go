@@ -0,0 +1,99 @@
+package gnovalidator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/testoutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAverageBlockTime(t *testing.T) {
+	now := time.Now()
+	_, ok := averageBlockTime([]database.Block{{Height: 10, Time: now}})
+	assert.False(t, ok, "one block has no interval")
+
+	avg, ok := averageBlockTime([]database.Block{
+		{Height: 13, Time: now},
+		{Height: 11, Time: now.Add(-4 * time.Second)},
+		{Height: 10, Time: now.Add(-9 * time.Second)},
+	})
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, avg)
+}
+
+func TestTrimHaltGap(t *testing.T) {
+	blocks := []database.Block{{Height: 103}, {Height: 102}, {Height: 101}, {Height: 100}, {Height: 99}}
+	assert.Equal(t, blocks[:2], trimHaltGap(blocks, 101), "blocks up to the halt height are dropped")
+	assert.Equal(t, blocks, trimHaltGap(blocks, 90), "a halt before the window changes nothing")
+	assert.Empty(t, trimHaltGap(blocks, 103))
+}
+
+func TestBlockTimeLevel(t *testing.T) {
+	assert.Equal(t, internal.AlertLevel(""), blockTimeLevel(2.9, 3, 10))
+	assert.Equal(t, internal.AlertWarning, blockTimeLevel(3, 3, 10))
+	assert.Equal(t, internal.AlertCritical, blockTimeLevel(15, 3, 10))
+	assert.Equal(t, internal.AlertWarning, blockTimeLevel(15, 3, 0), "0 disables CRITICAL")
+	assert.Equal(t, internal.AlertLevel(""), blockTimeLevel(5, 0, 10), "0 disables WARNING")
+	assert.Equal(t, internal.AlertWarning, blockTimeLevel(1.6, 1.5, 2.5), "multiples may be fractional")
+	assert.Equal(t, internal.AlertCritical, blockTimeLevel(2.5, 1.5, 2.5))
+}
+
+// TestLoadThresholds_BlockTimeMultiples checks that fractional multiples are
+// loaded and that a WARNING multiple not below the CRITICAL one falls back
+// to the defaults.
+func TestLoadThresholds_BlockTimeMultiples(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+	saved := GetThresholds()
+	defer func() {
+		thresholdsMu.Lock()
+		activeThresholds = saved
+		thresholdsMu.Unlock()
+	}()
+
+	require.NoError(t, database.SetAdminConfigBatch(db, map[string]string{
+		"block_time_warning_multiple":  "1.5",
+		"block_time_critical_multiple": "2.5",
+	}))
+	LoadThresholds(db)
+	assert.Equal(t, 1.5, GetThresholds().BlockTimeWarningMultiple)
+	assert.Equal(t, 2.5, GetThresholds().BlockTimeCriticalMultiple)
+
+	require.NoError(t, database.SetAdminConfig(db, "block_time_warning_multiple", "4"))
+	LoadThresholds(db)
+	assert.Equal(t, 3.0, GetThresholds().BlockTimeWarningMultiple)
+	assert.Equal(t, 10.0, GetThresholds().BlockTimeCriticalMultiple)
+}
+
+func TestBlockTimeWatch_EscalatesOnceAndResolves(t *testing.T) {
+	var w blockTimeWatch
+	none := internal.AlertLevel("")
+
+	raise, resolved := w.step(internal.AlertWarning)
+	assert.Equal(t, internal.AlertWarning, raise)
+	assert.Equal(t, none, resolved)
+
+	raise, _ = w.step(internal.AlertWarning)
+	assert.Equal(t, none, raise, "one alert per level")
+
+	raise, _ = w.step(internal.AlertCritical)
+	assert.Equal(t, internal.AlertCritical, raise)
+
+	raise, resolved = w.step(internal.AlertWarning)
+	assert.Equal(t, none, raise, "improving from CRITICAL raises nothing")
+	assert.Equal(t, none, resolved)
+
+	raise, resolved = w.step(none)
+	assert.Equal(t, none, raise)
+	assert.Equal(t, internal.AlertCritical, resolved)
+
+	raise, resolved = w.step(none)
+	assert.Equal(t, none, raise)
+	assert.Equal(t, none, resolved)
+
+	raise, _ = w.step(internal.AlertCritical)
+	assert.Equal(t, internal.AlertCritical, raise, "straight to CRITICAL")
+}
@@ -0,0 +1,45 @@
+package gnovalidator
+
+import (
+	"log"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoclient"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+)
+
+// newBlockRecord returns the blocks row of b, gas left at zero.
+func newBlockRecord(chainID string, b *types.Block) database.Block {
+	rec := database.Block{
+		ChainID:      chainID,
+		Height:       b.Header.Height,
+		Time:         b.Header.Time,
+		ProposerAddr: b.Header.ProposerAddress.String(),
+		TxCount:      len(b.Data.Txs),
+		Size:         b.Size(),
+	}
+	if b.LastCommit != nil {
+		rec.LastCommitRound = b.LastCommit.Round()
+	}
+	return rec
+}
+
+// blockRecord returns the blocks row of b. The gas of its transactions is
+// read from the block results, only fetched for a block with transactions;
+// when that fails the row is kept with no gas.
+func blockRecord(client gnoclient.Client, chainID string, b *types.Block) database.Block {
+	rec := newBlockRecord(chainID, b)
+	if rec.TxCount == 0 {
+		return rec
+	}
+	res, err := client.BlockResult(rec.Height)
+	if err != nil || res == nil || res.Results == nil {
+		log.Printf("[monitor][%s] error fetching block results %d: %v", chainID, rec.Height, err)
+		return rec
+	}
+	for _, tx := range res.Results.DeliverTxs {
+		rec.GasWanted += tx.GasWanted
+		rec.GasUsed += tx.GasUsed
+	}
+	return rec
+}
@@ -0,0 +1,35 @@
+package gnovalidator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBlockRecord(t *testing.T) {
+	now := time.Now().UTC()
+	proposer := crypto.AddressFromPreimage([]byte("proposer"))
+	b := &types.Block{
+		Header: types.Header{Height: 42, Time: now, ProposerAddress: proposer},
+		Data:   types.Data{Txs: types.Txs{types.Tx("a"), types.Tx("b")}},
+		LastCommit: &types.Commit{Precommits: []*types.CommitSig{
+			nil,
+			{Type: types.PrecommitType, Height: 41, Round: 2},
+		}},
+	}
+
+	rec := newBlockRecord("test12", b)
+	assert.Equal(t, "test12", rec.ChainID)
+	assert.Equal(t, int64(42), rec.Height)
+	assert.Equal(t, now, rec.Time)
+	assert.Equal(t, proposer.String(), rec.ProposerAddr)
+	assert.Equal(t, 2, rec.TxCount)
+	assert.Equal(t, 2, rec.LastCommitRound)
+	assert.Positive(t, rec.Size)
+
+	b.LastCommit = nil
+	assert.Zero(t, newBlockRecord("test12", b).LastCommitRound, "the first block has no last commit")
+}
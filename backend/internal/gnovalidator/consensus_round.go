@@ -0,0 +1,330 @@
+package gnovalidator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/samouraiworld/gnomonitoring/backend/internal"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"gorm.io/gorm"
+)
+
+// consensusRoundCheckInterval is how often WatchConsensusRounds polls the
+// consensus state. A round lasts a few seconds, far less than the alert
+// check interval.
+const consensusRoundCheckInterval = 5 * time.Second
+
+// roundProposers returns the proposer of rounds 0..rounds-1 of a height
+// whose validators, with the proposer priorities of round 0, are vals and
+// whose round-0 proposer is proposer. Each new round moves the priorities
+// on by one step, as the consensus does when it enters the next round.
+func roundProposers(vals []*types.Validator, proposer *types.Validator, rounds int) []string {
+	if len(vals) == 0 || proposer == nil || rounds <= 0 {
+		return nil
+	}
+	set := (&types.ValidatorSet{Validators: vals, Proposer: proposer}).Copy()
+	proposers := make([]string, 0, rounds)
+	for r := 0; r < rounds; r++ {
+		if r > 0 {
+			set.IncrementProposerPriority(1)
+		}
+		proposers = append(proposers, set.GetProposer().Address.String())
+	}
+	return proposers
+}
+
+// roundZeroProposer returns the round-0 proposer of a height whose
+// validators, as served by the Validators RPC, are cur; prev are those of
+// the previous height. The RPC serves the proposer priorities but not the
+// proposer, which is the validator picked when the priorities of the
+// previous height were moved on to cur's.
+func roundZeroProposer(prev, cur []*types.Validator) *types.Validator {
+	if len(cur) == 0 {
+		return nil
+	}
+	if len(prev) > 0 {
+		next := (&types.ValidatorSet{Validators: prev}).CopyIncrementProposerPriority(1)
+		if samePriorities(next.Validators, cur) {
+			for _, v := range cur {
+				if v.Address == next.Proposer.Address {
+					return v
+				}
+			}
+		}
+	}
+	// The set changed at this height: the proposer is the validator that
+	// was ahead of every other one before the total voting power was taken
+	// off its priority. When several could have been, the lowest priority
+	// is the most likely.
+	var total int64
+	for _, v := range cur {
+		total += v.VotingPower
+	}
+	var best *types.Validator
+	for _, v := range cur {
+		ahead := true
+		for _, u := range cur {
+			if u != v && u.ProposerPriority > v.ProposerPriority+total {
+				ahead = false
+				break
+			}
+		}
+		if ahead && (best == nil || v.ProposerPriority < best.ProposerPriority) {
+			best = v
+		}
+	}
+	if best == nil {
+		best = cur[0]
+	}
+	return best
+}
+
+// samePriorities reports whether a and b list the same validators with the
+// same voting powers and proposer priorities.
+func samePriorities(a, b []*types.Validator) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Address != b[i].Address || a[i].VotingPower != b[i].VotingPower ||
+			a[i].ProposerPriority != b[i].ProposerPriority {
+			return false
+		}
+	}
+	return true
+}
+
+// fetchRoundProposers returns the proposer of rounds 0..rounds-1 of height.
+// The Validators RPC serves the set of a height, for committed heights as
+// well as for the one in progress.
+func fetchRoundProposers(rpcClient *FallbackRPCClient, height int64, rounds int) ([]string, error) {
+	cur, err := rpcClient.Validators(&height)
+	if err != nil {
+		return nil, err
+	}
+	if cur == nil || len(cur.Validators) == 0 {
+		return nil, fmt.Errorf("empty validator set at height %d", height)
+	}
+	var prev []*types.Validator
+	if prevHeight := height - 1; prevHeight > 0 {
+		res, err := rpcClient.Validators(&prevHeight)
+		if err != nil {
+			log.Printf("[consensus] validators of height %d: %v", prevHeight, err)
+		} else if res != nil {
+			prev = res.Validators
+		}
+	}
+	return roundProposers(cur.Validators, roundZeroProposer(prev, cur.Validators), rounds), nil
+}
+
+// recordMissedProposals stores a missed proposal for each round height went
+// through before it was committed in commitRound, attributed to the proposer
+// of that round. committedAt is the time of the block carrying the commit.
+func recordMissedProposals(db *gorm.DB, chainID string, height int64, commitRound int, committedAt time.Time) {
+	rpcClient, ok := GetChainRPCClient(chainID)
+	if !ok || rpcClient == nil {
+		return
+	}
+	proposers, err := fetchRoundProposers(rpcClient, height, commitRound)
+	if err != nil {
+		log.Printf("[consensus][%s] proposers of height %d: %v", chainID, height, err)
+		return
+	}
+	monikers := GetMonikerMap(chainID)
+	mps := make([]database.MissedProposal, 0, len(proposers))
+	for round, addr := range proposers {
+		mps = append(mps, database.MissedProposal{
+			ChainID:     chainID,
+			Height:      height,
+			Round:       round,
+			Addr:        addr,
+			Moniker:     monikers[addr],
+			CommitRound: commitRound,
+			CommittedAt: committedAt,
+		})
+	}
+	if err := database.InsertMissedProposals(db, mps); err != nil {
+		log.Printf("[consensus][%s] %v", chainID, err)
+		return
+	}
+	log.Printf("[consensus][%s] height %d committed in round %d, missed proposals: %s",
+		chainID, height, commitRound, strings.Join(proposers, ", "))
+}
+
+// parseHeightRound parses the "height/round/step" string of the consensus
+// state.
+func parseHeightRound(hrs string) (height int64, round int, ok bool) {
+	parts := strings.Split(hrs, "/")
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	height, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	round, err = strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	return height, round, true
+}
+
+// roundWatch is the state of WatchConsensusRounds: the height a WARNING was
+// raised for, until that height is committed.
+type roundWatch struct {
+	alertHeight int64
+	silenceID   uint
+}
+
+// step records that the chain is at height/round and reports whether a
+// WARNING must be raised for height (its round went past threshold for
+// the first time) and whether the height of the previous WARNING got
+// committed. A threshold <= 0 disables the WARNING.
+func (w *roundWatch) step(height int64, round, threshold int) (raise bool, resolved int64) {
+	if w.alertHeight != 0 && height > w.alertHeight {
+		resolved = w.alertHeight
+		w.alertHeight = 0
+	}
+	if threshold > 0 && round > threshold && w.alertHeight == 0 {
+		w.alertHeight = height
+		raise = true
+	}
+	return raise, resolved
+}
+
+// WatchConsensusRounds polls the consensus state of chainID and raises a
+// WARNING when a height is still undecided after the consensus_round_warning
+// round, naming the proposers of the rounds that failed. An INFO follows
+// once the height is committed.
+func WatchConsensusRounds(ctx context.Context, db *gorm.DB, chainID string, checkInterval time.Duration) {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("[consensus][%s] panic recovered: %v", chainID, r)
+			}
+		}()
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+
+		var w roundWatch
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			rpcClient, ok := GetChainRPCClient(chainID)
+			if !ok || rpcClient == nil {
+				continue
+			}
+			res, err := rpcClient.ConsensusState()
+			if err != nil || res == nil {
+				log.Printf("[consensus][%s] ConsensusState() error: %v", chainID, err)
+				continue
+			}
+			height, round, ok := parseHeightRound(res.RoundState.HeightRoundStep)
+			if !ok {
+				continue
+			}
+			raise, resolved := w.step(height, round, GetThresholds().ConsensusRoundWarning)
+			if resolved != 0 {
+				sendConsensusRoundResolved(db, chainID, resolved, w.silenceID)
+				w.silenceID = 0
+			}
+			if raise {
+				w.silenceID = sendConsensusRoundWarning(db, chainID, rpcClient, height, round)
+			}
+		}
+	}()
+}
+
+// sendConsensusRoundWarning alerts that height is at round, and returns the
+// ID of the silence that muted the alert, if any.
+func sendConsensusRoundWarning(db *gorm.DB, chainID string, rpcClient *FallbackRPCClient, height int64, round int) uint {
+	fields := []internal.AlertField{
+		{Name: "height", Value: fmt.Sprintf("%d", height)},
+		{Name: "round", Value: fmt.Sprintf("%d", round)},
+	}
+	var skipped []string
+	proposers, err := fetchRoundProposers(rpcClient, height, round+1)
+	if err != nil {
+		log.Printf("[consensus][%s] proposers of height %d: %v", chainID, height, err)
+	} else {
+		monikers := GetMonikerMap(chainID)
+		name := func(addr string) string {
+			if m := monikers[addr]; m != "" {
+				return m
+			}
+			return addr
+		}
+		for r, addr := range proposers[:round] {
+			skipped = append(skipped, fmt.Sprintf("%s (round %d)", name(addr), r))
+		}
+		fields = append(fields,
+			internal.AlertField{Name: "skipped proposers", Value: strings.Join(skipped, ", ")},
+			internal.AlertField{Name: "current proposer", Value: name(proposers[round])},
+		)
+	}
+
+	msg := fmt.Sprintf("⚠️ [%s] WARNING : height %d still undecided at round %d", chainID, height, round)
+	if len(skipped) > 0 {
+		msg += fmt.Sprintf(" (skipped proposers: %s)", strings.Join(skipped, ", "))
+	}
+	log.Println(msg)
+
+	data := internal.AlertData{
+		ChainID:     chainID,
+		Level:       internal.AlertWarning,
+		Emoji:       "⚠️",
+		Title:       "Consensus round climbing",
+		Fields:      fields,
+		Addr:        "all",
+		StartHeight: height,
+		Kind:        internal.AlertKindConsensusRound,
+	}
+	silenceID := activeSilenceID(db, chainID, "all")
+	if silenceID != 0 {
+		log.Printf("[consensus][%s] silence #%d: not sending consensus round alert", chainID, silenceID)
+		if _, err := database.InsertSilencedAlertlog(db, silenceID, chainID, "all", "all", "WARNING", height, height, false, time.Now(), msg); err != nil {
+			log.Printf("[consensus][%s] InsertAlertlog error: %v", chainID, err)
+		}
+		return silenceID
+	}
+	alertLogID, err := database.InsertAlertlog(db, chainID, "all", "all", "WARNING", height, height, false, time.Now(), msg)
+	if err != nil {
+		log.Printf("[consensus][%s] InsertAlertlog error: %v", chainID, err)
+	}
+	if err := internal.SendInfoValidator(chainID, data, alertLogID, db); err != nil {
+		log.Printf("[consensus][%s] SendInfoValidator error: %v", chainID, err)
+	}
+	return 0
+}
+
+// sendConsensusRoundResolved announces that height, which had a consensus
+// round WARNING, was committed. It is not sent when the WARNING was
+// silenced. No alert_logs row is written: a chain-level RESOLVED would also
+// close a "Blockchain stuck" incident.
+func sendConsensusRoundResolved(db *gorm.DB, chainID string, height int64, silenceID uint) {
+	if silenceID != 0 {
+		return
+	}
+	data := internal.AlertData{
+		ChainID:       chainID,
+		Level:         internal.AlertInfo,
+		Emoji:         "✅",
+		Title:         "Consensus round recovered",
+		Description:   fmt.Sprintf("Height %d was committed.", height),
+		Addr:          "all",
+		StartHeight:   height,
+		Kind:          internal.AlertKindConsensusRound,
+		ResolvedLevel: internal.AlertWarning,
+	}
+	if err := internal.SendInfoValidator(chainID, data, 0, db); err != nil {
+		log.Printf("[consensus][%s] SendInfoValidator error: %v", chainID, err)
+	}
+}
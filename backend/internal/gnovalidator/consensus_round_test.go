@@ -0,0 +1,94 @@
+package gnovalidator
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validatorsAsServed copies the validators of set without its proposer, as
+// the Validators RPC serves them.
+func validatorsAsServed(set *types.ValidatorSet) []*types.Validator {
+	return set.Copy().Validators
+}
+
+func TestRoundProposers_FollowConsensus(t *testing.T) {
+	var vals []*types.Validator
+	for _, power := range []int64{10, 20, 30, 40} {
+		vals = append(vals, types.NewValidator(ed25519.GenPrivKey().PubKey(), power))
+	}
+	set := types.NewValidatorSet(vals)
+
+	for height := 0; height < 20; height++ {
+		prev := set
+		set = set.CopyIncrementProposerPriority(1)
+
+		// The consensus moves the priorities on by one step per round.
+		rounds := set.Copy()
+		var want []string
+		for r := 0; r < 5; r++ {
+			if r > 0 {
+				rounds.IncrementProposerPriority(1)
+			}
+			want = append(want, rounds.GetProposer().Address.String())
+		}
+
+		cur := validatorsAsServed(set)
+		proposer := roundZeroProposer(validatorsAsServed(prev), cur)
+		require.NotNil(t, proposer)
+		assert.Equal(t, set.GetProposer().Address, proposer.Address, "height %d", height)
+		assert.Equal(t, want, roundProposers(cur, proposer, 5), "height %d", height)
+	}
+}
+
+func TestRoundZeroProposer_WithoutPreviousSet(t *testing.T) {
+	a := types.NewValidator(ed25519.GenPrivKey().PubKey(), 10)
+	b := types.NewValidator(ed25519.GenPrivKey().PubKey(), 10)
+	// b was just picked: the total voting power was taken off its priority.
+	a.ProposerPriority, b.ProposerPriority = 10, -10
+	assert.Equal(t, b.Address, roundZeroProposer(nil, []*types.Validator{a, b}).Address)
+
+	assert.Nil(t, roundZeroProposer(nil, nil))
+	assert.Empty(t, roundProposers(nil, nil, 3))
+}
+
+func TestParseHeightRound(t *testing.T) {
+	height, round, ok := parseHeightRound("1234/5/RoundStepPropose")
+	require.True(t, ok)
+	assert.Equal(t, int64(1234), height)
+	assert.Equal(t, 5, round)
+
+	_, _, ok = parseHeightRound("garbage")
+	assert.False(t, ok)
+}
+
+func TestRoundWatch_OneWarningPerHeight(t *testing.T) {
+	var w roundWatch
+	raise, resolved := w.step(100, 2, 3)
+	assert.False(t, raise)
+	assert.Zero(t, resolved)
+
+	raise, _ = w.step(100, 4, 3)
+	assert.True(t, raise, "round went past the threshold")
+	raise, _ = w.step(100, 6, 3)
+	assert.False(t, raise, "already raised for this height")
+
+	raise, resolved = w.step(101, 0, 3)
+	assert.False(t, raise)
+	assert.Equal(t, int64(100), resolved, "height 100 got committed")
+
+	raise, resolved = w.step(102, 5, 3)
+	assert.True(t, raise)
+	assert.Zero(t, resolved)
+	// The next height is also stuck: resolve the previous one, raise again.
+	raise, resolved = w.step(103, 4, 3)
+	assert.True(t, raise)
+	assert.Equal(t, int64(102), resolved)
+
+	var off roundWatch
+	raise, _ = off.step(200, 50, 0)
+	assert.False(t, raise, "a zero threshold disables the warning")
+}
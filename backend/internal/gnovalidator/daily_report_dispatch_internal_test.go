@@ -73,3 +73,44 @@ func TestDispatchDailyReportToWebhooks_SendsEmbedToDiscordAndBlocksToSlack(t *te
 		t.Fatalf("expected a non-empty blocks payload sent to the Slack webhook, got: %+v", slackBlocks)
 	}
 }
+
+func TestDispatchDailyReportToWebhooks_SendsMatrixAndMattermost(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+
+	origSendMatrixMessage := sendMatrixMessage
+	origSendMattermostMessage := sendMattermostMessage
+	defer func() {
+		sendMatrixMessage = origSendMatrixMessage
+		sendMattermostMessage = origSendMattermostMessage
+	}()
+
+	var matrixURL, mattermostURL string
+	var matrixMsg internal.MatrixMessage
+	var mattermostMsg internal.MattermostMessage
+	sendMatrixMessage = func(m internal.MatrixMessage, webhookURL string) error {
+		matrixMsg, matrixURL = m, webhookURL
+		return nil
+	}
+	sendMattermostMessage = func(m internal.MattermostMessage, webhookURL string) error {
+		mattermostMsg, mattermostURL = m, webhookURL
+		return nil
+	}
+
+	const matrixWebhookURL = "https://hookshot.example.org/webhook/fake"
+	const mattermostWebhookURL = "https://mattermost.example.org/hooks/fake"
+
+	db.Create(&database.WebhookValidator{UserID: "u1", URL: matrixWebhookURL, Type: "matrix", Description: "m"})
+	db.Create(&database.WebhookValidator{UserID: "u1", URL: mattermostWebhookURL, Type: "mattermost", Description: "mm"})
+
+	data := DailyReportData{ChainID: "test12", Date: "2025-11-02", TotalCount: 1, AllHealthy: true}
+	if err := dispatchDailyReportToWebhooks(db, "u1", "test12", data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if matrixURL != matrixWebhookURL || matrixMsg.HTML == "" {
+		t.Fatalf("expected an HTML report sent to %s, got %q to %q", matrixWebhookURL, matrixMsg.HTML, matrixURL)
+	}
+	if mattermostURL != mattermostWebhookURL || len(mattermostMsg.Attachments) != 1 {
+		t.Fatalf("expected one attachment sent to %s, got %+v to %q", mattermostWebhookURL, mattermostMsg, mattermostURL)
+	}
+}
@@ -3,8 +3,10 @@ package gnovalidator
 import (
 	"fmt"
 	"html"
+	htmltemplate "html/template"
 	"sort"
 	"strings"
+	texttemplate "text/template"
 
 	"github.com/samouraiworld/gnomonitoring/backend/internal"
 	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
@@ -25,10 +27,13 @@ import (
 // truncates Telegram/Slack earlier than necessary or risks Discord going
 // over its field limit.
 const (
-	maxProblemsDiscord   = 20 // Discord embeds cap at 25 fields; leaves room for a "…and N more" field.
-	maxProblemsSlack     = 40 // Slack messages cap at 50 blocks; leaves room for header/summary/divider/link blocks.
-	maxProblemsTelegram  = 60 // Telegram caps messages at 4096 chars; ~60 one-line entries comfortably fits alongside the header/summary text.
-	maxProblemsPlainText = 60 // Server log line, no hard transport limit; matches Telegram's budget for a readable trace.
+	maxProblemsDiscord    = 20 // Discord embeds cap at 25 fields; leaves room for a "…and N more" field.
+	maxProblemsSlack      = 40 // Slack messages cap at 50 blocks; leaves room for header/summary/divider/link blocks.
+	maxProblemsTelegram   = 60 // Telegram caps messages at 4096 chars; ~60 one-line entries comfortably fits alongside the header/summary text.
+	maxProblemsPlainText  = 60 // Server log line, no hard transport limit; matches Telegram's budget for a readable trace.
+	maxProblemsEmail      = 60 // No hard transport limit; matches Telegram's budget so the email stays readable during a chain-wide incident.
+	maxProblemsMatrix     = 60 // Matrix events cap at 65 KB, far above 60 entries in both the text and HTML bodies; matches Telegram's budget.
+	maxProblemsMattermost = 60 // Mattermost posts cap at 16383 chars; 60 entries of ~120 chars stay well under it.
 )
 
 // DailyReportData is the channel-neutral summary of one chain's daily
@@ -335,6 +340,92 @@ func RenderDailyReportSlackBlocks(d DailyReportData) []internal.SlackBlock {
 	return blocks
 }
 
+// RenderDailyReportMatrix formats DailyReportData for a Matrix hookshot
+// webhook: the plain-text body is the same report as
+// RenderDailyReportPlainText, the HTML body follows
+// RenderDailyReportTelegramHTML, with the report link inline.
+func RenderDailyReportMatrix(d DailyReportData) internal.MatrixMessage {
+	shown, truncatedCount := truncateProblems(d.Problems, maxProblemsMatrix)
+
+	var text, body strings.Builder
+	text.WriteString(fmt.Sprintf("📊 [%s] Daily Summary — %s\n", d.ChainID, d.Date))
+	body.WriteString(fmt.Sprintf("📊 <b><font data-mx-color=\"#%06X\">[%s] Daily Summary — %s</font></b><br>\n",
+		RenderDailyReportDiscordEmbed(d).Color, html.EscapeString(d.ChainID), html.EscapeString(d.Date)))
+	if d.ChainSummary != "" {
+		text.WriteString(d.ChainSummary + "\n")
+		body.WriteString(strings.ReplaceAll(html.EscapeString(d.ChainSummary), "\n", "<br>\n") + "<br>\n")
+	}
+	if w := reportWindowText(d); w != "" {
+		text.WriteString(w + "\n")
+		body.WriteString(html.EscapeString(w) + "<br>\n")
+	}
+	if d.AllHealthy {
+		line := fmt.Sprintf("✅ All %d validators healthy (last 24h)", d.TotalCount)
+		text.WriteString(line + "\n")
+		body.WriteString(line + "<br>\n")
+	} else {
+		line := fmt.Sprintf("⚠️ %d/%d validators need attention (last 24h):", len(d.Problems), d.TotalCount)
+		text.WriteString(line + "\n")
+		body.WriteString(line + "\n<ul>\n")
+		for _, p := range shown {
+			text.WriteString(fmt.Sprintf("  %s (%s) — Tier: %s | Score: %d | Missed: %d\n",
+				p.DisplayName(), p.Addr, p.Tier, p.Score, p.MissedBlocks))
+			body.WriteString(fmt.Sprintf("<li><b>%s</b> (<code>%s</code>) — Tier: %s | Score: %d | Missed: %d</li>\n",
+				html.EscapeString(p.DisplayName()), html.EscapeString(p.Addr), p.Tier, p.Score, p.MissedBlocks))
+		}
+		body.WriteString("</ul>\n")
+		if truncatedCount > 0 {
+			text.WriteString("  " + truncatedSummaryText(truncatedCount) + "\n")
+			body.WriteString(html.EscapeString(truncatedSummaryText(truncatedCount)) + "<br>\n")
+		}
+	}
+	if d.ReportLink != "" {
+		text.WriteString("📊 Full report: " + d.ReportLink + "\n")
+		body.WriteString(fmt.Sprintf("<a href=\"%s\">📊 Full report</a>\n", html.EscapeString(d.ReportLink)))
+	}
+	return internal.MatrixMessage{Text: text.String(), HTML: body.String()}
+}
+
+// RenderDailyReportMattermost builds a Mattermost message summarizing the
+// daily report as one attachment: colored like RenderDailyReportDiscordEmbed,
+// titled with a link to the full report when set, and one markdown line per
+// problem validator.
+func RenderDailyReportMattermost(d DailyReportData) internal.MattermostMessage {
+	var lines []string
+	if d.ChainSummary != "" {
+		lines = append(lines, d.ChainSummary)
+	}
+	if w := reportWindowText(d); w != "" {
+		lines = append(lines, w)
+	}
+	if d.AllHealthy {
+		lines = append(lines, fmt.Sprintf("✅ All %d validators healthy (last 24h)", d.TotalCount))
+	} else {
+		shown, truncatedCount := truncateProblems(d.Problems, maxProblemsMattermost)
+		lines = append(lines, fmt.Sprintf("⚠️ %d/%d validators need attention (last 24h)", len(d.Problems), d.TotalCount))
+		for _, p := range shown {
+			lines = append(lines, fmt.Sprintf("- **%s** (`%s`) — Tier: %s | Score: %d | Missed: %d",
+				p.DisplayName(), p.Addr, p.Tier, p.Score, p.MissedBlocks))
+		}
+		if truncatedCount > 0 {
+			lines = append(lines, truncatedSummaryText(truncatedCount))
+		}
+	}
+
+	title := fmt.Sprintf("[%s] Daily Summary — %s", d.ChainID, d.Date)
+	attachment := internal.MattermostAttachment{
+		Fallback:  title,
+		Color:     fmt.Sprintf("#%06X", RenderDailyReportDiscordEmbed(d).Color),
+		Title:     title,
+		TitleLink: d.ReportLink,
+		Text:      strings.Join(lines, "\n"),
+	}
+	if d.ReportLink != "" {
+		attachment.Footer = "Full report: " + d.ReportLink
+	}
+	return internal.MattermostMessage{Attachments: []internal.MattermostAttachment{attachment}}
+}
+
 // RenderDailyReportTelegramHTML formats DailyReportData as an HTML message
 // (Telegram parse_mode=HTML) plus an optional link-button's text/URL. Callers
 // pass buttonURL to SendTelegramMessageWithButton (empty means "no button").
@@ -366,3 +457,79 @@ func RenderDailyReportTelegramHTML(d DailyReportData) (text, buttonText, buttonU
 	}
 	return sb.String(), "", ""
 }
+
+// dailyReportEmailView is what the daily report email templates render:
+// DailyReportData with Problems already truncated to maxProblemsEmail.
+type dailyReportEmailView struct {
+	DailyReportData
+	Window    string
+	Shown     []database.ValidatorReportEntry
+	Truncated string
+	Color     string
+}
+
+var dailyReportEmailText = texttemplate.Must(texttemplate.New("report").Parse(
+	`[{{.ChainID}}] Daily Summary — {{.Date}}
+{{if .ChainSummary}}
+{{.ChainSummary}}
+{{end}}{{if .Window}}{{.Window}}
+{{end}}
+{{if .AllHealthy}}✅ All {{.TotalCount}} validators healthy (last 24h)
+{{else}}⚠️ {{len .Problems}}/{{.TotalCount}} validators need attention (last 24h):
+{{range .Shown}}  {{.DisplayName}} ({{.Addr}}) — Tier: {{.Tier}} | Score: {{.Score}} | Missed: {{.MissedBlocks}}
+{{end}}{{if .Truncated}}  {{.Truncated}}
+{{end}}{{end}}{{range .ValsetChanges}}{{if eq .NewPower 0}}Valset: block #{{.BlockNum}} — {{.Address}} removed
+{{else}}Valset: block #{{.BlockNum}} — {{.Address}} added (power: {{.NewPower}})
+{{end}}{{end}}{{if .ReportLink}}
+📊 Full report: {{.ReportLink}}
+{{end}}`))
+
+var dailyReportEmailHTML = htmltemplate.Must(htmltemplate.New("report").Parse(
+	`<!DOCTYPE html>
+<html><body style="font-family:Arial,Helvetica,sans-serif;color:#222">
+<div style="border-left:6px solid {{.Color}};padding:8px 16px">
+<h2 style="margin:0 0 8px">[{{.ChainID}}] Daily Summary — {{.Date}}</h2>
+{{if .ChainSummary}}<p style="white-space:pre-line">{{.ChainSummary}}</p>{{end}}
+{{if .Window}}<p style="color:#888">{{.Window}}</p>{{end}}
+{{if .AllHealthy}}<p>✅ All {{.TotalCount}} validators healthy (last 24h)</p>
+{{else}}<p>⚠️ {{len .Problems}}/{{.TotalCount}} validators need attention (last 24h):</p>
+<table style="border-collapse:collapse">
+<tr><th align="left" style="padding:2px 12px 2px 0">Validator</th><th align="left" style="padding:2px 12px 2px 0">Tier</th><th align="right" style="padding:2px 12px 2px 0">Score</th><th align="right" style="padding:2px 0">Missed</th></tr>
+{{range .Shown}}<tr><td style="padding:2px 12px 2px 0"><b>{{.DisplayName}}</b><br><code>{{.Addr}}</code></td><td style="padding:2px 12px 2px 0">{{.Tier}}</td><td align="right" style="padding:2px 12px 2px 0">{{.Score}}</td><td align="right" style="padding:2px 0">{{.MissedBlocks}}</td></tr>
+{{end}}</table>
+{{if .Truncated}}<p>{{.Truncated}}</p>{{end}}
+{{end}}{{if .ValsetChanges}}<ul>
+{{range .ValsetChanges}}<li>Valset: block #{{.BlockNum}} — <code>{{.Address}}</code> {{if eq .NewPower 0}}removed{{else}}added (power: {{.NewPower}}){{end}}</li>
+{{end}}</ul>{{end}}
+{{if .ReportLink}}<p><a href="{{.ReportLink}}">📊 Full report</a></p>{{end}}
+</div>
+</body></html>
+`))
+
+// RenderDailyReportEmail formats DailyReportData as an email (plain text and
+// HTML). The accent color follows RenderDailyReportDiscordEmbed's.
+func RenderDailyReportEmail(d DailyReportData) (internal.EmailMessage, error) {
+	shown, truncatedCount := truncateProblems(d.Problems, maxProblemsEmail)
+	view := dailyReportEmailView{
+		DailyReportData: d,
+		Window:          reportWindowText(d),
+		Shown:           shown,
+		Color:           fmt.Sprintf("#%06X", RenderDailyReportDiscordEmbed(d).Color),
+	}
+	if truncatedCount > 0 {
+		view.Truncated = truncatedSummaryText(truncatedCount)
+	}
+
+	var text, body strings.Builder
+	if err := dailyReportEmailText.Execute(&text, view); err != nil {
+		return internal.EmailMessage{}, fmt.Errorf("render daily report email text: %w", err)
+	}
+	if err := dailyReportEmailHTML.Execute(&body, view); err != nil {
+		return internal.EmailMessage{}, fmt.Errorf("render daily report email html: %w", err)
+	}
+	return internal.EmailMessage{
+		Subject: fmt.Sprintf("[%s] Daily Summary — %s", d.ChainID, d.Date),
+		Text:    text.String(),
+		HTML:    body.String(),
+	}, nil
+}
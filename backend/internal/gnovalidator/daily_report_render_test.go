@@ -454,3 +454,79 @@ func TestRenderDailyReportDiscordEmbed_TruncatedCountAddsField(t *testing.T) {
 		t.Fatalf("did not expect an extra field when Problems is within the limit, got: %+v", embed.Fields)
 	}
 }
+
+func TestRenderDailyReportEmail_TextAndEscapedHTML(t *testing.T) {
+	d := DailyReportData{
+		ChainID: "test12", Date: "2025-11-02", TotalCount: 3,
+		Problems: []database.ValidatorReportEntry{
+			{Addr: "g1bad", Moniker: "<script>", Score: 10, Tier: score.TierCritical, MissedBlocks: 5},
+		},
+		ValsetChanges: []ValsetChange{{BlockNum: 42, Address: "g1gone", NewPower: 0}},
+		ReportLink:    "https://example.com/reports/test12",
+	}
+	m, err := RenderDailyReportEmail(d)
+	if err != nil {
+		t.Fatalf("RenderDailyReportEmail error: %v", err)
+	}
+
+	if m.Subject != "[test12] Daily Summary — 2025-11-02" {
+		t.Fatalf("Subject = %q", m.Subject)
+	}
+	for _, want := range []string{"1/3 validators need attention", "<script> (g1bad) — Tier: Critical", "block #42 — g1gone removed", "Full report: https://example.com/reports/test12"} {
+		if !strings.Contains(m.Text, want) {
+			t.Fatalf("text part missing %q:\n%s", want, m.Text)
+		}
+	}
+	if strings.Contains(m.HTML, "<script>") || !strings.Contains(m.HTML, "&lt;script&gt;") {
+		t.Fatalf("moniker must be HTML-escaped, got:\n%s", m.HTML)
+	}
+	if !strings.Contains(m.HTML, `href="https://example.com/reports/test12"`) {
+		t.Fatalf("html part must link the report, got:\n%s", m.HTML)
+	}
+}
+
+func TestRenderDailyReportMatrix_EscapesMonikerAndLinksReport(t *testing.T) {
+	d := DailyReportData{
+		ChainID: "test12", Date: "2025-11-02", TotalCount: 2,
+		Problems: []database.ValidatorReportEntry{
+			{Addr: "g1", Moniker: "<script>", Score: 10, Tier: score.TierCritical, MissedBlocks: 5},
+		},
+		ReportLink: "https://example.com/reports/test12",
+	}
+	m := RenderDailyReportMatrix(d)
+
+	if strings.Contains(m.HTML, "<script>") || !strings.Contains(m.HTML, "<li><b>&lt;script&gt;</b> (<code>g1</code>)") {
+		t.Fatalf("moniker must be HTML-escaped, got:\n%s", m.HTML)
+	}
+	if !strings.Contains(m.HTML, `<a href="https://example.com/reports/test12">`) {
+		t.Fatalf("expected the report link in the html body, got:\n%s", m.HTML)
+	}
+	if !strings.Contains(m.Text, "<script> (g1) — Tier: Critical | Score: 10 | Missed: 5") {
+		t.Fatalf("text body must stay unescaped, got:\n%s", m.Text)
+	}
+}
+
+func TestRenderDailyReportMattermost_ColorTitleLinkAndProblems(t *testing.T) {
+	d := DailyReportData{
+		ChainID: "test12", Date: "2025-11-02", TotalCount: 2,
+		Problems: []database.ValidatorReportEntry{
+			{Addr: "g2", Moniker: "m2", Score: 40, Tier: score.TierWatch, MissedBlocks: 2},
+		},
+		ReportLink: "https://example.com/reports/test12",
+	}
+	m := RenderDailyReportMattermost(d)
+
+	if len(m.Attachments) != 1 {
+		t.Fatalf("len(attachments) = %d, want 1", len(m.Attachments))
+	}
+	a := m.Attachments[0]
+	if a.Color != "#F39C12" {
+		t.Fatalf("color = %q, want orange for a Watch-only report", a.Color)
+	}
+	if a.TitleLink != d.ReportLink {
+		t.Fatalf("title_link = %q, want the report link", a.TitleLink)
+	}
+	if !strings.Contains(a.Text, "- **m2** (`g2`) — Tier: Watch | Score: 40 | Missed: 2") {
+		t.Fatalf("expected a markdown line for m2, got:\n%s", a.Text)
+	}
+}
@@ -0,0 +1,318 @@
+package gnovalidator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	rpcclient "github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	"github.com/samouraiworld/gnomonitoring/backend/internal"
+	"gorm.io/gorm"
+)
+
+// endpointCheckInterval is how often WatchEndpointConsistency compares the
+// RPC endpoints of a chain.
+const endpointCheckInterval = 30 * time.Second
+
+// endpointView is what one RPC endpoint reported in a check: its latest
+// height, and the block and app hashes at the compared height. Height is 0
+// when the endpoint could not be reached.
+type endpointView struct {
+	Endpoint  string
+	Height    int64
+	BlockHash string
+	AppHash   string
+}
+
+// endpointVerdict is the outcome of comparing the views of all endpoints.
+type endpointVerdict struct {
+	BestHeight int64
+	// Reachable lists the endpoints that answered.
+	Reachable map[string]bool
+	// Lagging maps the endpoints more than the lag threshold behind the
+	// highest one to how many blocks they are behind.
+	Lagging map[string]int64
+	// Groups maps each "block hash/app hash" pair seen at the compared
+	// height to the endpoints reporting it; more than one group is a
+	// divergence.
+	Groups map[string][]string
+}
+
+// compareHeight is the height all reachable endpoints have: the lowest of
+// their latest heights.
+func compareHeight(views []endpointView) int64 {
+	var h int64
+	for _, v := range views {
+		if v.Height > 0 && (h == 0 || v.Height < h) {
+			h = v.Height
+		}
+	}
+	return h
+}
+
+// compareEndpoints builds the verdict of views. Unreachable endpoints are
+// left out: the failover already logs their errors.
+func compareEndpoints(views []endpointView, lagThreshold int64) endpointVerdict {
+	v := endpointVerdict{Reachable: map[string]bool{}, Lagging: map[string]int64{}, Groups: map[string][]string{}}
+	for _, ev := range views {
+		if ev.Height > v.BestHeight {
+			v.BestHeight = ev.Height
+		}
+	}
+	for _, ev := range views {
+		if ev.Height == 0 {
+			continue
+		}
+		v.Reachable[ev.Endpoint] = true
+		if behind := v.BestHeight - ev.Height; lagThreshold > 0 && behind > lagThreshold {
+			v.Lagging[ev.Endpoint] = behind
+		}
+		if ev.BlockHash != "" {
+			key := ev.BlockHash + "/" + ev.AppHash
+			v.Groups[key] = append(v.Groups[key], ev.Endpoint)
+		}
+	}
+	return v
+}
+
+// endpointWatch is the state of WatchEndpointConsistency: the endpoints a
+// lag WARNING was sent for and whether a divergence is open.
+type endpointWatch struct {
+	lagging  map[string]bool
+	diverged bool
+}
+
+// update records verdict and returns what changed since the previous one:
+// endpoints that started or stopped lagging, and whether a divergence
+// started or ended.
+func (w *endpointWatch) update(v endpointVerdict) (lagStarted, lagEnded []string, divergenceStarted, divergenceEnded bool) {
+	if w.lagging == nil {
+		w.lagging = map[string]bool{}
+	}
+	for ep := range v.Lagging {
+		if !w.lagging[ep] {
+			w.lagging[ep] = true
+			lagStarted = append(lagStarted, ep)
+		}
+	}
+	for ep := range w.lagging {
+		// An endpoint that stopped answering has not caught up.
+		if _, still := v.Lagging[ep]; !still && v.Reachable[ep] {
+			delete(w.lagging, ep)
+			lagEnded = append(lagEnded, ep)
+		}
+	}
+	sort.Strings(lagStarted)
+	sort.Strings(lagEnded)
+
+	diverged := len(v.Groups) > 1
+	divergenceStarted = diverged && !w.diverged
+	// A check where fewer than two endpoints answered proves nothing:
+	// keep the divergence open.
+	divergenceEnded = w.diverged && len(v.Groups) == 1 && len(firstGroup(v.Groups)) > 1
+	if divergenceStarted {
+		w.diverged = true
+	} else if divergenceEnded {
+		w.diverged = false
+	}
+	return lagStarted, lagEnded, divergenceStarted, divergenceEnded
+}
+
+func firstGroup(groups map[string][]string) []string {
+	for _, eps := range groups {
+		return eps
+	}
+	return nil
+}
+
+// endpointChecker queries each RPC endpoint of a chain on its own, unlike
+// FallbackRPCClient which only talks to the active one.
+type endpointChecker struct {
+	endpoints []string
+	clients   []*rpcclient.RPCClient
+}
+
+func newEndpointChecker(endpoints []string) *endpointChecker {
+	return &endpointChecker{endpoints: endpoints, clients: make([]*rpcclient.RPCClient, len(endpoints))}
+}
+
+func (c *endpointChecker) client(i int) *rpcclient.RPCClient {
+	if c.clients[i] == nil {
+		cl, err := rpcclient.NewHTTPClient(c.endpoints[i])
+		if err != nil {
+			log.Printf("[endpoints] failed to create client for %s: %v", internal.EndpointLabel(c.endpoints[i]), err)
+			return nil
+		}
+		c.clients[i] = cl
+	}
+	return c.clients[i]
+}
+
+// views fetches the latest height of every endpoint, then the block at the
+// height they all have.
+func (c *endpointChecker) views(chainID string) []endpointView {
+	views := make([]endpointView, len(c.endpoints))
+	clients := make([]*rpcclient.RPCClient, len(c.endpoints))
+	for i, ep := range c.endpoints {
+		views[i].Endpoint = ep
+		clients[i] = c.client(i)
+	}
+
+	var wg sync.WaitGroup
+	for i, cl := range clients {
+		if cl == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, cl *rpcclient.RPCClient) {
+			defer wg.Done()
+			st, err := cl.Status()
+			if err != nil || st == nil {
+				log.Printf("[endpoints][%s] %s Status() error: %v", chainID, internal.EndpointLabel(views[i].Endpoint), err)
+				return
+			}
+			views[i].Height = st.SyncInfo.LatestBlockHeight
+		}(i, cl)
+	}
+	wg.Wait()
+
+	h := compareHeight(views)
+	if h == 0 {
+		return views
+	}
+	for i, cl := range clients {
+		if cl == nil || views[i].Height == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, cl *rpcclient.RPCClient) {
+			defer wg.Done()
+			b, err := cl.Block(&h)
+			if err != nil || b == nil || b.Block == nil {
+				log.Printf("[endpoints][%s] %s Block(%d) error: %v", chainID, internal.EndpointLabel(views[i].Endpoint), h, err)
+				return
+			}
+			views[i].BlockHash = fmt.Sprintf("%X", b.BlockMeta.BlockID.Hash)
+			views[i].AppHash = fmt.Sprintf("%X", b.Block.Header.AppHash)
+		}(i, cl)
+	}
+	wg.Wait()
+	return views
+}
+
+// WatchEndpointConsistency compares the RPC endpoints of chainID every
+// checkInterval: a CRITICAL is sent when they report different blocks at
+// the same height (a chain split or a misbehaving node), and a WARNING for
+// each endpoint more than endpoint_lag_blocks behind the highest one. Each
+// is followed by an INFO once it clears. The alerts are chain-level and are
+// not written to alert_logs, so they never touch the "Blockchain stuck"
+// incident.
+func WatchEndpointConsistency(ctx context.Context, db *gorm.DB, chainID string, endpoints []string, checkInterval time.Duration) {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("[endpoints][%s] panic recovered: %v", chainID, r)
+			}
+		}()
+		checker := newEndpointChecker(endpoints)
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+
+		var w endpointWatch
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			views := checker.views(chainID)
+			h := compareHeight(views)
+			v := compareEndpoints(views, int64(GetThresholds().EndpointLagBlocks))
+			lagStarted, lagEnded, divStarted, divEnded := w.update(v)
+
+			for _, ep := range lagStarted {
+				sendEndpointAlert(db, chainID, internal.AlertData{
+					Level: internal.AlertWarning,
+					Emoji: "⚠️",
+					Title: "RPC endpoint lagging",
+					Fields: []internal.AlertField{
+						{Name: "endpoint", Value: internal.EndpointLabel(ep)},
+						{Name: "behind", Value: fmt.Sprintf("%d blocks", v.Lagging[ep])},
+						{Name: "highest height", Value: fmt.Sprintf("%d", v.BestHeight)},
+					},
+				})
+			}
+			for _, ep := range lagEnded {
+				sendEndpointAlert(db, chainID, internal.AlertData{
+					Level:         internal.AlertInfo,
+					Emoji:         "✅",
+					Title:         "RPC endpoint caught up",
+					Description:   fmt.Sprintf("%s is back within %d blocks of the other endpoints.", internal.EndpointLabel(ep), GetThresholds().EndpointLagBlocks),
+					ResolvedLevel: internal.AlertWarning,
+				})
+			}
+			if divStarted {
+				fields := []internal.AlertField{{Name: "height", Value: fmt.Sprintf("%d", h)}}
+				keys := make([]string, 0, len(v.Groups))
+				for k := range v.Groups {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				for _, k := range keys {
+					blockHash, appHash, _ := strings.Cut(k, "/")
+					labels := make([]string, 0, len(v.Groups[k]))
+					for _, ep := range v.Groups[k] {
+						labels = append(labels, internal.EndpointLabel(ep))
+					}
+					fields = append(fields, internal.AlertField{
+						Name:  strings.Join(labels, ", "),
+						Value: fmt.Sprintf("block %s, app hash %s", shortHash(blockHash), shortHash(appHash)),
+					})
+				}
+				log.Printf("[endpoints][%s] endpoints disagree at height %d: %v", chainID, h, v.Groups)
+				sendEndpointAlert(db, chainID, internal.AlertData{
+					Level:  internal.AlertCritical,
+					Emoji:  "🚨",
+					Title:  "RPC endpoints disagree",
+					Fields: fields,
+				})
+			}
+			if divEnded {
+				sendEndpointAlert(db, chainID, internal.AlertData{
+					Level:         internal.AlertInfo,
+					Emoji:         "✅",
+					Title:         "RPC endpoints agree again",
+					Description:   fmt.Sprintf("All reachable endpoints report the same block at height %d.", h),
+					ResolvedLevel: internal.AlertCritical,
+				})
+			}
+		}
+	}()
+}
+
+// shortHash keeps the first 12 characters of a hex hash.
+func shortHash(h string) string {
+	if len(h) > 12 {
+		return h[:12]
+	}
+	return h
+}
+
+// sendEndpointAlert sends a chain-level endpoint check alert, unless a
+// chain-wide silence is active.
+func sendEndpointAlert(db *gorm.DB, chainID string, data internal.AlertData) {
+	data.ChainID = chainID
+	data.Addr = "all"
+	data.Kind = internal.AlertKindEndpointDivergence
+	if silenceID := activeSilenceID(db, chainID, "all"); silenceID != 0 {
+		log.Printf("[endpoints][%s] silence #%d: not sending %q", chainID, silenceID, data.Title)
+		return
+	}
+	if err := internal.SendInfoValidator(chainID, data, 0, db); err != nil {
+		log.Printf("[endpoints][%s] SendInfoValidator error: %v", chainID, err)
+	}
+}
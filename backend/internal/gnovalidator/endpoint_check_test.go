@@ -0,0 +1,63 @@
+package gnovalidator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareEndpoints(t *testing.T) {
+	views := []endpointView{
+		{Endpoint: "https://a", Height: 120, BlockHash: "AA", AppHash: "01"},
+		{Endpoint: "https://b", Height: 118, BlockHash: "AA", AppHash: "01"},
+		{Endpoint: "https://c", Height: 100, BlockHash: "BB", AppHash: "01"},
+		{Endpoint: "https://down"},
+	}
+	assert.Equal(t, int64(100), compareHeight(views), "the height every reachable endpoint has")
+
+	v := compareEndpoints(views, 10)
+	assert.Equal(t, int64(120), v.BestHeight)
+	assert.Equal(t, map[string]int64{"https://c": 20}, v.Lagging)
+	assert.Equal(t, map[string][]string{"AA/01": {"https://a", "https://b"}, "BB/01": {"https://c"}}, v.Groups)
+
+	assert.Empty(t, compareEndpoints(views, 0).Lagging, "a zero threshold disables the lag check")
+}
+
+func TestEndpointWatch_Transitions(t *testing.T) {
+	var w endpointWatch
+	agree := endpointVerdict{
+		Reachable: map[string]bool{"a": true, "b": true, "c": true},
+		Groups:    map[string][]string{"AA/01": {"a", "b", "c"}},
+	}
+	split := endpointVerdict{
+		Reachable: map[string]bool{"a": true, "b": true, "c": true},
+		Lagging:   map[string]int64{"c": 15},
+		Groups:    map[string][]string{"AA/01": {"a"}, "BB/01": {"b"}},
+	}
+
+	lagStarted, lagEnded, divStarted, divEnded := w.update(agree)
+	assert.Empty(t, lagStarted)
+	assert.Empty(t, lagEnded)
+	assert.False(t, divStarted)
+	assert.False(t, divEnded)
+
+	lagStarted, _, divStarted, _ = w.update(split)
+	assert.Equal(t, []string{"c"}, lagStarted)
+	assert.True(t, divStarted)
+
+	lagStarted, _, divStarted, divEnded = w.update(split)
+	assert.Empty(t, lagStarted, "already reported")
+	assert.False(t, divStarted)
+	assert.False(t, divEnded)
+
+	_, lagEnded, _, divEnded = w.update(endpointVerdict{
+		Reachable: map[string]bool{"a": true},
+		Groups:    map[string][]string{"AA/01": {"a"}},
+	})
+	assert.Empty(t, lagEnded, "an endpoint that stopped answering has not caught up")
+	assert.False(t, divEnded, "a single answering endpoint does not prove agreement")
+
+	_, lagEnded, _, divEnded = w.update(agree)
+	assert.Equal(t, []string{"c"}, lagEnded)
+	assert.True(t, divEnded)
+}
@@ -0,0 +1,208 @@
+package gnovalidator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	rpcclient "github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/samouraiworld/gnomonitoring/backend/internal"
+)
+
+// endpointProbeInterval is how often WatchEndpointHealth probes the
+// endpoints of a chain.
+const endpointProbeInterval = 15 * time.Second
+
+// endpointProbeTimeout bounds a GraphQL or gnoweb probe.
+const endpointProbeTimeout = 10 * time.Second
+
+var endpointProbeHTTP = &http.Client{Timeout: endpointProbeTimeout}
+
+// probeGraphQL asks a tx-indexer for the latest block height it indexed.
+func probeGraphQL(ctx context.Context, endpoint string) (int64, error) {
+	body, _ := json.Marshal(map[string]string{"query": "{ latestBlockHeight }"})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := endpointProbeHTTP.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("HTTP status %d", resp.StatusCode)
+	}
+	var out struct {
+		Data struct {
+			LatestBlockHeight int64 `json:"latestBlockHeight"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return 0, err
+	}
+	if len(out.Errors) > 0 {
+		return 0, fmt.Errorf("graphql: %s", out.Errors[0].Message)
+	}
+	return out.Data.LatestBlockHeight, nil
+}
+
+// probeGnoweb checks that a gnoweb instance serves its home page. gnoweb has
+// no height to report.
+func probeGnoweb(ctx context.Context, endpoint string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := endpointProbeHTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("HTTP status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// endpointProber probes the RPC, GraphQL and gnoweb endpoints of a chain.
+type endpointProber struct {
+	chainID string
+	lists   map[string][]string // by endpoint kind
+	rpc     map[string]*rpcclient.RPCClient
+}
+
+func newEndpointProber(chainID string, chainCfg *internal.ChainConfig) *endpointProber {
+	return &endpointProber{
+		chainID: chainID,
+		lists: map[string][]string{
+			internal.EndpointRPC:     chainCfg.RPCEndpoints,
+			internal.EndpointGraphQL: chainCfg.GraphqlEndpoints,
+			internal.EndpointGnoweb:  chainCfg.GnowebEndpoints,
+		},
+		rpc: map[string]*rpcclient.RPCClient{},
+	}
+}
+
+func (p *endpointProber) probe(ctx context.Context, kind, endpoint string) (int64, error) {
+	switch kind {
+	case internal.EndpointRPC:
+		st, err := p.rpc[endpoint].Status()
+		if err != nil {
+			return 0, err
+		}
+		return st.SyncInfo.LatestBlockHeight, nil
+	case internal.EndpointGraphQL:
+		return probeGraphQL(ctx, endpoint)
+	default:
+		return 0, probeGnoweb(ctx, endpoint)
+	}
+}
+
+// run probes every endpoint whose circuit allows it, all at once, and
+// records the outcomes. An RPC endpoint whose client cannot be created is
+// skipped.
+func (p *endpointProber) run(ctx context.Context) {
+	// The RPC clients are created up front: the probes run concurrently.
+	for _, ep := range p.lists[internal.EndpointRPC] {
+		if _, ok := p.rpc[ep]; !ok {
+			c, err := rpcclient.NewHTTPClient(ep)
+			if err != nil {
+				log.Printf("[endpoints][%s] failed to create client for %s: %v", p.chainID, internal.EndpointLabel(ep), err)
+				continue
+			}
+			p.rpc[ep] = c
+		}
+	}
+	var wg sync.WaitGroup
+	for kind, list := range p.lists {
+		for _, ep := range list {
+			if !internal.EndpointProbeDue(kind, ep) {
+				continue
+			}
+			if _, ok := p.rpc[ep]; kind == internal.EndpointRPC && !ok {
+				continue
+			}
+			wg.Add(1)
+			go func(kind, ep string) {
+				defer wg.Done()
+				start := time.Now()
+				height, err := p.probe(ctx, kind, ep)
+				internal.RecordEndpointCall(kind, ep, time.Since(start), err)
+				if err != nil {
+					log.Printf("[endpoints][%s] %s probe of %s failed: %v", p.chainID, kind, internal.EndpointLabel(ep), err)
+					return
+				}
+				if height > 0 {
+					internal.RecordEndpointHeight(kind, ep, height)
+				}
+			}(kind, ep)
+		}
+	}
+	wg.Wait()
+}
+
+// updateMetrics sets the endpoint gauges of the chain.
+func (p *endpointProber) updateMetrics() {
+	chainLabel := prometheus.Labels{"chain": p.chainID}
+	EndpointUp.DeletePartialMatch(chainLabel)
+	EndpointSuccessRatio.DeletePartialMatch(chainLabel)
+	EndpointLatencyP95.DeletePartialMatch(chainLabel)
+	EndpointHeightLag.DeletePartialMatch(chainLabel)
+	EndpointPreferred.DeletePartialMatch(chainLabel)
+	for kind, list := range p.lists {
+		for i, st := range internal.EndpointHealth(kind, list) {
+			labels := []string{p.chainID, kind, strconv.Itoa(i), internal.EndpointLabel(st.Endpoint)}
+			up, preferred := 1.0, 0.0
+			if st.CircuitOpen {
+				up = 0
+			}
+			if st.Preferred {
+				preferred = 1
+			}
+			EndpointUp.WithLabelValues(labels...).Set(up)
+			EndpointSuccessRatio.WithLabelValues(labels...).Set(st.SuccessRate)
+			EndpointLatencyP95.WithLabelValues(labels...).Set(st.P95.Seconds())
+			EndpointHeightLag.WithLabelValues(labels...).Set(float64(st.HeightLag))
+			EndpointPreferred.WithLabelValues(labels...).Set(preferred)
+		}
+	}
+}
+
+// WatchEndpointHealth probes the RPC, GraphQL and gnoweb endpoints of
+// chainID every checkInterval and updates their gauges. The probes feed the
+// endpoint health tracker: they refresh the heights the endpoints are
+// ranked on and close the circuit of an endpoint that recovered, so callers
+// fail back to it.
+func WatchEndpointHealth(ctx context.Context, chainID string, chainCfg *internal.ChainConfig, checkInterval time.Duration) {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("[endpoints][%s] panic recovered: %v", chainID, r)
+			}
+		}()
+		p := newEndpointProber(chainID, chainCfg)
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+		for {
+			p.run(ctx)
+			p.updateMetrics()
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -0,0 +1,65 @@
+package gnovalidator
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/samouraiworld/gnomonitoring/backend/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbeGraphQL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "latestBlockHeight") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"latestBlockHeight":4242}}`))
+	}))
+	defer srv.Close()
+
+	height, err := probeGraphQL(context.Background(), srv.URL)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4242), height)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errors":[{"message":"indexer unavailable"}]}`))
+	}))
+	defer failing.Close()
+	_, err = probeGraphQL(context.Background(), failing.URL)
+	assert.ErrorContains(t, err, "indexer unavailable")
+}
+
+func TestProbeGnoweb(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	assert.NoError(t, probeGnoweb(context.Background(), srv.URL))
+	status = http.StatusBadGateway
+	assert.Error(t, probeGnoweb(context.Background(), srv.URL))
+}
+
+func TestEndpointMetrics_SameHost(t *testing.T) {
+	const chainID = "endpoint-metrics-test"
+	p := newEndpointProber(chainID, &internal.ChainConfig{
+		GraphqlEndpoints: []string{"https://indexer.example.com/a/graphql/query", "https://indexer.example.com/b/graphql/query"},
+	})
+	p.updateMetrics()
+
+	chainLabel := prometheus.Labels{"chain": chainID}
+	assert.Equal(t, 2, EndpointUp.DeletePartialMatch(chainLabel), "one series per endpoint, even on the same host")
+	EndpointSuccessRatio.DeletePartialMatch(chainLabel)
+	EndpointLatencyP95.DeletePartialMatch(chainLabel)
+	EndpointHeightLag.DeletePartialMatch(chainLabel)
+	EndpointPreferred.DeletePartialMatch(chainLabel)
+}
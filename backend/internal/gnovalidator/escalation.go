@@ -0,0 +1,105 @@
+package gnovalidator
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"gorm.io/gorm"
+)
+
+// escalationCheckInterval is how often WatchEscalations looks for
+// unanswered incidents. Policy delays are in minutes, so a tier fires at
+// most a minute late.
+const escalationCheckInterval = time.Minute
+
+// dueEscalationTiers returns the tiers of p whose delay has elapsed for an
+// incident opened at openedAt.
+func dueEscalationTiers(p database.EscalationPolicy, openedAt, now time.Time) []int {
+	var tiers []int
+	for _, tier := range []int{2, 3} {
+		after, _, _ := p.Tier(tier)
+		if after > 0 && now.Sub(openedAt) >= after {
+			tiers = append(tiers, tier)
+		}
+	}
+	return tiers
+}
+
+// EvaluateEscalations queues every escalation tier that became due for the
+// open CRITICAL incidents of chainID. A policy only escalates the validators
+// it covers (see database.EscalationPolicy.Covers). Incidents of silenced
+// validators are not escalated; each tier fires once per incident (see
+// database.MarkEscalated).
+func EvaluateEscalations(db *gorm.DB, chainID string, now time.Time) {
+	policies, err := database.EscalationPoliciesForChain(db, chainID)
+	if err != nil {
+		log.Printf("[escalation][%s] %v", chainID, err)
+		return
+	}
+	if len(policies) == 0 {
+		return
+	}
+	incidents, err := database.OpenCriticalIncidents(db, chainID)
+	if err != nil {
+		log.Printf("[escalation][%s] %v", chainID, err)
+		return
+	}
+
+	for _, inc := range incidents {
+		if activeSilenceID(db, chainID, inc.Addr) != 0 {
+			continue
+		}
+		for _, p := range policies {
+			covered, err := p.Covers(db, chainID, inc.Addr, inc.Moniker)
+			if err != nil {
+				log.Printf("[escalation][%s] %v", chainID, err)
+				continue
+			}
+			if !covered {
+				continue
+			}
+			for _, tier := range dueEscalationTiers(p, inc.OpenedAt, now) {
+				fresh, err := database.MarkEscalated(db, chainID, inc.AlertLogID, p.ID, tier)
+				if err != nil {
+					log.Printf("[escalation][%s] %v", chainID, err)
+					continue
+				}
+				if !fresh {
+					continue
+				}
+				log.Printf("[escalation][%s] policy #%d tier %d: %s (%s) CRITICAL unanswered since %s",
+					chainID, p.ID, tier, inc.Moniker, inc.Addr, inc.OpenedAt.Format(time.RFC3339))
+				if err := internal.SendEscalation(db, chainID, p, tier, inc); err != nil {
+					log.Printf("[escalation][%s] SendEscalation error: %v", chainID, err)
+				}
+			}
+		}
+	}
+}
+
+// WatchEscalations runs EvaluateEscalations for chainID every
+// checkInterval, alongside WatchValidatorAlerts. It waits for the chain to
+// be synced, like new-alert detection does.
+func WatchEscalations(ctx context.Context, db *gorm.DB, chainID string, checkInterval time.Duration) {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("[escalation][%s] WatchEscalations panic: %v", chainID, r)
+			}
+		}()
+		for {
+			if isChainSynced(chainID) {
+				EvaluateEscalations(db, chainID, time.Now())
+			}
+			select {
+			case <-ctx.Done():
+				log.Printf("[monitor][%s] WatchEscalations stopped", chainID)
+				return
+			case <-time.After(checkInterval):
+			}
+		}
+	}()
+}
@@ -0,0 +1,21 @@
+package gnovalidator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDueEscalationTiers(t *testing.T) {
+	opened := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	p := database.EscalationPolicy{Tier2After: 15, Tier3After: 60}
+
+	assert.Empty(t, dueEscalationTiers(p, opened, opened.Add(14*time.Minute)))
+	assert.Equal(t, []int{2}, dueEscalationTiers(p, opened, opened.Add(15*time.Minute)))
+	assert.Equal(t, []int{2, 3}, dueEscalationTiers(p, opened, opened.Add(2*time.Hour)))
+
+	p.Tier2After = 0
+	assert.Equal(t, []int{3}, dueEscalationTiers(p, opened, opened.Add(2*time.Hour)), "a zero delay disables the tier")
+}
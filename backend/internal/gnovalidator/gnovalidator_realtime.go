@@ -85,6 +85,11 @@ var lastRPCErrorAlert = make(map[string]time.Time)      // per-chain RPC error a
 var lastProgressTime = make(map[string]time.Time)       // per-chain last block progress time
 var lastStagnationAlertTime = make(map[string]time.Time) // per-chain last stagnation alert time
 
+// stagnationMutedBy[chainID] is the silence that muted every "Blockchain
+// stuck" alert of the ongoing incident, 0 once one of them was delivered.
+// The "Activity Restored" that closes the incident is muted along with them.
+var stagnationMutedBy = make(map[string]uint)
+
 // lastProgressHeight[chainID] = block height
 var lastProgressHeight = make(map[string]int64)
 var heightMutex sync.RWMutex
@@ -149,7 +154,11 @@ type Participation struct {
 	Proposed       bool
 }
 
-func CollectParticipation(ctx context.Context, db *gorm.DB, chainID string, client gnoclient.Client) {
+// CollectParticipation stores the participation of every new block of
+// chainID. It polls the RPC for the latest height, or, with a connected
+// stream, waits for the heights it pushes. Heights left without
+// participation are found and backfilled by a gapTracker.
+func CollectParticipation(ctx context.Context, db *gorm.DB, chainID string, client gnoclient.Client, stream *blockStream) {
 	// simulateCount := 0
 	// simulateMax := 4   // for test
 	go func() {
@@ -171,9 +180,17 @@ func CollectParticipation(ctx context.Context, db *gorm.DB, chainID string, clie
 		}
 
 		currentHeight := lastStored + 1
+		gaps := gapTracker{checked: lastStored}
+		var pushed int64
 		for {
-
-			latest, err := client.LatestBlockHeight()
+			// A pushed height at or below the last one seen (the indexer may
+			// trail the RPC) is no news: poll instead.
+			latest := pushed
+			pushed = 0
+			var err error
+			if latest <= GetLastHeight(chainID) {
+				latest, err = client.LatestBlockHeight()
+			}
 			if err != nil {
 				log.Printf("[monitor][%s] error fetching latest height: %v", chainID, err)
 
@@ -188,6 +205,20 @@ func CollectParticipation(ctx context.Context, db *gorm.DB, chainID string, clie
 					timeMu.Lock()
 					lastRPCErrorAlert[chainID] = time.Now()
 					timeMu.Unlock()
+					data := internal.AlertData{
+						ChainID: chainID,
+						Level:   internal.AlertWarning,
+						Emoji:   "⚠️",
+						Title:   "RPC error",
+						Fields: []internal.AlertField{
+							{Name: "error", Value: err.Error()},
+							{Name: "last known height", Value: fmt.Sprintf("%d", currentHeight)},
+						},
+						Kind: internal.AlertKindRPCError,
+					}
+					if err := internal.SendInfoValidator(chainID, data, 0, db); err != nil {
+						log.Printf("[monitor][%s] SendInfoValidator error: %v", chainID, err)
+					}
 				}
 				select {
 				case <-ctx.Done():
@@ -214,6 +245,30 @@ func CollectParticipation(ctx context.Context, db *gorm.DB, chainID string, clie
 				shouldAlert := (firstAlert && stuckFor > t.StagnationFirstAlert()) ||
 					(!firstAlert && time.Since(lastAlert) > t.StagnationRepeat())
 
+				// Repeats of an acknowledged stall are not sent.
+				if shouldAlert && !firstAlert {
+					acked, err := database.IsIncidentAcked(db, chainID, "all", "CRITICAL")
+					if err != nil {
+						log.Printf("[monitor][%s] DB error checking acknowledgement: %v", chainID, err)
+					} else if acked {
+						shouldAlert = false
+						timeMu.Lock()
+						lastStagnationAlertTime[chainID] = time.Now()
+						timeMu.Unlock()
+					}
+				}
+
+				// A chain stopped at a planned halt height is expected to
+				// stay stuck for the halt window.
+				if shouldAlert {
+					halt, err := database.GetPlannedHaltAt(db, chainID, latest)
+					if err != nil {
+						log.Printf("[monitor][%s] DB error checking planned halts: %v", chainID, err)
+					} else if halt != nil && stuckFor < time.Duration(halt.WindowMinutes)*time.Minute {
+						shouldAlert = false
+					}
+				}
+
 				if shouldAlert {
 					blockTime, err := database.GetTimeOfBlock(db, chainID, latest)
 					if err != nil {
@@ -241,16 +296,32 @@ func CollectParticipation(ctx context.Context, db *gorm.DB, chainID string, clie
 							{Name: "since", Value: blockTime.Format(time.RFC822)},
 							{Name: "elapsed", Value: elapsed.String() + " ago"},
 						},
+						Addr:        "all",
+						StartHeight: latest,
+						Incident:    internal.IncidentTrigger,
+						Kind:        internal.AlertKindStagnation,
 					}
-					if err := internal.SendInfoValidator(chainID, data, db); err != nil {
-						log.Printf("[monitor][%s] SendInfoValidator error: %v", chainID, err)
-					}
-					if err := database.InsertAlertlog(db, chainID, "all", "all", "CRITICAL", latest, latest, false, time.Now(), msg); err != nil {
-						log.Printf("[monitor][%s] InsertAlertlog error: %v", chainID, err)
+					silenceID := activeSilenceID(db, chainID, "all")
+					if silenceID != 0 {
+						log.Printf("[monitor][%s] silence #%d: not sending stagnation alert", chainID, silenceID)
+						if _, err := database.InsertSilencedAlertlog(db, silenceID, chainID, "all", "all", "CRITICAL", latest, latest, false, time.Now(), msg); err != nil {
+							log.Printf("[monitor][%s] InsertAlertlog error: %v", chainID, err)
+						}
+					} else {
+						alertLogID, err := database.InsertAlertlog(db, chainID, "all", "all", "CRITICAL", latest, latest, false, time.Now(), msg)
+						if err != nil {
+							log.Printf("[monitor][%s] InsertAlertlog error: %v", chainID, err)
+						}
+						if err := internal.SendInfoValidator(chainID, data, alertLogID, db); err != nil {
+							log.Printf("[monitor][%s] SendInfoValidator error: %v", chainID, err)
+						}
 					}
 
 					timeMu.Lock()
 					lastStagnationAlertTime[chainID] = time.Now()
+					if firstAlert || silenceID == 0 {
+						stagnationMutedBy[chainID] = silenceID
+					}
 					timeMu.Unlock()
 					SetAlertSent(chainID, "all", true)
 					SetRestoredNotified(chainID, "all", false)
@@ -270,12 +341,33 @@ func CollectParticipation(ctx context.Context, db *gorm.DB, chainID string, clie
 						Emoji:       "✅",
 						Title:       "Activity Restored",
 						Description: "Gno.land is back to normal.",
+						// lph is the height the chain was stuck at, i.e. the
+						// StartHeight of the "Blockchain stuck" incident.
+						Addr:          "all",
+						StartHeight:   lph,
+						Incident:      internal.IncidentResolve,
+						Kind:          internal.AlertKindStagnation,
+						ResolvedLevel: internal.AlertCritical,
 					}
-					if err := internal.SendInfoValidator(chainID, data, db); err != nil {
-						log.Printf("[monitor][%s] SendInfoValidator error: %v", chainID, err)
-					}
-					if err := database.InsertAlertlog(db, chainID, "all", "all", "RESOLVED", latest, latest, false, time.Now(), msg); err != nil {
-						log.Printf("[monitor][%s] InsertAlertlog error: %v", chainID, err)
+					// Only muted when the outage was never announced: once a
+					// "Blockchain stuck" went out, its end always does too,
+					// even under a silence that started since.
+					timeMu.Lock()
+					silenceID := stagnationMutedBy[chainID]
+					timeMu.Unlock()
+					if silenceID != 0 {
+						log.Printf("[monitor][%s] silence #%d: not sending activity restored", chainID, silenceID)
+						if _, err := database.InsertSilencedAlertlog(db, silenceID, chainID, "all", "all", "RESOLVED", latest, latest, false, time.Now(), msg); err != nil {
+							log.Printf("[monitor][%s] InsertAlertlog error: %v", chainID, err)
+						}
+					} else {
+						alertLogID, err := database.InsertAlertlog(db, chainID, "all", "all", "RESOLVED", latest, latest, false, time.Now(), msg)
+						if err != nil {
+							log.Printf("[monitor][%s] InsertAlertlog error: %v", chainID, err)
+						}
+						if err := internal.SendInfoValidator(chainID, data, alertLogID, db); err != nil {
+							log.Printf("[monitor][%s] SendInfoValidator error: %v", chainID, err)
+						}
 					}
 					SetRestoredNotified(chainID, "all", true)
 					SetAlertSent(chainID, "all", false)
@@ -287,11 +379,11 @@ func CollectParticipation(ctx context.Context, db *gorm.DB, chainID string, clie
 			timeMu.Unlock()
 
 			if latest <= currentHeight {
-				select {
-				case <-ctx.Done():
+				h, ok := stream.wait(ctx)
+				if !ok {
 					return
-				case <-time.After(3 * time.Second):
 				}
+				pushed = h
 				continue
 			}
 			// *** BLOCKING BACKFILL IF LARGE GAP ***
@@ -310,6 +402,7 @@ func CollectParticipation(ctx context.Context, db *gorm.DB, chainID string, clie
 				} else {
 					// jump directly to the end of the backfill
 					currentHeight = stop + 1
+					gaps.checked = stop
 					log.Printf("[monitor][%s] backfill complete up to %d, switching to realtime", chainID, stop)
 				}
 				// do not switch to "realtime" while the gap is still large
@@ -328,6 +421,10 @@ func CollectParticipation(ctx context.Context, db *gorm.DB, chainID string, clie
 
 				// ================================ Get Participation and date ==================== //
 
+				// tm2 blocks carry no Evidence (no evidence pool: a conflicting
+				// vote is not turned into DuplicateVoteEvidence), so there is no
+				// double-sign record to read here.
+
 				// Actual block proposer, resolved once. A block always has a
 				// proposer; hasTx gates whether TxContribution is meaningful.
 				proposerAddr := block.Block.Header.ProposerAddress.String()
@@ -349,8 +446,18 @@ func CollectParticipation(ctx context.Context, db *gorm.DB, chainID string, clie
 				if err != nil {
 					log.Printf("[monitor][%s] failed to save participation at height %d: %v", chainID, h, err)
 				}
+				if err := database.UpsertBlocks(db, []database.Block{blockRecord(client, chainID, block.Block)}); err != nil {
+					log.Printf("[monitor][%s] failed to save block %d: %v", chainID, h, err)
+				}
+
+				// A commit after round 0 means the proposers of the earlier
+				// rounds of h-1 did not get a block committed.
+				if round := block.Block.LastCommit.Round(); round > 0 {
+					recordMissedProposals(db, chainID, h-1, round, timeStp)
+				}
 			}
 
+			gaps.check(db, client, chainID, latest)
 			currentHeight = latest
 		}
 	}()
@@ -380,7 +487,7 @@ func WatchNewValidators(ctx context.Context, db *gorm.DB, chainID string, client
 				// independently that same cycle.
 				if len(valopers) > 0 {
 					setSigningToOperator(chainID, signingToOperatorFromValopers(valopers))
-					setCachedValopers(chainID, valopers)
+					SetCachedValopers(chainID, valopers)
 				}
 				// classifyValsetChanges needs currentValopers to correlate a rotation;
 				// fall back to the last known-good snapshot on a transient fetch
@@ -406,6 +513,8 @@ func WatchNewValidators(ctx context.Context, db *gorm.DB, chainID string, client
 								{Name: "moniker", Value: ev.Moniker},
 								{Name: "address", Value: ev.NewAddr},
 							},
+							Addr:    ev.NewAddr,
+							Moniker: ev.Moniker,
 						}
 					case ValidatorLeft:
 						msg = fmt.Sprintf("[%s] ⚠️ **Validator left the valset**: %s (%s)", chainID, ev.Moniker, ev.OldAddr)
@@ -419,6 +528,8 @@ func WatchNewValidators(ctx context.Context, db *gorm.DB, chainID string, client
 								{Name: "moniker", Value: ev.Moniker},
 								{Name: "address", Value: ev.OldAddr},
 							},
+							Addr:    ev.OldAddr,
+							Moniker: ev.Moniker,
 						}
 					case ValidatorAddressChanged:
 						msg = fmt.Sprintf("[%s] 🔄 **Validator address changed**: %s (%s → %s)", chainID, ev.Moniker, ev.OldAddr, ev.NewAddr)
@@ -433,10 +544,13 @@ func WatchNewValidators(ctx context.Context, db *gorm.DB, chainID string, client
 								{Name: "old address", Value: ev.OldAddr},
 								{Name: "new address", Value: ev.NewAddr},
 							},
+							Addr:    ev.NewAddr,
+							Moniker: ev.Moniker,
 						}
 					}
+					data.Kind = internal.AlertKindValsetChange
 					log.Println(msg)
-					if err := internal.SendInfoValidator(chainID, data, db); err != nil {
+					if err := internal.SendInfoValidator(chainID, data, 0, db); err != nil {
 						log.Printf("[monitor][%s] SendInfoValidator error: %v", chainID, err)
 					}
 				}
@@ -527,6 +641,11 @@ func WatchValidatorAlerts(ctx context.Context, db *gorm.DB, chainID string, chec
 				// UNLESS a RESOLVED was dispatched after that alert AND the current missed
 				// sequence starts strictly after the resolved range (i.e. a genuinely new
 				// incident, not the same down-period being re-detected).
+				//
+				// Alerts muted by an earlier silence do not count, so the alert
+				// goes out as soon as the silence is over if the validator is
+				// still down.
+				silenceID := activeSilenceID(db, chainID, addr)
 				resendHours := t.ResendHoursForLevel(level)
 				window := fmt.Sprintf("%d hours", resendHours)
 				var recentCount int64
@@ -534,6 +653,7 @@ func WatchValidatorAlerts(ctx context.Context, db *gorm.DB, chainID string, chec
 					SELECT COUNT(*) FROM alert_logs al
 					WHERE al.chain_id = ? AND al.addr = ? AND al.level = ?
 					AND al.skipped = true
+					AND (al.silence_id IS NULL OR al.silence_id = ?)
 					AND al.sent_at >= NOW() - ?::interval
 					AND NOT EXISTS (
 						SELECT 1 FROM alert_logs r
@@ -543,7 +663,7 @@ func WatchValidatorAlerts(ctx context.Context, db *gorm.DB, chainID string, chec
 						  AND r.sent_at > al.sent_at
 						  AND ? > r.end_height
 					)
-				`, chainID, addr, level, window, start_height).Scan(&recentCount).Error
+				`, chainID, addr, level, silenceID, window, start_height).Scan(&recentCount).Error
 				if err != nil {
 					log.Printf("[validator][%s] DB error checking alert_logs: %v", chainID, err)
 					continue
@@ -554,6 +674,17 @@ func WatchValidatorAlerts(ctx context.Context, db *gorm.DB, chainID string, chec
 					continue
 				}
 
+				// Someone is on it: an acknowledged incident is not re-sent
+				// until it is RESOLVED (an escalation to CRITICAL still is).
+				acked, err := database.IsIncidentAcked(db, chainID, addr, level)
+				if err != nil {
+					log.Printf("[validator][%s] DB error checking acknowledgement: %v", chainID, err)
+				} else if acked {
+					log.Printf("[validator][%s] ack: skipping %s alert for %s (%s) start=%d end=%d: incident acknowledged",
+						chainID, level, moniker, addr, start_height, end_height)
+					continue
+				}
+
 				// Silence permanently dead validators: skip if no participation in the last N days.
 				if t.DeadValidatorSilenceDays > 0 {
 					silenceWindow := fmt.Sprintf("%d days", t.DeadValidatorSilenceDays)
@@ -574,12 +705,23 @@ func WatchValidatorAlerts(ctx context.Context, db *gorm.DB, chainID string, chec
 					}
 				}
 
-				if err := internal.SendAllValidatorAlerts(chainID, missed, today, level, addr, moniker, start_height, end_height, db); err != nil {
-					log.Printf("[validator][%s] SendAllValidatorAlerts error: %v", chainID, err)
+				// Maintenance window: record the alert for reports, but do not send it.
+				if silenceID != 0 {
+					log.Printf("[validator][%s] silence #%d: not sending %s alert for %s (%s) start=%d end=%d",
+						chainID, silenceID, level, moniker, addr, start_height, end_height)
+					if _, err := database.InsertSilencedAlertlog(db, silenceID, chainID, addr, moniker, level, start_height, end_height, true, time.Now(), ""); err != nil {
+						log.Printf("[monitor][%s] InsertAlertlog error: %v", chainID, err)
+					}
+					continue
 				}
-				if err := database.InsertAlertlog(db, chainID, addr, moniker, level, start_height, end_height, true, time.Now(), ""); err != nil {
+
+				alertLogID, err := database.InsertAlertlog(db, chainID, addr, moniker, level, start_height, end_height, true, time.Now(), "")
+				if err != nil {
 					log.Printf("[monitor][%s] InsertAlertlog error: %v", chainID, err)
 				}
+				if err := internal.SendAllValidatorAlerts(chainID, missed, today, level, addr, moniker, start_height, end_height, alertLogID, db); err != nil {
+					log.Printf("[validator][%s] SendAllValidatorAlerts error: %v", chainID, err)
+				}
 			}
 
 			SendResolveAlerts(db, chainID)
@@ -599,8 +741,11 @@ func SendResolveAlerts(db *gorm.DB, chainID string) {
 	type pendingAlert struct {
 		Addr        string
 		Moniker     string
+		Level       string
 		StartHeight int64
 		EndHeight   int64
+		SilenceID   *uint
+		Notified    bool // a non-silenced alert went out since the last RESOLVED
 	}
 
 	var pending []pendingAlert
@@ -608,8 +753,25 @@ func SendResolveAlerts(db *gorm.DB, chainID string) {
 		SELECT DISTINCT ON (al.addr)
 		       al.addr,
 		       COALESCE(am.moniker, al.moniker, '') AS moniker,
+		       al.level,
 		       al.start_height,
-		       al.end_height
+		       al.end_height,
+		       al.silence_id,
+		       EXISTS (
+		           SELECT 1 FROM alert_logs n
+		           WHERE n.chain_id = al.chain_id
+		             AND n.addr     = al.addr
+		             AND n.level IN ('WARNING', 'CRITICAL')
+		             AND n.skipped = true
+		             AND n.silence_id IS NULL
+		             AND NOT EXISTS (
+		                 SELECT 1 FROM alert_logs r
+		                 WHERE r.chain_id = n.chain_id
+		                   AND r.addr     = n.addr
+		                   AND r.level    = 'RESOLVED'
+		                   AND r.end_height >= n.end_height
+		             )
+		       ) AS notified
 		FROM alert_logs al
 		LEFT JOIN addr_monikers am ON am.chain_id = al.chain_id AND am.addr = al.addr
 		WHERE al.chain_id = ?
@@ -656,12 +818,26 @@ func SendResolveAlerts(db *gorm.DB, chainID string) {
 			continue
 		}
 
-		if err := internal.SendResolveValidator(chainID, a.Addr, a.Moniker, resumeHeight, db); err != nil {
-			log.Printf("[validator][%s] SendResolveValidator error: %v", chainID, err)
+		// Mute the RESOLVED only when nothing but muted alerts was raised
+		// for this incident (there is nothing to resolve on the receiving
+		// end). An incident that was delivered is always resolved, even
+		// under a silence that started since.
+		if !a.Notified && a.SilenceID != nil {
+			silenceID := *a.SilenceID
+			log.Printf("[validator][%s] silence #%d: not sending RESOLVED for %s (%s)", chainID, silenceID, a.Moniker, a.Addr)
+			if _, err := database.InsertSilencedAlertlog(db, silenceID, chainID, a.Addr, a.Moniker, "RESOLVED", a.StartHeight, a.EndHeight, false, time.Now(), ""); err != nil {
+				log.Printf("[monitor][%s] InsertAlertlog RESOLVED error: %v", chainID, err)
+			}
+			continue
 		}
-		if err := database.InsertAlertlog(db, chainID, a.Addr, a.Moniker, "RESOLVED", a.StartHeight, a.EndHeight, false, time.Now(), ""); err != nil {
+
+		alertLogID, err := database.InsertAlertlog(db, chainID, a.Addr, a.Moniker, "RESOLVED", a.StartHeight, a.EndHeight, false, time.Now(), "")
+		if err != nil {
 			log.Printf("[monitor][%s] InsertAlertlog RESOLVED error: %v", chainID, err)
 		}
+		if err := internal.SendResolveValidator(chainID, a.Addr, a.Moniker, a.Level, a.StartHeight, resumeHeight, alertLogID, db); err != nil {
+			log.Printf("[validator][%s] SendResolveValidator error: %v", chainID, err)
+		}
 	}
 }
 
@@ -716,7 +892,7 @@ func StartValidatorMonitoring(ctx context.Context, db *gorm.DB, chainID string,
 	// from an empty prevSigningToOperator snapshot.
 	if len(valopers) > 0 {
 		setSigningToOperator(chainID, signingToOperatorFromValopers(valopers))
-		setCachedValopers(chainID, valopers)
+		SetCachedValopers(chainID, valopers)
 	}
 
 	if ctx.Err() != nil {
@@ -732,8 +908,33 @@ func StartValidatorMonitoring(ctx context.Context, db *gorm.DB, chainID string,
 	}
 
 	WatchNewValidators(ctx, db, chainID, client, chainCfg, t.NewValidatorScan())
-	CollectParticipation(ctx, db, chainID, client)
+	var stream *blockStream
+	if chainCfg.BlockIngestion == internal.BlockIngestionWebsocket {
+		if len(chainCfg.GraphqlEndpoints) > 0 {
+			stream = subscribeNewBlocks(ctx, chainID, chainCfg.GraphqlEndpoints)
+		} else {
+			log.Printf("[monitor][%s] block_ingestion websocket needs a graphql endpoint, polling", chainID)
+		}
+	}
+	CollectParticipation(ctx, db, chainID, client, stream)
 	WatchValidatorAlerts(ctx, db, chainID, t.AlertCheckInterval())
+	WatchEscalations(ctx, db, chainID, escalationCheckInterval)
+	WatchConsensusRounds(ctx, db, chainID, consensusRoundCheckInterval)
+	WatchBlockTime(ctx, db, chainID, blockTimeCheckInterval)
+	WatchNodeVersions(ctx, db, chainID, nodeVersionCheckInterval)
+	WatchPlannedHalts(ctx, db, chainID, plannedHaltCheckInterval)
+	if len(chainCfg.RPCEndpoints) > 0 {
+		WatchPeers(ctx, db, chainID, chainCfg.RPCEndpoints[0], peerCheckInterval)
+	}
+	WatchAccountBalances(ctx, db, chainID, balanceCheckInterval)
+	WatchEndpointHealth(ctx, chainID, chainCfg, endpointProbeInterval)
+	if chainCfg.CrossCheckEndpoints {
+		if len(chainCfg.RPCEndpoints) > 1 {
+			WatchEndpointConsistency(ctx, db, chainID, chainCfg.RPCEndpoints, endpointCheckInterval)
+		} else {
+			log.Printf("[endpoints][%s] cross_check_endpoints needs at least two rpc_endpoints", chainID)
+		}
+	}
 }
 
 // Moniker helpers
@@ -790,6 +991,22 @@ func SetLastHeight(chainID string, height int64) {
 
 // Alert helpers
 
+// activeSilenceID returns the ID of the silence muting alerts about addr on
+// chainID right now (addr "all" for chain-level alerts), 0 when there is
+// none. A failed lookup is logged and treated as "not silenced": a database
+// hiccup must not swallow alerts.
+func activeSilenceID(db *gorm.DB, chainID, addr string) uint {
+	s, err := database.ActiveSilence(db, chainID, addr, time.Now())
+	if err != nil {
+		log.Printf("[monitor][%s] silence lookup for %s failed: %v", chainID, addr, err)
+		return 0
+	}
+	if s == nil {
+		return 0
+	}
+	return s.ID
+}
+
 func IsAlertSent(chainID, addr string) bool {
 	alertMutex.RLock()
 	defer alertMutex.RUnlock()
@@ -20,12 +20,16 @@ var SendTelegramMessage func(token string, chatID int64, text string) error
 // pattern. buttonURL == "" means "send text only, no button".
 var SendTelegramMessageWithButton func(token string, chatID int64, text, buttonText, buttonURL string) error
 
-// sendDiscordEmbed and sendSlackBlocks are function variables (not direct
-// internal.SendDiscordEmbed/SendSlackBlocks calls) so tests in this package
-// can substitute a fake sender instead of needing real HTTP through the
-// SSRF-guarded alertHTTPClient in package internal.
+// sendDiscordEmbed, sendSlackBlocks, sendMatrixMessage,
+// sendMattermostMessage and sendDiscordChannelEmbed are function variables (not direct internal.Send*
+// calls) so tests in this package can substitute a fake sender instead of
+// needing real HTTP through the SSRF-guarded alertHTTPClient in package
+// internal.
 var sendDiscordEmbed = internal.SendDiscordEmbed
 var sendSlackBlocks = internal.SendSlackBlocks
+var sendMatrixMessage = internal.SendMatrixMessage
+var sendMattermostMessage = internal.SendMattermostMessage
+var sendDiscordChannelEmbed = internal.SendDiscordChannelEmbed
 
 type ValidatorRate struct {
 	Rate    float64
@@ -138,10 +142,43 @@ func SheduleTelegramReport(chatID int64, chainID string, hour, minute int, timez
 	}
 }
 
-// dispatchDailyReportToWebhooks sends DailyReportData to every Discord/Slack
-// webhook the user has configured for chainID (or unscoped to all chains),
-// rendering a channel-appropriate payload for each (Discord embed, Slack
-// Block Kit). Unlike the disabled/stuck report path (dispatchPlainTextReport),
+// SheduleDiscordReport sends the daily report of chainID to a Discord
+// channel every day at hour:minute in timezone, until reload is closed.
+func SheduleDiscordReport(channelID int64, chainID string, hour, minute int, timezone string, db *gorm.DB, reload <-chan struct{}) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		log.Printf("⚠️ Invalid timezone for discord channel %d chain %s: %s, defaulting to UTC", channelID, chainID, timezone)
+		loc = time.UTC
+	}
+
+	for {
+		now := time.Now().In(loc)
+		next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, loc)
+		if next.Before(now) {
+			next = next.Add(24 * time.Hour)
+		}
+		wait := time.Until(next)
+
+		log.Printf("[report][%s] next for discord channel %d at %s (in %s)", chainID, channelID, next.Format(time.RFC1123), wait)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+			log.Printf("[report][%s] sending for discord channel %d", chainID, channelID)
+			SendDailyStatsForDiscord(db, chainID, channelID)
+		case <-reload:
+			timer.Stop()
+			log.Printf("[report][%s] reloading schedule for discord channel %d", chainID, channelID)
+			return
+		}
+	}
+}
+
+// dispatchDailyReportToWebhooks sends DailyReportData to every
+// Discord/Slack/Matrix/Mattermost webhook the user has configured for
+// chainID (or unscoped to all chains), rendering a channel-appropriate
+// payload for each (Discord embed, Slack Block Kit, Matrix HTML, Mattermost
+// attachment). Unlike the disabled/stuck report path (dispatchPlainTextReport),
 // this never falls back to a single shared plain-text string.
 func dispatchDailyReportToWebhooks(db *gorm.DB, userID, chainID string, data DailyReportData) error {
 	var webhooks []database.WebhookValidator
@@ -162,6 +199,14 @@ func dispatchDailyReportToWebhooks(db *gorm.DB, userID, chainID string, data Dai
 			if err := sendSlackBlocks(blocks, wh.URL); err != nil {
 				log.Printf("❌ Failed to send daily report blocks to %s: %v", wh.URL, err)
 			}
+		case "matrix":
+			if err := sendMatrixMessage(RenderDailyReportMatrix(data), wh.URL); err != nil {
+				log.Printf("❌ Failed to send daily report to Matrix webhook %s: %v", wh.URL, err)
+			}
+		case "mattermost":
+			if err := sendMattermostMessage(RenderDailyReportMattermost(data), wh.URL); err != nil {
+				log.Printf("❌ Failed to send daily report to Mattermost webhook %s: %v", wh.URL, err)
+			}
 		default:
 			log.Printf("⚠️ Unknown webhook type for user %s: %s", userID, wh.Type)
 		}
@@ -169,6 +214,31 @@ func dispatchDailyReportToWebhooks(db *gorm.DB, userID, chainID string, data Dai
 	return nil
 }
 
+// queueReportEmail queues the daily report rendered by render for userID's
+// email when the user opted in to report emails (database.User.EmailReports)
+// and an smtp server is configured.
+func queueReportEmail(db *gorm.DB, userID, chainID string, render func() (internal.EmailMessage, error)) {
+	if !internal.Config.SMTP.Enabled() {
+		return
+	}
+	user, err := database.GetUserById(db, userID)
+	if err != nil {
+		log.Printf("[report][%s] GetUserById %s: %v", chainID, userID, err)
+		return
+	}
+	if user == nil || !user.EmailReports || user.Email == "" {
+		return
+	}
+	m, err := render()
+	if err != nil {
+		log.Printf("[report][%s] %v", chainID, err)
+		return
+	}
+	if err := internal.EnqueueEmail(db, chainID, userID, m); err != nil {
+		log.Printf("[report][%s] EnqueueEmail for user %s: %v", chainID, userID, err)
+	}
+}
+
 // dispatchPlainTextReport sends a plain-text report body (used for the
 // disabled/stuck report variants, which are not channel-rendered) to either
 // a web user's webhooks (chunked) or a Telegram chat, exactly as
@@ -177,6 +247,9 @@ func dispatchPlainTextReport(db *gorm.DB, chainID, msg string, userID *string, c
 	switch {
 	case userID != nil:
 		SendUserReportInChunks(*userID, chainID, msg, db, 1500)
+		queueReportEmail(db, *userID, chainID, func() (internal.EmailMessage, error) {
+			return internal.PlainTextEmail(fmt.Sprintf("[%s] Daily Summary", chainID), msg), nil
+		})
 	case chatID != nil:
 		if SendTelegramMessage != nil {
 			if err := SendTelegramMessage(internal.Config.TokenTelegramValidator, *chatID, msg); err != nil {
@@ -190,39 +263,53 @@ func dispatchPlainTextReport(db *gorm.DB, chainID, msg string, userID *string, c
 	}
 }
 
-func SendDailyStatsForUser(db *gorm.DB, chainID string, userID *string, chatID *int64) {
+// dailyReport builds yesterday's report of chainID. When the chain is
+// disabled or stuck it returns the plain-text report body instead of data;
+// ok is false when there is nothing to report.
+func dailyReport(db *gorm.DB, chainID string) (data DailyReportData, plainText string, ok bool) {
 	snap := FetchChainHealthSnapshot(db, chainID)
 
 	if snap.IsDisabled {
-		msg := FormatDisabledReport(chainID, snap) + reportLinkLine(db, chainID)
-		dispatchPlainTextReport(db, chainID, msg, userID, chatID)
-		return
+		return data, FormatDisabledReport(chainID, snap) + reportLinkLine(db, chainID), true
 	}
 	if snap.IsStuck {
-		msg := FormatStuckReport(chainID, snap) + reportLinkLine(db, chainID)
-		dispatchPlainTextReport(db, chainID, msg, userID, chatID)
-		return
+		return data, FormatStuckReport(chainID, snap) + reportLinkLine(db, chainID), true
 	}
 
 	yesterday := ReportYesterdayUTC(time.Now())
 	rates, minBlock, maxBlock := CalculateRate(db, chainID, yesterday)
 	if len(rates) == 0 {
 		log.Printf("[report][%s] no participation data for %s, skipping", chainID, yesterday)
-		return
+		return data, "", false
 	}
 
 	data, err := BuildDailyReportData(db, chainID, yesterday, snap, minBlock, maxBlock)
 	if err != nil {
 		log.Printf("[report][%s] BuildDailyReportData error: %v", chainID, err)
-		return
+		return data, "", false
 	}
 	log.Print(RenderDailyReportPlainText(data)) // human-readable trace in server logs
+	return data, "", true
+}
+
+func SendDailyStatsForUser(db *gorm.DB, chainID string, userID *string, chatID *int64) {
+	data, plainText, ok := dailyReport(db, chainID)
+	if !ok {
+		return
+	}
+	if plainText != "" {
+		dispatchPlainTextReport(db, chainID, plainText, userID, chatID)
+		return
+	}
 
 	switch {
 	case userID != nil:
 		if err := dispatchDailyReportToWebhooks(db, *userID, chainID, data); err != nil {
 			log.Printf("[report][%s] dispatch error for user %s: %v", chainID, *userID, err)
 		}
+		queueReportEmail(db, *userID, chainID, func() (internal.EmailMessage, error) {
+			return RenderDailyReportEmail(data)
+		})
 	case chatID != nil:
 		text, buttonText, buttonURL := RenderDailyReportTelegramHTML(data)
 		if SendTelegramMessageWithButton != nil {
@@ -237,6 +324,38 @@ func SendDailyStatsForUser(db *gorm.DB, chainID string, userID *string, chatID *
 	}
 }
 
+// SendDailyStatsForDiscord posts the daily report of chainID in a Discord
+// channel as the validator bot: the Discord embed of the webhook reports, or
+// the plain-text body when the chain is disabled or stuck.
+func SendDailyStatsForDiscord(db *gorm.DB, chainID string, channelID int64) {
+	data, plainText, ok := dailyReport(db, chainID)
+	if !ok {
+		return
+	}
+	embed := internal.DiscordEmbed{Description: plainText}
+	if plainText == "" {
+		embed = RenderDailyReportDiscordEmbed(data)
+	}
+	if err := sendDiscordChannelEmbed(channelID, embed); err != nil {
+		log.Printf("❌ Discord send failed (channel %d): %v", channelID, err)
+	}
+}
+
+// DailyReportSlackBlocks renders the latest daily report of chainID like the
+// Slack webhook reports, for the Slack app's /gnomon report. A disabled or
+// stuck chain gets its plain-text body in a single section. ok is false when
+// there is no participation data to report yet.
+func DailyReportSlackBlocks(db *gorm.DB, chainID string) (blocks []internal.SlackBlock, ok bool) {
+	data, plainText, ok := dailyReport(db, chainID)
+	if !ok {
+		return nil, false
+	}
+	if plainText != "" {
+		return []internal.SlackBlock{{Type: "section", Text: &internal.SlackText{Type: "mrkdwn", Text: plainText}}}, true
+	}
+	return RenderDailyReportSlackBlocks(data), true
+}
+
 func CalculateRate(db *gorm.DB, chainID, date string) (map[string]ValidatorRate, int64, int64) {
 	rates := make(map[string]ValidatorRate)
 
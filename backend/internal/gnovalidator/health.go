@@ -56,6 +56,11 @@ type ChainHealthSnapshot struct {
 	ValidatorSet     []ValidatorInfo
 	ValsetChanges    []ValsetChange
 	PrecommitBitmap  map[string]bool // validator address → is precommitting in current round
+
+	// The planned halt the chain restarted from in the last 24 hours, if
+	// any, and when each validator of the set at the halt signed again.
+	LastHalt *database.PlannedHalt
+	Rejoins  []database.ValidatorRejoin
 }
 
 func FetchChainHealthSnapshot(db *gorm.DB, chainID string) ChainHealthSnapshot {
@@ -221,6 +226,16 @@ func FetchChainHealthSnapshot(db *gorm.DB, chainID string) ChainHealthSnapshot {
 	snap.MinBlock = minBlock
 	snap.MaxBlock = maxBlock
 
+	halt, err := database.GetLastResumedHalt(db, chainID, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		log.Printf("[health][%s] GetLastResumedHalt error: %v", chainID, err)
+	} else if halt != nil {
+		snap.LastHalt = halt
+		if snap.Rejoins, err = database.GetValidatorRejoins(db, chainID, halt.HaltHeight); err != nil {
+			log.Printf("[health][%s] GetValidatorRejoins error: %v", chainID, err)
+		}
+	}
+
 	// FormatStuckReport is the only remaining reader of MissedLast24h (the
 	// Telegram /status and daily-report healthy paths build their own
 	// missed-blocks view from BuildChainValidatorReport instead), so skip the
@@ -684,6 +699,21 @@ func FormatMissedBlocksLast24h(rows []database.MissedBlockCount) string {
 	return sb.String()
 }
 
+// formatHaltRejoins is the report section on the planned halt the chain
+// restarted from in the last 24 hours: how long each validator took to sign
+// again. Empty when there is none.
+func formatHaltRejoins(snap ChainHealthSnapshot) string {
+	if snap.LastHalt == nil || snap.LastHalt.ResumedAt == nil {
+		return ""
+	}
+	resumed := *snap.LastHalt.ResumedAt
+	header := fmt.Sprintf("Planned halt at #%d — restarted %s UTC", snap.LastHalt.HaltHeight, resumed.UTC().Format("2006-01-02 15:04"))
+	if snap.LastHalt.HaltedAt != nil {
+		header += fmt.Sprintf(" after %s", max(resumed.Sub(*snap.LastHalt.HaltedAt), 0).Truncate(time.Second))
+	}
+	return header + ":\n" + formatRejoins(snap.Rejoins, resumed, "  ")
+}
+
 func FormatDisabledReport(chainID string, snap ChainHealthSnapshot) string {
 	date := time.Now().UTC().Format("2006-01-02")
 	var sb strings.Builder
@@ -809,6 +839,7 @@ func FormatStuckReport(chainID string, snap ChainHealthSnapshot) string {
 		}
 	}
 
+	sb.WriteString(formatHaltRejoins(snap))
 	sb.WriteString(FormatMissedBlocksLast24h(snap.MissedLast24h))
 	return sb.String()
 }
@@ -968,6 +999,7 @@ func FormatHealthyReport(chainID, date string, snap ChainHealthSnapshot, rates m
 		}
 	}
 
+	sb.WriteString(formatHaltRejoins(snap))
 	sb.WriteString(FormatMissedBlocksLast24h(snap.MissedLast24h))
 	return sb.String()
 }
@@ -172,10 +172,10 @@ func TestMultiChain_AlertLogIsolation(t *testing.T) {
 	sentAt := time.Date(2025, 11, 1, 9, 0, 0, 0, time.UTC)
 
 	// Insert one alert for chainA and one for chainB.
-	err := database.InsertAlertlog(db, "chainA", "g1aaaa", "ValidatorAlpha", "CRITICAL", 300, 350, true, sentAt, "chainA alert")
+	_, err := database.InsertAlertlog(db, "chainA", "g1aaaa", "ValidatorAlpha", "CRITICAL", 300, 350, true, sentAt, "chainA alert")
 	require.NoError(t, err)
 
-	err = database.InsertAlertlog(db, "chainB", "g1bbbb", "ValidatorBeta", "WARNING", 100, 104, true, sentAt, "chainB alert")
+	_, err = database.InsertAlertlog(db, "chainB", "g1bbbb", "ValidatorBeta", "WARNING", 100, 104, true, sentAt, "chainB alert")
 	require.NoError(t, err)
 
 	// Query for chainA using "all_time" period.
@@ -0,0 +1,401 @@
+package gnovalidator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	rpcclient "github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/client/http"
+	"github.com/samouraiworld/gnomonitoring/backend/internal"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"gorm.io/gorm"
+)
+
+const (
+	// nodeVersionCheckInterval is how often WatchNodeVersions takes the
+	// inventory of the nodes of a chain.
+	nodeVersionCheckInterval = 10 * time.Minute
+	// NodeVersionStaleAfter is how long a node stays in the inventory
+	// after it was last seen.
+	NodeVersionStaleAfter = 24 * time.Hour
+	// nodeStatusTimeout bounds the Status call to a peer: most peers do not
+	// serve RPC publicly and the call only times out.
+	nodeStatusTimeout = 5 * time.Second
+	// nodeStatusWorkers is how many peers are asked for their Status at
+	// once.
+	nodeStatusWorkers = 8
+)
+
+// peerRPCURL returns the URL the RPC of a peer would answer on: the port of
+// the RPC address it advertises, on the IP it connected from. The advertised
+// host is never dialed, and a remote IP that is not public is refused, so a
+// peer cannot point the monitor at an internal service. "" when the peer
+// advertises no RPC port or its IP is refused.
+func peerRPCURL(rpcAddress, remoteIP string) string {
+	addr := rpcAddress
+	if _, rest, ok := strings.Cut(addr, "://"); ok {
+		addr = rest
+	}
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil || port == "" {
+		return ""
+	}
+	ip := net.ParseIP(remoteIP)
+	if ip == nil || !internal.IsPublicUnicastIP(ip) {
+		return ""
+	}
+	return "http://" + net.JoinHostPort(ip.String(), port)
+}
+
+// nodeValidator returns the validator of valMonikers (address → moniker)
+// a node signs for: the validator address its Status reports, or else the
+// one validator whose moniker is the node's. "" when neither is known.
+func nodeValidator(status *ctypes.ResultStatus, nodeMoniker string, valMonikers map[string]string) string {
+	if status != nil {
+		if addr := status.ValidatorInfo.Address.String(); addr != "" {
+			if _, ok := valMonikers[addr]; ok {
+				return addr
+			}
+		}
+	}
+	nodeMoniker = strings.TrimSpace(nodeMoniker)
+	if nodeMoniker == "" {
+		return ""
+	}
+	match := ""
+	for addr, moniker := range valMonikers {
+		if strings.EqualFold(strings.TrimSpace(moniker), nodeMoniker) {
+			if match != "" {
+				return ""
+			}
+			match = addr
+		}
+	}
+	return match
+}
+
+// nodeVersions builds the inventory rows of the RPC node (self) and its
+// peers. statuses holds the Status of the peers that answered, by node ID.
+func nodeVersions(chainID string, self *ctypes.ResultStatus, peers []ctypes.Peer, statuses map[string]*ctypes.ResultStatus, valMonikers map[string]string, now time.Time) []database.NodeVersion {
+	rows := make([]database.NodeVersion, 0, len(peers)+1)
+	seen := map[string]bool{}
+	add := func(nodeID, version, moniker, remoteIP string, status *ctypes.ResultStatus) {
+		if nodeID == "" || version == "" || seen[nodeID] {
+			return
+		}
+		seen[nodeID] = true
+		rows = append(rows, database.NodeVersion{
+			ChainID:   chainID,
+			NodeID:    nodeID,
+			Version:   version,
+			Moniker:   moniker,
+			Addr:      nodeValidator(status, moniker, valMonikers),
+			RemoteIP:  remoteIP,
+			FirstSeen: now,
+			LastSeen:  now,
+		})
+	}
+	if self != nil && self.NodeInfo.NetAddress != nil {
+		add(self.NodeInfo.ID().String(), self.NodeInfo.Version, self.NodeInfo.Moniker, "", self)
+	}
+	for _, p := range peers {
+		if p.NodeInfo.NetAddress == nil {
+			continue
+		}
+		id := p.NodeInfo.ID().String()
+		version := p.NodeInfo.Version
+		// A node's own Status is fresher than the handshake its peer
+		// connection was opened with.
+		if st := statuses[id]; st != nil && st.NodeInfo.Version != "" {
+			version = st.NodeInfo.Version
+		}
+		add(id, version, p.NodeInfo.Moniker, p.RemoteIP, statuses[id])
+	}
+	return rows
+}
+
+// fetchPeerStatuses asks every peer advertising an RPC address for its
+// Status, nodeStatusWorkers at a time, and returns the answers by node ID.
+func fetchPeerStatuses(ctx context.Context, peers []ctypes.Peer) map[string]*ctypes.ResultStatus {
+	statuses := map[string]*ctypes.ResultStatus{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, nodeStatusWorkers)
+	for _, p := range peers {
+		if p.NodeInfo.NetAddress == nil {
+			continue
+		}
+		url := peerRPCURL(p.NodeInfo.Other.RPCAddress, p.RemoteIP)
+		if url == "" {
+			continue
+		}
+		id := p.NodeInfo.ID().String()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+			caller, err := http.NewClient(url)
+			if err != nil {
+				return
+			}
+			st, err := rpcclient.NewRPCClient(caller, rpcclient.WithRequestTimeout(nodeStatusTimeout)).Status()
+			// The node must be the peer: the advertised port may lead
+			// elsewhere.
+			if err != nil || st == nil || st.NodeInfo.NetAddress == nil || st.NodeInfo.ID().String() != id {
+				return
+			}
+			mu.Lock()
+			statuses[id] = st
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return statuses
+}
+
+// VersionCount is how many nodes, and validators, run Version.
+type VersionCount struct {
+	Version    string `json:"version"`
+	Nodes      int    `json:"nodes"`
+	Validators int    `json:"validators"`
+}
+
+// validatorVersions returns the version of each validator of nodes, from
+// the node last seen when it has several.
+func validatorVersions(nodes []database.NodeVersion) map[string]string {
+	versions := map[string]string{}
+	lastSeen := map[string]time.Time{}
+	for _, n := range nodes {
+		if n.Addr == "" || n.LastSeen.Before(lastSeen[n.Addr]) {
+			continue
+		}
+		versions[n.Addr] = n.Version
+		lastSeen[n.Addr] = n.LastSeen
+	}
+	return versions
+}
+
+// NodeVersionDistribution counts the nodes and validators running each
+// version of nodes, most nodes first.
+func NodeVersionDistribution(nodes []database.NodeVersion) []VersionCount {
+	byVersion := map[string]*VersionCount{}
+	count := func(v string) *VersionCount {
+		c, ok := byVersion[v]
+		if !ok {
+			c = &VersionCount{Version: v}
+			byVersion[v] = c
+		}
+		return c
+	}
+	for _, n := range nodes {
+		count(n.Version).Nodes++
+	}
+	for _, v := range validatorVersions(nodes) {
+		count(v).Validators++
+	}
+	out := make([]VersionCount, 0, len(byVersion))
+	for _, c := range byVersion {
+		out = append(out, *c)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Nodes != out[j].Nodes {
+			return out[i].Nodes > out[j].Nodes
+		}
+		return out[i].Version < out[j].Version
+	})
+	return out
+}
+
+// MajorityVersion returns the version run by more than half of the
+// validators of nodes; ok is false when no version is.
+func MajorityVersion(nodes []database.NodeVersion) (version string, ok bool) {
+	return majorityOf(validatorVersions(nodes))
+}
+
+func majorityOf(versions map[string]string) (string, bool) {
+	counts := map[string]int{}
+	for _, v := range versions {
+		counts[v]++
+		if 2*counts[v] > len(versions) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// versionWatch is the state of WatchNodeVersions: the validators alerted as
+// running another version than the majority, with that version.
+type versionWatch struct {
+	diverged map[string]string
+}
+
+// update compares versions (validator address → version) with the
+// majority and returns the validators that started diverging, or moved to
+// another minority version, and those back on the majority version. A
+// validator no longer seen is forgotten. Without a majority nothing
+// changes.
+func (w *versionWatch) update(versions map[string]string) (started, ended []string) {
+	if w.diverged == nil {
+		w.diverged = map[string]string{}
+	}
+	majority, ok := majorityOf(versions)
+	if !ok {
+		return nil, nil
+	}
+	for addr, v := range versions {
+		if v != majority && w.diverged[addr] != v {
+			w.diverged[addr] = v
+			started = append(started, addr)
+		}
+	}
+	for addr := range w.diverged {
+		v, seen := versions[addr]
+		switch {
+		case !seen:
+			delete(w.diverged, addr)
+		case v == majority:
+			delete(w.diverged, addr)
+			ended = append(ended, addr)
+		}
+	}
+	sort.Strings(started)
+	sort.Strings(ended)
+	return started, ended
+}
+
+// WatchNodeVersions takes the inventory of the node versions of chainID
+// every checkInterval: the RPC node and its peers, with the version they
+// advertise or, for the peers serving RPC, the one their Status reports.
+// A node is attributed to a validator from the validator address in its
+// Status or, failing that, from its moniker. A WARNING is raised for each
+// validator running another version than the majority of the validators
+// seen while a planned halt of the chain is ahead, the check to run before
+// a coordinated upgrade, and an INFO once it runs the majority version.
+// Outside of that window versions differ during every rollout, so nothing
+// is sent and the divergences are forgotten.
+func WatchNodeVersions(ctx context.Context, db *gorm.DB, chainID string, checkInterval time.Duration) {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("[versions][%s] panic recovered: %v", chainID, r)
+			}
+		}()
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+
+		var w versionWatch
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			rpcClient, ok := GetChainRPCClient(chainID)
+			if !ok || rpcClient == nil {
+				continue
+			}
+			netInfo, err := rpcClient.NetInfo()
+			if err != nil || netInfo == nil {
+				log.Printf("[versions][%s] NetInfo() error: %v", chainID, err)
+				continue
+			}
+			self, err := rpcClient.Status()
+			if err != nil {
+				log.Printf("[versions][%s] Status() error: %v", chainID, err)
+			}
+			valMonikers := GetMonikerMap(chainID)
+			statuses := fetchPeerStatuses(ctx, netInfo.Peers)
+			if err := database.UpsertNodeVersions(db, nodeVersions(chainID, self, netInfo.Peers, statuses, valMonikers, time.Now())); err != nil {
+				log.Printf("[versions][%s] %v", chainID, err)
+				continue
+			}
+
+			nodes, err := database.GetCurrentNodeVersions(db, chainID, time.Now().Add(-NodeVersionStaleAfter))
+			if err != nil {
+				log.Printf("[versions][%s] %v", chainID, err)
+				continue
+			}
+			halt, err := upcomingPlannedHalt(db, chainID)
+			if err != nil {
+				log.Printf("[versions][%s] %v", chainID, err)
+				continue
+			}
+			if halt == nil {
+				w = versionWatch{}
+				continue
+			}
+			versions := validatorVersions(nodes)
+			majority, _ := majorityOf(versions)
+			started, ended := w.update(versions)
+			for _, addr := range started {
+				sendVersionAlert(db, chainID, internal.AlertData{
+					Level: internal.AlertWarning,
+					Emoji: "⚠️",
+					Title: "Validator node version diverges",
+					Fields: []internal.AlertField{
+						{Name: "moniker", Value: valMonikers[addr]},
+						{Name: "address", Value: addr},
+						{Name: "version", Value: versions[addr]},
+						{Name: "majority version", Value: majority},
+						{Name: "halt height", Value: fmt.Sprintf("%d", halt.HaltHeight)},
+					},
+					Addr:    addr,
+					Moniker: valMonikers[addr],
+				})
+			}
+			for _, addr := range ended {
+				sendVersionAlert(db, chainID, internal.AlertData{
+					Level:         internal.AlertInfo,
+					Emoji:         "✅",
+					Title:         "Validator node version back in line",
+					Description:   fmt.Sprintf("%s runs %s, the majority version.", valMonikers[addr], majority),
+					Addr:          addr,
+					Moniker:       valMonikers[addr],
+					ResolvedLevel: internal.AlertWarning,
+				})
+			}
+		}
+	}()
+}
+
+// upcomingPlannedHalt returns the lowest planned halt of chainID the chain
+// has not reached yet, or nil when there is none.
+func upcomingPlannedHalt(db *gorm.DB, chainID string) (*database.PlannedHalt, error) {
+	halts, err := database.GetOpenPlannedHalts(db, chainID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range halts {
+		if halts[i].HaltedAt == nil {
+			return &halts[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// sendVersionAlert sends a node version alert about data.Addr to the
+// validator webhooks and the chats subscribed to that validator, unless a
+// silence covers it.
+func sendVersionAlert(db *gorm.DB, chainID string, data internal.AlertData) {
+	data.ChainID = chainID
+	data.Kind = internal.AlertKindNodeVersion
+	if silenceID := activeSilenceID(db, chainID, data.Addr); silenceID != 0 {
+		log.Printf("[versions][%s] silence #%d: not sending %q for %s", chainID, silenceID, data.Title, data.Addr)
+		return
+	}
+	if err := internal.SendValidatorAlert(chainID, data, 0, db); err != nil {
+		log.Printf("[versions][%s] SendValidatorAlert error: %v", chainID, err)
+	}
+}
@@ -0,0 +1,132 @@
+package gnovalidator
+
+import (
+	"testing"
+	"time"
+
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	p2pTypes "github.com/gnolang/gno/tm2/pkg/p2p/types"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/testoutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerRPCURL(t *testing.T) {
+	assert.Equal(t, "http://1.2.3.4:26657", peerRPCURL("tcp://0.0.0.0:26657", "1.2.3.4"))
+	assert.Equal(t, "http://1.2.3.4:26657", peerRPCURL("tcp://127.0.0.1:26657", "1.2.3.4"))
+	assert.Equal(t, "http://1.2.3.4:443", peerRPCURL("tcp://rpc.example.com:443", "1.2.3.4"), "the advertised host is never dialed")
+	assert.Equal(t, "", peerRPCURL("", "1.2.3.4"))
+	assert.Equal(t, "", peerRPCURL("tcp://0.0.0.0:26657", ""))
+	assert.Equal(t, "", peerRPCURL("tcp://0.0.0.0:26657", "10.0.0.5"), "private addresses are refused")
+	assert.Equal(t, "", peerRPCURL("tcp://0.0.0.0:26657", "169.254.169.254"))
+}
+
+func testNodeInfo(name, moniker, version string) p2pTypes.NodeInfo {
+	return p2pTypes.NodeInfo{
+		NetAddress: &p2pTypes.NetAddress{ID: p2pTypes.ID(name)},
+		Moniker:    moniker,
+		Version:    version,
+	}
+}
+
+func TestNodeVersions(t *testing.T) {
+	valKey := crypto.AddressFromPreimage([]byte("val"))
+	valMonikers := map[string]string{valKey.String(): "Samourai", "g1other": "Other"}
+	now := time.Now()
+
+	self := &ctypes.ResultStatus{NodeInfo: testNodeInfo("self", "rpc-node", "v1.0.0")}
+	peers := []ctypes.Peer{
+		{NodeInfo: testNodeInfo("a", "sentry-1", "v1.0.0"), RemoteIP: "1.1.1.1"},
+		{NodeInfo: testNodeInfo("b", "other", "v0.9.0"), RemoteIP: "2.2.2.2"},
+		{NodeInfo: testNodeInfo("self", "rpc-node", "v1.0.0")},
+		{NodeInfo: p2pTypes.NodeInfo{Moniker: "no address"}},
+	}
+	statuses := map[string]*ctypes.ResultStatus{
+		"a": {NodeInfo: testNodeInfo("a", "sentry-1", "v1.1.0"), ValidatorInfo: ctypes.ValidatorInfo{Address: valKey}},
+	}
+
+	rows := nodeVersions("test12", self, peers, statuses, valMonikers, now)
+	require.Len(t, rows, 3)
+	assert.Equal(t, "self", rows[0].NodeID)
+	assert.Empty(t, rows[0].Addr)
+	assert.Equal(t, "v1.1.0", rows[1].Version, "the node's own Status wins")
+	assert.Equal(t, valKey.String(), rows[1].Addr, "validator from Status")
+	assert.Equal(t, "1.1.1.1", rows[1].RemoteIP)
+	assert.Equal(t, "g1other", rows[2].Addr, "validator from moniker")
+}
+
+func TestNodeValidator_AmbiguousMoniker(t *testing.T) {
+	assert.Empty(t, nodeValidator(nil, "twin", map[string]string{"g1a": "twin", "g1b": "Twin"}))
+	assert.Empty(t, nodeValidator(nil, "", map[string]string{"g1a": ""}))
+}
+
+func TestNodeVersionDistribution(t *testing.T) {
+	now := time.Now()
+	nodes := []database.NodeVersion{
+		{NodeID: "a", Addr: "g1a", Version: "v2", LastSeen: now},
+		{NodeID: "a2", Addr: "g1a", Version: "v1", LastSeen: now.Add(-time.Hour)},
+		{NodeID: "b", Addr: "g1b", Version: "v2", LastSeen: now},
+		{NodeID: "c", Addr: "g1c", Version: "v1", LastSeen: now},
+		{NodeID: "s", Version: "v1", LastSeen: now},
+	}
+	assert.Equal(t, []VersionCount{
+		{Version: "v1", Nodes: 3, Validators: 1},
+		{Version: "v2", Nodes: 2, Validators: 2},
+	}, NodeVersionDistribution(nodes))
+
+	majority, ok := MajorityVersion(nodes)
+	assert.True(t, ok)
+	assert.Equal(t, "v2", majority, "a validator counts once, with its node last seen")
+
+	_, ok = MajorityVersion(nodes[2:4])
+	assert.False(t, ok, "a tie is no majority")
+}
+
+func TestVersionWatch(t *testing.T) {
+	var w versionWatch
+	started, ended := w.update(map[string]string{"g1a": "v1", "g1b": "v1", "g1c": "v0"})
+	assert.Equal(t, []string{"g1c"}, started)
+	assert.Empty(t, ended)
+
+	started, _ = w.update(map[string]string{"g1a": "v1", "g1b": "v1", "g1c": "v0"})
+	assert.Empty(t, started, "one alert per version")
+
+	// The majority upgrades: g1c is still behind, g1a now is.
+	started, ended = w.update(map[string]string{"g1a": "v1", "g1b": "v2", "g1c": "v2", "g1d": "v2"})
+	assert.Equal(t, []string{"g1a"}, started)
+	assert.Equal(t, []string{"g1c"}, ended)
+
+	started, ended = w.update(map[string]string{"g1a": "v1", "g1b": "v2"})
+	assert.Empty(t, started, "no majority: nothing changes")
+	assert.Empty(t, ended)
+
+	started, ended = w.update(map[string]string{"g1b": "v2", "g1c": "v2"})
+	assert.Empty(t, started)
+	assert.Empty(t, ended, "a validator no longer seen is forgotten")
+	assert.Empty(t, w.diverged)
+}
+
+func TestUpcomingPlannedHalt(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+
+	halt, err := upcomingPlannedHalt(db, "test12")
+	require.NoError(t, err)
+	assert.Nil(t, halt, "no planned halt, no divergence alert")
+
+	reached := database.PlannedHalt{ChainID: "test12", HaltHeight: 100, WindowMinutes: 30, CreatedBy: "admin"}
+	require.NoError(t, database.CreatePlannedHalt(db, &reached))
+	now := time.Now()
+	reached.HaltedAt = &now
+	require.NoError(t, database.UpdatePlannedHaltProgress(db, &reached))
+	halt, err = upcomingPlannedHalt(db, "test12")
+	require.NoError(t, err)
+	assert.Nil(t, halt, "a halt already reached is not ahead")
+
+	require.NoError(t, database.CreatePlannedHalt(db, &database.PlannedHalt{ChainID: "test12", HaltHeight: 500, WindowMinutes: 30, CreatedBy: "admin"}))
+	halt, err = upcomingPlannedHalt(db, "test12")
+	require.NoError(t, err)
+	require.NotNil(t, halt)
+	assert.Equal(t, int64(500), halt.HaltHeight)
+}
@@ -0,0 +1,278 @@
+package gnovalidator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	rpcclient "github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/samouraiworld/gnomonitoring/backend/internal"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"gorm.io/gorm"
+)
+
+const (
+	// peerCheckInterval is how often WatchPeers samples the peer list of
+	// the RPC node of a chain.
+	peerCheckInterval = time.Minute
+	// PeerSampleStaleAfter is how old the last peer sample may be to still
+	// stand for the current peer list.
+	PeerSampleStaleAfter = 5 * time.Minute
+)
+
+// netPeers returns the net_peers rows of a NetInfo peer list sampled at
+// now. Peers with no node address are skipped.
+func netPeers(chainID string, peers []ctypes.Peer, now time.Time) []database.NetPeer {
+	rows := make([]database.NetPeer, 0, len(peers))
+	for _, p := range peers {
+		if p.NodeInfo.NetAddress == nil {
+			continue
+		}
+		direction := "inbound"
+		if p.IsOutbound {
+			direction = "outbound"
+		}
+		rows = append(rows, database.NetPeer{
+			ChainID:   chainID,
+			SeenAt:    now,
+			NodeID:    p.NodeInfo.ID().String(),
+			Moniker:   p.NodeInfo.Moniker,
+			RemoteIP:  p.RemoteIP,
+			Direction: direction,
+			SendRate:  p.ConnectionStatus.SendMonitor.CurRate,
+			RecvRate:  p.ConnectionStatus.RecvMonitor.CurRate,
+		})
+	}
+	return rows
+}
+
+// peerCountWatch is the state of the peer count check of WatchPeers.
+type peerCountWatch struct {
+	low bool
+}
+
+// update records the current peer count and reports whether it just fell
+// under min, or just got back to it. A min <= 0 disables the check, and
+// ends an ongoing alert.
+func (w *peerCountWatch) update(count, min int) (dropped, recovered bool) {
+	low := min > 0 && count < min
+	dropped, recovered = low && !w.low, !low && w.low
+	w.low = low
+	return dropped, recovered
+}
+
+// sentryWatch is the state of the sentry check of WatchPeers: when each
+// node was last in the peer list, and the nodes alerted as gone.
+type sentryWatch struct {
+	seen    map[string]time.Time
+	alerted map[string]bool
+}
+
+// update records the peer list sampled at now and reports the validator
+// nodes of known (node ID → validator address) absent from it for at least
+// after, and the alerted ones back in it. Only a node this watch has seen
+// in the peer list can go missing. A node no longer known is forgotten
+// silently.
+func (w *sentryWatch) update(known map[string]string, present map[string]bool, now time.Time, after time.Duration) (gone, back []string) {
+	if w.seen == nil {
+		w.seen = make(map[string]time.Time)
+		w.alerted = make(map[string]bool)
+	}
+	for id := range present {
+		w.seen[id] = now
+		if w.alerted[id] {
+			back = append(back, id)
+			delete(w.alerted, id)
+		}
+	}
+	for id, last := range w.seen {
+		if _, ok := known[id]; !ok {
+			if !present[id] {
+				delete(w.seen, id)
+			}
+			delete(w.alerted, id)
+			continue
+		}
+		if !present[id] && !w.alerted[id] && after > 0 && now.Sub(last) >= after {
+			gone = append(gone, id)
+			w.alerted[id] = true
+		}
+	}
+	sort.Strings(gone)
+	sort.Strings(back)
+	return gone, back
+}
+
+// validatorNodes returns the nodes of chainID known to sign for a
+// validator (node ID → node), from the node version inventory.
+func validatorNodes(db *gorm.DB, chainID string) (map[string]database.NodeVersion, error) {
+	nodes, err := database.GetCurrentNodeVersions(db, chainID, time.Now().Add(-NodeVersionStaleAfter))
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]database.NodeVersion)
+	for _, n := range nodes {
+		if n.Addr != "" {
+			out[n.NodeID] = n
+		}
+	}
+	return out, nil
+}
+
+// WatchPeers samples the peer list of the RPC node of chainID at endpoint,
+// its first configured RPC endpoint, every checkInterval and stores it in
+// net_peers. The sample is pinned to that node rather than going through
+// the fallback client: another endpoint has other peers, and switching
+// would make validator nodes look gone. It raises a WARNING when the
+// node has fewer than peer_count_min peers, and a WARNING per validator
+// node, as known from the node version inventory, missing from the peer
+// list for sentry_missing_minutes; an INFO follows each once it clears.
+// The peer count alert is chain-level, the sentry alert is about the
+// validator of the node.
+func WatchPeers(ctx context.Context, db *gorm.DB, chainID, endpoint string, checkInterval time.Duration) {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("[peers][%s] panic recovered: %v", chainID, r)
+			}
+		}()
+		rpcClient, err := rpcclient.NewHTTPClient(endpoint)
+		if err != nil {
+			log.Printf("[peers][%s] failed to create client for %s: %v", chainID, internal.EndpointLabel(endpoint), err)
+			return
+		}
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+
+		var counts peerCountWatch
+		var sentries sentryWatch
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			info, err := rpcClient.NetInfo()
+			if err != nil || info == nil {
+				log.Printf("[peers][%s] NetInfo(%s) error: %v", chainID, internal.EndpointLabel(endpoint), err)
+				continue
+			}
+			now := time.Now()
+			rows := netPeers(chainID, info.Peers, now)
+			if err := database.InsertNetPeers(db, chainID, now, rows); err != nil {
+				log.Printf("[peers][%s] %v", chainID, err)
+			}
+
+			t := GetThresholds()
+			dropped, recovered := counts.update(len(rows), t.PeerCountMin)
+			if dropped {
+				sendPeerCountAlert(db, chainID, len(rows), t.PeerCountMin)
+			}
+			if recovered {
+				sendPeerCountResolved(db, chainID, len(rows))
+			}
+
+			nodes, err := validatorNodes(db, chainID)
+			if err != nil {
+				log.Printf("[peers][%s] %v", chainID, err)
+				continue
+			}
+			known := make(map[string]string, len(nodes))
+			for id, n := range nodes {
+				known[id] = n.Addr
+			}
+			present := make(map[string]bool, len(rows))
+			for _, p := range rows {
+				present[p.NodeID] = true
+			}
+			gone, back := sentries.update(known, present, now, time.Duration(t.SentryMissingMinutes)*time.Minute)
+			monikers := GetMonikerMap(chainID)
+			for _, id := range gone {
+				sendSentryAlert(db, chainID, nodes[id], monikers[nodes[id].Addr], sentries.seen[id], false)
+			}
+			for _, id := range back {
+				sendSentryAlert(db, chainID, nodes[id], monikers[nodes[id].Addr], now, true)
+			}
+		}
+	}()
+}
+
+func sendPeerCountAlert(db *gorm.DB, chainID string, count, min int) {
+	log.Printf("⚠️ [%s] WARNING : RPC node down to %d peers (minimum %d)", chainID, count, min)
+	if silenceID := activeSilenceID(db, chainID, "all"); silenceID != 0 {
+		log.Printf("[peers][%s] silence #%d: not sending peer count alert", chainID, silenceID)
+		return
+	}
+	data := internal.AlertData{
+		ChainID: chainID,
+		Level:   internal.AlertWarning,
+		Emoji:   "⚠️",
+		Title:   "Peer count low",
+		Fields: []internal.AlertField{
+			{Name: "peers", Value: fmt.Sprintf("%d", count)},
+			{Name: "minimum", Value: fmt.Sprintf("%d", min)},
+		},
+		Addr: "all",
+		Kind: internal.AlertKindPeerCount,
+	}
+	if err := internal.SendInfoValidator(chainID, data, 0, db); err != nil {
+		log.Printf("[peers][%s] SendInfoValidator error: %v", chainID, err)
+	}
+}
+
+func sendPeerCountResolved(db *gorm.DB, chainID string, count int) {
+	if activeSilenceID(db, chainID, "all") != 0 {
+		return
+	}
+	data := internal.AlertData{
+		ChainID:       chainID,
+		Level:         internal.AlertInfo,
+		Emoji:         "✅",
+		Title:         "Peer count recovered",
+		Description:   fmt.Sprintf("The RPC node has %d peers again.", count),
+		Addr:          "all",
+		Kind:          internal.AlertKindPeerCount,
+		ResolvedLevel: internal.AlertWarning,
+	}
+	if err := internal.SendInfoValidator(chainID, data, 0, db); err != nil {
+		log.Printf("[peers][%s] SendInfoValidator error: %v", chainID, err)
+	}
+}
+
+// sendSentryAlert alerts that node, signing for a validator, left the peer
+// list (last seen at seen), or announces it is back. It goes to the
+// subscribers of that validator, not to every channel of the chain.
+func sendSentryAlert(db *gorm.DB, chainID string, node database.NodeVersion, moniker string, seen time.Time, back bool) {
+	if moniker == "" {
+		moniker = node.Addr
+	}
+	data := internal.AlertData{
+		ChainID: chainID,
+		Level:   internal.AlertWarning,
+		Emoji:   "⚠️",
+		Title:   "Validator node left the peer list",
+		Fields: []internal.AlertField{
+			{Name: "validator", Value: moniker},
+			{Name: "node", Value: fmt.Sprintf("%s (%s)", node.NodeID, node.Moniker)},
+			{Name: "last seen", Value: seen.UTC().Format("2006-01-02 15:04 UTC")},
+		},
+		Addr: node.Addr,
+		Kind: internal.AlertKindSentryMissing,
+	}
+	if back {
+		data.Level, data.Emoji, data.Title = internal.AlertInfo, "✅", "Validator node back in the peer list"
+		data.Fields = data.Fields[:2]
+		data.ResolvedLevel = internal.AlertWarning
+	}
+	log.Printf("%s [%s] %s: %s node %s", data.Emoji, chainID, data.Title, moniker, node.NodeID)
+	if silenceID := activeSilenceID(db, chainID, node.Addr); silenceID != 0 {
+		log.Printf("[peers][%s] silence #%d: not sending sentry alert for %s", chainID, silenceID, node.Addr)
+		return
+	}
+	if err := internal.SendValidatorAlert(chainID, data, 0, db); err != nil {
+		log.Printf("[peers][%s] SendValidatorAlert error: %v", chainID, err)
+	}
+}
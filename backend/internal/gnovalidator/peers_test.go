@@ -0,0 +1,85 @@
+package gnovalidator
+
+import (
+	"testing"
+	"time"
+
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/flow"
+	p2pTypes "github.com/gnolang/gno/tm2/pkg/p2p/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetPeers(t *testing.T) {
+	now := time.Now()
+	out := ctypes.Peer{NodeInfo: testNodeInfo("a", "sentry-1", "v1"), IsOutbound: true, RemoteIP: "1.1.1.1"}
+	out.ConnectionStatus.SendMonitor = flow.Status{CurRate: 100}
+	out.ConnectionStatus.RecvMonitor = flow.Status{CurRate: 200}
+	peers := []ctypes.Peer{
+		out,
+		{NodeInfo: testNodeInfo("b", "rpc", "v1"), RemoteIP: "2.2.2.2"},
+		{NodeInfo: p2pTypes.NodeInfo{Moniker: "no address"}},
+	}
+
+	rows := netPeers("test12", peers, now)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "a", rows[0].NodeID)
+	assert.Equal(t, "outbound", rows[0].Direction)
+	assert.Equal(t, int64(100), rows[0].SendRate)
+	assert.Equal(t, int64(200), rows[0].RecvRate)
+	assert.Equal(t, "1.1.1.1", rows[0].RemoteIP)
+	assert.Equal(t, "inbound", rows[1].Direction)
+	assert.Equal(t, now, rows[1].SeenAt)
+}
+
+func TestPeerCountWatch(t *testing.T) {
+	var w peerCountWatch
+	dropped, recovered := w.update(5, 3)
+	assert.False(t, dropped)
+	assert.False(t, recovered)
+
+	dropped, _ = w.update(2, 3)
+	assert.True(t, dropped)
+	dropped, _ = w.update(1, 3)
+	assert.False(t, dropped, "one alert per drop")
+
+	_, recovered = w.update(3, 3)
+	assert.True(t, recovered)
+
+	w.update(0, 3)
+	_, recovered = w.update(0, 0)
+	assert.True(t, recovered, "disabling the check ends the alert")
+}
+
+func TestSentryWatch(t *testing.T) {
+	var w sentryWatch
+	known := map[string]string{"a": "g1a", "b": "g1b", "self": "g1s"}
+	start := time.Now()
+	after := 10 * time.Minute
+
+	gone, back := w.update(known, map[string]bool{"a": true, "b": true, "x": true}, start, after)
+	assert.Empty(t, gone)
+	assert.Empty(t, back)
+
+	gone, _ = w.update(known, map[string]bool{"b": true}, start.Add(5*time.Minute), after)
+	assert.Empty(t, gone, "not missing long enough")
+
+	gone, _ = w.update(known, map[string]bool{"b": true}, start.Add(10*time.Minute), after)
+	assert.Equal(t, []string{"a"}, gone, "self, never in the peer list, is not reported")
+
+	gone, _ = w.update(known, map[string]bool{"b": true}, start.Add(11*time.Minute), after)
+	assert.Empty(t, gone, "one alert per disappearance")
+
+	gone, back = w.update(known, map[string]bool{"a": true, "b": true}, start.Add(12*time.Minute), after)
+	assert.Empty(t, gone)
+	assert.Equal(t, []string{"a"}, back)
+
+	// b leaves the inventory: forgotten, never reported.
+	delete(known, "b")
+	gone, back = w.update(known, map[string]bool{"a": true}, start.Add(30*time.Minute), after)
+	assert.Empty(t, gone)
+	assert.Empty(t, back)
+	assert.NotContains(t, w.seen, "b")
+	assert.NotContains(t, w.seen, "x")
+}
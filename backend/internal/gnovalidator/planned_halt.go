@@ -0,0 +1,325 @@
+package gnovalidator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"gorm.io/gorm"
+)
+
+// plannedHaltCheckInterval is how often WatchPlannedHalts reloads the
+// planned halts of a chain.
+const plannedHaltCheckInterval = 30 * time.Second
+
+// plannedHaltHeightPoll is how often WatchPlannedHalts looks for a new
+// height: the halts are moved forward at every block, so that the
+// haltCountdownBlocks notification, a few seconds before the halt, is not
+// skipped between two reloads.
+const plannedHaltHeightPoll = time.Second
+
+// haltCountdownBlocks is how many blocks before the halt height the last
+// countdown notification is sent.
+const haltCountdownBlocks = 10
+
+// rejoinReportAfter is how long after the restart the rejoin report is
+// sent when some validators have not signed again by then.
+const rejoinReportAfter = time.Hour
+
+// Countdown stages of a planned halt, stored in its notified_stage.
+const (
+	haltStageDay     = iota + 1 // 24 hours to go
+	haltStageHour               // 1 hour to go
+	haltStageBlocks             // haltCountdownBlocks blocks to go
+	haltStageReached            // the chain reached the halt height
+)
+
+// haltCountdownStage is the countdown stage of a halt remaining blocks
+// away, at blockTime per block. A zero blockTime is unknown: only the
+// stages counted in blocks apply.
+func haltCountdownStage(remaining int64, blockTime time.Duration) int {
+	eta := time.Duration(remaining) * blockTime
+	switch {
+	case remaining <= 0:
+		return haltStageReached
+	case remaining <= haltCountdownBlocks:
+		return haltStageBlocks
+	case blockTime > 0 && eta <= time.Hour:
+		return haltStageHour
+	case blockTime > 0 && eta <= 24*time.Hour:
+		return haltStageDay
+	}
+	return 0
+}
+
+// estimateBlockTime is the block time chainID is expected to keep: the
+// average over the last block_time_window blocks, otherwise its block time
+// baseline, 0 when neither is known.
+func estimateBlockTime(db *gorm.DB, chainID string) time.Duration {
+	t := GetThresholds()
+	if t.BlockTimeWindow > 0 {
+		blocks, err := recentBlocks(db, chainID, t.BlockTimeWindow+1)
+		if err != nil {
+			log.Printf("[halt][%s] %v", chainID, err)
+		} else if avg, ok := averageBlockTime(blocks); ok {
+			return avg
+		}
+	}
+	baseline, _ := blockTimeBaseline(db, chainID, t.BlockTimeBaselineMs)
+	return baseline
+}
+
+// allRejoined reports whether every validator signed again.
+func allRejoined(rejoins []database.ValidatorRejoin) bool {
+	for _, r := range rejoins {
+		if r.FirstHeight == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// formatRejoins lists how long each validator took to sign again after the
+// chain restarted at resumedAt, one line each, indented by indent.
+func formatRejoins(rejoins []database.ValidatorRejoin, resumedAt time.Time, indent string) string {
+	var sb strings.Builder
+	for _, r := range rejoins {
+		name := r.Moniker
+		if name == "" {
+			name = "unknown"
+		}
+		addrShort := r.Addr
+		if len(addrShort) > 10 {
+			addrShort = addrShort[:10] + "..."
+		}
+		if r.FirstHeight == nil || r.FirstSigned == nil {
+			sb.WriteString(fmt.Sprintf("%s🔴 %-14s (%s) not signing yet\n", indent, name, addrShort))
+			continue
+		}
+		took := max(r.FirstSigned.Sub(resumedAt), 0).Truncate(time.Second)
+		sb.WriteString(fmt.Sprintf("%s🟢 %-14s (%s) signed #%d after %s\n", indent, name, addrShort, *r.FirstHeight, took))
+	}
+	return sb.String()
+}
+
+// WatchPlannedHalts follows the planned halts of chainID. Ahead of a halt
+// it posts a countdown when the halt is about 24 hours, 1 hour and
+// haltCountdownBlocks blocks away, estimated from the recent block time;
+// it then announces the chain reaching the halt height and restarting. Once
+// every validator of the set at the halt signed again, or rejoinReportAfter
+// after the restart, it reports how long each took. Stagnation alerts are
+// held back during the halt by CollectParticipation. The open halts are
+// reloaded every checkInterval and moved forward at every new height in
+// between.
+func WatchPlannedHalts(ctx context.Context, db *gorm.DB, chainID string, checkInterval time.Duration) {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("[halt][%s] panic recovered: %v", chainID, r)
+			}
+		}()
+		ticker := time.NewTicker(plannedHaltHeightPoll)
+		defer ticker.Stop()
+
+		var (
+			halts    []database.PlannedHalt
+			loadedAt time.Time
+			seen     int64
+		)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			latest := GetLastHeight(chainID)
+			if latest == 0 {
+				continue
+			}
+			reload := time.Since(loadedAt) >= checkInterval
+			if latest == seen && !reload {
+				continue
+			}
+			if reload {
+				rows, err := database.GetOpenPlannedHalts(db, chainID)
+				if err != nil {
+					log.Printf("[halt][%s] %v", chainID, err)
+					continue
+				}
+				halts, loadedAt = rows, time.Now()
+			}
+			seen = latest
+			open := halts[:0]
+			for i := range halts {
+				if stepPlannedHalt(db, chainID, &halts[i], latest) {
+					if err := database.UpdatePlannedHaltProgress(db, &halts[i]); err != nil {
+						log.Printf("[halt][%s] %v", chainID, err)
+					}
+				}
+				if halts[i].ReportedAt == nil {
+					open = append(open, halts[i])
+				}
+			}
+			halts = open
+		}
+	}()
+}
+
+// stepPlannedHalt moves h forward with the chain at height latest, sends
+// what it has to announce, and reports whether h changed.
+func stepPlannedHalt(db *gorm.DB, chainID string, h *database.PlannedHalt, latest int64) bool {
+	switch {
+	case h.HaltedAt == nil && latest < h.HaltHeight:
+		blockTime := estimateBlockTime(db, chainID)
+		remaining := h.HaltHeight - latest
+		stage := haltCountdownStage(remaining, blockTime)
+		if stage <= h.NotifiedStage {
+			return false
+		}
+		sendHaltCountdown(db, h, latest, blockTime)
+		h.NotifiedStage = stage
+
+	case h.HaltedAt == nil:
+		at, err := database.GetTimeOfBlock(db, chainID, h.HaltHeight)
+		if err != nil || at.IsZero() {
+			at = time.Now()
+		}
+		h.HaltedAt = &at
+		h.NotifiedStage = haltStageReached
+		// Past the halt height already: the chain did not stop there, the
+		// restart is announced next.
+		if latest == h.HaltHeight {
+			sendHaltReached(db, h)
+		}
+
+	case h.ResumedAt == nil:
+		if latest <= h.HaltHeight {
+			return false
+		}
+		// Wait for the first block after the halt to be stored: the rejoin
+		// delays are counted from its time.
+		at, err := database.GetTimeOfBlock(db, chainID, h.HaltHeight+1)
+		if err != nil || at.IsZero() {
+			return false
+		}
+		h.ResumedAt = &at
+		sendHaltResumed(db, h)
+
+	default:
+		// The participations after the restart are not all stored yet.
+		if !isChainSynced(chainID) {
+			return false
+		}
+		rejoins, err := database.GetValidatorRejoins(db, chainID, h.HaltHeight)
+		if err != nil {
+			log.Printf("[halt][%s] %v", chainID, err)
+			return false
+		}
+		if !allRejoined(rejoins) && time.Since(*h.ResumedAt) < rejoinReportAfter {
+			return false
+		}
+		sendRejoinReport(db, h, rejoins)
+		now := time.Now()
+		h.ReportedAt = &now
+	}
+	return true
+}
+
+// sendPlannedHaltAlert sends data about a planned halt of chainID to every
+// channel of the chain, unless a chain-wide silence is active.
+func sendPlannedHaltAlert(db *gorm.DB, chainID string, data internal.AlertData) {
+	if silenceID := activeSilenceID(db, chainID, "all"); silenceID != 0 {
+		log.Printf("[halt][%s] silence #%d: not sending %q", chainID, silenceID, data.Title)
+		return
+	}
+	data.ChainID = chainID
+	data.Addr = "all"
+	data.Kind = internal.AlertKindPlannedHalt
+	if err := internal.SendInfoValidator(chainID, data, 0, db); err != nil {
+		log.Printf("[halt][%s] SendInfoValidator error: %v", chainID, err)
+	}
+}
+
+// haltFields are the fields shared by the notifications about h.
+func haltFields(h *database.PlannedHalt) []internal.AlertField {
+	fields := []internal.AlertField{{Name: "halt height", Value: fmt.Sprintf("%d", h.HaltHeight)}}
+	if h.Reason != "" {
+		fields = append(fields, internal.AlertField{Name: "reason", Value: h.Reason})
+	}
+	return fields
+}
+
+func sendHaltCountdown(db *gorm.DB, h *database.PlannedHalt, latest int64, blockTime time.Duration) {
+	remaining := h.HaltHeight - latest
+	title := fmt.Sprintf("Planned halt in %d blocks", remaining)
+	fields := append(haltFields(h), internal.AlertField{Name: "current height", Value: fmt.Sprintf("%d", latest)})
+	if blockTime > 0 {
+		eta := time.Duration(remaining) * blockTime
+		if remaining > haltCountdownBlocks {
+			title = fmt.Sprintf("Planned halt in about %s", strings.TrimSuffix(eta.Round(time.Minute).String(), "0s"))
+		}
+		fields = append(fields, internal.AlertField{
+			Name:  "expected at",
+			Value: time.Now().Add(eta).UTC().Format("2006-01-02 15:04 UTC"),
+		})
+	}
+	log.Printf("⏳ [%s] %s: height %d, now at %d", h.ChainID, title, h.HaltHeight, latest)
+	sendPlannedHaltAlert(db, h.ChainID, internal.AlertData{
+		Level:       internal.AlertInfo,
+		Emoji:       "⏳",
+		Title:       title,
+		Description: fmt.Sprintf("The chain is set to halt at height %d for an upgrade.", h.HaltHeight),
+		Fields:      fields,
+	})
+}
+
+func sendHaltReached(db *gorm.DB, h *database.PlannedHalt) {
+	log.Printf("⏸️ [%s] planned halt height %d reached", h.ChainID, h.HaltHeight)
+	sendPlannedHaltAlert(db, h.ChainID, internal.AlertData{
+		Level: internal.AlertInfo,
+		Emoji: "⏸️",
+		Title: "Planned halt height reached",
+		Description: fmt.Sprintf("Stagnation alerts are held back for %d minutes while the chain is stopped.",
+			h.WindowMinutes),
+		Fields:      haltFields(h),
+		StartHeight: h.HaltHeight,
+	})
+}
+
+func sendHaltResumed(db *gorm.DB, h *database.PlannedHalt) {
+	fields := haltFields(h)
+	if h.HaltedAt != nil {
+		downtime := max(h.ResumedAt.Sub(*h.HaltedAt), 0).Truncate(time.Second)
+		fields = append(fields, internal.AlertField{Name: "downtime", Value: downtime.String()})
+	}
+	log.Printf("▶️ [%s] chain restarted after planned halt height %d", h.ChainID, h.HaltHeight)
+	sendPlannedHaltAlert(db, h.ChainID, internal.AlertData{
+		Level:       internal.AlertInfo,
+		Emoji:       "▶️",
+		Title:       "Chain restarted after planned halt",
+		Fields:      fields,
+		StartHeight: h.HaltHeight + 1,
+	})
+}
+
+// sendRejoinReport reports how the validators rejoined after h: an INFO
+// when all of them signed again, a WARNING naming the missing ones
+// otherwise.
+func sendRejoinReport(db *gorm.DB, h *database.PlannedHalt, rejoins []database.ValidatorRejoin) {
+	level, emoji, title := internal.AlertInfo, "📋", "All validators rejoined after planned halt"
+	if !allRejoined(rejoins) {
+		level, emoji, title = internal.AlertWarning, "⚠️", "Validators missing after planned halt"
+	}
+	log.Printf("%s [%s] %s at height %d", emoji, h.ChainID, title, h.HaltHeight)
+	sendPlannedHaltAlert(db, h.ChainID, internal.AlertData{
+		Level:       level,
+		Emoji:       emoji,
+		Title:       title,
+		Description: formatRejoins(rejoins, *h.ResumedAt, ""),
+		Fields:      haltFields(h),
+	})
+}
@@ -0,0 +1,52 @@
+package gnovalidator
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHaltCountdownStage(t *testing.T) {
+	const blockTime = 2 * time.Second
+	assert.Equal(t, 0, haltCountdownStage(100_000, blockTime), "more than a day away")
+	assert.Equal(t, haltStageDay, haltCountdownStage(43_200, blockTime))
+	assert.Equal(t, haltStageHour, haltCountdownStage(1_800, blockTime))
+	assert.Equal(t, haltStageBlocks, haltCountdownStage(10, blockTime))
+	assert.Equal(t, haltStageReached, haltCountdownStage(0, blockTime))
+
+	// With no block time estimate only the block countdown applies.
+	assert.Equal(t, 0, haltCountdownStage(1_800, 0))
+	assert.Equal(t, haltStageBlocks, haltCountdownStage(5, 0))
+}
+
+func TestFormatRejoins(t *testing.T) {
+	resumed := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	h1, h2 := int64(501), int64(530)
+	t1, t2 := resumed, resumed.Add(90*time.Second)
+	rejoins := []database.ValidatorRejoin{
+		{Addr: "g1aaaaaaaaaaaa", Moniker: "fast", FirstHeight: &h1, FirstSigned: &t1},
+		{Addr: "g1bbbbbbbbbbbb", Moniker: "slow", FirstHeight: &h2, FirstSigned: &t2},
+		{Addr: "g1cccccccccccc"},
+	}
+	assert.False(t, allRejoined(rejoins))
+	assert.True(t, allRejoined(rejoins[:2]))
+
+	out := formatRejoins(rejoins, resumed, "  ")
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	assert.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "signed #501 after 0s")
+	assert.Contains(t, lines[1], "signed #530 after 1m30s")
+	assert.Contains(t, lines[2], "unknown")
+	assert.Contains(t, lines[2], "not signing yet")
+
+	halted := resumed.Add(-40 * time.Minute)
+	report := formatHaltRejoins(ChainHealthSnapshot{
+		LastHalt: &database.PlannedHalt{HaltHeight: 500, HaltedAt: &halted, ResumedAt: &resumed},
+		Rejoins:  rejoins,
+	})
+	assert.True(t, strings.HasPrefix(report, "Planned halt at #500 — restarted 2026-10-01 12:00 UTC after 40m0s:\n"))
+	assert.Empty(t, formatHaltRejoins(ChainHealthSnapshot{}))
+}
@@ -4,94 +4,107 @@ import (
 	"fmt"
 	"log"
 	"sync"
+	"time"
 
 	rpcclient "github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
 	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/samouraiworld/gnomonitoring/backend/internal"
 )
 
-// FallbackRPCClient wraps an rpcclient.RPCClient and automatically retries
-// on the next endpoint when a call fails.
+// FallbackRPCClient wraps an rpcclient.RPCClient per endpoint and sends
+// each call to the healthiest one (internal.OrderEndpoints), moving on to
+// the next when a call fails. Every call is recorded in the endpoint health
+// tracker, and Status results also record the height of the endpoint.
 type FallbackRPCClient struct {
 	mu        sync.Mutex
 	endpoints []string
 	activeIdx int
-	inner     *rpcclient.RPCClient
+	clients   map[string]*rpcclient.RPCClient
 }
 
-// NewFallbackRPCClient creates a FallbackRPCClient that tries each endpoint in
-// order when a call fails. The first endpoint is used initially.
+// NewFallbackRPCClient creates a FallbackRPCClient over endpoints. With no
+// health recorded yet they are tried in order.
 func NewFallbackRPCClient(endpoints []string) *FallbackRPCClient {
 	if len(endpoints) == 0 {
 		log.Printf("[rpc_fallback] no endpoints provided")
-		return &FallbackRPCClient{endpoints: endpoints}
-	}
-	inner, err := rpcclient.NewHTTPClient(endpoints[0])
-	if err != nil {
-		log.Printf("[rpc_fallback] failed to create initial client for %s: %v", endpoints[0], err)
 	}
 	return &FallbackRPCClient{
 		endpoints: endpoints,
-		activeIdx: 0,
-		inner:     inner,
+		clients:   make(map[string]*rpcclient.RPCClient, len(endpoints)),
 	}
 }
 
-// rotate advances to the next endpoint and recreates the inner client.
-// Must be called with f.mu held.
-func (f *FallbackRPCClient) rotate() {
-	if len(f.endpoints) == 0 {
-		return
+// client returns the client of endpoint, creating it on first use.
+func (f *FallbackRPCClient) client(endpoint string) (*rpcclient.RPCClient, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if c, ok := f.clients[endpoint]; ok {
+		return c, nil
 	}
-	f.activeIdx = (f.activeIdx + 1) % len(f.endpoints)
-	next := f.endpoints[f.activeIdx]
-	log.Printf("[rpc_fallback] rotating to endpoint #%d: %s", f.activeIdx, next)
-	inner, err := rpcclient.NewHTTPClient(next)
+	c, err := rpcclient.NewHTTPClient(endpoint)
 	if err != nil {
-		log.Printf("[rpc_fallback] failed to create client for %s: %v", next, err)
-		return
+		return nil, err
 	}
-	f.inner = inner
+	f.clients[endpoint] = c
+	return c, nil
 }
 
-// withRetry calls fn with the current inner client, and if it fails, rotates to
-// the next endpoint and retries once.
-func (f *FallbackRPCClient) withRetry(fn func(c *rpcclient.RPCClient) error) error {
+// setActive records that endpoint served the last successful call, and
+// logs when it is not the one that served the previous.
+func (f *FallbackRPCClient) setActive(endpoint string) {
 	f.mu.Lock()
-	inner := f.inner
-	f.mu.Unlock()
-
-	if inner == nil {
-		return fmt.Errorf("no RPC client available")
-	}
-
-	err := fn(inner)
-	if err == nil {
-		return nil
+	defer f.mu.Unlock()
+	for i, ep := range f.endpoints {
+		if ep == endpoint && i != f.activeIdx {
+			log.Printf("[rpc_fallback] switching to endpoint #%d: %s", i, internal.EndpointLabel(ep))
+			f.activeIdx = i
+		}
 	}
+}
 
-	if len(f.endpoints) <= 1 {
-		return err
+// withEndpoint calls fn on the endpoints of f, in health order, until one
+// succeeds, and returns the last error when none does.
+func (f *FallbackRPCClient) withEndpoint(fn func(endpoint string, c *rpcclient.RPCClient) error) error {
+	if len(f.endpoints) == 0 {
+		return fmt.Errorf("no RPC client available")
 	}
-
-	f.mu.Lock()
-	f.rotate()
-	inner = f.inner
-	f.mu.Unlock()
-
-	if inner == nil {
-		return err
+	var lastErr error
+	for _, ep := range internal.OrderEndpoints(internal.EndpointRPC, f.endpoints) {
+		c, err := f.client(ep)
+		if err != nil {
+			log.Printf("[rpc_fallback] failed to create client for %s: %v", internal.EndpointLabel(ep), err)
+			lastErr = err
+			continue
+		}
+		start := time.Now()
+		err = fn(ep, c)
+		internal.RecordEndpointCall(internal.EndpointRPC, ep, time.Since(start), err)
+		if err == nil {
+			f.setActive(ep)
+			return nil
+		}
+		lastErr = err
 	}
+	return lastErr
+}
 
-	return fn(inner)
+// withRetry is withEndpoint for calls that do not need to know the endpoint.
+func (f *FallbackRPCClient) withRetry(fn func(c *rpcclient.RPCClient) error) error {
+	return f.withEndpoint(func(_ string, c *rpcclient.RPCClient) error {
+		return fn(c)
+	})
 }
 
 // Status implements rpcclient.Client.
 func (f *FallbackRPCClient) Status() (*ctypes.ResultStatus, error) {
 	var result *ctypes.ResultStatus
-	err := f.withRetry(func(c *rpcclient.RPCClient) error {
+	err := f.withEndpoint(func(ep string, c *rpcclient.RPCClient) error {
 		var e error
 		result, e = c.Status()
+		if e == nil && result != nil {
+			internal.RecordEndpointHeight(internal.EndpointRPC, ep, result.SyncInfo.LatestBlockHeight)
+		}
 		return e
 	})
 	return result, err
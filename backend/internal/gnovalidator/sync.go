@@ -23,8 +23,9 @@ type dpRow struct {
 
 type job struct{ H int64 }
 type out struct {
-	Rows []dpRow
-	Err  error
+	Rows  []dpRow
+	Block database.Block
+	Err   error
 }
 
 // RecordActivationOrSkip is the shared first-activation guard used while
@@ -131,6 +132,7 @@ func BackfillRange(db *gorm.DB, client gnoclient.Client, chainID string, from, t
 
 	firstActiveBlocks := GetFirstActiveBlockMap(chainID)
 	buf := make([]dpRow, 0, flushThreshold)
+	blocks := make([]database.Block, 0, chunk)
 
 	for start := from + 1; start <= to; start += chunk {
 		end := start + chunk - 1
@@ -157,6 +159,7 @@ func BackfillRange(db *gorm.DB, client gnoclient.Client, chainID string, from, t
 				}
 			}
 			participating := buildParticipation(precommitAddrs, proposerAddr, hasTx, timeStp)
+			blocks = append(blocks, blockRecord(client, chainID, block.Block))
 			for valAddr, moniker := range monikerMap {
 				participated := participating[valAddr] // false if not found
 
@@ -200,6 +203,10 @@ func BackfillRange(db *gorm.DB, client gnoclient.Client, chainID string, from, t
 			return err
 		}
 		buf = buf[:0]
+		if err := database.UpsertBlocks(db, blocks); err != nil {
+			return err
+		}
+		blocks = blocks[:0]
 	}
 	return nil
 }
@@ -210,6 +217,7 @@ func BackfillRange(db *gorm.DB, client gnoclient.Client, chainID string, from, t
 func BackfillParallel(db *gorm.DB, client gnoclient.Client, chainID string, from, to int64, monikerMap map[string]string) error {
 	const workers = 20
 	const flushThreshold = 2000
+	const blockFlushThreshold = 500
 
 	jobs := make(chan job, 2048)
 	outs := make(chan out, 2048)
@@ -255,7 +263,7 @@ func BackfillParallel(db *gorm.DB, client gnoclient.Client, chainID string, from
 						Proposed:       p.Proposed,
 					})
 				}
-				outs <- out{Rows: rows}
+				outs <- out{Rows: rows, Block: blockRecord(client, chainID, b.Block)}
 			}
 		}()
 	}
@@ -273,12 +281,14 @@ func BackfillParallel(db *gorm.DB, client gnoclient.Client, chainID string, from
 
 	// writer unique + batch
 	buf := make([]dpRow, 0, flushThreshold)
+	blocks := make([]database.Block, 0, blockFlushThreshold)
 	var minDate, maxDate time.Time
 	for o := range outs {
 		if o.Err != nil {
 			continue
 		}
 		buf = append(buf, o.Rows...)
+		blocks = append(blocks, o.Block)
 		for _, r := range o.Rows {
 			if minDate.IsZero() || r.Date.Before(minDate) {
 				minDate = r.Date
@@ -293,10 +303,19 @@ func BackfillParallel(db *gorm.DB, client gnoclient.Client, chainID string, from
 			}
 			buf = buf[:0]
 		}
+		if len(blocks) >= blockFlushThreshold {
+			if err := database.UpsertBlocks(db, blocks); err != nil {
+				return err
+			}
+			blocks = blocks[:0]
+		}
 	}
 	if err := flushBatch(db, buf); err != nil {
 		return err
 	}
+	if err := database.UpsertBlocks(db, blocks); err != nil {
+		return err
+	}
 
 	// The backfilled range can span days already rolled into
 	// daily_participation_agregas (this runs whenever the live sync gap
@@ -26,6 +26,15 @@ type Thresholds struct {
 	RawRetentionDays            int
 	AggregatorPeriodMinutes     int
 	RecentBlocksWindow          int
+	ConsensusRoundWarning       int
+	EndpointLagBlocks           int
+	BlockTimeWindow             int
+	BlockTimeWarningMultiple    float64
+	BlockTimeCriticalMultiple   float64
+	BlockTimeBaselineMs         int // 0: averaged from the daily block rollups
+	PeerCountMin                int
+	SentryMissingMinutes        int
+	LowBalanceUgnot             int
 }
 
 var (
@@ -43,6 +52,15 @@ var (
 		RawRetentionDays:            7,
 		AggregatorPeriodMinutes:     60,
 		RecentBlocksWindow:          50,
+		ConsensusRoundWarning:       3,
+		EndpointLagBlocks:           10,
+		BlockTimeWindow:             100,
+		BlockTimeWarningMultiple:    3,
+		BlockTimeCriticalMultiple:   10,
+		BlockTimeBaselineMs:         0,
+		PeerCountMin:                3,
+		SentryMissingMinutes:        10,
+		LowBalanceUgnot:             1000000,
 	}
 	thresholdsMu sync.RWMutex
 )
@@ -66,6 +84,19 @@ func LoadThresholds(db *gorm.DB) {
 		RawRetentionDays:            database.GetAdminConfigInt(db, "raw_retention_days", 7),
 		AggregatorPeriodMinutes:     database.GetAdminConfigInt(db, "aggregator_period_minutes", 60),
 		RecentBlocksWindow:          database.GetAdminConfigInt(db, "recent_blocks_window", 50),
+		ConsensusRoundWarning:       database.GetAdminConfigInt(db, "consensus_round_warning", 3),
+		EndpointLagBlocks:           database.GetAdminConfigInt(db, "endpoint_lag_blocks", 10),
+		BlockTimeWindow:             database.GetAdminConfigInt(db, "block_time_window", 100),
+		BlockTimeWarningMultiple:    database.GetAdminConfigFloat(db, "block_time_warning_multiple", 3),
+		BlockTimeCriticalMultiple:   database.GetAdminConfigFloat(db, "block_time_critical_multiple", 10),
+		BlockTimeBaselineMs:         database.GetAdminConfigInt(db, "block_time_baseline_ms", 0),
+		PeerCountMin:                database.GetAdminConfigInt(db, "peer_count_min", 3),
+		SentryMissingMinutes:        database.GetAdminConfigInt(db, "sentry_missing_minutes", 10),
+		LowBalanceUgnot:             database.GetAdminConfigInt(db, "low_balance_ugnot", 1000000),
+	}
+	if w, c := activeThresholds.BlockTimeWarningMultiple, activeThresholds.BlockTimeCriticalMultiple; w > 0 && c > 0 && w >= c {
+		log.Printf("[thresholds] block_time_warning_multiple (%g) must be below block_time_critical_multiple (%g), using the defaults 3 and 10", w, c)
+		activeThresholds.BlockTimeWarningMultiple, activeThresholds.BlockTimeCriticalMultiple = 3, 10
 	}
 	log.Printf("[thresholds] loaded: warning=%d critical=%d resend_critical=%dh resend_warning=%dh stagnation_first=%ds stagnation_repeat=%dmin",
 		activeThresholds.WarningThreshold,
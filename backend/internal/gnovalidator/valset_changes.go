@@ -89,13 +89,24 @@ func getCachedValopers(chainID string) []Valoper {
 	return cachedValopersMap[chainID]
 }
 
-// setCachedValopers replaces the last known-good valoper snapshot for chainID.
-func setCachedValopers(chainID string, valopers []Valoper) {
+// SetCachedValopers replaces the last known-good valoper snapshot for chainID.
+func SetCachedValopers(chainID string, valopers []Valoper) {
 	cachedValopersMutex.Lock()
 	defer cachedValopersMutex.Unlock()
 	cachedValopersMap[chainID] = valopers
 }
 
+// IsValoperOperator reports whether addr is the operator address of a
+// valoper of chainID, in the last known-good valoper snapshot.
+func IsValoperOperator(chainID, addr string) bool {
+	for _, v := range getCachedValopers(chainID) {
+		if v.Address == addr {
+			return true
+		}
+	}
+	return false
+}
+
 // classifyValsetChanges compares oldMap (the moniker snapshot before this
 // refresh cycle) with newMap (the snapshot after), and correlates any
 // departures with arrivals via prevSigningToOperator (each now-removed
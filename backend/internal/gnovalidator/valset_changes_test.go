@@ -96,7 +96,7 @@ func TestCachedValopersState(t *testing.T) {
 	require.Nil(t, getCachedValopers(chainID))
 
 	valopers := []Valoper{{Name: "A", Address: "g1opA", SigningAddress: "g1signA"}}
-	setCachedValopers(chainID, valopers)
+	SetCachedValopers(chainID, valopers)
 	require.Equal(t, valopers, getCachedValopers(chainID))
 }
 
@@ -16,7 +16,6 @@ import (
 	"github.com/machinebox/graphql"
 	"github.com/samouraiworld/gnomonitoring/backend/internal"
 	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
-	"github.com/samouraiworld/gnomonitoring/backend/internal/telegram"
 	"gorm.io/gorm"
 )
 
@@ -115,9 +114,11 @@ func GetMessageTitle(height int, graphqlEndpoint string) error {
 	return nil
 }
 
+// FetchGovDAOEvents returns the ProposalCreated transactions of the GovDAO
+// realm, asking the GraphQL endpoints in health order.
 func FetchGovDAOEvents(graphqlEndpoints []string) ([]Transaction, error) {
 	var lastErr error
-	for _, endpoint := range graphqlEndpoints {
+	for _, endpoint := range internal.OrderEndpoints(internal.EndpointGraphQL, graphqlEndpoints) {
 		client := graphql.NewClient(endpoint)
 		req := graphql.NewRequest(`
 			query getEvents {
@@ -160,8 +161,11 @@ func FetchGovDAOEvents(graphqlEndpoints []string) ([]Transaction, error) {
 		var respData struct {
 			GetTransactions []Transaction `json:"getTransactions"`
 		}
-		if err := client.Run(context.Background(), req, &respData); err != nil {
-			log.Printf("[govdao] FetchGovDAOEvents endpoint %s failed: %v", endpoint, err)
+		start := time.Now()
+		err := client.Run(context.Background(), req, &respData)
+		internal.RecordEndpointCall(internal.EndpointGraphQL, endpoint, time.Since(start), err)
+		if err != nil {
+			log.Printf("[govdao] FetchGovDAOEvents endpoint %s failed: %v", internal.EndpointLabel(endpoint), err)
 			lastErr = err
 			continue
 		}
@@ -189,13 +193,10 @@ func ExtractGovDAOIDs(txs []Transaction) []string {
 	return ids
 }
 
-func WebsocketGovdao(ctx context.Context, db *gorm.DB, chainID string, graphqlEndpoints []string, rpcEndpoint string, gnowebEndpoint string) {
-	primaryGraphQL := ""
-	if len(graphqlEndpoints) > 0 {
-		primaryGraphQL = graphqlEndpoints[0]
-	}
-	wsURL := strings.Replace(primaryGraphQL, "http", "ws", 1)
-
+// WebsocketGovdao subscribes to new GovDAO proposals on the healthiest
+// GraphQL endpoint, picked again on every reconnection. Proposal links point
+// to the healthiest gnoweb endpoint at the time they are processed.
+func WebsocketGovdao(ctx context.Context, db *gorm.DB, chainID string, graphqlEndpoints []string, rpcEndpoint string, gnowebEndpoints []string) {
 	const (
 		backoffMin = 2 * time.Second
 		backoffMax = 60 * time.Second
@@ -210,7 +211,11 @@ func WebsocketGovdao(ctx context.Context, db *gorm.DB, chainID string, graphqlEn
 		default:
 		}
 
+		endpoint := internal.BestEndpoint(internal.EndpointGraphQL, graphqlEndpoints)
+		wsURL := strings.Replace(endpoint, "http", "ws", 1)
+		start := time.Now()
 		c, wsResp, err := websocket.DefaultDialer.Dial(wsURL, nil)
+		internal.RecordEndpointCall(internal.EndpointGraphQL, endpoint, time.Since(start), err)
 		if err != nil {
 			if wsResp != nil {
 				wsResp.Body.Close()
@@ -307,7 +312,7 @@ func WebsocketGovdao(ctx context.Context, db *gorm.DB, chainID string, graphqlEn
 			}
 
 			tx := msg.Payload.Data.GetTransactions
-			ProcessProposal(tx, "socket", db, chainID, graphqlEndpoints, rpcEndpoint, gnowebEndpoint)
+			ProcessProposal(tx, "socket", db, chainID, graphqlEndpoints, rpcEndpoint, internal.BestEndpoint(internal.EndpointGnoweb, gnowebEndpoints))
 		}
 
 		c.Close()
@@ -365,7 +370,7 @@ func parseGnoStringResponse(bz []byte) (string, error) {
 
 func GetTxsByBlockHeight(height int, graphqlEndpoints []string) (*TxBlock, error) {
 	var lastErr error
-	for _, endpoint := range graphqlEndpoints {
+	for _, endpoint := range internal.OrderEndpoints(internal.EndpointGraphQL, graphqlEndpoints) {
 		client := graphql.NewClient(endpoint)
 
 		req := graphql.NewRequest(`
@@ -383,8 +388,11 @@ func GetTxsByBlockHeight(height int, graphqlEndpoints []string) (*TxBlock, error
 		req.Var("height", height)
 
 		var respData GetBlocksResponse
-		if err := client.Run(context.Background(), req, &respData); err != nil {
-			log.Printf("[govdao] GetTxsByBlockHeight endpoint %s failed: %v", endpoint, err)
+		start := time.Now()
+		err := client.Run(context.Background(), req, &respData)
+		internal.RecordEndpointCall(internal.EndpointGraphQL, endpoint, time.Since(start), err)
+		if err != nil {
+			log.Printf("[govdao] GetTxsByBlockHeight endpoint %s failed: %v", internal.EndpointLabel(endpoint), err)
 			lastErr = err
 			continue
 		}
@@ -401,7 +409,8 @@ func GetTxsByBlockHeight(height int, graphqlEndpoints []string) (*TxBlock, error
 	return nil, fmt.Errorf("no GraphQL endpoints configured")
 }
 
-func InitGovdao(db *gorm.DB, chainID string, graphqlEndpoints []string, rpcEndpoint string, gnowebEndpoint string) {
+func InitGovdao(db *gorm.DB, chainID string, graphqlEndpoints []string, rpcEndpoint string, gnowebEndpoints []string) {
+	gnowebEndpoint := internal.BestEndpoint(internal.EndpointGnoweb, gnowebEndpoints)
 	Trans, err := FetchGovDAOEvents(graphqlEndpoints)
 	if err != nil {
 		log.Printf("[govdao][%s] init fetch failed: %v", chainID, err)
@@ -464,7 +473,7 @@ func ProcessProposal(tx Transaction, who string, db *gorm.DB, chainID string, gr
 			if txData, err := fetchTxByHeight(tx.BlockHeight, graphqlEndpoints); err != nil {
 				log.Printf("[govdao][%s] tx hash unavailable for proposal %d (block %d): %v", chainID, idInt, tx.BlockHeight, err)
 			} else {
-				txurl = fmt.Sprintf("https://gnoscan.io/transactions/details?txhash=%s", txData.Hash)
+				txurl = gnoscanTxURL(txData.Hash)
 			}
 
 			// Insert to db
@@ -542,30 +551,11 @@ func CheckProposalStatus(db *gorm.DB) {
 		if currentStatus == "ACCEPTED" && p.Status != "ACCEPTED" {
 			log.Printf("[govdao] proposal %d (%s) accepted", p.Id, p.Title)
 
-			// Send notification
-			msg := fmt.Sprintf("--- \n 🗳️"+
-				"Proposal N° %d: %s  -  \n"+
-				" 🔗source: %s \n "+
-				" ACCEPTED",
-				p.Id, p.Title, p.Url)
-			if err := internal.SendInfoGovdao(chainID, msg, db); err != nil {
+			// Send notification (webhooks + govdao Telegram chats)
+			if err := internal.SendInfoGovdao(chainID, internal.GovdaoAcceptedAlert(chainID, p.Id, p.Title, p.Url), db); err != nil {
 				log.Printf("[govdao] SendInfoGovdao error: %v", err)
 			}
 
-			// Send Telegram message
-			msgT := fmt.Sprintf(
-				"🗳️ [%s] <b>✅ Proposal Nº %d</b>: %s\n"+
-					"🔗 Source: <a href=\"%s\">Gno.land</a>\n"+
-					"<b>ACCEPTED</b>\n",
-				chainID,
-				p.Id,
-				p.Title,
-				p.Url,
-			)
-			if err := telegram.MsgTelegram(msgT, internal.Config.TokenTelegramGovdao, "govdao", db); err != nil {
-				log.Printf("[govdao] MsgTelegram error: %v", err)
-			}
-
 			// update GovDao (explicit WHERE to handle id=0)
 			if err := db.Model(&database.Govdao{}).
 				Where("id = ?", p.Id).
@@ -589,6 +579,7 @@ func StartProposalWatcher(db *gorm.DB) {
 }
 
 func StartGovDAo(ctx context.Context, db *gorm.DB, chainID string, chainCfg *internal.ChainConfig) {
-	InitGovdao(db, chainID, chainCfg.GraphqlEndpoints, chainCfg.RPCEndpoint(), chainCfg.GnowebEndpoint())
-	WebsocketGovdao(ctx, db, chainID, chainCfg.GraphqlEndpoints, chainCfg.RPCEndpoint(), chainCfg.GnowebEndpoint())
+	go WatchTransactions(ctx, db, chainID, chainCfg.GraphqlEndpoints, txWatchInterval)
+	InitGovdao(db, chainID, chainCfg.GraphqlEndpoints, chainCfg.RPCEndpoint(), chainCfg.GnowebEndpoints)
+	WebsocketGovdao(ctx, db, chainID, chainCfg.GraphqlEndpoints, chainCfg.RPCEndpoint(), chainCfg.GnowebEndpoints)
 }
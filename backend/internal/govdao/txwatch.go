@@ -0,0 +1,440 @@
+package govdao
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/machinebox/graphql"
+	"github.com/samouraiworld/gnomonitoring/backend/internal"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"gorm.io/gorm"
+)
+
+const (
+	// txWatchInterval is how often WatchTransactions reads the new
+	// transactions of the indexer.
+	txWatchInterval = 30 * time.Second
+	// txWatchMaxBlocks is how many blocks WatchTransactions reads at once,
+	// so catching up after an indexer outage stays a series of small queries.
+	txWatchMaxBlocks = 200
+	// txErrorMaxLen is how much of the log of a failed transaction is shown.
+	txErrorMaxLen = 300
+	// txWatchMaxAlerts is how many transactions of one watch are notified one
+	// by one per tick; the rest are summed up in a single notification.
+	txWatchMaxAlerts = 5
+	// txWatchMaxPages is how many pages FetchTransactions reads in one call,
+	// so an indexer that keeps serving pages cannot hold a tick forever.
+	txWatchMaxPages = 50
+)
+
+// IndexedTx is a transaction served by the GraphQL indexer, with the fields
+// tx watches match on.
+type IndexedTx struct {
+	Hash        string      `json:"hash"`
+	BlockHeight int64       `json:"block_height"`
+	Index       int         `json:"index"`
+	Success     bool        `json:"success"`
+	Messages    []TxMessage `json:"messages"`
+	Response    struct {
+		Log string `json:"log"`
+	} `json:"response"`
+}
+
+// TxMessage is one message of an IndexedTx.
+type TxMessage struct {
+	TypeURL string         `json:"typeUrl"`
+	Value   TxMessageValue `json:"value"`
+}
+
+// TxMessageValue holds the fields of the message types a watch can match:
+// MsgCall, MsgAddPackage, MsgRun and BankMsgSend.
+type TxMessageValue struct {
+	Caller      string     `json:"caller"`       // MsgCall, MsgRun
+	PkgPath     string     `json:"pkg_path"`     // MsgCall
+	Func        string     `json:"func"`         // MsgCall
+	Creator     string     `json:"creator"`      // MsgAddPackage
+	Package     *TxPackage `json:"package"`      // MsgAddPackage, MsgRun
+	FromAddress string     `json:"from_address"` // BankMsgSend
+	ToAddress   string     `json:"to_address"`   // BankMsgSend
+	Amount      string     `json:"amount"`       // BankMsgSend
+}
+
+// TxPackage is the package a MsgAddPackage deploys or a MsgRun runs.
+type TxPackage struct {
+	Path string `json:"path"`
+}
+
+// touches reports whether m calls, deploys or runs target, or is sent by or
+// to it.
+func (m TxMessage) touches(target string) bool {
+	v := m.Value
+	if v.Package != nil && v.Package.Path == target {
+		return true
+	}
+	for _, f := range []string{v.PkgPath, v.Caller, v.Creator, v.FromAddress, v.ToAddress} {
+		if f == target {
+			return true
+		}
+	}
+	return false
+}
+
+// describe summarizes m in one line.
+func (m TxMessage) describe() string {
+	v := m.Value
+	switch {
+	case v.PkgPath != "":
+		return fmt.Sprintf("call %s.%s", v.PkgPath, v.Func)
+	case v.Creator != "" && v.Package != nil:
+		return "addpkg " + v.Package.Path
+	case v.Caller != "":
+		return "run"
+	case v.FromAddress != "":
+		return fmt.Sprintf("send %s to %s", v.Amount, v.ToAddress)
+	}
+	return m.TypeURL
+}
+
+// sender is the account that signed m.
+func (m TxMessage) sender() string {
+	v := m.Value
+	for _, f := range []string{v.Caller, v.Creator, v.FromAddress} {
+		if f != "" {
+			return f
+		}
+	}
+	return ""
+}
+
+// txWatchMatch is a transaction touching the target of a watch.
+type txWatchMatch struct {
+	Tx    IndexedTx
+	Watch database.TxWatch
+}
+
+// matchTxWatches returns the watches each transaction of txs touches, in
+// block order, then watch order.
+func matchTxWatches(txs []IndexedTx, watches []database.TxWatch) []txWatchMatch {
+	sort.SliceStable(txs, func(i, j int) bool {
+		if txs[i].BlockHeight != txs[j].BlockHeight {
+			return txs[i].BlockHeight < txs[j].BlockHeight
+		}
+		return txs[i].Index < txs[j].Index
+	})
+	var matches []txWatchMatch
+	for _, tx := range txs {
+		for _, w := range watches {
+			for _, m := range tx.Messages {
+				if m.touches(w.Target) {
+					matches = append(matches, txWatchMatch{Tx: tx, Watch: w})
+					break
+				}
+			}
+		}
+	}
+	return matches
+}
+
+// gnoscanTxURL is the Gnoscan page of the transaction hash.
+func gnoscanTxURL(hash string) string {
+	return fmt.Sprintf("https://gnoscan.io/transactions/details?txhash=%s", hash)
+}
+
+// txWatchAlert is the notification sent to the owner of w about tx: an INFO
+// when it succeeded, a WARNING with its error otherwise.
+func txWatchAlert(chainID string, w database.TxWatch, tx IndexedTx) internal.AlertData {
+	name := w.Label
+	if name == "" {
+		name = w.Target
+	}
+	msgs := make([]string, 0, len(tx.Messages))
+	from := ""
+	for _, m := range tx.Messages {
+		msgs = append(msgs, m.describe())
+		if from == "" {
+			from = m.sender()
+		}
+	}
+	data := internal.AlertData{
+		ChainID: chainID,
+		Level:   internal.AlertInfo,
+		Emoji:   "🔎",
+		Title:   "Transaction on " + name,
+		Fields: []internal.AlertField{
+			{Name: "watch", Value: w.Target},
+			{Name: "height", Value: fmt.Sprintf("%d", tx.BlockHeight)},
+			{Name: "messages", Value: strings.Join(msgs, "; ")},
+		},
+		Addr: "all",
+		Kind: internal.AlertKindTxWatch,
+	}
+	if from != "" {
+		data.Fields = append(data.Fields, internal.AlertField{Name: "from", Value: from})
+	}
+	if !tx.Success {
+		data.Level, data.Emoji, data.Title = internal.AlertWarning, "❌", "Failed transaction on "+name
+		errLog := strings.TrimSpace(tx.Response.Log)
+		if len(errLog) > txErrorMaxLen {
+			errLog = errLog[:txErrorMaxLen] + "..."
+		}
+		if errLog != "" {
+			data.Fields = append(data.Fields, internal.AlertField{Name: "error", Value: errLog})
+		}
+	}
+	if tx.Hash != "" {
+		data.Fields = append(data.Fields, internal.AlertField{Name: "tx", Value: tx.Hash, URL: gnoscanTxURL(tx.Hash)})
+	}
+	return data
+}
+
+// txWatchNotice is a notification for the owner of Watch.
+type txWatchNotice struct {
+	Watch database.TxWatch
+	Data  internal.AlertData
+}
+
+// txWatchAlerts returns the notifications of matches, in order: at most
+// txWatchMaxAlerts per watch, then one summing up the transactions left of
+// that watch, so a busy realm cannot flood its owner.
+func txWatchAlerts(chainID string, matches []txWatchMatch) []txWatchNotice {
+	var notices []txWatchNotice
+	sent := make(map[uint]int)
+	rest := make(map[uint][]IndexedTx)
+	var summed []database.TxWatch
+	for _, m := range matches {
+		id := m.Watch.ID
+		if sent[id] < txWatchMaxAlerts {
+			sent[id]++
+			notices = append(notices, txWatchNotice{Watch: m.Watch, Data: txWatchAlert(chainID, m.Watch, m.Tx)})
+			continue
+		}
+		if len(rest[id]) == 0 {
+			summed = append(summed, m.Watch)
+		}
+		rest[id] = append(rest[id], m.Tx)
+	}
+	for _, w := range summed {
+		notices = append(notices, txWatchNotice{Watch: w, Data: txWatchSummary(chainID, w, rest[w.ID])})
+	}
+	return notices
+}
+
+// txWatchSummary is the notification sent to the owner of w about the txs
+// left over once txWatchMaxAlerts of them were notified: how many, how many
+// failed, and the blocks they span.
+func txWatchSummary(chainID string, w database.TxWatch, txs []IndexedTx) internal.AlertData {
+	name := w.Label
+	if name == "" {
+		name = w.Target
+	}
+	failed := 0
+	for _, tx := range txs {
+		if !tx.Success {
+			failed++
+		}
+	}
+	return internal.AlertData{
+		ChainID: chainID,
+		Level:   internal.AlertInfo,
+		Emoji:   "🔎",
+		Title:   fmt.Sprintf("%d more transactions on %s", len(txs), name),
+		Fields: []internal.AlertField{
+			{Name: "watch", Value: w.Target},
+			{Name: "blocks", Value: fmt.Sprintf("%d-%d", txs[0].BlockHeight, txs[len(txs)-1].BlockHeight)},
+			{Name: "failed", Value: fmt.Sprintf("%d", failed)},
+		},
+		Addr: "all",
+		Kind: internal.AlertKindTxWatch,
+	}
+}
+
+// FetchLatestIndexedHeight returns the last block height the indexer
+// processed, asking the GraphQL endpoints in health order.
+func FetchLatestIndexedHeight(graphqlEndpoints []string) (int64, error) {
+	var lastErr error
+	for _, endpoint := range internal.OrderEndpoints(internal.EndpointGraphQL, graphqlEndpoints) {
+		client := graphql.NewClient(endpoint)
+		req := graphql.NewRequest(`query { latestBlockHeight }`)
+		var respData struct {
+			LatestBlockHeight int64 `json:"latestBlockHeight"`
+		}
+		start := time.Now()
+		err := client.Run(context.Background(), req, &respData)
+		internal.RecordEndpointCall(internal.EndpointGraphQL, endpoint, time.Since(start), err)
+		if err != nil {
+			log.Printf("[txwatch] FetchLatestIndexedHeight endpoint %s failed: %v", internal.EndpointLabel(endpoint), err)
+			lastErr = err
+			continue
+		}
+		return respData.LatestBlockHeight, nil
+	}
+	if lastErr != nil {
+		return 0, lastErr
+	}
+	return 0, fmt.Errorf("no GraphQL endpoints configured")
+}
+
+// FetchTransactions returns every transaction, successful or not, of the
+// blocks after from up to to, asking the GraphQL endpoints in health order.
+// The indexer caps how many transactions one query returns, so they are read
+// a page at a time, each page starting after the last transaction of the
+// previous one, until a page comes back empty. A page that does not move past
+// the previous one, or more than txWatchMaxPages pages, is an error.
+func FetchTransactions(from, to int64, graphqlEndpoints []string) ([]IndexedTx, error) {
+	if to <= from {
+		return nil, nil
+	}
+	var txs []IndexedTx
+	height, index := from+1, -1
+	for pages := 0; ; pages++ {
+		if pages == txWatchMaxPages {
+			return nil, fmt.Errorf("transactions of blocks %d to %d span more than %d pages", from+1, to, txWatchMaxPages)
+		}
+		page, err := fetchTransactionsPage(height, index, to, graphqlEndpoints)
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			return txs, nil
+		}
+		last := page[len(page)-1]
+		if last.BlockHeight < height || (last.BlockHeight == height && last.Index <= index) {
+			return nil, fmt.Errorf("indexer page ends at tx %d of block %d, not after tx %d of block %d",
+				last.Index, last.BlockHeight, index, height)
+		}
+		txs = append(txs, page...)
+		height, index = last.BlockHeight, last.Index
+	}
+}
+
+// fetchTransactionsPage returns, in block order, the transactions after the
+// one at index of block height, up to block to, as far as one query of the
+// indexer goes.
+func fetchTransactionsPage(height int64, index int, to int64, graphqlEndpoints []string) ([]IndexedTx, error) {
+	var lastErr error
+	for _, endpoint := range internal.OrderEndpoints(internal.EndpointGraphQL, graphqlEndpoints) {
+		client := graphql.NewClient(endpoint)
+		req := graphql.NewRequest(`
+			query getTxs($height: Int!, $index: Int!, $to: Int!) {
+				getTransactions(
+					where: {
+						_or: [
+							{ block_height: { eq: $height }, index: { gt: $index } }
+							{ block_height: { gt: $height, lt: $to } }
+						]
+					}
+					order: { heightAndIndex: ASC }
+				) {
+					hash
+					block_height
+					index
+					success
+					messages {
+						typeUrl
+						value {
+							... on MsgCall { caller pkg_path func }
+							... on MsgAddPackage { creator package { path } }
+							... on MsgRun { caller package { path } }
+							... on BankMsgSend { from_address to_address amount }
+						}
+					}
+					response { log }
+				}
+			}
+		`)
+		req.Var("height", height)
+		req.Var("index", index)
+		req.Var("to", to+1)
+
+		var respData struct {
+			GetTransactions []IndexedTx `json:"getTransactions"`
+		}
+		start := time.Now()
+		err := client.Run(context.Background(), req, &respData)
+		internal.RecordEndpointCall(internal.EndpointGraphQL, endpoint, time.Since(start), err)
+		if err != nil {
+			log.Printf("[txwatch] FetchTransactions endpoint %s failed: %v", internal.EndpointLabel(endpoint), err)
+			lastErr = err
+			continue
+		}
+		return respData.GetTransactions, nil
+	}
+	if lastErr != nil {
+		return nil, lastErr
+	}
+	return nil, fmt.Errorf("no GraphQL endpoints configured")
+}
+
+// WatchTransactions follows the transactions the indexer of chainID serves,
+// every checkInterval, and notifies the owner of every tx watch a transaction
+// touches, through SendWatchAlert; past txWatchMaxAlerts transactions of a
+// watch in one tick, the rest come as a single summary. The last height read
+// is stored in tx_watch_cursors, so a restart resumes from it; the first
+// start, and a cursor ahead of the indexer (a chain reset), begin at the
+// indexer head. Blocks are read txWatchMaxBlocks at a time; a failed read is
+// retried from the same height on the next tick.
+func WatchTransactions(ctx context.Context, db *gorm.DB, chainID string, graphqlEndpoints []string, checkInterval time.Duration) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[txwatch][%s] panic recovered: %v", chainID, r)
+		}
+	}()
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	last, err := database.GetTxWatchCursor(db, chainID)
+	if err != nil {
+		log.Printf("[txwatch][%s] %v", chainID, err)
+	}
+	advance := func(height int64) {
+		last = height
+		if err := database.SetTxWatchCursor(db, chainID, height); err != nil {
+			log.Printf("[txwatch][%s] %v", chainID, err)
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		head, err := FetchLatestIndexedHeight(graphqlEndpoints)
+		if err != nil {
+			log.Printf("[txwatch][%s] %v", chainID, err)
+			continue
+		}
+		if last == 0 || head < last {
+			advance(head)
+			continue
+		}
+		if head == last {
+			continue
+		}
+		watches, err := database.TxWatchesForChain(db, chainID)
+		if err != nil {
+			log.Printf("[txwatch][%s] %v", chainID, err)
+			continue
+		}
+		if len(watches) == 0 {
+			advance(head)
+			continue
+		}
+		to := min(head, last+txWatchMaxBlocks)
+		txs, err := FetchTransactions(last, to, graphqlEndpoints)
+		if err != nil {
+			log.Printf("[txwatch][%s] blocks %d-%d: %v", chainID, last+1, to, err)
+			continue
+		}
+		for _, n := range txWatchAlerts(chainID, matchTxWatches(txs, watches)) {
+			log.Printf("%s [%s] %s", n.Data.Emoji, chainID, n.Data.Title)
+			if err := internal.SendWatchAlert(n.Watch.CreatedBy, chainID, n.Data, db); err != nil {
+				log.Printf("[txwatch][%s] SendWatchAlert error: %v", chainID, err)
+			}
+		}
+		advance(to)
+	}
+}
@@ -0,0 +1,218 @@
+package govdao
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func callMsg(caller, pkgPath, fn string) TxMessage {
+	return TxMessage{TypeURL: "exec", Value: TxMessageValue{Caller: caller, PkgPath: pkgPath, Func: fn}}
+}
+
+func TestTxMessageTouches(t *testing.T) {
+	call := callMsg("g1caller", "gno.land/r/gov/dao", "MustVoteOnProposal")
+	assert.True(t, call.touches("gno.land/r/gov/dao"))
+	assert.True(t, call.touches("g1caller"))
+	assert.False(t, call.touches("gno.land/r/gov"), "exact path only")
+	assert.Equal(t, "call gno.land/r/gov/dao.MustVoteOnProposal", call.describe())
+	assert.Equal(t, "g1caller", call.sender())
+
+	add := TxMessage{TypeURL: "add_package", Value: TxMessageValue{Creator: "g1dev", Package: &TxPackage{Path: "gno.land/r/dev/app"}}}
+	assert.True(t, add.touches("gno.land/r/dev/app"))
+	assert.Equal(t, "addpkg gno.land/r/dev/app", add.describe())
+
+	run := TxMessage{TypeURL: "run", Value: TxMessageValue{Caller: "g1dev", Package: &TxPackage{Path: "gno.land/r/g1dev/run"}}}
+	assert.False(t, run.touches("gno.land/r/gov/dao"), "realms called by a script are not visible")
+	assert.Equal(t, "run", run.describe())
+
+	send := TxMessage{TypeURL: "send", Value: TxMessageValue{FromAddress: "g1a", ToAddress: "g1b", Amount: "10ugnot"}}
+	assert.True(t, send.touches("g1b"))
+	assert.Equal(t, "send 10ugnot to g1b", send.describe())
+	assert.Equal(t, "g1a", send.sender())
+}
+
+func TestMatchTxWatches(t *testing.T) {
+	dao := database.TxWatch{ID: 1, Target: "gno.land/r/gov/dao", CreatedBy: "u1"}
+	user := database.TxWatch{ID: 2, Target: "g1caller", CreatedBy: "telegram:42"}
+	txs := []IndexedTx{
+		{Hash: "c", BlockHeight: 12, Messages: []TxMessage{callMsg("g1other", "gno.land/r/gov/dao", "Vote")}},
+		{Hash: "b", BlockHeight: 11, Index: 1, Messages: []TxMessage{
+			callMsg("g1caller", "gno.land/r/gov/dao", "Vote"),
+			callMsg("g1caller", "gno.land/r/gov/dao", "Vote"),
+		}},
+		{Hash: "a", BlockHeight: 11, Messages: []TxMessage{callMsg("g1other", "gno.land/r/demo/boards", "Post")}},
+	}
+
+	var got []string
+	for _, m := range matchTxWatches(txs, []database.TxWatch{dao, user}) {
+		got = append(got, m.Tx.Hash+"/"+m.Watch.CreatedBy)
+	}
+	assert.Equal(t, []string{"b/u1", "b/telegram:42", "c/u1"}, got, "one match per tx and watch, in block order")
+}
+
+func TestTxWatchAlert(t *testing.T) {
+	w := database.TxWatch{Target: "gno.land/r/gov/dao", Label: "dao"}
+	tx := IndexedTx{Hash: "abc", BlockHeight: 42, Success: true, Messages: []TxMessage{callMsg("g1caller", "gno.land/r/gov/dao", "Vote")}}
+
+	data := txWatchAlert("test12", w, tx)
+	assert.Equal(t, internal.AlertInfo, data.Level)
+	assert.Equal(t, internal.AlertKindTxWatch, data.Kind)
+	assert.Equal(t, "Transaction on dao", data.Title)
+	fields := make(map[string]internal.AlertField)
+	for _, f := range data.Fields {
+		fields[f.Name] = f
+	}
+	assert.Equal(t, "42", fields["height"].Value)
+	assert.Equal(t, "g1caller", fields["from"].Value)
+	assert.Equal(t, gnoscanTxURL("abc"), fields["tx"].URL)
+	assert.NotContains(t, fields, "error")
+
+	tx.Success = false
+	tx.Response.Log = strings.Repeat("x", txErrorMaxLen+50)
+	w.Label = ""
+	data = txWatchAlert("test12", w, tx)
+	assert.Equal(t, internal.AlertWarning, data.Level)
+	assert.Equal(t, "Failed transaction on gno.land/r/gov/dao", data.Title)
+	var errField string
+	for _, f := range data.Fields {
+		if f.Name == "error" {
+			errField = f.Value
+		}
+	}
+	assert.Len(t, errField, txErrorMaxLen+3, "long logs are truncated")
+}
+
+func TestFetchTransactions(t *testing.T) {
+	const pageSize = 2
+	indexed := []string{
+		`{"hash":"h0","block_height":1200,"index":0,"success":true}`,
+		`{"hash":"h1","block_height":1201,"index":0,"success":false,
+			"messages":[{"typeUrl":"exec","value":{"caller":"g1a","pkg_path":"gno.land/r/gov/dao","func":"Vote"}}],
+			"response":{"log":"unauthorized"}}`,
+		`{"hash":"h2","block_height":1201,"index":1,"success":true}`,
+		`{"hash":"h3","block_height":1201,"index":2,"success":true}`,
+		`{"hash":"h4","block_height":1230,"index":0,"success":true}`,
+		`{"hash":"h5","block_height":1235,"index":0,"success":true}`,
+	}
+	var pages int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query     string `json:"query"`
+			Variables struct {
+				Height int64 `json:"height"`
+				Index  int   `json:"index"`
+				To     int64 `json:"to"`
+			} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(body.Query, "latestBlockHeight") {
+			w.Write([]byte(`{"data":{"latestBlockHeight":1234}}`))
+			return
+		}
+		pages++
+		v := body.Variables
+		var page []string
+		for _, raw := range indexed {
+			var tx IndexedTx
+			require.NoError(t, json.Unmarshal([]byte(raw), &tx))
+			after := tx.BlockHeight == v.Height && tx.Index > v.Index || tx.BlockHeight > v.Height && tx.BlockHeight < v.To
+			if after && len(page) < pageSize {
+				page = append(page, raw)
+			}
+		}
+		w.Write([]byte(`{"data":{"getTransactions":[` + strings.Join(page, ",") + `]}}`))
+	}))
+	defer srv.Close()
+
+	head, err := FetchLatestIndexedHeight([]string{srv.URL})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1234), head)
+
+	txs, err := FetchTransactions(1200, 1234, []string{srv.URL})
+	require.NoError(t, err)
+	var hashes []string
+	for _, tx := range txs {
+		hashes = append(hashes, tx.Hash)
+	}
+	assert.Equal(t, []string{"h1", "h2", "h3", "h4"}, hashes, "blocks after from up to to, read across pages")
+	assert.Equal(t, 3, pages, "two full pages, then an empty one")
+	assert.False(t, txs[0].Success)
+	assert.Equal(t, "unauthorized", txs[0].Response.Log)
+	assert.True(t, txs[0].Messages[0].touches("gno.land/r/gov/dao"))
+
+	_, err = FetchTransactions(0, 1, nil)
+	assert.Error(t, err)
+}
+
+func TestFetchTransactions_Stuck(t *testing.T) {
+	var pages int
+	serve := func(page string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			pages++
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"data":{"getTransactions":[` + page + `]}}`))
+		}))
+	}
+
+	// An indexer ignoring the cursor serves the same page again.
+	srv := serve(`{"hash":"h1","block_height":1201,"index":0,"success":true}`)
+	defer srv.Close()
+	_, err := FetchTransactions(1200, 1234, []string{srv.URL})
+	assert.Error(t, err)
+	assert.Equal(t, 2, pages, "the repeated page is refused")
+
+	// An indexer serving pages past to never runs dry.
+	pages = 0
+	var height int64 = 1200
+	endless := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		height++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"data":{"getTransactions":[{"hash":"h","block_height":%d,"index":0,"success":true}]}}`, height)
+	}))
+	defer endless.Close()
+	_, err = FetchTransactions(1200, 1234, []string{endless.URL})
+	assert.Error(t, err)
+	assert.Equal(t, txWatchMaxPages, pages)
+}
+
+func TestTxWatchAlerts(t *testing.T) {
+	busy := database.TxWatch{ID: 1, Target: "gno.land/r/gov/dao", Label: "dao", CreatedBy: "u1"}
+	quiet := database.TxWatch{ID: 2, Target: "g1caller", CreatedBy: "u2"}
+	var matches []txWatchMatch
+	for i := 0; i < txWatchMaxAlerts+3; i++ {
+		tx := IndexedTx{Hash: fmt.Sprintf("h%d", i), BlockHeight: int64(100 + i), Success: i != txWatchMaxAlerts+1}
+		matches = append(matches, txWatchMatch{Tx: tx, Watch: busy})
+	}
+	matches = append(matches, txWatchMatch{Tx: IndexedTx{Hash: "q", BlockHeight: 120, Success: true}, Watch: quiet})
+
+	notices := txWatchAlerts("test12", matches)
+	require.Len(t, notices, txWatchMaxAlerts+2, "the busy watch is capped, then summed up")
+	var titles []string
+	for _, n := range notices {
+		titles = append(titles, n.Data.Title)
+	}
+	assert.Equal(t, "Transaction on g1caller", titles[txWatchMaxAlerts])
+	summary := notices[len(notices)-1]
+	assert.Equal(t, busy.ID, summary.Watch.ID)
+	assert.Equal(t, "3 more transactions on dao", summary.Data.Title)
+	assert.Equal(t, internal.AlertKindTxWatch, summary.Data.Kind)
+	fields := make(map[string]string)
+	for _, f := range summary.Data.Fields {
+		fields[f.Name] = f.Value
+	}
+	assert.Equal(t, "105-107", fields["blocks"])
+	assert.Equal(t, "1", fields["failed"])
+
+	assert.Empty(t, txWatchAlerts("test12", nil))
+}
@@ -0,0 +1,346 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/telegram"
+	"gorm.io/gorm"
+)
+
+// Notifier turns a channel-neutral AlertData into one channel's wire payload
+// and delivers that payload to a single Destination. Every alert path
+// (validator, resolve, chain info, user report, GovDAO) goes through the
+// registered notifiers, so a new channel only has to implement this
+// interface and register itself under its webhook type.
+type Notifier interface {
+	// Render builds the channel payload for d (the HTTP body for webhook
+	// channels, the HTML message text for Telegram).
+	Render(d AlertData) ([]byte, error)
+	// Send delivers a payload previously produced by Render.
+	Send(payload []byte, dest Destination) error
+}
+
+// Destination is one concrete recipient of a notification: a webhook row
+// (WebhookValidator / WebhookGovDAO) or a Telegram chat.
+type Destination struct {
+	Type      string // notifier key, e.g. "discord", "slack", "telegram"
+	WebhookID int    // webhook row ID, 0 for Telegram chats
+	UserID    string // webhook owner, "" for Telegram chats
+	URL       string // webhook URL, "" for Telegram chats
+	ChatID    int64  // Telegram chat / Discord channel ID, 0 for webhooks
+	Token     string // Telegram / Discord / Slack bot token, "" for webhooks
+	Secret    string // HMAC signing key / incident integration key
+	Table     string // webhook table ("webhook_validators", "webhook_gov_daos")
+	Bot       string // chat bot ("validator", "govdao", "discord", "slack")
+	Email     string // recipient address of "email" destinations
+	Channel   string // Slack channel ID of "slack_bot" destinations
+
+	// Filter is the validator webhook's routing filter; the zero value
+	// (every other destination) accepts all alerts.
+	Filter database.WebhookFilter
+}
+
+// String identifies the destination in logs without needing the caller to
+// know which kind it is.
+func (d Destination) String() string {
+	switch d.Type {
+	case "telegram":
+		return fmt.Sprintf("telegram chat_id=%d", d.ChatID)
+	case "discord_bot":
+		return fmt.Sprintf("discord channel_id=%d", d.ChatID)
+	case "slack_bot":
+		return "slack channel=" + d.Channel
+	case "email":
+		return fmt.Sprintf("email user=%s", d.UserID)
+	}
+	return fmt.Sprintf("%s (%s)", d.URL, d.Type)
+}
+
+// ErrNotApplicable is returned by Render when a notifier has nothing to
+// send for an alert (e.g. an incident channel given a WARNING). Deliver
+// skips the destination silently.
+var ErrNotApplicable = errors.New("notifier: alert not applicable to this channel")
+
+var (
+	notifiersMu sync.RWMutex
+	notifiers   = map[string]Notifier{}
+)
+
+// RegisterNotifier makes n the notifier for destinations of type kind,
+// replacing any notifier previously registered under that name. Channel
+// implementations call it from their init function.
+func RegisterNotifier(kind string, n Notifier) {
+	notifiersMu.Lock()
+	defer notifiersMu.Unlock()
+	notifiers[kind] = n
+}
+
+// NotifierFor returns the notifier registered for kind.
+func NotifierFor(kind string) (Notifier, bool) {
+	notifiersMu.RLock()
+	defer notifiersMu.RUnlock()
+	n, ok := notifiers[kind]
+	return n, ok
+}
+
+// NotifierTypes returns the sorted list of registered notifier kinds.
+func NotifierTypes() []string {
+	notifiersMu.RLock()
+	defer notifiersMu.RUnlock()
+	kinds := make([]string, 0, len(notifiers))
+	for k := range notifiers {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// postJSON posts body to url through the SSRF-guarded alertHTTPClient and
+// turns a non-2xx answer into a *DeliveryError tagged with label.
+func postJSON(label, url string, body []byte) error {
+	resp, err := alertHTTPClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error sending %s: %w", label, err)
+	}
+	defer resp.Body.Close()
+
+	return checkDeliveryStatus(label, resp)
+}
+
+// Deliver renders and sends one notification per destination. dataFor
+// returns the AlertData for a given destination, which lets callers attach
+// per-webhook details (e.g. AlertContact mentions) without re-rendering
+// for every other destination by hand. Failures are logged and joined into
+// the returned error; one failing destination never blocks the others.
+func Deliver(dests []Destination, dataFor func(Destination) AlertData) error {
+	var errs []error
+	for _, dest := range dests {
+		n, ok := NotifierFor(dest.Type)
+		if !ok {
+			log.Printf("⚠️ Unknown webhook type for user %s: %s", dest.UserID, dest.Type)
+			continue
+		}
+		data := dataFor(dest)
+		if !webhookAccepts(dest.Filter, data) {
+			continue
+		}
+		payload, err := n.Render(data)
+		if errors.Is(err, ErrNotApplicable) {
+			continue
+		}
+		if err != nil {
+			log.Printf("❌ Failed to render alert for %s: %v", dest, err)
+			errs = append(errs, err)
+			continue
+		}
+		if err := n.Send(payload, dest); err != nil {
+			log.Printf("❌ Failed to send alert to %s: %v", dest, err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// same returns a dataFor callback that hands every destination d unchanged.
+func same(d AlertData) func(Destination) AlertData {
+	return func(Destination) AlertData { return d }
+}
+
+// ====== Destination lookups ======
+
+// validatorWebhookDestinations returns the validator webhooks scoped to
+// chainID plus the unscoped (all-chains) ones.
+func validatorWebhookDestinations(db *gorm.DB, chainID string) ([]Destination, error) {
+	var webhooks []database.WebhookValidator
+	if err := db.Where("chain_id = ? OR chain_id IS NULL", chainID).
+		Find(&webhooks).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch webhooks: %w", err)
+	}
+	dests := make([]Destination, 0, len(webhooks))
+	for _, wh := range webhooks {
+		dests = append(dests, ValidatorWebhookDestination(wh))
+	}
+	return dests, nil
+}
+
+// ValidatorWebhookDestination converts a validator webhook row to a Destination.
+func ValidatorWebhookDestination(wh database.WebhookValidator) Destination {
+	dest := Destination{Type: wh.Type, WebhookID: wh.ID, UserID: wh.UserID, URL: wh.URL, Table: "webhook_validators", Filter: wh.Filter}
+	if wh.Secret != nil {
+		dest.Secret = *wh.Secret
+	}
+	return dest
+}
+
+// escalationDestinations returns the validator webhooks of userID listed in
+// webhookIDs or used by the alert contacts listed in contactIDs, and the
+// mention tags of those contacts keyed by webhook ID. IDs that are not
+// userID's are ignored.
+func escalationDestinations(db *gorm.DB, userID string, webhookIDs, contactIDs []int) ([]Destination, map[int][]string, error) {
+	mentions := map[int][]string{}
+	ids := append([]int(nil), webhookIDs...)
+	if len(contactIDs) > 0 {
+		var contacts []database.AlertContact
+		if err := db.Where("id IN ? AND user_id = ?", contactIDs, userID).
+			Find(&contacts).Error; err != nil {
+			return nil, nil, fmt.Errorf("failed to fetch alert contacts: %w", err)
+		}
+		for _, c := range contacts {
+			if c.IDwebhook == 0 {
+				continue
+			}
+			ids = append(ids, c.IDwebhook)
+			if c.MentionTag != "" {
+				mentions[c.IDwebhook] = append(mentions[c.IDwebhook], c.MentionTag)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil, mentions, nil
+	}
+
+	var webhooks []database.WebhookValidator
+	if err := db.Where("id IN ? AND user_id = ?", ids, userID).
+		Find(&webhooks).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch webhooks: %w", err)
+	}
+	dests := make([]Destination, 0, len(webhooks))
+	for _, wh := range webhooks {
+		dests = append(dests, ValidatorWebhookDestination(wh))
+	}
+	return dests, mentions, nil
+}
+
+// GovdaoWebhookDestination converts a GovDAO webhook row to a Destination.
+func GovdaoWebhookDestination(wh database.WebhookGovDAO) Destination {
+	dest := Destination{Type: wh.Type, WebhookID: wh.ID, UserID: wh.UserID, URL: wh.URL, Table: "webhook_gov_daos"}
+	if wh.Secret != nil {
+		dest.Secret = *wh.Secret
+	}
+	return dest
+}
+
+// govdaoWebhookDestinations returns every GovDAO webhook.
+func govdaoWebhookDestinations(db *gorm.DB) ([]Destination, error) {
+	var webhooks []database.WebhookGovDAO
+	if err := db.Find(&webhooks).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch webhooks: %w", err)
+	}
+	dests := make([]Destination, 0, len(webhooks))
+	for _, wh := range webhooks {
+		dests = append(dests, GovdaoWebhookDestination(wh))
+	}
+	return dests, nil
+}
+
+// telegramDestinations wraps chat IDs as Telegram destinations for bot
+// ("validator" or "govdao"), whose token is token. An empty token yields no
+// destinations, matching the "token is empty" guard of the MsgTelegram*
+// helpers.
+func telegramDestinations(bot, token string, chatIDs []int64) []Destination {
+	if token == "" {
+		return nil
+	}
+	dests := make([]Destination, 0, len(chatIDs))
+	for _, id := range chatIDs {
+		dests = append(dests, Destination{Type: "telegram", ChatID: id, Token: token, Bot: bot})
+	}
+	return dests
+}
+
+// validatorAlertChats returns the validator-bot chats subscribed to addr on
+// chainID.
+func validatorAlertChats(db *gorm.DB, chainID, addr string) []Destination {
+	ids, err := telegram.AlertChatIDs(db, "validator", chainID, addr)
+	if err != nil {
+		log.Printf("❌ AlertChatIDs: %v", err)
+		return nil
+	}
+	return telegramDestinations("validator", Config.TokenTelegramValidator, ids)
+}
+
+// emailAlertDestinations returns the users who opted in to alert emails for
+// chainID (see database.ListEmailAlertUsers) and want d: a user with
+// validator webhooks on chainID only gets the alerts one of their webhook
+// filters accepts, a user without any gets them all. Without an smtp config
+// there are none, matching telegramDestinations' empty-token guard.
+func emailAlertDestinations(db *gorm.DB, chainID string, d AlertData) []Destination {
+	if !Config.SMTP.Enabled() {
+		return nil
+	}
+	users, err := database.ListEmailAlertUsers(db, chainID, Config.DefaultChain)
+	if err != nil {
+		log.Printf("❌ ListEmailAlertUsers failed (chain=%s): %v", chainID, err)
+		return nil
+	}
+	if len(users) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.UserID)
+	}
+	var webhooks []database.WebhookValidator
+	if err := db.Where("user_id IN ? AND (chain_id = ? OR chain_id IS NULL)", ids, chainID).
+		Find(&webhooks).Error; err != nil {
+		log.Printf("❌ email alert filters failed (chain=%s): %v", chainID, err)
+		return nil
+	}
+	filters := map[string][]database.WebhookFilter{}
+	for _, wh := range webhooks {
+		filters[wh.UserID] = append(filters[wh.UserID], wh.Filter)
+	}
+
+	dests := make([]Destination, 0, len(users))
+	for _, u := range users {
+		wanted := len(filters[u.UserID]) == 0
+		for _, f := range filters[u.UserID] {
+			if webhookAccepts(f, d) {
+				wanted = true
+				break
+			}
+		}
+		if wanted {
+			dests = append(dests, Destination{Type: "email", UserID: u.UserID, Email: u.Email})
+		}
+	}
+	return dests
+}
+
+// validatorChainChats returns the validator-bot chats following chainID.
+func validatorChainChats(db *gorm.DB, chainID string) []Destination {
+	ids, err := database.GetChatIDsForChain(db, "validator", chainID)
+	if err != nil {
+		log.Printf("❌ GetChatIDsForChain failed (chain=%s): %v", chainID, err)
+		return nil
+	}
+	return telegramDestinations("validator", Config.TokenTelegramValidator, ids)
+}
+
+// govdaoChats returns every GovDAO-bot chat.
+func govdaoChats(db *gorm.DB) []Destination {
+	ids, err := database.GetAllChatIDs(db, "govdao")
+	if err != nil {
+		log.Printf("❌ GetAllChatIDs failed: %v", err)
+		return nil
+	}
+	return telegramDestinations("govdao", Config.TokenTelegramGovdao, ids)
+}
+
+// alertMentions returns the AlertContact mention tags dest's owner attached
+// to moniker for that webhook.
+func alertMentions(db *gorm.DB, dest Destination, moniker string) ([]string, error) {
+	var tags []string
+	if err := db.Model(&database.AlertContact{}).
+		Where("user_id = ? AND moniker = ? AND id_webhook = ?", dest.UserID, moniker, dest.WebhookID).
+		Pluck("mention_tag", &tags).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch mentions: %w", err)
+	}
+	return tags, nil
+}
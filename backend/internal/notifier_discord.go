@@ -0,0 +1,29 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// discordNotifier posts AlertData as a Discord embed, with any AlertContact
+// mentions carried in the message content (see RenderAlertDiscordEmbed).
+type discordNotifier struct{}
+
+func init() { RegisterNotifier("discord", discordNotifier{}) }
+
+func (discordNotifier) Render(d AlertData) ([]byte, error) {
+	content, embed := RenderAlertDiscordEmbed(d)
+	payload := map[string]any{"embeds": []DiscordEmbed{embed}}
+	if content != "" {
+		payload["content"] = content
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal discord alert embed: %w", err)
+	}
+	return body, nil
+}
+
+func (discordNotifier) Send(payload []byte, dest Destination) error {
+	return postJSON("discord webhook", dest.URL, payload)
+}
@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/discord"
+	"gorm.io/gorm"
+)
+
+// discordBotNotifier posts AlertData in a Discord channel as the validator
+// bot (see DiscordBotConfig), with an Acknowledge button on alerts that can
+// be acknowledged. Which channels receive an alert is decided by their
+// /subscribe and /setchain state, like the Telegram chats.
+type discordBotNotifier struct{}
+
+func init() { RegisterNotifier("discord_bot", discordBotNotifier{}) }
+
+func (discordBotNotifier) Render(d AlertData) ([]byte, error) {
+	_, embed := RenderAlertDiscordEmbed(d)
+	payload := map[string]any{"embeds": []DiscordEmbed{embed}}
+	if d.Acknowledgeable() {
+		payload["components"] = discord.AckButtons(d.AlertLogID)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal discord bot alert: %w", err)
+	}
+	return body, nil
+}
+
+func (discordBotNotifier) Send(payload []byte, dest Destination) error {
+	return discordAPIError(discord.SendChannelMessage(dest.Token, dest.ChatID, payload))
+}
+
+// SendDiscordChannelEmbed posts embed in a Discord channel as the validator
+// bot (daily reports).
+func SendDiscordChannelEmbed(channelID int64, embed DiscordEmbed) error {
+	body, err := json.Marshal(map[string]any{"embeds": []DiscordEmbed{embed}})
+	if err != nil {
+		return fmt.Errorf("marshal discord embed: %w", err)
+	}
+	return discordAPIError(discord.SendChannelMessage(Config.DiscordBot.Token, channelID, body))
+}
+
+// discordAPIError maps a failed Discord REST call to a *DeliveryError so
+// the outbox retries or drops it like a webhook failure.
+func discordAPIError(err error) error {
+	var apiErr *discord.APIError
+	if errors.As(err, &apiErr) {
+		return &DeliveryError{
+			Label:      "discord bot",
+			StatusCode: apiErr.StatusCode,
+			Detail:     apiErr.Message,
+			RetryAfter: apiErr.RetryAfter,
+		}
+	}
+	return err
+}
+
+// discordBotDestinations wraps channel IDs as Discord bot destinations. No
+// bot token yields no destinations, like telegramDestinations.
+func discordBotDestinations(channelIDs []int64) []Destination {
+	token := Config.DiscordBot.Token
+	if token == "" {
+		return nil
+	}
+	dests := make([]Destination, 0, len(channelIDs))
+	for _, id := range channelIDs {
+		dests = append(dests, Destination{Type: "discord_bot", ChatID: id, Token: token, Bot: "discord"})
+	}
+	return dests
+}
+
+// validatorAlertChannels returns the Discord channels subscribed to addr on
+// chainID.
+func validatorAlertChannels(db *gorm.DB, chainID, addr string) []Destination {
+	if Config.DiscordBot.Token == "" {
+		return nil
+	}
+	ids, err := database.GetDiscordAlertChannelIDs(db, chainID, addr)
+	if err != nil {
+		log.Printf("❌ GetDiscordAlertChannelIDs failed (chain=%s): %v", chainID, err)
+		return nil
+	}
+	return discordBotDestinations(ids)
+}
+
+// validatorChainChannels returns the Discord channels following chainID.
+func validatorChainChannels(db *gorm.DB, chainID string) []Destination {
+	if Config.DiscordBot.Token == "" {
+		return nil
+	}
+	ids, err := database.GetDiscordChannelIDsForChain(db, chainID)
+	if err != nil {
+		log.Printf("❌ GetDiscordChannelIDsForChain failed (chain=%s): %v", chainID, err)
+		return nil
+	}
+	return discordBotDestinations(ids)
+}
@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/discord"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscordBotNotifier_RenderAddsAckButton(t *testing.T) {
+	n := discordBotNotifier{}
+	d := AlertData{ChainID: "test12", Level: AlertCritical, Emoji: "🚨", Title: "CRITICAL", Mentions: []string{"111"}, AlertLogID: 7}
+
+	payload, err := n.Render(d)
+	require.NoError(t, err)
+	var msg struct {
+		Content    string              `json:"content"`
+		Embeds     []DiscordEmbed      `json:"embeds"`
+		Components []discord.Component `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(payload, &msg))
+	require.Len(t, msg.Embeds, 1)
+	assert.Contains(t, msg.Embeds[0].Title, "CRITICAL")
+	assert.Empty(t, msg.Content, "webhook mention tags do not apply to bot channels")
+	require.Len(t, msg.Components, 1)
+	assert.Equal(t, "c=ack&id=7", msg.Components[0].Components[0].CustomID)
+
+	d.Level, d.Title = AlertResolved, "RESOLVED"
+	payload, err = n.Render(d)
+	require.NoError(t, err)
+	assert.NotContains(t, string(payload), "components", "RESOLVED alerts cannot be acknowledged")
+}
+
+func TestDiscordBotDestinations(t *testing.T) {
+	orig := Config.DiscordBot
+	defer func() { Config.DiscordBot = orig }()
+
+	Config.DiscordBot.Token = ""
+	assert.Empty(t, discordBotDestinations([]int64{1}))
+
+	Config.DiscordBot.Token = "bot-token"
+	dests := discordBotDestinations([]int64{1, 2})
+	require.Len(t, dests, 2)
+	assert.Equal(t, Destination{Type: "discord_bot", ChatID: 2, Token: "bot-token", Bot: "discord"}, dests[1])
+	assert.Equal(t, "discord_bot/2", deliveryDestKey(dests[1]))
+}
+
+func TestDiscordAPIError_MapsToDeliveryError(t *testing.T) {
+	err := discordAPIError(&discord.APIError{StatusCode: http.StatusForbidden, Message: "Missing Access"})
+	var de *DeliveryError
+	require.True(t, errors.As(err, &de))
+	assert.True(t, de.Permanent(), "a channel the bot cannot post in is not retried")
+
+	err = discordAPIError(&discord.APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: 3 * time.Second})
+	require.True(t, errors.As(err, &de))
+	assert.False(t, de.Permanent())
+	assert.Equal(t, 3*time.Second, de.RetryAfter)
+}
@@ -0,0 +1,286 @@
+package internal
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"gorm.io/gorm"
+)
+
+// emailNotifier mails AlertData to a user who opted in to alert emails
+// (database.User.EmailAlerts) through the smtp server of the config. The
+// payload is the rendered EmailMessage; the address is looked up at send
+// time.
+type emailNotifier struct{}
+
+func init() { RegisterNotifier("email", emailNotifier{}) }
+
+// EmailMessage is a rendered email: a subject and the same content as plain
+// text and HTML, sent as multipart/alternative.
+type EmailMessage struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+}
+
+var alertEmailText = texttemplate.Must(texttemplate.New("alert").Parse(
+	`[{{.ChainID}}] {{.Emoji}} {{.Title}}
+{{if .Description}}
+{{.Description}}
+{{end}}
+{{range .Fields}}{{.Name}}: {{.Value}}{{if .URL}} <{{.URL}}>{{end}}
+{{end}}{{if .Date}}
+{{.Date}}
+{{end}}{{if .Acknowledgeable}}
+Alert ID {{.AlertLogID}}: acknowledge it with POST /alerts/{{.AlertLogID}}/ack or from Telegram to stop resends.
+{{end}}`))
+
+var alertEmailHTML = htmltemplate.Must(htmltemplate.New("alert").Parse(
+	`<!DOCTYPE html>
+<html><body style="font-family:Arial,Helvetica,sans-serif;color:#222">
+<div style="border-left:6px solid {{.Color}};padding:8px 16px">
+<h2 style="margin:0 0 8px">[{{.ChainID}}] {{.Emoji}} {{.Title}}</h2>
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+{{if .Fields}}<table style="border-collapse:collapse">
+{{range .Fields}}<tr><td style="padding:2px 12px 2px 0;font-weight:bold">{{.Name}}</td><td style="padding:2px 0">{{if .URL}}<a href="{{.URL}}">{{.Value}}</a>{{else if .Code}}<code>{{.Value}}</code>{{else}}{{.Value}}{{end}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Date}}<p style="color:#888">{{.Date}}</p>{{end}}
+{{if .Acknowledgeable}}<p style="color:#888">Alert ID {{.AlertLogID}}: acknowledge it with <code>POST /alerts/{{.AlertLogID}}/ack</code> or from Telegram to stop resends.</p>{{end}}
+</div>
+</body></html>
+`))
+
+// emailField is an AlertField as the HTML template shows it: addresses in
+// <code>, like the Telegram renderer.
+type emailField struct {
+	AlertField
+	Code bool
+}
+
+// RenderAlertEmail formats d as an email. The subject names the validator
+// when there is one, so alerts about different validators do not thread
+// together in mail clients.
+func RenderAlertEmail(d AlertData) (EmailMessage, error) {
+	subject := fmt.Sprintf("[%s] %s %s", d.ChainID, d.Emoji, d.Title)
+	if d.Moniker != "" && !strings.Contains(d.Title, d.Moniker) {
+		subject += " — " + d.Moniker
+	}
+
+	var text bytes.Buffer
+	if err := alertEmailText.Execute(&text, d); err != nil {
+		return EmailMessage{}, fmt.Errorf("render alert email text: %w", err)
+	}
+
+	fields := make([]emailField, len(d.Fields))
+	for i, f := range d.Fields {
+		fields[i] = emailField{AlertField: f, Code: strings.Contains(strings.ToLower(f.Name), "addr")}
+	}
+	var body bytes.Buffer
+	err := alertEmailHTML.Execute(&body, struct {
+		AlertData
+		Fields          []emailField
+		Color           string
+		Acknowledgeable bool
+	}{d, fields, fmt.Sprintf("#%06X", alertColor(d.Level)), d.Acknowledgeable()})
+	if err != nil {
+		return EmailMessage{}, fmt.Errorf("render alert email html: %w", err)
+	}
+
+	return EmailMessage{Subject: strings.Join(strings.Fields(subject), " "), Text: text.String(), HTML: body.String()}, nil
+}
+
+func (emailNotifier) Render(d AlertData) ([]byte, error) {
+	m, err := RenderAlertEmail(d)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(m)
+}
+
+func (emailNotifier) Send(payload []byte, dest Destination) error {
+	var m EmailMessage
+	if err := json.Unmarshal(payload, &m); err != nil {
+		return fmt.Errorf("decode email payload: %w", err)
+	}
+	return SendEmail(Config.SMTP, dest.Email, m)
+}
+
+// EnqueueEmail queues the already-rendered m for userID in the
+// alert_deliveries outbox (reports, which do not go through Notifier.Render).
+// The delivery worker looks the address up when it sends it.
+func EnqueueEmail(db *gorm.DB, chainID, userID string, m EmailMessage) error {
+	payload, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("marshal email: %w", err)
+	}
+	dest := Destination{Type: "email", UserID: userID}
+	err = database.EnqueueAlertDeliveries(db, []database.AlertDelivery{{
+		ChainID:  chainID,
+		DestKey:  deliveryDestKey(dest),
+		DestType: dest.Type,
+		UserID:   userID,
+		Title:    m.Subject,
+		Payload:  string(payload),
+	}})
+	if err != nil {
+		return err
+	}
+	select {
+	case deliveryWake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// PlainTextEmail wraps a plain-text body (the stuck/disabled chain reports)
+// as an email, the HTML part showing it preformatted.
+func PlainTextEmail(subject, text string) EmailMessage {
+	return EmailMessage{
+		Subject: subject,
+		Text:    text,
+		HTML:    "<!DOCTYPE html>\n<html><body><pre style=\"font-family:Menlo,Consolas,monospace\">" + html.EscapeString(text) + "</pre></body></html>\n",
+	}
+}
+
+// buildEmail encodes m as a MIME multipart/alternative message from from to
+// to.
+func buildEmail(from, to string, m EmailMessage, now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	var id [12]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, err
+	}
+	domain := "gnomonitoring"
+	if at := strings.LastIndex(from, "@"); at >= 0 {
+		domain = strings.Trim(from[at+1:], "> ")
+	}
+
+	headers := []string{
+		"From: " + from,
+		"To: " + to,
+		"Subject: " + mime.QEncoding.Encode("utf-8", m.Subject),
+		"Date: " + now.Format(time.RFC1123Z),
+		fmt.Sprintf("Message-ID: <%s@%s>", hex.EncodeToString(id[:]), domain),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + mw.Boundary(),
+	}
+	head := strings.Join(headers, "\r\n") + "\r\n\r\n"
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=UTF-8", m.Text},
+		{"text/html; charset=UTF-8", m.HTML},
+	} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return append([]byte(head), buf.Bytes()...), nil
+}
+
+// smtpTimeout bounds a whole SMTP session.
+const smtpTimeout = 30 * time.Second
+
+// SendEmail sends m to the address to through the SMTP server cfg. With
+// cfg.TLS the connection is TLS from the start; otherwise STARTTLS is used
+// when the server offers it. Credentials are only sent when cfg.Username is
+// set (net/smtp refuses plain auth over an unencrypted connection, except to
+// localhost).
+func SendEmail(cfg SMTPConfig, to string, m EmailMessage) error {
+	if !cfg.Enabled() {
+		return fmt.Errorf("smtp is not configured")
+	}
+	if to == "" {
+		return fmt.Errorf("no email address")
+	}
+	msg, err := buildEmail(cfg.From, to, m, time.Now())
+	if err != nil {
+		return fmt.Errorf("build email: %w", err)
+	}
+
+	addr := cfg.Addr()
+	var conn net.Conn
+	if cfg.TLS {
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: smtpTimeout}, "tcp", addr, &tls.Config{ServerName: cfg.Host})
+	} else {
+		conn, err = net.DialTimeout("tcp", addr, smtpTimeout)
+	}
+	if err != nil {
+		return fmt.Errorf("smtp dial %s: %w", addr, err)
+	}
+	conn.SetDeadline(time.Now().Add(smtpTimeout))
+
+	c, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp hello: %w", err)
+	}
+	defer c.Close()
+
+	if !cfg.TLS {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(&tls.Config{ServerName: cfg.Host}); err != nil {
+				return fmt.Errorf("smtp starttls: %w", err)
+			}
+		}
+	}
+	if cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+
+	from := cfg.From
+	if a, err := mail.ParseAddress(from); err == nil {
+		from = a.Address
+	}
+	if err := c.Mail(from); err != nil {
+		return fmt.Errorf("smtp MAIL FROM: %w", err)
+	}
+	if err := c.Rcpt(to); err != nil {
+		return fmt.Errorf("smtp RCPT TO %s: %w", to, err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("smtp write: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp send: %w", err)
+	}
+	return c.Quit()
+}
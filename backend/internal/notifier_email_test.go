@@ -0,0 +1,207 @@
+package internal
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/testoutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpSink is a minimal local SMTP server that accepts every message, so
+// SendEmail can be tested end to end without a mail provider.
+type smtpSink struct {
+	ln   net.Listener
+	msgs chan sinkMessage
+}
+
+type sinkMessage struct {
+	From, To string
+	Data     string
+}
+
+func startSMTPSink(t *testing.T) *smtpSink {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &smtpSink{ln: ln, msgs: make(chan sinkMessage, 8)}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpSink) port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpSink) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	reply("220 sink ESMTP")
+	var msg sinkMessage
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 sink")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			msg.From = strings.Trim(strings.TrimSpace(line)[len("MAIL FROM:"):], "<>")
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			msg.To = strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>")
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			msg.Data = data.String()
+			s.msgs <- msg
+			reply("250 queued")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSendEmail_DeliversMultipartToSink(t *testing.T) {
+	sink := startSMTPSink(t)
+	cfg := SMTPConfig{Host: "127.0.0.1", Port: sink.port(), From: "Gnomonitoring <alerts@example.com>"}
+
+	m, err := RenderAlertEmail(AlertData{
+		ChainID: "test12", Level: AlertCritical, Emoji: "🚨", Title: "CRITICAL",
+		Fields:  []AlertField{{Name: "addr", Value: "g1abc"}, {Name: "moniker", Value: "val-1"}},
+		Moniker: "val-1", AlertLogID: 42,
+	})
+	require.NoError(t, err)
+	require.NoError(t, SendEmail(cfg, "ops@example.com", m))
+
+	var got sinkMessage
+	select {
+	case got = <-sink.msgs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message reached the sink")
+	}
+	assert.Equal(t, "alerts@example.com", got.From)
+	assert.Equal(t, "ops@example.com", got.To)
+
+	parsed, err := mail.ReadMessage(strings.NewReader(got.Data))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "[test12] 🚨 CRITICAL — val-1", subject)
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+	mr := multipart.NewReader(parsed.Body, params["boundary"])
+	var types []string
+	bodies := map[string]string{}
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		b, err := io.ReadAll(p) // multipart.Reader decodes quoted-printable
+		require.NoError(t, err)
+		ct, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		types = append(types, ct)
+		bodies[ct] = string(b)
+	}
+	assert.Equal(t, []string{"text/plain", "text/html"}, types)
+	assert.Contains(t, bodies["text/plain"], "addr: g1abc")
+	assert.Contains(t, bodies["text/plain"], "POST /alerts/42/ack")
+	assert.Contains(t, bodies["text/html"], "<code>g1abc</code>")
+}
+
+func TestSendEmail_RequiresConfig(t *testing.T) {
+	assert.Error(t, SendEmail(SMTPConfig{}, "ops@example.com", EmailMessage{Subject: "x"}))
+	assert.Error(t, SendEmail(SMTPConfig{Host: "127.0.0.1", From: "a@example.com"}, "", EmailMessage{Subject: "x"}))
+}
+
+func TestRenderAlertEmail_EscapesHTML(t *testing.T) {
+	m, err := RenderAlertEmail(AlertData{
+		ChainID: "test12", Level: AlertResolved, Emoji: "✅", Title: "RESOLVED",
+		Fields: []AlertField{{Name: "validator", Value: "<b>evil</b> (g1abc)"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "[test12] ✅ RESOLVED", m.Subject)
+	assert.NotContains(t, m.HTML, "<b>evil</b>")
+	assert.Contains(t, m.HTML, "&lt;b&gt;evil&lt;/b&gt;")
+	assert.Contains(t, m.Text, "validator: <b>evil</b> (g1abc)", "the text part is not escaped")
+	assert.NotContains(t, m.Text, "acknowledge", "RESOLVED alerts cannot be acknowledged")
+	assert.Contains(t, m.HTML, "#"+strings.ToUpper(strconv.FormatInt(alertColorResolved, 16)))
+}
+
+// TestEmailAlertDestinations_FollowWebhookFilters checks that an alert email
+// goes to users whose webhook filters accept the alert, or who have none.
+func TestEmailAlertDestinations_FollowWebhookFilters(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+	saved := Config
+	defer func() { Config = saved }()
+	Config.SMTP = SMTPConfig{Host: "127.0.0.1", From: "alerts@example.com"}
+	Config.DefaultChain = "test12"
+
+	on := true
+	for _, id := range []string{"mine", "others", "nohook"} {
+		require.NoError(t, database.InsertUser(id, id+"@example.com", id, db))
+		require.NoError(t, database.SetUserEmailOptIn(db, id, &on, nil))
+	}
+	require.NoError(t, db.Create(&[]database.WebhookValidator{
+		{UserID: "mine", URL: "https://example.com/a", Type: "discord",
+			Filter: database.WebhookFilter{IncludeValidators: database.StringList{"g1mine"}}},
+		{UserID: "others", URL: "https://example.com/b", Type: "discord",
+			Filter: database.WebhookFilter{ExcludeValidators: database.StringList{"g1mine"}}},
+	}).Error)
+
+	users := func(d AlertData) []string {
+		var out []string
+		for _, dest := range emailAlertDestinations(db, "test12", d) {
+			out = append(out, dest.UserID)
+		}
+		return out
+	}
+	missed := AlertData{Level: AlertCritical, Addr: "g1mine", Kind: AlertKindMissedBlocks}
+	assert.ElementsMatch(t, []string{"mine", "nohook"}, users(missed))
+	missed.Addr = "g1else"
+	assert.ElementsMatch(t, []string{"others", "nohook"}, users(missed))
+	assert.ElementsMatch(t, []string{"mine", "others", "nohook"},
+		users(AlertData{Level: AlertCritical, Addr: "all", Kind: AlertKindStagnation}),
+		"chain-level alerts pass validator filters")
+}
@@ -0,0 +1,106 @@
+package internal
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// GenericPayloadVersion is the schema version of the JSON body posted to
+// "generic" webhooks. Bump it on any incompatible change to GenericPayload.
+const GenericPayloadVersion = 1
+
+// GenericSignatureHeader carries the hex HMAC-SHA256 of the request body,
+// keyed with the webhook's secret, as "sha256=<hex>".
+const GenericSignatureHeader = "X-Gnomonitoring-Signature"
+
+// GenericPayload is the versioned JSON document a "generic" webhook
+// receives: AlertData with machine-friendly field names.
+type GenericPayload struct {
+	Version     int            `json:"version"`
+	ChainID     string         `json:"chain_id"`
+	Level       AlertLevel     `json:"level"`
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	Date        string         `json:"date,omitempty"`
+	Addr        string         `json:"addr,omitempty"`
+	Moniker     string         `json:"moniker,omitempty"`
+	StartHeight int64          `json:"start_height,omitempty"`
+	EndHeight   int64          `json:"end_height,omitempty"`
+	AlertID     uint           `json:"alert_id,omitempty"` // acknowledge with POST /alerts/{alert_id}/ack
+	Fields      []GenericField `json:"fields,omitempty"`
+	SentAt      time.Time      `json:"sent_at"`
+}
+
+type GenericField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	URL   string `json:"url,omitempty"`
+}
+
+// genericNotifier posts GenericPayload JSON signed with the webhook secret,
+// for teams routing alerts into their own tooling.
+type genericNotifier struct{}
+
+func init() { RegisterNotifier("generic", genericNotifier{}) }
+
+func (genericNotifier) Render(d AlertData) ([]byte, error) {
+	p := GenericPayload{
+		Version:     GenericPayloadVersion,
+		ChainID:     d.ChainID,
+		Level:       d.Level,
+		Title:       d.Title,
+		Description: d.Description,
+		Date:        d.Date,
+		Addr:        d.Addr,
+		Moniker:     d.Moniker,
+		StartHeight: d.StartHeight,
+		EndHeight:   d.EndHeight,
+		SentAt:      time.Now().UTC(),
+	}
+	if d.Acknowledgeable() {
+		p.AlertID = d.AlertLogID
+	}
+	for _, f := range d.Fields {
+		p.Fields = append(p.Fields, GenericField{Name: f.Name, Value: f.Value, URL: f.URL})
+	}
+	body, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("marshal generic payload: %w", err)
+	}
+	return body, nil
+}
+
+func (genericNotifier) Send(payload []byte, dest Destination) error {
+	if dest.Secret == "" {
+		return fmt.Errorf("generic webhook %d has no signing secret", dest.WebhookID)
+	}
+	req, err := http.NewRequest(http.MethodPost, dest.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("build generic webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(GenericSignatureHeader, SignGenericPayload(dest.Secret, payload))
+
+	resp, err := alertHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending generic webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return checkDeliveryStatus("generic webhook", resp)
+}
+
+// SignGenericPayload returns the GenericSignatureHeader value for body.
+// Receivers recompute it with their copy of the secret and compare in
+// constant time.
+func SignGenericPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
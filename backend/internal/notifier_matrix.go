@@ -0,0 +1,25 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// matrixNotifier posts AlertData to a Matrix room through a hookshot
+// generic webhook, with any AlertContact mentions as user pills (see
+// RenderAlertMatrix).
+type matrixNotifier struct{}
+
+func init() { RegisterNotifier("matrix", matrixNotifier{}) }
+
+func (matrixNotifier) Render(d AlertData) ([]byte, error) {
+	body, err := json.Marshal(RenderAlertMatrix(d))
+	if err != nil {
+		return nil, fmt.Errorf("marshal matrix message: %w", err)
+	}
+	return body, nil
+}
+
+func (matrixNotifier) Send(payload []byte, dest Destination) error {
+	return postJSON("matrix webhook", dest.URL, payload)
+}
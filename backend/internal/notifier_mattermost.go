@@ -0,0 +1,25 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// mattermostNotifier posts AlertData as a message attachment to a Mattermost
+// incoming webhook, with any AlertContact mentions carried in the message
+// text (see RenderAlertMattermost).
+type mattermostNotifier struct{}
+
+func init() { RegisterNotifier("mattermost", mattermostNotifier{}) }
+
+func (mattermostNotifier) Render(d AlertData) ([]byte, error) {
+	body, err := json.Marshal(RenderAlertMattermost(d))
+	if err != nil {
+		return nil, fmt.Errorf("marshal mattermost message: %w", err)
+	}
+	return body, nil
+}
+
+func (mattermostNotifier) Send(payload []byte, dest Destination) error {
+	return postJSON("mattermost webhook", dest.URL, payload)
+}
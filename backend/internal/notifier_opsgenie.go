@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// opsgenieRequest is the rendered form of an Opsgenie call: Opsgenie uses a
+// different endpoint to create and to close an alert, so the payload records
+// which one it targets alongside the request body.
+type opsgenieRequest struct {
+	Action string         `json:"action"` // "trigger" or "resolve"
+	Alias  string         `json:"alias"`
+	Body   map[string]any `json:"body"`
+}
+
+// opsgenieNotifier creates and closes Opsgenie alerts for alerts carrying
+// an Incident action, using the incident key as the Opsgenie alias. The
+// webhook URL is the API base (https://api.opsgenie.com or the EU host) and
+// the webhook secret holds the API integration key.
+type opsgenieNotifier struct{}
+
+func init() { RegisterNotifier("opsgenie", opsgenieNotifier{}) }
+
+func (opsgenieNotifier) Render(d AlertData) ([]byte, error) {
+	if d.Incident == "" {
+		return nil, ErrNotApplicable
+	}
+	req := opsgenieRequest{Action: string(d.Incident), Alias: d.IncidentKey()}
+	switch d.Incident {
+	case IncidentTrigger:
+		req.Body = map[string]any{
+			"message":     incidentSummary(d),
+			"alias":       req.Alias,
+			"description": d.Description,
+			"priority":    "P1",
+			"source":      "gnomonitoring",
+			"tags":        []string{"gnomonitoring", d.ChainID},
+			"details":     incidentDetails(d),
+		}
+	case IncidentResolve:
+		req.Body = map[string]any{
+			"source": "gnomonitoring",
+			"note":   incidentSummary(d),
+		}
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal opsgenie request: %w", err)
+	}
+	return body, nil
+}
+
+func (opsgenieNotifier) Send(payload []byte, dest Destination) error {
+	if dest.Secret == "" {
+		return fmt.Errorf("opsgenie webhook %d has no API key", dest.WebhookID)
+	}
+	var req opsgenieRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return fmt.Errorf("decode opsgenie request: %w", err)
+	}
+
+	base := strings.TrimRight(dest.URL, "/")
+	endpoint := base + "/v2/alerts"
+	if req.Action == string(IncidentResolve) {
+		endpoint = fmt.Sprintf("%s/v2/alerts/%s/close?identifierType=alias", base, url.PathEscape(req.Alias))
+	}
+
+	body, err := json.Marshal(req.Body)
+	if err != nil {
+		return fmt.Errorf("marshal opsgenie body: %w", err)
+	}
+	httpReq, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build opsgenie request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "GenieKey "+dest.Secret)
+
+	resp, err := alertHTTPClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("error sending opsgenie request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return checkDeliveryStatus("opsgenie", resp)
+}
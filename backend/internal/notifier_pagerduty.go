@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// pagerDutyEvent is a PagerDuty Events API v2 event. See
+// https://developer.pagerduty.com/docs/events-api-v2/trigger-events/
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"` // "trigger" or "resolve"
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"` // trigger only
+}
+
+type pagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+// pagerDutyNotifier opens and resolves PagerDuty incidents for alerts
+// carrying an Incident action. The webhook URL is the Events API endpoint
+// and the webhook secret holds the integration routing key.
+type pagerDutyNotifier struct{}
+
+func init() { RegisterNotifier("pagerduty", pagerDutyNotifier{}) }
+
+// Render leaves the routing key out: it is added at Send time from the
+// destination, so rendered payloads never carry credentials.
+func (pagerDutyNotifier) Render(d AlertData) ([]byte, error) {
+	if d.Incident == "" {
+		return nil, ErrNotApplicable
+	}
+	ev := pagerDutyEvent{EventAction: string(d.Incident), DedupKey: d.IncidentKey()}
+	if d.Incident == IncidentTrigger {
+		ev.Payload = &pagerDutyPayload{
+			Summary:       incidentSummary(d),
+			Source:        "gnomonitoring/" + d.ChainID,
+			Severity:      "critical",
+			CustomDetails: incidentDetails(d),
+		}
+	}
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return nil, fmt.Errorf("marshal pagerduty event: %w", err)
+	}
+	return body, nil
+}
+
+func (pagerDutyNotifier) Send(payload []byte, dest Destination) error {
+	if dest.Secret == "" {
+		return fmt.Errorf("pagerduty webhook %d has no routing key", dest.WebhookID)
+	}
+	var ev pagerDutyEvent
+	if err := json.Unmarshal(payload, &ev); err != nil {
+		return fmt.Errorf("decode pagerduty event: %w", err)
+	}
+	ev.RoutingKey = dest.Secret
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("marshal pagerduty event: %w", err)
+	}
+	return postJSON("pagerduty events", dest.URL, body)
+}
+
+// incidentSummary is the one-line incident title shared by the incident
+// channels, e.g. "[test12] 🚨 CRITICAL: mon1 (g1...)".
+func incidentSummary(d AlertData) string {
+	summary := fmt.Sprintf("[%s] %s %s", d.ChainID, d.Emoji, d.Title)
+	if d.Addr != "" && d.Addr != "all" {
+		summary += fmt.Sprintf(": %s (%s)", d.Moniker, d.Addr)
+	}
+	return strings.TrimSpace(summary)
+}
+
+// incidentDetails flattens d.Fields (plus the date) into the key/value
+// details both PagerDuty and Opsgenie display on an incident.
+func incidentDetails(d AlertData) map[string]string {
+	details := make(map[string]string, len(d.Fields)+1)
+	for _, f := range d.Fields {
+		details[f.Name] = f.Value
+	}
+	if d.Description != "" {
+		details["description"] = d.Description
+	}
+	if d.Date != "" {
+		details["date"] = d.Date
+	}
+	return details
+}
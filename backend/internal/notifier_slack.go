@@ -0,0 +1,24 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// slackNotifier posts AlertData as a Block Kit message to a Slack incoming
+// webhook (see RenderAlertSlackBlocks).
+type slackNotifier struct{}
+
+func init() { RegisterNotifier("slack", slackNotifier{}) }
+
+func (slackNotifier) Render(d AlertData) ([]byte, error) {
+	body, err := json.Marshal(map[string]any{"blocks": RenderAlertSlackBlocks(d)})
+	if err != nil {
+		return nil, fmt.Errorf("marshal slack blocks: %w", err)
+	}
+	return body, nil
+}
+
+func (slackNotifier) Send(payload []byte, dest Destination) error {
+	return postJSON("slack webhook", dest.URL, payload)
+}
@@ -0,0 +1,118 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/slack"
+	"gorm.io/gorm"
+)
+
+// slackBotNotifier posts AlertData in a Slack channel as the app's bot user
+// (see SlackAppConfig): the Block Kit message of RenderAlertSlackBlocks,
+// with an Acknowledge button on alerts that can be acknowledged. Which
+// channels receive an alert is decided by their /gnomon subscribe and
+// setchain state.
+type slackBotNotifier struct{}
+
+func init() { RegisterNotifier("slack_bot", slackBotNotifier{}) }
+
+func (slackBotNotifier) Render(d AlertData) ([]byte, error) {
+	var blocks []any
+	for _, b := range RenderAlertSlackBlocks(d) {
+		blocks = append(blocks, b)
+	}
+	if d.Acknowledgeable() {
+		blocks = append(blocks, slack.AckBlock(d.AlertLogID))
+	}
+	body, err := json.Marshal(map[string]any{
+		"text":   fmt.Sprintf("[%s] %s %s", d.ChainID, d.Emoji, d.Title),
+		"blocks": blocks,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal slack bot alert: %w", err)
+	}
+	return body, nil
+}
+
+func (slackBotNotifier) Send(payload []byte, dest Destination) error {
+	return slackAPIError(slack.PostMessage(dest.Token, dest.Channel, payload))
+}
+
+// transientSlackErrors are the Web API error codes worth retrying; the
+// others (channel_not_found, not_in_channel, invalid_auth, ...) need the
+// app or the channel to be fixed first.
+var transientSlackErrors = map[string]bool{
+	"internal_error":      true,
+	"fatal_error":         true,
+	"service_unavailable": true,
+	"request_timeout":     true,
+}
+
+// slackAPIError maps a failed Slack call to a *DeliveryError so the outbox
+// retries or drops it like a webhook failure. Slack answers call errors with
+// HTTP 200 and ok=false; they are reported as 400 (permanent) or 503.
+func slackAPIError(err error) error {
+	var apiErr *slack.APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	status := apiErr.StatusCode
+	if status == http.StatusOK {
+		status = http.StatusBadRequest
+		if transientSlackErrors[apiErr.Code] {
+			status = http.StatusServiceUnavailable
+		}
+	}
+	return &DeliveryError{
+		Label:      "slack bot",
+		StatusCode: status,
+		Detail:     apiErr.Code,
+		RetryAfter: apiErr.RetryAfter,
+	}
+}
+
+// slackBotDestinations wraps channel IDs as Slack bot destinations. No bot
+// token yields no destinations, like telegramDestinations.
+func slackBotDestinations(channelIDs []string) []Destination {
+	token := Config.SlackApp.BotToken
+	if token == "" {
+		return nil
+	}
+	dests := make([]Destination, 0, len(channelIDs))
+	for _, id := range channelIDs {
+		dests = append(dests, Destination{Type: "slack_bot", Channel: id, Token: token, Bot: "slack"})
+	}
+	return dests
+}
+
+// validatorAlertSlackChannels returns the Slack channels subscribed to addr
+// on chainID.
+func validatorAlertSlackChannels(db *gorm.DB, chainID, addr string) []Destination {
+	if Config.SlackApp.BotToken == "" {
+		return nil
+	}
+	ids, err := database.GetSlackAlertChannelIDs(db, chainID, addr)
+	if err != nil {
+		log.Printf("❌ GetSlackAlertChannelIDs failed (chain=%s): %v", chainID, err)
+		return nil
+	}
+	return slackBotDestinations(ids)
+}
+
+// validatorChainSlackChannels returns the Slack channels following chainID.
+func validatorChainSlackChannels(db *gorm.DB, chainID string) []Destination {
+	if Config.SlackApp.BotToken == "" {
+		return nil
+	}
+	ids, err := database.GetSlackChannelIDsForChain(db, chainID)
+	if err != nil {
+		log.Printf("❌ GetSlackChannelIDsForChain failed (chain=%s): %v", chainID, err)
+		return nil
+	}
+	return slackBotDestinations(ids)
+}
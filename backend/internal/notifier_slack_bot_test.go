@@ -0,0 +1,66 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlackBotNotifier_RenderAddsAckButton(t *testing.T) {
+	n := slackBotNotifier{}
+	d := AlertData{ChainID: "test12", Level: AlertCritical, Emoji: "🚨", Title: "CRITICAL", AlertLogID: 7}
+
+	payload, err := n.Render(d)
+	require.NoError(t, err)
+	var msg struct {
+		Text   string        `json:"text"`
+		Blocks []slack.Block `json:"blocks"`
+	}
+	require.NoError(t, json.Unmarshal(payload, &msg))
+	assert.Equal(t, "[test12] 🚨 CRITICAL", msg.Text)
+	require.Len(t, msg.Blocks, len(RenderAlertSlackBlocks(d))+1)
+	ack := msg.Blocks[len(msg.Blocks)-1]
+	assert.Equal(t, "actions", ack.Type)
+	assert.Equal(t, "c=ack&id=7", ack.Elements[0].Value)
+
+	d.Level, d.Title = AlertResolved, "RESOLVED"
+	payload, err = n.Render(d)
+	require.NoError(t, err)
+	assert.NotContains(t, string(payload), "c=ack", "RESOLVED alerts cannot be acknowledged")
+}
+
+func TestSlackBotDestinations(t *testing.T) {
+	orig := Config.SlackApp
+	defer func() { Config.SlackApp = orig }()
+
+	Config.SlackApp.BotToken = ""
+	assert.Empty(t, slackBotDestinations([]string{"C1"}))
+
+	Config.SlackApp.BotToken = "xoxb-token"
+	dests := slackBotDestinations([]string{"C1", "C2"})
+	require.Len(t, dests, 2)
+	assert.Equal(t, Destination{Type: "slack_bot", Channel: "C2", Token: "xoxb-token", Bot: "slack"}, dests[1])
+	assert.Equal(t, "slack_bot/C2", deliveryDestKey(dests[1]))
+}
+
+func TestSlackAPIError_MapsToDeliveryError(t *testing.T) {
+	var de *DeliveryError
+	err := slackAPIError(&slack.APIError{StatusCode: http.StatusOK, Code: "not_in_channel"})
+	require.True(t, errors.As(err, &de))
+	assert.True(t, de.Permanent(), "a channel the app was not invited to is not retried")
+
+	err = slackAPIError(&slack.APIError{StatusCode: http.StatusOK, Code: "internal_error"})
+	require.True(t, errors.As(err, &de))
+	assert.False(t, de.Permanent())
+
+	err = slackAPIError(&slack.APIError{StatusCode: http.StatusTooManyRequests, Code: "ratelimited", RetryAfter: 3 * time.Second})
+	require.True(t, errors.As(err, &de))
+	assert.False(t, de.Permanent())
+	assert.Equal(t, 3*time.Second, de.RetryAfter)
+}
@@ -0,0 +1,53 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/telegram"
+)
+
+// telegramNotifier sends AlertData as an HTML message to one Telegram chat
+// (see RenderAlertTelegramHTML). Which chats receive an alert is decided by
+// the caller through the subscription-aware destination lookups.
+type telegramNotifier struct{}
+
+func init() { RegisterNotifier("telegram", telegramNotifier{}) }
+
+// telegramPayload is the rendered form of a Telegram alert: the HTML text
+// and, on alerts that can be acknowledged, the Acknowledge button.
+type telegramPayload struct {
+	Text   string                         `json:"text"`
+	Markup *telegram.InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+func (telegramNotifier) Render(d AlertData) ([]byte, error) {
+	p := telegramPayload{Text: RenderAlertTelegramHTML(d)}
+	if d.Acknowledgeable() {
+		p.Markup = telegram.AckMarkup(d.AlertLogID)
+	}
+	return json.Marshal(p)
+}
+
+func (telegramNotifier) Send(payload []byte, dest Destination) error {
+	if dest.Token == "" {
+		return fmt.Errorf("token is empty")
+	}
+	var p telegramPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("decode telegram payload: %w", err)
+	}
+	err := telegram.SendMessageTelegramWithMarkup(dest.Token, dest.ChatID, p.Text, p.Markup)
+	var apiErr *telegram.APIError
+	if errors.As(err, &apiErr) {
+		return &DeliveryError{
+			Label:      "telegram",
+			StatusCode: apiErr.StatusCode,
+			Detail:     apiErr.Description,
+			RetryAfter: time.Duration(apiErr.RetryAfter) * time.Second,
+		}
+	}
+	return err
+}
@@ -0,0 +1,319 @@
+package internal
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNotifierRegistry_BuiltinChannels(t *testing.T) {
+	for _, kind := range []string{"discord", "slack", "telegram", "email", "matrix", "mattermost", "discord_bot", "slack_bot"} {
+		if _, ok := NotifierFor(kind); !ok {
+			t.Fatalf("notifier %q not registered, have %v", kind, NotifierTypes())
+		}
+	}
+	if _, ok := NotifierFor("carrier-pigeon"); ok {
+		t.Fatal("unexpected notifier for unknown kind")
+	}
+}
+
+func TestDeliver_RoutesEachDestinationToItsNotifier(t *testing.T) {
+	captured := map[string]map[string]any{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		captured[r.URL.Path] = body
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	// Swap alertHTTPClient to bypass SSRF protection for this test.
+	orig := alertHTTPClient
+	alertHTTPClient = &http.Client{Timeout: 10 * time.Second}
+	defer func() { alertHTTPClient = orig }()
+
+	dests := []Destination{
+		{Type: "discord", WebhookID: 1, URL: srv.URL + "/discord"},
+		{Type: "slack", WebhookID: 2, URL: srv.URL + "/slack"},
+		{Type: "unknown", WebhookID: 3, URL: srv.URL + "/unknown"},
+	}
+	d := AlertData{ChainID: "test12", Level: AlertCritical, Emoji: "🚨", Title: "CRITICAL"}
+	err := Deliver(dests, func(dest Destination) AlertData {
+		if dest.WebhookID == 1 {
+			withMention := d
+			withMention.Mentions = []string{"111"}
+			return withMention
+		}
+		return d
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if captured["/discord"]["content"] != "<@111>" {
+		t.Fatalf("discord payload missing per-destination mention: %+v", captured["/discord"])
+	}
+	if _, ok := captured["/discord"]["embeds"].([]any); !ok {
+		t.Fatalf("discord payload missing embeds: %+v", captured["/discord"])
+	}
+	if _, ok := captured["/slack"]["blocks"].([]any); !ok {
+		t.Fatalf("slack payload missing blocks: %+v", captured["/slack"])
+	}
+	if _, hit := captured["/unknown"]; hit {
+		t.Fatal("unknown webhook type must be skipped")
+	}
+}
+
+func TestDeliver_ContinuesPastFailingDestination(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if strings.HasSuffix(r.URL.Path, "/down") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	orig := alertHTTPClient
+	alertHTTPClient = &http.Client{Timeout: 10 * time.Second}
+	defer func() { alertHTTPClient = orig }()
+
+	dests := []Destination{
+		{Type: "discord", URL: srv.URL + "/down"},
+		{Type: "discord", URL: srv.URL + "/up"},
+	}
+	err := Deliver(dests, same(AlertData{ChainID: "test12", Title: "t"}))
+	if err == nil {
+		t.Fatal("expected an error for the failing destination")
+	}
+	if hits != 2 {
+		t.Fatalf("hits = %d, want 2 (a failure must not stop delivery)", hits)
+	}
+}
+
+func TestTelegramNotifier_RendersHTML(t *testing.T) {
+	n, _ := NotifierFor("telegram")
+	payload, err := n.Render(AlertData{ChainID: "test12", Emoji: "✅", Title: "RESOLVED"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(payload) != `{"text":"\u003cb\u003e[test12] ✅ RESOLVED\u003c/b\u003e"}` {
+		t.Fatalf("payload = %q", payload)
+	}
+	if err := n.Send(payload, Destination{Type: "telegram", ChatID: 1}); err == nil {
+		t.Fatal("expected an error when the bot token is empty")
+	}
+}
+
+func TestTelegramNotifier_AckButtonOnRecordedAlerts(t *testing.T) {
+	n, _ := NotifierFor("telegram")
+	cases := []struct {
+		name string
+		data AlertData
+		want bool
+	}{
+		{"recorded critical", AlertData{Level: AlertCritical, AlertLogID: 42}, true},
+		{"recorded warning", AlertData{Level: AlertWarning, AlertLogID: 42}, true},
+		{"resolved", AlertData{Level: AlertResolved, AlertLogID: 42}, false},
+		{"not recorded", AlertData{Level: AlertCritical}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			payload, err := n.Render(tc.data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var p telegramPayload
+			if err := json.Unmarshal(payload, &p); err != nil {
+				t.Fatalf("payload is not JSON: %v", err)
+			}
+			if got := p.Markup != nil; got != tc.want {
+				t.Fatalf("button = %v, want %v", got, tc.want)
+			}
+			if tc.want && p.Markup.InlineKeyboard[0][0].CallbackData != "c=ack&id=42" {
+				t.Fatalf("callback data = %q", p.Markup.InlineKeyboard[0][0].CallbackData)
+			}
+		})
+	}
+}
+
+func TestGovdaoProposalAlert_OmitsTxWhenUnknown(t *testing.T) {
+	d := GovdaoProposalAlert("test12", 7, "Upgrade", "https://gno.land/r/gov/dao:7", "")
+	for _, f := range d.Fields {
+		if strings.Contains(f.Name, "Tx") {
+			t.Fatalf("unexpected Tx field with empty tx url: %+v", f)
+		}
+	}
+	last := d.Fields[len(d.Fields)-1]
+	if last.URL != "https://memba.samourai.app/dao/gno.land~r~gov~dao/proposal/7" {
+		t.Fatalf("vote link = %q", last.URL)
+	}
+}
+
+func TestGenericNotifier_PostsSignedVersionedJSON(t *testing.T) {
+	var body []byte
+	var sig string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		sig = r.Header.Get(GenericSignatureHeader)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	orig := alertHTTPClient
+	alertHTTPClient = &http.Client{Timeout: 10 * time.Second}
+	defer func() { alertHTTPClient = orig }()
+
+	d := AlertData{
+		ChainID: "test12", Level: AlertCritical, Title: "CRITICAL",
+		Fields: []AlertField{{Name: "addr", Value: "g1addr"}},
+		Addr:   "g1addr", Moniker: "mon1", StartHeight: 100, EndHeight: 130,
+	}
+	dest := Destination{Type: "generic", WebhookID: 9, URL: srv.URL, Secret: "s3cret"}
+	if err := Deliver([]Destination{dest}, same(d)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); sig != want {
+		t.Fatalf("signature = %q, want %q", sig, want)
+	}
+
+	var got GenericPayload
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("body is not JSON: %v", err)
+	}
+	if got.Version != GenericPayloadVersion || got.ChainID != "test12" || got.Level != AlertCritical {
+		t.Fatalf("unexpected envelope: %+v", got)
+	}
+	if got.Addr != "g1addr" || got.Moniker != "mon1" || got.StartHeight != 100 || got.EndHeight != 130 {
+		t.Fatalf("structured validator fields not passed through: %+v", got)
+	}
+	if len(got.Fields) != 1 || got.Fields[0].Value != "g1addr" {
+		t.Fatalf("fields = %+v", got.Fields)
+	}
+}
+
+func TestGenericNotifier_RefusesUnsignedSend(t *testing.T) {
+	n, _ := NotifierFor("generic")
+	if err := n.Send([]byte(`{}`), Destination{Type: "generic", URL: "https://example.com"}); err == nil {
+		t.Fatal("expected an error when the webhook has no secret")
+	}
+}
+
+func TestGenericNotifier_RateLimitIsADeliveryError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	orig := alertHTTPClient
+	alertHTTPClient = &http.Client{Timeout: 10 * time.Second}
+	defer func() { alertHTTPClient = orig }()
+
+	n, _ := NotifierFor("generic")
+	err := n.Send([]byte(`{}`), Destination{Type: "generic", URL: srv.URL, Secret: "s3cret"})
+	var de *DeliveryError
+	if !errors.As(err, &de) {
+		t.Fatalf("err = %v, want a *DeliveryError", err)
+	}
+	if de.StatusCode != http.StatusTooManyRequests || de.RetryAfter != 7*time.Second || de.Permanent() {
+		t.Fatalf("unexpected delivery error: %+v", de)
+	}
+}
+
+func TestPagerDutyNotifier_TriggerAndResolveShareDedupKey(t *testing.T) {
+	var events []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev map[string]any
+		json.NewDecoder(r.Body).Decode(&ev)
+		events = append(events, ev)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	orig := alertHTTPClient
+	alertHTTPClient = &http.Client{Timeout: 10 * time.Second}
+	defer func() { alertHTTPClient = orig }()
+
+	dest := Destination{Type: "pagerduty", WebhookID: 7, URL: srv.URL + "/v2/enqueue", Secret: "routing-key"}
+	base := AlertData{ChainID: "test12", Addr: "g1addr", Moniker: "mon1", StartHeight: 100}
+
+	trigger := base
+	trigger.Level, trigger.Emoji, trigger.Title, trigger.Incident = AlertCritical, "🚨", "CRITICAL", IncidentTrigger
+	// A WARNING opens no incident and must not reach PagerDuty.
+	warning := base
+	warning.Level, warning.Title = AlertWarning, "WARNING"
+	resolve := base
+	resolve.Level, resolve.Title, resolve.Incident = AlertResolved, "RESOLVED", IncidentResolve
+
+	for _, d := range []AlertData{warning, trigger, resolve} {
+		if err := Deliver([]Destination{dest}, same(d)); err != nil {
+			t.Fatalf("Deliver: %v", err)
+		}
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2 (trigger, resolve): %+v", len(events), events)
+	}
+	wantKey := "gnomonitoring/test12/g1addr/100"
+	for i, action := range []string{"trigger", "resolve"} {
+		ev := events[i]
+		if ev["event_action"] != action || ev["dedup_key"] != wantKey || ev["routing_key"] != "routing-key" {
+			t.Fatalf("event %d = %+v", i, ev)
+		}
+	}
+	payload, _ := events[0]["payload"].(map[string]any)
+	if payload["severity"] != "critical" || !strings.Contains(payload["summary"].(string), "mon1") {
+		t.Fatalf("trigger payload = %+v", payload)
+	}
+}
+
+func TestOpsgenieNotifier_CreatesAndClosesByAlias(t *testing.T) {
+	type call struct{ uri, auth string }
+	var calls []call
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, call{r.URL.RequestURI(), r.Header.Get("Authorization")})
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	orig := alertHTTPClient
+	alertHTTPClient = &http.Client{Timeout: 10 * time.Second}
+	defer func() { alertHTTPClient = orig }()
+
+	dest := Destination{Type: "opsgenie", WebhookID: 8, URL: srv.URL + "/", Secret: "api-key"}
+	d := AlertData{ChainID: "test12", Title: "Blockchain stuck", Addr: "all", StartHeight: 42, Incident: IncidentTrigger}
+	if err := Deliver([]Destination{dest}, same(d)); err != nil {
+		t.Fatalf("trigger: %v", err)
+	}
+	d.Incident = IncidentResolve
+	if err := Deliver([]Destination{dest}, same(d)); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+
+	want := []call{
+		{"/v2/alerts", "GenieKey api-key"},
+		{"/v2/alerts/gnomonitoring%2Ftest12%2Fall%2F42/close?identifierType=alias", "GenieKey api-key"},
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %+v", calls)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Fatalf("call %d = %+v, want %+v", i, calls[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,403 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"gorm.io/gorm"
+)
+
+// Alerts are not sent inline: EnqueueAlert renders them once per destination
+// into the alert_deliveries outbox and the delivery worker sends them,
+// retrying with exponential backoff (or the receiver's Retry-After) until
+// they succeed or reach delivery_max_attempts, after which they are
+// dead-lettered for an admin to inspect and replay (/admin/deliveries).
+
+const (
+	deliveryBatchSize          = 100
+	deliveryPollInterval       = 5 * time.Second
+	deliveryBaseBackoff        = 30 * time.Second
+	deliveryMaxBackoff         = time.Hour
+	deliveryRetention          = 7 * 24 * time.Hour
+	defaultDeliveryMaxAttempts = 8
+
+	// deliveryClaimLease is how long a claimed batch is reserved to the
+	// worker that claimed it; past it, another worker may send it again.
+	deliveryClaimLease = 5 * time.Minute
+)
+
+// deliveryWake nudges the worker right after new rows are queued so alerts
+// do not wait for the next poll.
+var deliveryWake = make(chan struct{}, 1)
+
+// errWebhookGone is returned when a queued delivery's webhook was deleted.
+var errWebhookGone = errors.New("webhook no longer exists")
+
+// DeliveryError is a non-2xx answer from the service a notification was
+// sent to. RetryAfter is the wait it asked for (Retry-After header, or
+// Telegram's retry_after), zero when it gave none.
+type DeliveryError struct {
+	Label      string
+	StatusCode int
+	Detail     string
+	RetryAfter time.Duration
+}
+
+func (e *DeliveryError) Error() string {
+	if e.Detail != "" {
+		return fmt.Sprintf("%s HTTP status: %d: %s", e.Label, e.StatusCode, e.Detail)
+	}
+	return fmt.Sprintf("%s HTTP status: %d", e.Label, e.StatusCode)
+}
+
+// Permanent reports whether retrying cannot help: any 4xx except 408
+// (request timeout) and 429 (rate limited), e.g. a deleted Discord webhook
+// (404) or a Telegram bot blocked by the chat (403).
+func (e *DeliveryError) Permanent() bool {
+	return e.StatusCode >= 400 && e.StatusCode < 500 &&
+		e.StatusCode != http.StatusRequestTimeout && e.StatusCode != http.StatusTooManyRequests
+}
+
+// checkDeliveryStatus turns a non-2xx response into a *DeliveryError.
+func checkDeliveryStatus(label string, resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	return &DeliveryError{
+		Label:      label,
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// parseRetryAfter reads a Retry-After header value, either delay-seconds
+// (Discord sends fractional seconds) or an HTTP date.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.ParseFloat(v, 64); err == nil {
+		if secs <= 0 {
+			return 0
+		}
+		return time.Duration(secs * float64(time.Second))
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// deliveryBackoff is the wait before the next attempt once attempts have
+// failed: retryAfter when the receiver asked for one, else 30s doubling per
+// attempt, capped at one hour.
+func deliveryBackoff(attempts int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+	delay := deliveryBaseBackoff
+	for i := 1; i < attempts && delay < deliveryMaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > deliveryMaxBackoff {
+		delay = deliveryMaxBackoff
+	}
+	return delay
+}
+
+// applyDeliveryResult updates d after a send attempt that returned err.
+// Rate-limited attempts (429) do not consume the attempt budget: the
+// receiver is up and told us when to come back.
+func applyDeliveryResult(d *database.AlertDelivery, err error, maxAttempts int, now time.Time) {
+	if err == nil {
+		d.Attempts++
+		d.Status = database.DeliverySent
+		d.SentAt = &now
+		d.LastError = ""
+		return
+	}
+
+	d.LastError = err.Error()
+	var de *DeliveryError
+	isDeliveryErr := errors.As(err, &de)
+	if !isDeliveryErr || de.StatusCode != http.StatusTooManyRequests {
+		d.Attempts++
+	}
+
+	switch {
+	case errors.Is(err, errWebhookGone), isDeliveryErr && de.Permanent():
+		d.Status = database.DeliveryDead
+	case d.Attempts >= maxAttempts:
+		d.Status = database.DeliveryDead
+	default:
+		var retryAfter time.Duration
+		if isDeliveryErr {
+			retryAfter = de.RetryAfter
+		}
+		d.NextAttemptAt = now.Add(deliveryBackoff(d.Attempts, retryAfter))
+	}
+}
+
+// deliveryDestKey identifies a destination across deliveries; rows sharing
+// it are sent in order.
+func deliveryDestKey(dest Destination) string {
+	switch dest.Type {
+	case "telegram":
+		return fmt.Sprintf("telegram/%s/%d", dest.Bot, dest.ChatID)
+	case "email":
+		return "email/" + dest.UserID
+	case "discord_bot":
+		return fmt.Sprintf("discord_bot/%d", dest.ChatID)
+	case "slack_bot":
+		return "slack_bot/" + dest.Channel
+	}
+	return fmt.Sprintf("%s/%d", dest.Table, dest.WebhookID)
+}
+
+// EnqueueAlert renders one notification per destination (see Deliver) and
+// stores them in the outbox for the delivery worker. alertLogID links them
+// to the alert_logs row they notify about (0 when there is none).
+func EnqueueAlert(db *gorm.DB, alertLogID uint, dests []Destination, dataFor func(Destination) AlertData) error {
+	var errs []error
+	var muted map[string]uint // personal silences, looked up on first use
+	rows := make([]database.AlertDelivery, 0, len(dests))
+	for _, dest := range dests {
+		n, ok := NotifierFor(dest.Type)
+		if !ok {
+			log.Printf("⚠️ Unknown webhook type for user %s: %s", dest.UserID, dest.Type)
+			continue
+		}
+		data := dataFor(dest)
+		if !webhookAccepts(dest.Filter, data) {
+			continue
+		}
+		if owner := silenceOwner(dest); owner != "" && personallySilenceable(data) {
+			if muted == nil {
+				muted = personalSilences(db, data)
+			}
+			if id := muted[owner]; id != 0 {
+				log.Printf("[delivery] silence #%d: not sending %q to %s", id, data.Title, deliveryDestKey(dest))
+				continue
+			}
+		}
+		data.AlertLogID = alertLogID
+		payload, err := n.Render(data)
+		if errors.Is(err, ErrNotApplicable) {
+			continue
+		}
+		if err != nil {
+			log.Printf("❌ Failed to render alert for %s: %v", dest, err)
+			errs = append(errs, err)
+			continue
+		}
+		row := database.AlertDelivery{
+			ChainID:      data.ChainID,
+			DestKey:      deliveryDestKey(dest),
+			DestType:     dest.Type,
+			WebhookTable: dest.Table,
+			WebhookID:    dest.WebhookID,
+			UserID:       dest.UserID,
+			ChatID:       dest.ChatID,
+			Channel:      dest.Channel,
+			Bot:          dest.Bot,
+			Title:        data.Title,
+			Payload:      string(payload),
+		}
+		if alertLogID != 0 {
+			id := alertLogID
+			row.AlertLogID = &id
+		}
+		rows = append(rows, row)
+	}
+
+	if err := database.EnqueueAlertDeliveries(db, rows); err != nil {
+		errs = append(errs, err)
+	} else if len(rows) > 0 {
+		select {
+		case deliveryWake <- struct{}{}:
+		default:
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// silenceOwner is the CreatedBy a personal silence muting dest carries:
+// the webhook or email owner, or the validator-bot chat. "" when no
+// personal silence can mute dest.
+func silenceOwner(dest Destination) string {
+	switch {
+	case dest.UserID != "":
+		return dest.UserID
+	case dest.Type == "telegram" && dest.ChatID != 0:
+		return fmt.Sprintf("telegram:%d", dest.ChatID)
+	}
+	return ""
+}
+
+// personallySilenceable reports whether a personal silence may hold back
+// d: alerts about a validator or a chain, but never the end of an incident,
+// which the receiving end may have opened before the silence started.
+func personallySilenceable(d AlertData) bool {
+	return d.Addr != "" && d.Level != AlertResolved && d.ResolvedLevel == ""
+}
+
+// personalSilences returns the personal silences covering d, by creator. A
+// failed lookup is logged and treated as "not silenced".
+func personalSilences(db *gorm.DB, d AlertData) map[string]uint {
+	muted, err := database.PersonalSilences(db, d.ChainID, d.Addr, time.Now())
+	if err != nil {
+		log.Printf("[delivery] %v", err)
+		return map[string]uint{}
+	}
+	return muted
+}
+
+// deliveryDestination rebuilds the Destination of a queued delivery. The
+// webhook URL and secret (or the user's email address) are re-read so edits
+// and secret rotations made while the delivery was queued are honoured.
+func deliveryDestination(db *gorm.DB, d database.AlertDelivery) (Destination, error) {
+	switch d.WebhookTable {
+	case "webhook_validators":
+		var wh database.WebhookValidator
+		if err := db.First(&wh, d.WebhookID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return Destination{}, errWebhookGone
+			}
+			return Destination{}, err
+		}
+		dest := ValidatorWebhookDestination(wh)
+		dest.Type = d.DestType // the payload was rendered for this type
+		return dest, nil
+	case "webhook_gov_daos":
+		var wh database.WebhookGovDAO
+		if err := db.First(&wh, d.WebhookID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return Destination{}, errWebhookGone
+			}
+			return Destination{}, err
+		}
+		dest := GovdaoWebhookDestination(wh)
+		dest.Type = d.DestType
+		return dest, nil
+	}
+
+	if d.DestType == "email" {
+		user, err := database.GetUserById(db, d.UserID)
+		if err != nil {
+			return Destination{}, err
+		}
+		if user == nil || user.Email == "" {
+			return Destination{}, errWebhookGone
+		}
+		return Destination{Type: d.DestType, UserID: d.UserID, Email: user.Email}, nil
+	}
+
+	dest := Destination{Type: d.DestType, ChatID: d.ChatID, Channel: d.Channel, Bot: d.Bot}
+	switch d.Bot {
+	case "validator":
+		dest.Token = Config.TokenTelegramValidator
+	case "govdao":
+		dest.Token = Config.TokenTelegramGovdao
+	case "discord":
+		dest.Token = Config.DiscordBot.Token
+	case "slack":
+		dest.Token = Config.SlackApp.BotToken
+	}
+	return dest, nil
+}
+
+// sendDelivery makes one attempt at d.
+func sendDelivery(db *gorm.DB, d database.AlertDelivery) error {
+	n, ok := NotifierFor(d.DestType)
+	if !ok {
+		return fmt.Errorf("no notifier for type %q", d.DestType)
+	}
+	dest, err := deliveryDestination(db, d)
+	if err != nil {
+		return err
+	}
+	return n.Send([]byte(d.Payload), dest)
+}
+
+// DrainAlertDeliveries claims the due deliveries, so other replicas skip
+// them, and makes one attempt at each. Once a destination fails, its later
+// deliveries are released to wait for the retry so they keep their order.
+func DrainAlertDeliveries(db *gorm.DB) {
+	now := time.Now()
+	due, err := database.ClaimAlertDeliveries(db, now, now.Add(deliveryClaimLease), deliveryBatchSize)
+	if err != nil {
+		log.Printf("[delivery] %v", err)
+		return
+	}
+	if len(due) == 0 {
+		return
+	}
+	maxAttempts := database.GetAdminConfigInt(db, "delivery_max_attempts", defaultDeliveryMaxAttempts)
+
+	held := map[string]bool{}
+	for _, d := range due {
+		if held[d.DestKey] {
+			if err := database.ReleaseAlertDelivery(db, d.ID, now); err != nil {
+				log.Printf("[delivery] release #%d: %v", d.ID, err)
+			}
+			continue
+		}
+		err := sendDelivery(db, d)
+		applyDeliveryResult(&d, err, maxAttempts, time.Now())
+		switch d.Status {
+		case database.DeliveryPending:
+			held[d.DestKey] = true
+			log.Printf("⚠️ [delivery] #%d to %s failed (attempt %d/%d), retrying at %s: %v",
+				d.ID, d.DestKey, d.Attempts, maxAttempts, d.NextAttemptAt.Format(time.RFC3339), err)
+		case database.DeliveryDead:
+			log.Printf("❌ [delivery] #%d to %s dead-lettered after %d attempt(s): %v", d.ID, d.DestKey, d.Attempts, err)
+		}
+		if err := database.SaveAlertDeliveryAttempt(db, d); err != nil {
+			log.Printf("[delivery] save #%d: %v", d.ID, err)
+		}
+	}
+}
+
+// StartDeliveryWorker drains the alert_deliveries outbox in the background:
+// right after alerts are queued and every few seconds for retries. Sent
+// deliveries are pruned after a week.
+func StartDeliveryWorker(db *gorm.DB) {
+	go func() {
+		for {
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						log.Printf("[delivery] panic recovered: %v", r)
+					}
+				}()
+
+				ticker := time.NewTicker(deliveryPollInterval)
+				defer ticker.Stop()
+				lastPrune := time.Time{}
+				for {
+					DrainAlertDeliveries(db)
+					if time.Since(lastPrune) > time.Hour {
+						if n, err := database.PruneSentAlertDeliveries(db, time.Now().Add(-deliveryRetention)); err != nil {
+							log.Printf("[delivery] prune failed: %v", err)
+						} else if n > 0 {
+							log.Printf("[delivery] pruned %d sent deliveries", n)
+						}
+						lastPrune = time.Now()
+					}
+					select {
+					case <-ticker.C:
+					case <-deliveryWake:
+					}
+				}
+			}()
+			// Only reached after a panic — brief pause before restarting.
+			log.Printf("[delivery] restarting after panic")
+			time.Sleep(30 * time.Second)
+		}
+	}()
+}
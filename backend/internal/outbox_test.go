@@ -0,0 +1,285 @@
+package internal
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/testoutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	cases := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{"0.25", 250 * time.Millisecond}, // Discord sends fractional seconds
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"soon", 0},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, parseRetryAfter(tc.in, now), "Retry-After %q", tc.in)
+	}
+}
+
+func TestDeliveryBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, deliveryBackoff(1, 0))
+	assert.Equal(t, 60*time.Second, deliveryBackoff(2, 0))
+	assert.Equal(t, 4*time.Minute, deliveryBackoff(4, 0))
+	assert.Equal(t, time.Hour, deliveryBackoff(20, 0), "capped")
+	assert.Equal(t, 7*time.Second, deliveryBackoff(3, 7*time.Second), "Retry-After wins")
+}
+
+func TestApplyDeliveryResult(t *testing.T) {
+	now := time.Now()
+
+	t.Run("success", func(t *testing.T) {
+		d := database.AlertDelivery{Status: database.DeliveryPending, LastError: "old"}
+		applyDeliveryResult(&d, nil, 3, now)
+		assert.Equal(t, database.DeliverySent, d.Status)
+		assert.Equal(t, 1, d.Attempts)
+		assert.Empty(t, d.LastError)
+		require.NotNil(t, d.SentAt)
+	})
+
+	t.Run("server error backs off", func(t *testing.T) {
+		d := database.AlertDelivery{Status: database.DeliveryPending, Attempts: 1}
+		applyDeliveryResult(&d, &DeliveryError{Label: "discord webhook", StatusCode: 502}, 3, now)
+		assert.Equal(t, database.DeliveryPending, d.Status)
+		assert.Equal(t, 2, d.Attempts)
+		assert.Equal(t, now.Add(time.Minute), d.NextAttemptAt)
+	})
+
+	t.Run("rate limit keeps attempt budget and honours Retry-After", func(t *testing.T) {
+		d := database.AlertDelivery{Status: database.DeliveryPending, Attempts: 2}
+		applyDeliveryResult(&d, &DeliveryError{Label: "telegram", StatusCode: 429, RetryAfter: 12 * time.Second}, 3, now)
+		assert.Equal(t, database.DeliveryPending, d.Status)
+		assert.Equal(t, 2, d.Attempts)
+		assert.Equal(t, now.Add(12*time.Second), d.NextAttemptAt)
+	})
+
+	t.Run("permanent client error is dead-lettered at once", func(t *testing.T) {
+		d := database.AlertDelivery{Status: database.DeliveryPending}
+		applyDeliveryResult(&d, &DeliveryError{Label: "discord webhook", StatusCode: 404}, 3, now)
+		assert.Equal(t, database.DeliveryDead, d.Status)
+	})
+
+	t.Run("deleted webhook is dead-lettered at once", func(t *testing.T) {
+		d := database.AlertDelivery{Status: database.DeliveryPending}
+		applyDeliveryResult(&d, errWebhookGone, 3, now)
+		assert.Equal(t, database.DeliveryDead, d.Status)
+	})
+
+	t.Run("attempt budget exhausted", func(t *testing.T) {
+		d := database.AlertDelivery{Status: database.DeliveryPending, Attempts: 2}
+		applyDeliveryResult(&d, errors.New("connection refused"), 3, now)
+		assert.Equal(t, database.DeliveryDead, d.Status)
+		assert.Equal(t, 3, d.Attempts)
+		assert.Equal(t, "connection refused", d.LastError)
+	})
+}
+
+func TestPostJSON_ReturnsDeliveryErrorWithRetryAfter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "3")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	orig := alertHTTPClient
+	alertHTTPClient = &http.Client{Timeout: 10 * time.Second}
+	defer func() { alertHTTPClient = orig }()
+
+	err := postJSON("slack webhook", srv.URL, []byte(`{}`))
+	var de *DeliveryError
+	require.ErrorAs(t, err, &de)
+	assert.Equal(t, http.StatusTooManyRequests, de.StatusCode)
+	assert.Equal(t, 3*time.Second, de.RetryAfter)
+	assert.False(t, de.Permanent())
+}
+
+// TestDrainAlertDeliveries_RetriesInOrder queues a CRITICAL and its RESOLVED
+// for one webhook, fails the first attempt, and checks the RESOLVED is held
+// back until the CRITICAL went through.
+func TestDrainAlertDeliveries_RetriesInOrder(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+
+	var mu sync.Mutex
+	var received []string
+	fail := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if fail {
+			fail = false
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		received = append(received, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	orig := alertHTTPClient
+	alertHTTPClient = &http.Client{Timeout: 10 * time.Second}
+	defer func() { alertHTTPClient = orig }()
+
+	chainID := "test12"
+	wh := database.WebhookValidator{UserID: "u1", URL: srv.URL + "/hook", Type: "discord", ChainID: &chainID}
+	require.NoError(t, db.Create(&wh).Error)
+	dest := ValidatorWebhookDestination(wh)
+
+	critical := AlertData{ChainID: chainID, Level: AlertCritical, Title: "CRITICAL"}
+	resolved := AlertData{ChainID: chainID, Level: AlertResolved, Title: "RESOLVED"}
+	require.NoError(t, EnqueueAlert(db, 1, []Destination{dest}, same(critical)))
+	require.NoError(t, EnqueueAlert(db, 2, []Destination{dest}, same(resolved)))
+
+	DrainAlertDeliveries(db)
+
+	rows, err := database.ListAlertDeliveries(db, "", chainID, 0)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	// Newest first: rows[0] is the RESOLVED, rows[1] the CRITICAL.
+	assert.Equal(t, database.DeliveryPending, rows[1].Status)
+	assert.Equal(t, 1, rows[1].Attempts)
+	assert.Contains(t, rows[1].LastError, "503")
+	assert.Equal(t, database.DeliveryPending, rows[0].Status)
+	assert.Equal(t, 0, rows[0].Attempts, "RESOLVED must wait for the CRITICAL")
+
+	// Make the retry due and drain again: both go out, in order.
+	require.NoError(t, db.Model(&database.AlertDelivery{}).Where("id = ?", rows[1].ID).
+		Update("next_attempt_at", time.Now().Add(-time.Second)).Error)
+	DrainAlertDeliveries(db)
+
+	rows, err = database.ListAlertDeliveries(db, database.DeliverySent, chainID, 0)
+	require.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, []string{"/hook", "/hook"}, received)
+}
+
+func TestClaimAlertDeliveries_ClaimsOnce(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+
+	require.NoError(t, database.EnqueueAlertDeliveries(db, []database.AlertDelivery{
+		{ChainID: "test12", DestKey: "telegram/validator/1", DestType: "telegram", Payload: "{}"},
+		{ChainID: "test12", DestKey: "telegram/validator/2", DestType: "telegram", Payload: "{}"},
+	}))
+
+	now := time.Now()
+	claimed, err := database.ClaimAlertDeliveries(db, now, now.Add(deliveryClaimLease), 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	assert.Less(t, claimed[0].ID, claimed[1].ID, "oldest first")
+
+	again, err := database.ClaimAlertDeliveries(db, now, now.Add(deliveryClaimLease), 10)
+	require.NoError(t, err)
+	assert.Empty(t, again, "a claimed delivery is not handed to a second worker")
+
+	expired, err := database.ClaimAlertDeliveries(db, now.Add(deliveryClaimLease+time.Second), now.Add(2*deliveryClaimLease), 10)
+	require.NoError(t, err)
+	assert.Len(t, expired, 2, "an expired claim is picked up again")
+}
+
+func TestClaimAlertDeliveries_ConcurrentKeepsOrder(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+
+	require.NoError(t, database.EnqueueAlertDeliveries(db, []database.AlertDelivery{
+		{ChainID: "test12", DestKey: "telegram/validator/1", DestType: "telegram", Payload: "1"},
+		{ChainID: "test12", DestKey: "telegram/validator/1", DestType: "telegram", Payload: "2"},
+		{ChainID: "test12", DestKey: "telegram/validator/1", DestType: "telegram", Payload: "3"},
+	}))
+
+	now := time.Now()
+	var wg sync.WaitGroup
+	claimed := make([][]database.AlertDelivery, 4)
+	for i := range claimed {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rows, err := database.ClaimAlertDeliveries(db, now, now.Add(deliveryClaimLease), 1)
+			assert.NoError(t, err)
+			claimed[i] = rows
+		}()
+	}
+	wg.Wait()
+
+	var payloads []string
+	for _, rows := range claimed {
+		for _, r := range rows {
+			payloads = append(payloads, r.Payload)
+		}
+	}
+	assert.Equal(t, []string{"1"}, payloads, "concurrent workers never split a destination's rows")
+}
+
+func TestPersonallySilenceable(t *testing.T) {
+	assert.True(t, personallySilenceable(AlertData{Addr: "g1val", Level: AlertCritical}))
+	assert.True(t, personallySilenceable(AlertData{Addr: "all", Level: AlertWarning}))
+	assert.False(t, personallySilenceable(AlertData{Level: AlertInfo}), "reports are never muted")
+	assert.False(t, personallySilenceable(AlertData{Addr: "g1val", Level: AlertResolved}), "a RESOLVED always goes out")
+	assert.False(t, personallySilenceable(AlertData{Addr: "all", Level: AlertInfo, ResolvedLevel: AlertCritical}))
+
+	assert.Equal(t, "u1", silenceOwner(Destination{Type: "discord", UserID: "u1", WebhookID: 3}))
+	assert.Equal(t, "telegram:42", silenceOwner(Destination{Type: "telegram", ChatID: 42}))
+	assert.Empty(t, silenceOwner(Destination{Type: "discord_bot", ChatID: 42}))
+}
+
+func TestEnqueueAlert_PersonalSilence(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+
+	require.NoError(t, database.CreateSilence(db, &database.Silence{
+		ChainID: "test12", Addr: "g1val", EndAt: time.Now().Add(time.Hour), CreatedBy: "telegram:1",
+	}))
+	dests := []Destination{
+		{Type: "telegram", ChatID: 1, Token: "t", Bot: "validator"},
+		{Type: "telegram", ChatID: 2, Token: "t", Bot: "validator"},
+	}
+	critical := AlertData{ChainID: "test12", Level: AlertCritical, Title: "CRITICAL", Addr: "g1val"}
+	require.NoError(t, EnqueueAlert(db, 0, dests, same(critical)))
+	resolved := AlertData{ChainID: "test12", Level: AlertResolved, Title: "RESOLVED", Addr: "g1val"}
+	require.NoError(t, EnqueueAlert(db, 0, dests, same(resolved)))
+
+	rows, err := database.ListAlertDeliveries(db, "", "test12", 0)
+	require.NoError(t, err)
+	var got []string
+	for _, r := range rows {
+		got = append(got, r.Title+"/"+r.DestKey)
+	}
+	assert.ElementsMatch(t, []string{
+		"CRITICAL/" + deliveryDestKey(dests[1]),
+		"RESOLVED/" + deliveryDestKey(dests[0]),
+		"RESOLVED/" + deliveryDestKey(dests[1]),
+	}, got, "the silence only mutes its own chat, and never a RESOLVED")
+}
+
+func TestReplayAlertDelivery(t *testing.T) {
+	db := testoutils.NewTestDB(t)
+
+	dead := database.AlertDelivery{ChainID: "test12", DestKey: "telegram/validator/1", DestType: "telegram", Payload: "x"}
+	require.NoError(t, database.EnqueueAlertDeliveries(db, []database.AlertDelivery{dead}))
+	require.NoError(t, db.Model(&database.AlertDelivery{}).Where("1 = 1").
+		Updates(map[string]any{"status": database.DeliveryDead, "attempts": 8, "last_error": "boom"}).Error)
+
+	n, err := database.ReplayDeadAlertDeliveries(db, "test12")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	rows, err := database.ListAlertDeliveries(db, database.DeliveryPending, "", 0)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, 0, rows[0].Attempts)
+	assert.Empty(t, rows[0].LastError)
+
+	assert.ErrorIs(t, database.ReplayAlertDelivery(db, rows[0].ID+100), gorm.ErrRecordNotFound)
+}
@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+)
+
+// alertLevelRank orders levels for WebhookFilter.MinLevel.
+var alertLevelRank = map[AlertLevel]int{
+	AlertInfo:     0,
+	AlertWarning:  1,
+	AlertCritical: 2,
+}
+
+// webhookAccepts reports whether a destination with filter f wants d.
+// Notifications without a Kind (reports the user scheduled) always pass.
+// Validator lists only apply to alerts about one validator, so chain-level
+// alerts (stagnation, RPC errors) are never dropped by them.
+func webhookAccepts(f database.WebhookFilter, d AlertData) bool {
+	if d.Kind == "" {
+		return true
+	}
+	if len(f.AlertKinds) > 0 && !slices.Contains(f.AlertKinds, string(d.Kind)) {
+		return false
+	}
+
+	if f.MinLevel != "" {
+		level := d.Level
+		if level == AlertResolved || d.Incident == IncidentResolve {
+			level = d.ResolvedLevel
+		}
+		rank, known := alertLevelRank[level]
+		if known && rank < alertLevelRank[AlertLevel(f.MinLevel)] {
+			return false
+		}
+	}
+
+	if d.Addr == "" || d.Addr == "all" {
+		return true
+	}
+	if len(f.IncludeValidators) > 0 && !matchesValidator(f.IncludeValidators, d.Addr, d.Moniker) {
+		return false
+	}
+	return !matchesValidator(f.ExcludeValidators, d.Addr, d.Moniker)
+}
+
+// matchesValidator reports whether list names addr or (case-insensitively)
+// moniker.
+func matchesValidator(list []string, addr, moniker string) bool {
+	for _, v := range list {
+		if v == addr || (moniker != "" && strings.EqualFold(v, moniker)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+)
+
+func TestWebhookAccepts(t *testing.T) {
+	critical := AlertData{Level: AlertCritical, Kind: AlertKindMissedBlocks, Addr: "g1ours", Moniker: "Ours"}
+	warning := AlertData{Level: AlertWarning, Kind: AlertKindMissedBlocks, Addr: "g1ours", Moniker: "Ours"}
+	theirs := AlertData{Level: AlertCritical, Kind: AlertKindMissedBlocks, Addr: "g1theirs", Moniker: "Theirs"}
+	resolvedWarning := AlertData{Level: AlertResolved, Kind: AlertKindMissedBlocks, Addr: "g1ours", ResolvedLevel: AlertWarning, Incident: IncidentResolve}
+	resolvedCritical := AlertData{Level: AlertResolved, Kind: AlertKindMissedBlocks, Addr: "g1ours", ResolvedLevel: AlertCritical, Incident: IncidentResolve}
+	stuck := AlertData{Level: AlertCritical, Kind: AlertKindStagnation, Addr: "all"}
+	restored := AlertData{Level: AlertInfo, Kind: AlertKindStagnation, Addr: "all", ResolvedLevel: AlertCritical, Incident: IncidentResolve}
+	joined := AlertData{Level: AlertInfo, Kind: AlertKindValsetChange, Addr: "g1new", Moniker: "New"}
+	report := AlertData{Level: AlertInfo, Title: "Validator report"}
+
+	opsChannel := database.WebhookFilter{
+		MinLevel:          "CRITICAL",
+		IncludeValidators: database.StringList{"g1ours"},
+		AlertKinds:        database.StringList{"missed_blocks"},
+	}
+	noNewcomers := database.WebhookFilter{ExcludeValidators: database.StringList{"new"}}
+
+	cases := []struct {
+		name   string
+		filter database.WebhookFilter
+		data   AlertData
+		want   bool
+	}{
+		{"zero filter gets everything", database.WebhookFilter{}, joined, true},
+		{"ops: own critical", opsChannel, critical, true},
+		{"ops: own warning below min level", opsChannel, warning, false},
+		{"ops: other validator", opsChannel, theirs, false},
+		{"ops: resolve of a warning", opsChannel, resolvedWarning, false},
+		{"ops: resolve of a critical", opsChannel, resolvedCritical, true},
+		{"ops: stagnation not subscribed", opsChannel, stuck, false},
+		{"min level judges restore by what it resolves", database.WebhookFilter{MinLevel: "CRITICAL"}, restored, true},
+		{"validator lists ignore chain-level alerts", database.WebhookFilter{IncludeValidators: database.StringList{"g1ours"}}, stuck, true},
+		{"exclude matches moniker case-insensitively", noNewcomers, joined, false},
+		{"reports bypass filters", opsChannel, report, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := webhookAccepts(tc.filter, tc.data); got != tc.want {
+				t.Fatalf("webhookAccepts = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDeliver_SkipsFilteredDestinations(t *testing.T) {
+	var hits []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits = append(hits, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	orig := alertHTTPClient
+	alertHTTPClient = &http.Client{Timeout: 10 * time.Second}
+	defer func() { alertHTTPClient = orig }()
+
+	dests := []Destination{
+		{Type: "discord", WebhookID: 1, URL: srv.URL + "/all"},
+		{Type: "discord", WebhookID: 2, URL: srv.URL + "/critical-only", Filter: database.WebhookFilter{MinLevel: "CRITICAL"}},
+	}
+	d := AlertData{ChainID: "test12", Level: AlertWarning, Title: "WARNING", Kind: AlertKindMissedBlocks, Addr: "g1addr"}
+	if err := Deliver(dests, same(d)); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+	if len(hits) != 1 || hits[0] != "/all" {
+		t.Fatalf("hits = %v, want only /all", hits)
+	}
+}
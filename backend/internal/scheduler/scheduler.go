@@ -5,6 +5,8 @@ import (
 	"log"
 	"sync"
 
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/discord"
 	"github.com/samouraiworld/gnomonitoring/backend/internal/gnovalidator"
 	"gorm.io/gorm"
 )
@@ -22,8 +24,10 @@ func InitScheduler(db *gorm.DB) {
 		db:          db,
 		reloadChans: make(map[string]chan struct{}),
 	}
+	discord.SchedulerInstance = Schedulerinstance
 	Schedulerinstance.StartAll(db)
 	Schedulerinstance.StartAllTelegram(db)
+	Schedulerinstance.StartAllDiscord(db)
 
 	println("Scheduler started")
 }
@@ -144,3 +148,59 @@ func (s *Scheduler) ReloadForTelegram(chatID int64, chainID string, db *gorm.DB)
 	s.StartForTelegram(chatID, chainID, config.DailyReportHour, config.DailyReportMinute, config.Timezone, db)
 	return nil
 }
+
+// StartAllDiscord starts the daily report of every Discord channel that
+// activated it.
+func (s *Scheduler) StartAllDiscord(db *gorm.DB) {
+	reports, err := database.ListActiveDiscordHourReports(db)
+	if err != nil {
+		log.Printf("❌ Failed to fetch discord report hours: %v", err)
+		return
+	}
+	for _, r := range reports {
+		s.StartForDiscord(r.ChannelID, r.ChainID, r.DailyReportHour, r.DailyReportMinute, r.Timezone, db)
+	}
+}
+
+func (s *Scheduler) StartForDiscord(channelID int64, chainID string, hour, minute int, timezone string, db *gorm.DB) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := discordKey(channelID, chainID)
+	if ch, exists := s.reloadChans[key]; exists {
+		close(ch) // sends a stop signal
+	}
+
+	reload := make(chan struct{})
+	s.reloadChans[key] = reload
+	go func() {
+		gnovalidator.SheduleDiscordReport(channelID, chainID, hour, minute, timezone, db, reload)
+	}()
+}
+
+// ReloadForDiscord restarts the daily report of a Discord channel with its
+// stored schedule, or stops it when the channel deactivated it.
+func (s *Scheduler) ReloadForDiscord(channelID int64, chainID string, db *gorm.DB) error {
+	hr, err := database.GetDiscordHourReport(db, channelID, chainID)
+	if err != nil {
+		return fmt.Errorf("failed to reload discord config: %w", err)
+	}
+	if !hr.Activate {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		key := discordKey(channelID, chainID)
+		if ch, exists := s.reloadChans[key]; exists {
+			close(ch)
+			delete(s.reloadChans, key)
+		}
+		return nil
+	}
+	s.StartForDiscord(channelID, chainID, hr.DailyReportHour, hr.DailyReportMinute, hr.Timezone, db)
+	return nil
+}
+
+// discordKey scopes a Discord schedule by channel and chain, like the
+// "tg:" keys of Telegram chats.
+func discordKey(channelID int64, chainID string) string {
+	return fmt.Sprintf("dc:%d:%s", channelID, chainID)
+}
@@ -0,0 +1,242 @@
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/telegram"
+	"gorm.io/gorm"
+)
+
+// maxRequestBody bounds the request body read from Slack.
+const maxRequestBody = 1 << 20
+
+// maxSignatureAge rejects replayed requests whose signed timestamp is older
+// than this.
+const maxSignatureAge = 5 * time.Minute
+
+// Interaction is the subset of a block_actions payload the app reads.
+type Interaction struct {
+	Type        string `json:"type"`
+	ResponseURL string `json:"response_url"`
+	User        struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+	} `json:"user"`
+	Team struct {
+		ID string `json:"id"`
+	} `json:"team"`
+	Channel struct {
+		ID string `json:"id"`
+	} `json:"channel"`
+	Message struct {
+		Blocks []json.RawMessage `json:"blocks"`
+	} `json:"message"`
+	Actions []Action `json:"actions"`
+}
+
+// Action is a clicked button or a submitted text input.
+type Action struct {
+	ActionID string `json:"action_id"`
+	BlockID  string `json:"block_id"`
+	Value    string `json:"value"`
+}
+
+// App is the http.Handler of the Slack endpoint, which receives both the
+// /gnomon slash command and the interactivity payloads. It acknowledges
+// every request at once and answers through its response_url, so slow
+// queries never hit Slack's 3-second deadline.
+type App struct {
+	db             *gorm.DB
+	signingSecret  []byte
+	defaultChainID string
+	enabledChains  []string
+}
+
+// NewApp returns the Slack endpoint of an app whose requests are signed
+// with signingSecret. Channels follow defaultChainID until setchain picks
+// one of enabledChains.
+func NewApp(db *gorm.DB, signingSecret, defaultChainID string, enabledChains []string) *App {
+	return &App{
+		db:             db,
+		signingSecret:  []byte(signingSecret),
+		defaultChainID: defaultChainID,
+		enabledChains:  enabledChains,
+	}
+}
+
+// verifySignature checks the signature Slack puts on every request:
+// "v0=" + hex(HMAC-SHA256(secret, "v0:" + timestamp + ":" + body)).
+func verifySignature(secret []byte, r *http.Request, body []byte, now time.Time) bool {
+	ts := r.Header.Get("X-Slack-Request-Timestamp")
+	secs, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := now.Sub(time.Unix(secs, 0)); age > maxSignatureAge || age < -maxSignatureAge {
+		return false
+	}
+	sig, ok := strings.CutPrefix(r.Header.Get("X-Slack-Signature"), "v0=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("v0:" + ts + ":"))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+func (a *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBody))
+	if err != nil {
+		http.Error(w, "Failed to read body", http.StatusBadRequest)
+		return
+	}
+	if !verifySignature(a.signingSecret, r, body, time.Now()) {
+		http.Error(w, "invalid request signature", http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "Invalid form", http.StatusBadRequest)
+		return
+	}
+
+	if payload := form.Get("payload"); payload != "" {
+		var in Interaction
+		if err := json.Unmarshal([]byte(payload), &in); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		if in.Type == "block_actions" && len(in.Actions) > 0 && in.ResponseURL != "" {
+			go a.handleAction(&in)
+		}
+		return
+	}
+
+	if form.Get("command") == "" || form.Get("response_url") == "" {
+		http.Error(w, "unsupported request", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	go a.answer(form.Get("response_url"), func() Message {
+		ch, ok := a.channel(form.Get("channel_id"), form.Get("team_id"))
+		if !ok {
+			return ephemeral("⚠️ Unable to load this channel.")
+		}
+		return a.runCommand(ch, form.Get("text"))
+	})
+}
+
+// handleAction answers a button click or a search: an alert
+// acknowledgement, a page change or a filtered list.
+func (a *App) handleAction(in *Interaction) {
+	act := in.Actions[0]
+	if act.ActionID == actionAck {
+		id, ok := telegram.ParseAckCallback(act.Value)
+		if ok {
+			a.acknowledge(in, id)
+		}
+		return
+	}
+
+	var req telegram.PageRequest
+	var ok bool
+	switch {
+	case act.ActionID == actionSearch:
+		req, ok = telegram.ParsePageCallback(act.BlockID)
+		req.Filter = strings.TrimSpace(act.Value)
+	case strings.HasPrefix(act.ActionID, actionPage):
+		req, ok = telegram.ParsePageCallback(act.Value)
+	}
+	if !ok {
+		return
+	}
+	a.answer(in.ResponseURL, func() Message {
+		ch, chOK := a.channel(in.Channel.ID, in.Team.ID)
+		if !chOK {
+			return ephemeral("⚠️ Unable to load this channel.")
+		}
+		msg := a.page(ch.ChainID, req)
+		msg.ReplaceOriginal = true
+		return msg
+	})
+}
+
+// acknowledge acknowledges alert id, replaces its button by who is on it
+// and keeps the rest of the alert.
+func (a *App) acknowledge(in *Interaction, id uint) {
+	text, err := a.ack(id, in.User.Username)
+	if err != nil {
+		log.Printf("[slack] ack alert %d failed: %v", id, err)
+		a.answer(in.ResponseURL, func() Message { return ephemeral("⚠️ This alert can no longer be acknowledged.") })
+		return
+	}
+	blocks := make([]any, 0, len(in.Message.Blocks)+1)
+	for _, raw := range in.Message.Blocks {
+		var b struct {
+			BlockID string `json:"block_id"`
+		}
+		if json.Unmarshal(raw, &b) == nil && b.BlockID == blockAck {
+			continue
+		}
+		blocks = append(blocks, raw)
+	}
+	blocks = append(blocks, Block{Type: "section", Text: &Text{Type: "mrkdwn", Text: text}})
+	a.answer(in.ResponseURL, func() Message {
+		return Message{ReplaceOriginal: true, Text: text, Blocks: blocks}
+	})
+}
+
+// answer posts the message built by build to responseURL.
+func (a *App) answer(responseURL string, build func() Message) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[slack] interaction panic: %v", r)
+		}
+	}()
+	if err := respond(responseURL, build()); err != nil {
+		log.Printf("[slack] respond failed: %v", err)
+	}
+}
+
+// channel loads channelID, registering it on the default chain the first
+// time the app is used there.
+func (a *App) channel(channelID, teamID string) (database.SlackChannel, bool) {
+	if channelID == "" {
+		log.Printf("[slack] request without channel_id")
+		return database.SlackChannel{}, false
+	}
+	ch, err := database.EnsureSlackChannel(a.db, channelID, teamID, a.defaultChainID)
+	if err != nil {
+		log.Printf("[slack] %v", err)
+		return database.SlackChannel{}, false
+	}
+	return ch, true
+}
+
+// ephemeral is a message only the user who ran the command sees.
+func ephemeral(md string) Message {
+	msg := textMessage(md)
+	msg.ResponseType = "ephemeral"
+	return msg
+}
@@ -0,0 +1,213 @@
+package slack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/telegram"
+	"gorm.io/gorm"
+)
+
+// pageCommands maps the paginated /gnomon subcommands to their page key.
+var pageCommands = map[string]string{
+	"status":         "health",
+	"rate":           "rate",
+	"uptime":         "uptime",
+	"operation_time": "operation_time",
+	"tx_contrib":     "tx_contrib",
+	"missing":        "missing",
+}
+
+// dailyReport renders the latest daily report of a chain as Block Kit
+// blocks; ok is false when there is nothing to report. It is nil until
+// SetDailyReportRenderer is called (from main.go, once gnovalidator is
+// ready), and /gnomon report then says the report is unavailable.
+var dailyReport func(db *gorm.DB, chainID string) (blocks []any, ok bool)
+
+// SetDailyReportRenderer registers the daily report renderer of
+// /gnomon report.
+func SetDailyReportRenderer(render func(db *gorm.DB, chainID string) (blocks []any, ok bool)) {
+	dailyReport = render
+}
+
+// runCommand answers "/gnomon <text>" run in channel ch. The arguments are
+// the key=value params of the Telegram commands.
+func (a *App) runCommand(ch database.SlackChannel, text string) Message {
+	name, args, _ := strings.Cut(strings.TrimSpace(text), " ")
+	name = strings.ToLower(name)
+	if cmdKey, ok := pageCommands[name]; ok {
+		return a.page(ch.ChainID, telegram.NewPageRequest(cmdKey, telegram.ParseArgs(args)))
+	}
+	switch name {
+	case "subscribe":
+		return textMessage(a.subscribe(ch, strings.Fields(args)))
+	case "report":
+		return a.report(ch.ChainID)
+	case "chain":
+		return ephemeral(a.formatChains(ch.ChainID))
+	case "setchain":
+		return textMessage(a.setChain(ch, args))
+	case "", "help":
+		return ephemeral(formatHelp())
+	}
+	return ephemeral(fmt.Sprintf("Unknown command `%s` ❓ try `/gnomon help`", mrkdwnEscaper.Replace(name)))
+}
+
+// page renders one page of a paginated command.
+func (a *App) page(chainID string, r telegram.PageRequest) Message {
+	text, markup, err := telegram.BuildPage(a.db, chainID, r)
+	if err != nil {
+		log.Printf("[slack] paginated response error cmd=%s: %v", r.Cmd, err)
+		return ephemeral("⚠️ Unable to fetch data.")
+	}
+	return pageMessage(text, markup)
+}
+
+// subscribe handles "subscribe list", "subscribe on|off <addr ...|all>" and
+// the "subscribe <addr ...>" shorthand for on.
+func (a *App) subscribe(ch database.SlackChannel, args []string) string {
+	chainID := ch.ChainID
+	if len(args) == 0 {
+		return subscribeUsage()
+	}
+	action := strings.ToLower(args[0])
+	targets := args[1:]
+	if action != "list" && action != "on" && action != "off" {
+		action, targets = "on", args
+	}
+
+	if action == "list" {
+		subs, err := database.GetSlackValidatorStatusList(a.db, ch.ChannelID, chainID)
+		if err != nil {
+			log.Printf("[slack] subscribe list failed: %v", err)
+			return "⚠️ Unable to fetch list of validators."
+		}
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("🧾 *Subscriptions of this channel* (chain: `%s`)\n", chainID))
+		for _, s := range subs {
+			sb.WriteString(fmt.Sprintf("• %s (`%s`) *%s*\n", mrkdwnEscaper.Replace(s.Moniker), s.Addr, s.Status))
+		}
+		return sb.String()
+	}
+	if len(targets) == 0 {
+		return subscribeUsage()
+	}
+
+	activate := action == "on"
+	var vals []database.AddrMoniker
+	var err error
+	switch {
+	case len(targets) == 1 && strings.EqualFold(targets[0], "all") && activate:
+		vals, err = database.GetAllValidators(a.db, chainID)
+	case len(targets) == 1 && strings.EqualFold(targets[0], "all"):
+		var subs []database.SlackValidatorSub
+		subs, err = database.GetSlackValidatorSubs(a.db, ch.ChannelID, chainID, true)
+		for _, s := range subs {
+			vals = append(vals, database.AddrMoniker{Addr: s.Addr, Moniker: s.Moniker})
+		}
+	default:
+		vals, err = database.ResolveAddrs(a.db, chainID, targets)
+	}
+	if err != nil {
+		log.Printf("[slack] subscribe %s failed: %v", action, err)
+		return "⚠️ Unable to fetch validator list."
+	}
+	if len(vals) == 0 {
+		return "No valid validators found."
+	}
+	var names []string
+	for _, v := range vals {
+		if err := database.SetSlackValidatorSub(a.db, ch.ChannelID, chainID, v.Addr, v.Moniker, activate); err != nil {
+			log.Printf("[slack] SetSlackValidatorSub channel=%s addr=%s: %v", ch.ChannelID, v.Addr, err)
+			continue
+		}
+		names = append(names, mrkdwnEscaper.Replace(v.Moniker))
+	}
+	if activate {
+		return fmt.Sprintf("✅ Enabled alerts for *%d* validators (chain: `%s`): %s", len(names), chainID, strings.Join(names, ", "))
+	}
+	return fmt.Sprintf("🛑 Disabled alerts for *%d* validators (chain: `%s`): %s", len(names), chainID, strings.Join(names, ", "))
+}
+
+func subscribeUsage() string {
+	return "Usage: `/gnomon subscribe list`, `/gnomon subscribe [on] <addr|moniker ...|all>`, `/gnomon subscribe off <addr|moniker ...|all>`"
+}
+
+// report answers with the latest daily report of chainID, rendered like the
+// Slack webhook reports.
+func (a *App) report(chainID string) Message {
+	if dailyReport == nil {
+		return ephemeral("⚠️ The daily report is not available.")
+	}
+	blocks, ok := dailyReport(a.db, chainID)
+	if !ok {
+		return ephemeral(fmt.Sprintf("No daily report for `%s` yet.", chainID))
+	}
+	return Message{ResponseType: "in_channel", Text: fmt.Sprintf("[%s] Daily Summary", chainID), Blocks: blocks}
+}
+
+func (a *App) formatChains(current string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Current chain: `%s`\n\nAvailable chains:\n", current))
+	for _, id := range a.enabledChains {
+		sb.WriteString(fmt.Sprintf("• `%s`\n", id))
+	}
+	sb.WriteString("\nUse `/gnomon setchain <chain>` to switch.")
+	return sb.String()
+}
+
+func (a *App) setChain(ch database.SlackChannel, requested string) string {
+	requested = strings.TrimSpace(requested)
+	valid := false
+	for _, id := range a.enabledChains {
+		if id == requested {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Sprintf("Unknown chain `%s`.\n\n%s", mrkdwnEscaper.Replace(requested), a.formatChains(ch.ChainID))
+	}
+	if err := database.UpdateSlackChannelChain(a.db, ch.ChannelID, requested); err != nil {
+		log.Printf("[slack] UpdateSlackChannelChain channel=%s: %v", ch.ChannelID, err)
+		return "❌ Failed to switch chain. Please try again."
+	}
+	return fmt.Sprintf("Chain set to `%s`.", requested)
+}
+
+// ack acknowledges alert id from its Acknowledge button.
+func (a *App) ack(id uint, user string) (string, error) {
+	al, err := database.AckAlert(a.db, id, "slack:"+user)
+	if err != nil {
+		return "", err
+	}
+	who := al.AckedBy
+	if i := strings.Index(who, ":"); i >= 0 {
+		who = who[i+1:]
+	}
+	target := al.Moniker
+	if al.Addr == "all" {
+		target = "chain"
+	}
+	return fmt.Sprintf("👀 %s alert for *%s* on `%s` acknowledged by %s. Resends are paused until it is resolved.",
+		al.Level, mrkdwnEscaper.Replace(target), al.ChainID, mrkdwnEscaper.Replace(who)), nil
+}
+
+func formatHelp() string {
+	return "🤖 *Gnomonitoring validator bot*\n\n" +
+		"*Validators*\n" +
+		"`/gnomon status [filter=...] [page=N] [limit=N]` — chain health and validators with missed blocks\n" +
+		"`/gnomon rate [period=...] [filter=...] [sort=asc|desc] [limit=N]` — participation rate\n" +
+		"`/gnomon uptime [filter=...] [sort=asc|desc] [limit=N]` — uptime\n" +
+		"`/gnomon operation_time [filter=...] [limit=N]` — time since the validators last went down\n" +
+		"`/gnomon tx_contrib [period=...] [filter=...] [sort=asc|desc] [limit=N]` — share of included transactions\n" +
+		"`/gnomon missing [period=...] [filter=...] [limit=N]` — missed blocks\n" +
+		"`/gnomon report` — latest daily report\n\n" +
+		"*Channel*\n" +
+		"`/gnomon subscribe list|on|off <addr ...|all>` — validator alerts posted in this channel\n" +
+		"`/gnomon chain`, `/gnomon setchain <chain>` — chain followed by this channel\n\n" +
+		"Periods: `current_week`, `current_month`, `current_year`, `all_time`. " +
+		"Use the buttons under a list to page through it or sort it, and its search box to filter it."
+}
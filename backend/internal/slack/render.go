@@ -0,0 +1,223 @@
+package slack
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/telegram"
+)
+
+// Block Kit limits: characters of a section's text, blocks per message,
+// characters of a button label and elements per actions block.
+const (
+	maxSectionText = 3000
+	maxBlocks      = 50
+	maxButtonText  = 75
+	maxElements    = 25
+)
+
+// maxPageSections leaves room under the text of a page for its buttons
+// and search input.
+const maxPageSections = maxBlocks - 5
+
+// Action IDs and block ID of the interactive elements. Page buttons carry
+// their telegram.PageRequest callback in their value; the search input in
+// its block_id.
+const (
+	actionPage   = "page"
+	actionSearch = "search"
+	actionAck    = "ack"
+	blockAck     = "ack"
+)
+
+var htmlTagRe = regexp.MustCompile(`<(/?)(b|strong|i|em|u|s|code|pre|a)(?:\s+href="([^"]*)")?\s*>`)
+
+// mrkdwnEscaper escapes the three characters Slack reserves for its own
+// markup (<@U123>, <url|text>).
+var mrkdwnEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// HTMLToMrkdwn converts the Telegram HTML produced by the telegram
+// formatters (b, i, s, code, pre, a) to Slack mrkdwn. Underline has no
+// mrkdwn equivalent and is dropped; other tags are left as text.
+func HTMLToMrkdwn(s string) string {
+	var b strings.Builder
+	inCode := false
+	var hrefs []string
+
+	writeText := func(t string) {
+		t = mrkdwnEscaper.Replace(html.UnescapeString(t))
+		if inCode {
+			t = strings.ReplaceAll(t, "`", "'")
+		}
+		b.WriteString(t)
+	}
+
+	last := 0
+	for _, m := range htmlTagRe.FindAllStringSubmatchIndex(s, -1) {
+		writeText(s[last:m[0]])
+		last = m[1]
+		closing := m[3] > m[2]
+		switch tag := s[m[4]:m[5]]; tag {
+		case "b", "strong":
+			b.WriteString("*")
+		case "i", "em":
+			b.WriteString("_")
+		case "s":
+			b.WriteString("~")
+		case "code":
+			inCode = !closing
+			b.WriteString("`")
+		case "pre":
+			inCode = !closing
+			b.WriteString("```")
+		case "a":
+			if !closing {
+				href := ""
+				if m[6] >= 0 {
+					href = html.UnescapeString(s[m[6]:m[7]])
+				}
+				hrefs = append(hrefs, href)
+				b.WriteString("<" + href + "|")
+				continue
+			}
+			if len(hrefs) == 0 {
+				continue
+			}
+			hrefs = hrefs[:len(hrefs)-1]
+			b.WriteString(">")
+		}
+	}
+	writeText(s[last:])
+	return b.String()
+}
+
+// truncate cuts s to at most max characters, marking the cut.
+func truncate(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max-1]) + "…"
+}
+
+// sections splits mrkdwn text into section blocks, cutting between lines
+// so each stays under Slack's text limit.
+func sections(md string) []any {
+	var blocks []any
+	var cur strings.Builder
+	flush := func() {
+		if strings.TrimSpace(cur.String()) != "" {
+			blocks = append(blocks, Block{Type: "section", Text: &Text{Type: "mrkdwn", Text: cur.String()}})
+		}
+		cur.Reset()
+	}
+	for _, line := range strings.Split(md, "\n") {
+		line = truncate(line, maxSectionText)
+		if cur.Len() > 0 && len([]rune(cur.String()))+1+len([]rune(line)) > maxSectionText {
+			flush()
+		}
+		if cur.Len() > 0 {
+			cur.WriteString("\n")
+		}
+		cur.WriteString(line)
+	}
+	flush()
+	if len(blocks) > maxPageSections {
+		blocks = append(blocks[:maxPageSections-1], Block{Type: "section",
+			Text: &Text{Type: "mrkdwn", Text: "_… list truncated, use `limit=` or `filter=`_"}})
+	}
+	return blocks
+}
+
+// Buttons converts a Telegram inline keyboard to Block Kit: each row
+// becomes an actions block whose buttons carry the same pagination state as
+// a Telegram tap, and the Search button becomes a text input that reloads
+// the list with the typed filter when Enter is pressed.
+func Buttons(markup *telegram.InlineKeyboardMarkup) []any {
+	var blocks []any
+	if markup == nil {
+		return blocks
+	}
+	for i, kbRow := range markup.InlineKeyboard {
+		row := Block{Type: "actions"}
+		var search *Block
+		for j, btn := range kbRow {
+			label := &Text{Type: "plain_text", Text: truncate(btn.Text, maxButtonText)}
+			if req, ok := telegram.ParsePageCallback(btn.CallbackData); ok && req.Action == "search" {
+				search = searchInput(req)
+				continue
+			}
+			switch {
+			case btn.URL != "":
+				row.Elements = append(row.Elements, Element{Type: "button", Text: label, URL: btn.URL, ActionID: fmt.Sprintf("link_%d_%d", i, j)})
+			case btn.CallbackData != "":
+				row.Elements = append(row.Elements, Element{Type: "button", Text: label, Value: btn.CallbackData, ActionID: fmt.Sprintf("%s_%d_%d", actionPage, i, j)})
+			}
+			if len(row.Elements) == maxElements {
+				break
+			}
+		}
+		if len(row.Elements) > 0 {
+			blocks = append(blocks, row)
+		}
+		if search != nil {
+			blocks = append(blocks, *search)
+		}
+	}
+	return blocks
+}
+
+// searchInput is the search box of a list: submitting it reloads the list
+// from page 1 with the period and sort of req.
+func searchInput(req telegram.PageRequest) *Block {
+	req.Action, req.Page, req.Filter = "", 1, ""
+	return &Block{
+		Type:           "input",
+		BlockID:        req.Callback(),
+		DispatchAction: true,
+		Label:          &Text{Type: "plain_text", Text: "🔎 Search"},
+		Element: &Element{
+			Type:        "plain_text_input",
+			ActionID:    actionSearch,
+			Placeholder: &Text{Type: "plain_text", Text: "Moniker or address, then Enter"},
+			MaxLength:   20,
+		},
+	}
+}
+
+// AckBlock is the Acknowledge button of acknowledgeable alerts posted in
+// channels; the click is handled like the Telegram one.
+func AckBlock(alertLogID uint) Block {
+	btn := telegram.AckMarkup(alertLogID).InlineKeyboard[0][0]
+	return Block{Type: "actions", BlockID: blockAck, Elements: []Element{{
+		Type:     "button",
+		Text:     &Text{Type: "plain_text", Text: btn.Text},
+		ActionID: actionAck,
+		Value:    btn.CallbackData,
+	}}}
+}
+
+// pageMessage wraps Telegram HTML text and its buttons as a Slack message.
+func pageMessage(text string, markup *telegram.InlineKeyboardMarkup) Message {
+	md := HTMLToMrkdwn(text)
+	return Message{
+		ResponseType: "in_channel",
+		Text:         firstLine(md),
+		Blocks:       append(sections(md), Buttons(markup)...),
+	}
+}
+
+// textMessage wraps mrkdwn as a message without buttons.
+func textMessage(md string) Message {
+	return Message{ResponseType: "in_channel", Text: firstLine(md), Blocks: sections(md)}
+}
+
+// firstLine is the notification fallback of a message.
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,145 @@
+// Package slack is the Slack app counterpart of the Telegram validator bot.
+// Slack posts the /gnomon slash command and button clicks to one HTTP
+// endpoint (App, see app.go); the same formatters and paginated pages as the
+// Telegram bot answer them, as Block Kit messages. The Web API helpers here
+// post alerts in the channels subscribed to a validator.
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var slackHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// apiBaseURL is the Slack Web API base URL. It is a package variable (not a
+// const) so tests can point it at an httptest server.
+var apiBaseURL = "https://slack.com/api"
+
+// APIError is a failed Slack call. Code is the Web API error code
+// ("channel_not_found", "not_in_channel", ...), which Slack reports with an
+// HTTP 200. RetryAfter is how long Slack asks to wait (HTTP 429 only).
+type APIError struct {
+	StatusCode int
+	Code       string
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("slack http %d: %s", e.StatusCode, e.Code)
+	}
+	return fmt.Sprintf("slack http %d", e.StatusCode)
+}
+
+// Message is a message posted to a response_url. Blocks holds Block values
+// or blocks rendered elsewhere (internal.SlackBlock, raw JSON); Text is the
+// notification fallback.
+type Message struct {
+	ResponseType    string `json:"response_type,omitempty"` // "in_channel" or "ephemeral"
+	ReplaceOriginal bool   `json:"replace_original,omitempty"`
+	Text            string `json:"text"`
+	Blocks          []any  `json:"blocks"`
+}
+
+// Block is a Block Kit layout block built by the app.
+type Block struct {
+	Type           string    `json:"type"`
+	BlockID        string    `json:"block_id,omitempty"`
+	Text           *Text     `json:"text,omitempty"`
+	Elements       []Element `json:"elements,omitempty"`
+	Element        *Element  `json:"element,omitempty"`
+	Label          *Text     `json:"label,omitempty"`
+	DispatchAction bool      `json:"dispatch_action,omitempty"`
+}
+
+// Text is a Block Kit text object.
+type Text struct {
+	Type string `json:"type"` // "mrkdwn" or "plain_text"
+	Text string `json:"text"`
+}
+
+// Element is an interactive element: a button or a text input.
+type Element struct {
+	Type        string `json:"type"`
+	Text        *Text  `json:"text,omitempty"`
+	ActionID    string `json:"action_id,omitempty"`
+	Value       string `json:"value,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Placeholder *Text  `json:"placeholder,omitempty"`
+	MaxLength   int    `json:"max_length,omitempty"`
+}
+
+// PostMessage posts body (a JSON message with text and blocks) in channel
+// as the app's bot user (chat.postMessage).
+func PostMessage(token, channel string, body []byte) error {
+	if token == "" {
+		return fmt.Errorf("slack bot token is empty")
+	}
+	var msg map[string]json.RawMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return fmt.Errorf("decode message: %w", err)
+	}
+	msg["channel"], _ = json.Marshal(channel)
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+	return doRequest(apiBaseURL+"/chat.postMessage", token, payload)
+}
+
+// respond posts msg to the response_url of a command or an interaction.
+// The URL authorizes up to five answers within 30 minutes.
+func respond(responseURL string, msg Message) error {
+	if msg.Blocks == nil {
+		msg.Blocks = []any{}
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+	return doRequest(responseURL, "", body)
+}
+
+// doRequest posts body to url, authenticated as the bot when token is set,
+// and turns a non-2xx answer or an ok=false Web API answer into an
+// *APIError.
+func doRequest(url, token string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := slackHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		secs, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+		return &APIError{StatusCode: resp.StatusCode, Code: "ratelimited", RetryAfter: time.Duration(secs) * time.Second}
+	}
+	if resp.StatusCode/100 != 2 {
+		return &APIError{StatusCode: resp.StatusCode, Code: string(bytes.TrimSpace(raw))}
+	}
+	// response_url answers "ok" as plain text; the Web API answers JSON.
+	var res struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(raw, &res) == nil && !res.OK && res.Error != "" {
+		return &APIError{StatusCode: resp.StatusCode, Code: res.Error}
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package slack
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/telegram"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTMLToMrkdwn(t *testing.T) {
+	cases := []struct{ in, want string }{
+		{"<b>Participation</b> — <code>test12</code>", "*Participation* — `test12`"},
+		{"<i>note</i> &lt;addr&gt; &amp; more", "_note_ &lt;addr&gt; &amp; more"},
+		{`<a href="https://gno.land/r/x?a=1&amp;b=2">proposal</a>`, "<https://gno.land/r/x?a=1&b=2|proposal>"},
+		{"<u>under</u> <s>gone</s>", "under ~gone~"},
+		{"<code>a`b</code>", "`a'b`"},
+		{"<pre>line</pre>", "```line```"},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, HTMLToMrkdwn(c.in), c.in)
+	}
+}
+
+func TestSections_SplitsBetweenLines(t *testing.T) {
+	line := strings.Repeat("x", 1000)
+	blocks := sections(strings.Join([]string{line, line, line, line}, "\n"))
+	require.Len(t, blocks, 2)
+	for _, b := range blocks {
+		assert.LessOrEqual(t, len(b.(Block).Text.Text), maxSectionText)
+	}
+
+	many := strings.Repeat(strings.Repeat("y", 2500)+"\n", maxBlocks)
+	assert.Len(t, sections(many), maxPageSections, "long lists are cut to leave room for the buttons")
+}
+
+func TestButtons_SearchBecomesInput(t *testing.T) {
+	search := telegram.PageRequest{Cmd: "rate", Page: 2, Limit: 10, Period: "current_week", SortOrder: "asc", Action: "search"}
+	markup := &telegram.InlineKeyboardMarkup{InlineKeyboard: [][]telegram.InlineKeyboardButton{
+		{{Text: "⬅️ Prev", CallbackData: "c=rt&p=1&l=10"}, {Text: "➡️ Next", CallbackData: "c=rt&p=3&l=10"}},
+		{{Text: "↕️ Sort %", CallbackData: "c=rt&p=1&l=10&s=d"}, {Text: "🔎 Search", CallbackData: search.Callback()}},
+	}}
+	blocks := Buttons(markup)
+	require.Len(t, blocks, 3)
+
+	nav := blocks[0].(Block)
+	assert.Equal(t, "actions", nav.Type)
+	require.Len(t, nav.Elements, 2)
+	assert.Equal(t, "c=rt&p=3&l=10", nav.Elements[1].Value)
+	assert.NotEqual(t, nav.Elements[0].ActionID, nav.Elements[1].ActionID, "action IDs are unique within a block")
+
+	assert.Len(t, blocks[1].(Block).Elements, 1, "the sort button stays a button")
+
+	input := blocks[2].(Block)
+	assert.Equal(t, "input", input.Type)
+	assert.True(t, input.DispatchAction)
+	assert.Equal(t, actionSearch, input.Element.ActionID)
+	req, ok := telegram.ParsePageCallback(input.BlockID)
+	require.True(t, ok)
+	assert.Equal(t, telegram.PageRequest{Cmd: "rate", Page: 1, Limit: 10, Period: "current_week", SortOrder: "asc"}, req,
+		"a search restarts the list at page 1, keeping the period and sort")
+
+	assert.Empty(t, Buttons(nil))
+}
+
+func TestAckBlock(t *testing.T) {
+	b := AckBlock(42)
+	assert.Equal(t, blockAck, b.BlockID)
+	id, ok := telegram.ParseAckCallback(b.Elements[0].Value)
+	require.True(t, ok)
+	assert.Equal(t, uint(42), id)
+}
+
+// signedRequest signs body like Slack does, with timestamp ts.
+func signedRequest(t *testing.T, secret, body string, ts time.Time) *http.Request {
+	t.Helper()
+	stamp := strconv.FormatInt(ts.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + stamp + ":" + body))
+	req := httptest.NewRequest(http.MethodPost, "/slack/interactions", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", stamp)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func TestServeHTTP_RejectsBadSignatures(t *testing.T) {
+	app := NewApp(nil, "s3cret", "test12", []string{"test12"})
+	body := url.Values{"command": {"/gnomon"}, "text": {"help"}, "response_url": {"https://hooks.slack.com/x"}}.Encode()
+
+	tampered := signedRequest(t, "s3cret", body, time.Now())
+	tampered.Body = io.NopCloser(bytes.NewBufferString(body + "&text=status"))
+
+	for name, req := range map[string]*http.Request{
+		"other secret": signedRequest(t, "other", body, time.Now()),
+		"tampered":     tampered,
+		"stale":        signedRequest(t, "s3cret", body, time.Now().Add(-time.Hour)),
+		"unsigned":     httptest.NewRequest(http.MethodPost, "/slack/interactions", bytes.NewBufferString(body)),
+	} {
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, name)
+	}
+}
+
+func TestServeHTTP_AcknowledgesSignedPayload(t *testing.T) {
+	app := NewApp(nil, "s3cret", "test12", []string{"test12"})
+	payload, err := json.Marshal(map[string]any{"type": "view_submission"})
+	require.NoError(t, err)
+	body := url.Values{"payload": {string(payload)}}.Encode()
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, signedRequest(t, "s3cret", body, time.Now()))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestRunCommand_HelpAndUnknown(t *testing.T) {
+	app := NewApp(nil, "s3cret", "test12", []string{"test12", "gnoland1"})
+	ch := database.SlackChannel{ChannelID: "C1", ChainID: "test12"}
+
+	msg := app.runCommand(ch, "")
+	assert.Equal(t, "ephemeral", msg.ResponseType)
+	assert.Contains(t, msg.Blocks[0].(Block).Text.Text, "/gnomon rate")
+
+	msg = app.runCommand(ch, "frobnicate now")
+	assert.Contains(t, msg.Text, "Unknown command `frobnicate`")
+
+	msg = app.runCommand(ch, "setchain nope")
+	assert.Contains(t, msg.Text, "Unknown chain `nope`")
+
+	msg = app.runCommand(ch, "subscribe")
+	assert.Contains(t, msg.Text, "Usage:")
+
+	msg = app.runCommand(ch, "report")
+	assert.Contains(t, msg.Text, "not available", "no renderer is registered in tests")
+}
+
+// fakeSlackAPI points apiBaseURL at a test server answering status and
+// body, and records the requests it gets.
+func fakeSlackAPI(t *testing.T, status int, header http.Header, body string) chan *http.Request {
+	t.Helper()
+	reqs := make(chan *http.Request, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(b))
+		reqs <- r
+		for k, v := range header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	old := apiBaseURL
+	apiBaseURL = srv.URL
+	t.Cleanup(func() { apiBaseURL = old })
+	return reqs
+}
+
+func TestPostMessage(t *testing.T) {
+	reqs := fakeSlackAPI(t, http.StatusOK, nil, `{"ok":true}`)
+	require.NoError(t, PostMessage("xoxb-token", "C123", []byte(`{"text":"hi","blocks":[]}`)))
+
+	r := <-reqs
+	assert.Equal(t, "/chat.postMessage", r.URL.Path)
+	assert.Equal(t, "Bearer xoxb-token", r.Header.Get("Authorization"))
+	body, _ := io.ReadAll(r.Body)
+	assert.JSONEq(t, `{"channel":"C123","text":"hi","blocks":[]}`, string(body))
+
+	assert.Error(t, PostMessage("", "C123", []byte(`{}`)))
+}
+
+func TestPostMessage_Errors(t *testing.T) {
+	fakeSlackAPI(t, http.StatusOK, nil, `{"ok":false,"error":"not_in_channel"}`)
+	var apiErr *APIError
+	require.ErrorAs(t, PostMessage("xoxb-token", "C123", []byte(`{}`)), &apiErr)
+	assert.Equal(t, "not_in_channel", apiErr.Code)
+
+	fakeSlackAPI(t, http.StatusTooManyRequests, http.Header{"Retry-After": {"30"}}, "")
+	require.ErrorAs(t, PostMessage("xoxb-token", "C123", []byte(`{}`)), &apiErr)
+	assert.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+	assert.Equal(t, 30*time.Second, apiErr.RetryAfter)
+}
+
+func TestRespond_SendsReplaceOriginal(t *testing.T) {
+	reqs := fakeSlackAPI(t, http.StatusOK, nil, "ok")
+	require.NoError(t, respond(apiBaseURL+"/response", Message{ReplaceOriginal: true, Text: "page 2"}))
+
+	r := <-reqs
+	assert.Empty(t, r.Header.Get("Authorization"), "response URLs are authorized by themselves")
+	body, _ := io.ReadAll(r.Body)
+	assert.JSONEq(t, `{"replace_original":true,"text":"page 2","blocks":[]}`, string(body))
+}
@@ -0,0 +1,77 @@
+package telegram
+
+import (
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// PageRequest is one page of a paginated validator command: the state a
+// pagination button carries. Other chat frontends (the Discord bot) render
+// the same pages as the Telegram bot through BuildPage.
+type PageRequest struct {
+	// Cmd is "health" (/status), "rate", "uptime", "operation_time",
+	// "tx_contrib" or "missing".
+	Cmd       string
+	Page      int
+	Limit     int
+	Period    string
+	Filter    string
+	SortOrder string
+	// Action is "search" for the Search button.
+	Action string
+}
+
+// NewPageRequest builds the request of cmdKey from command params (page,
+// limit, period, filter, sort) with the defaults of the Telegram commands.
+func NewPageRequest(cmdKey string, params map[string]string) PageRequest {
+	return PageRequest{
+		Cmd:       cmdKey,
+		Page:      parseIntWithDefault(params["page"], 1, "page"),
+		Limit:     parseIntWithDefault(params["limit"], defaultLimitFor(cmdKey), "limit"),
+		Period:    params["period"],
+		Filter:    params["filter"],
+		SortOrder: normalizeSort(params["sort"]),
+	}
+}
+
+// ParsePageCallback decodes the callback data of a pagination button. ok is
+// false for the other buttons (menu, acknowledgement).
+func ParsePageCallback(data string) (PageRequest, bool) {
+	cmdKey, page, limit, period, filter, sortOrder, action, ok := parseCallbackData(data)
+	if !ok || !supportsSearch(cmdKey) {
+		return PageRequest{}, false
+	}
+	return PageRequest{Cmd: cmdKey, Page: page, Limit: limit, Period: period, Filter: filter, SortOrder: sortOrder, Action: action}, true
+}
+
+// Callback encodes r as button callback data.
+func (r PageRequest) Callback() string {
+	return encodeCallbackData(r.Cmd, r.Page, r.Limit, r.Period, r.Filter, r.SortOrder, r.Action)
+}
+
+// BuildPage renders r for chainID as Telegram HTML, with its pagination
+// buttons (nil when there are none).
+func BuildPage(db *gorm.DB, chainID string, r PageRequest) (string, *InlineKeyboardMarkup, error) {
+	return buildPaginatedResponse(db, chainID, r.Cmd, r.Period, r.Filter, r.Page, r.Limit, r.SortOrder)
+}
+
+// ParseAckCallback returns the alert ID of an Acknowledge button's callback
+// data (see AckMarkup).
+func ParseAckCallback(data string) (uint, bool) {
+	params := parseCallbackParams(data)
+	if params["c"] != ackCallbackCode {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(params["id"], 10, 64)
+	if err != nil || id == 0 {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// ParseArgs reads the key=value arguments of a command, like the Telegram
+// handlers do.
+func ParseArgs(args string) map[string]string {
+	return parseParams(args)
+}
@@ -42,9 +42,29 @@ type message struct {
 }
 type callbackQuery struct {
 	ID      string   `json:"id"`
+	From    *tgUser  `json:"from,omitempty"`
 	Message *message `json:"message,omitempty"`
 	Data    string   `json:"data"`
 }
+type tgUser struct {
+	ID        int64  `json:"id"`
+	Username  string `json:"username"`
+	FirstName string `json:"first_name"`
+}
+
+// label identifies the user in acknowledgements: "@username" when set,
+// else the first name, else the numeric ID.
+func (u *tgUser) label() string {
+	switch {
+	case u == nil:
+		return "unknown"
+	case u.Username != "":
+		return "@" + u.Username
+	case u.FirstName != "":
+		return u.FirstName
+	}
+	return fmt.Sprintf("%d", u.ID)
+}
 type update struct {
 	UpdateID      int            `json:"update_id"`
 	Message       *message       `json:"message,omitempty"`
@@ -127,16 +147,31 @@ func SendMessageTelegram(botToken string, chatID int64, text string) error {
 	var res struct {
 		Ok          bool   `json:"ok"`
 		Description string `json:"description"`
+		Parameters  struct {
+			RetryAfter int `json:"retry_after"`
+		} `json:"parameters"`
 	}
 	// always decode (even if 400) to retrieve Description
 	_ = json.NewDecoder(resp.Body).Decode(&res)
 
 	if resp.StatusCode/100 != 2 || !res.Ok {
-		return fmt.Errorf("telegram http %d: %s", resp.StatusCode, res.Description)
+		return &APIError{StatusCode: resp.StatusCode, Description: res.Description, RetryAfter: res.Parameters.RetryAfter}
 	}
 	return nil
 }
 
+// APIError is a failed Bot API call. RetryAfter is the number of seconds
+// Telegram asks to wait before retrying (set on HTTP 429 only).
+type APIError struct {
+	StatusCode  int
+	Description string
+	RetryAfter  int
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram http %d: %s", e.StatusCode, e.Description)
+}
+
 func SendMessageTelegramWithMarkup(botToken string, chatID int64, text string, markup *InlineKeyboardMarkup) error {
 	apiURL := fmt.Sprintf("%s/bot%s/sendMessage", telegramAPIBaseURL, botToken)
 
@@ -171,10 +206,13 @@ func SendMessageTelegramWithMarkup(botToken string, chatID int64, text string, m
 	var res struct {
 		Ok          bool   `json:"ok"`
 		Description string `json:"description"`
+		Parameters  struct {
+			RetryAfter int `json:"retry_after"`
+		} `json:"parameters"`
 	}
 	_ = json.NewDecoder(resp.Body).Decode(&res)
 	if resp.StatusCode/100 != 2 || !res.Ok {
-		return fmt.Errorf("telegram http %d: %s", resp.StatusCode, res.Description)
+		return &APIError{StatusCode: resp.StatusCode, Description: res.Description, RetryAfter: res.Parameters.RetryAfter}
 	}
 	return nil
 }
@@ -238,8 +276,42 @@ func EditMessageTelegramWithMarkup(botToken string, chatID int64, messageID int,
 	return nil
 }
 
+// EditMessageReplyMarkup replaces the inline keyboard of a sent message; a
+// nil markup removes it.
+func EditMessageReplyMarkup(botToken string, chatID int64, messageID int, markup *InlineKeyboardMarkup) error {
+	apiURL := fmt.Sprintf("%s/bot%s/editMessageReplyMarkup", telegramAPIBaseURL, botToken)
+
+	if markup == nil {
+		markup = &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{}}
+	}
+	jsonBody, err := json.Marshal(map[string]any{
+		"chat_id":      chatID,
+		"message_id":   messageID,
+		"reply_markup": markup,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	resp, err := telegramHTTPClient.Post(apiURL, "application/json", bytes.NewReader(jsonBody))
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var res struct {
+		Ok          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&res)
+	if resp.StatusCode/100 != 2 || !res.Ok {
+		return fmt.Errorf("telegram http %d: %s", resp.StatusCode, res.Description)
+	}
+	return nil
+}
+
 func AnswerCallbackQuery(botToken, callbackID string) error {
-	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/answerCallbackQuery", botToken)
+	apiURL := fmt.Sprintf("%s/bot%s/answerCallbackQuery", telegramAPIBaseURL, botToken)
 	body := map[string]any{
 		"callback_query_id": callbackID,
 	}
@@ -262,34 +334,6 @@ func AnswerCallbackQuery(botToken, callbackID string) error {
 	return nil
 }
 
-func MsgTelegram(msg string, token, typeChatid string, db *gorm.DB) (err error) {
-
-	if token == "" {
-		return fmt.Errorf("token is empty")
-	}
-
-	ids, err := database.GetAllChatIDs(db, typeChatid)
-	if err != nil {
-		log.Printf("❌ GetAllChatIDs failed: %v", err)
-		return err
-	}
-
-	for _, chatID := range ids {
-
-		err := SendMessageTelegram(token, chatID, msg)
-		if err != nil {
-			fmt.Printf("❌ Error for  %d: %v\n", chatID, err)
-		} else {
-			fmt.Printf("✅ Msg Send  %d\n Msg: %s", chatID, msg)
-		}
-	}
-
-	return nil
-}
-// MsgTelegramAlert sends msg to every chat that has an active subscription for
-// the given (chainID, addr) pair. chainID is used to scope the subscription
-// lookup so that alerts from one chain do not bleed into chats that are
-// monitoring a different chain.
 // MsgTelegramChain sends msg to every chat of typeChatid that has at least one
 // active subscription for chainID. Use this for chain-level alerts (stuck chain,
 // activity restored, new validator) where no specific validator address is involved.
@@ -314,18 +358,16 @@ func MsgTelegramChain(msg, chainID, token, typeChatid string, db *gorm.DB) error
 	return nil
 }
 
-func MsgTelegramAlert(msg string, addr, chainID, token, typeChatid string, db *gorm.DB) (err error) {
-
-	if token == "" {
-		return fmt.Errorf("token is empty")
-	}
-
+// AlertChatIDs returns the chats of typeChatid holding an active subscription
+// for addr on chainID — the audience of a validator-scoped alert.
+func AlertChatIDs(db *gorm.DB, typeChatid, chainID, addr string) ([]int64, error) {
 	ids, err := database.GetAllChatIDs(db, typeChatid)
 	if err != nil {
 		log.Printf("❌ GetAllChatIDs failed: %v", err)
-		return err
+		return nil, err
 	}
 
+	var out []int64
 	for _, chatID := range ids {
 		// check sub scoped to the chain that produced the alert
 		subs, err := database.GetTelegramValidatorSub(db, chatID, chainID, true)
@@ -343,21 +385,16 @@ func MsgTelegramAlert(msg string, addr, chainID, token, typeChatid string, db *g
 		for _, s := range subs {
 			if s.Addr == addr {
 				matched = true
-				if err := SendMessageTelegram(token, chatID, msg); err != nil {
-					log.Printf("❌ send failed for chat_id=%d: %v", chatID, err)
-				} else {
-					log.Printf("✅ message sent to chat_id=%d (validator=%s)", chatID, addr)
-				}
 				break
 			}
 		}
 		if !matched {
 			log.Printf("↪️  skip chat_id=%d (no sub matching addr=%s on chain=%s)", chatID, addr, chainID)
+			continue
 		}
-
+		out = append(out, chatID)
 	}
-
-	return nil
+	return out, nil
 }
 
 // ============================== handler telegram ==============================
@@ -377,48 +414,22 @@ func extractCommand(msg *message) (cmd, args string, ok bool) {
 	return
 }
 
-// StartCommandLoop continuously reads getUpdates and calls the handlers.
-// - token: ton bot token
-// - stopCtx: to shut down properly (SIGINT/SIGTERM)
-
 // StartCommandLoop continuously reads getUpdates and calls the handlers.
 // chainID is used when creating the hour-report row for a new validator chat.
 // Pass an empty string for bots that do not need chain-scoped hour reports
-// (e.g. govdao).
-func StartCommandLoop(stopCtx context.Context, token string, handlers map[string]func(int64, string), callbackHandler func(int64, int, string), typeChatid string, db *gorm.DB, chainID ...string) error {
+// (e.g. govdao). Bots in webhook mode use NewWebhookHandler instead.
+func StartCommandLoop(stopCtx context.Context, token string, handlers map[string]func(int64, string), callbackHandler func(int64, int, string, string), typeChatid string, db *gorm.DB, chainID ...string) error {
 	base := "https://api.telegram.org/bot" + url.PathEscape(token) + "/getUpdates"
 	offset := 0
 	httpClient := &http.Client{Timeout: 50 * time.Second}
 
-	// Resolve the default chain for InsertChatID.
-	defaultChain := ""
-	if len(chainID) > 0 {
-		defaultChain = chainID[0]
-	}
+	d := newDispatcher(token, handlers, callbackHandler, typeChatid, db, chainID...)
 
-	// Hydrate chatChainState from DB for validator bots on startup.
-	if typeChatid == "validator" {
-		prefs, err := database.GetAllChatChains(db)
-		if err != nil {
-			log.Printf("⚠️ StartCommandLoop: failed to hydrate chatChainState: %v", err)
-		} else {
-			for chatID, cid := range prefs {
-				setActiveChain(chatID, cid)
-			}
-			log.Printf("ℹ️ Hydrated chain preferences for %d validator chats", len(prefs))
-		}
-	}
-
-	// Hydrate govdaoChatChainState from DB for govdao bots on startup.
-	if typeChatid == "govdao" {
-		prefs, err := database.GetAllGovdaoChatChains(db)
-		if err != nil {
-			log.Printf("⚠️ StartCommandLoop: failed to hydrate govdao chatChainState: %v", err)
-		} else {
-			for chatID, cid := range prefs {
-				setGovdaoActiveChain(chatID, cid)
-			}
-			log.Printf("ℹ️ Hydrated chain preferences for %d govdao chats", len(prefs))
+	// getUpdates is refused while a webhook is set, e.g. after switching the
+	// bot back from webhook mode.
+	if token != "" {
+		if err := DeleteWebhook(token); err != nil {
+			log.Printf("⚠️ StartCommandLoop: deleteWebhook failed for %s bot: %v", typeChatid, err)
 		}
 	}
 
@@ -455,57 +466,121 @@ func StartCommandLoop(stopCtx context.Context, token string, handlers map[string
 			if up.UpdateID >= offset {
 				offset = up.UpdateID + 1
 			}
-			if up.CallbackQuery != nil {
-				if up.CallbackQuery.Message == nil || up.CallbackQuery.Message.Chat.ID == 0 {
-					continue
-				}
-				_ = AnswerCallbackQuery(token, up.CallbackQuery.ID)
-				if callbackHandler != nil {
-					go callbackHandler(
-						up.CallbackQuery.Message.Chat.ID,
-						up.CallbackQuery.Message.MessageID,
-						up.CallbackQuery.Data,
-					)
-				}
-				continue
-			}
-			if up.Message == nil || up.Message.Chat.ID == 0 {
-				continue
+			d.dispatch(up)
+		}
+	}
+}
+
+// dispatcher routes the updates of one bot to its handlers. It is shared by
+// the getUpdates loop and the webhook handler.
+type dispatcher struct {
+	token           string
+	handlers        map[string]func(int64, string)
+	callbackHandler func(int64, int, string, string)
+	typeChatid      string
+	db              *gorm.DB
+	defaultChain    string
+}
+
+// newDispatcher returns the dispatcher of a bot and hydrates the per-chat
+// chain preferences of its type from the database.
+func newDispatcher(token string, handlers map[string]func(int64, string), callbackHandler func(int64, int, string, string), typeChatid string, db *gorm.DB, chainID ...string) *dispatcher {
+	// Resolve the default chain for InsertChatID.
+	defaultChain := ""
+	if len(chainID) > 0 {
+		defaultChain = chainID[0]
+	}
+
+	// Hydrate chatChainState from DB for validator bots on startup.
+	if typeChatid == "validator" {
+		prefs, err := database.GetAllChatChains(db)
+		if err != nil {
+			log.Printf("⚠️ StartCommandLoop: failed to hydrate chatChainState: %v", err)
+		} else {
+			for chatID, cid := range prefs {
+				setActiveChain(chatID, cid)
 			}
-			// For save Chat ID into db (fire-and-forget, idempotent upsert)
-			chatIDToInsert := up.Message.Chat.ID
-			go func() {
-				insert, err := database.InsertChatID(db, chatIDToInsert, typeChatid, defaultChain)
-				if err != nil {
-					log.Printf("⚠️ InsertChatID failed for chat_id=%d: %v", chatIDToInsert, err)
-					return
-				}
-				if insert && typeChatid == "govdao" {
-					log.Println("Send ultimate govdao telegram")
-					govdaolist, err := database.GetLastGovDaoInfo(db)
-					if err != nil {
-						log.Printf("error get lastid govdao: %s", err)
-						return
-					}
-					if err := SendReportGovdaoTelegram(govdaolist.ChainID, govdaolist.Id, govdaolist.Title, govdaolist.Url, govdaolist.Tx, token, chatIDToInsert); err != nil {
-						log.Printf("❌ SendReportGovdaoTelegram failed for chat_id=%d: %v", chatIDToInsert, err)
-					}
-				}
-			}()
-			if HandleSearchInput(token, db, typeChatid, up.Message.Chat.ID, up.Message.Text) {
-				continue
+			log.Printf("ℹ️ Hydrated chain preferences for %d validator chats", len(prefs))
+		}
+	}
+
+	// Hydrate govdaoChatChainState from DB for govdao bots on startup.
+	if typeChatid == "govdao" {
+		prefs, err := database.GetAllGovdaoChatChains(db)
+		if err != nil {
+			log.Printf("⚠️ StartCommandLoop: failed to hydrate govdao chatChainState: %v", err)
+		} else {
+			for chatID, cid := range prefs {
+				setGovdaoActiveChain(chatID, cid)
 			}
-			// ========================
-			cmd, args, ok := extractCommand(up.Message)
-			if !ok {
-				continue
+			log.Printf("ℹ️ Hydrated chain preferences for %d govdao chats", len(prefs))
+		}
+	}
+
+	return &dispatcher{
+		token:           token,
+		handlers:        handlers,
+		callbackHandler: callbackHandler,
+		typeChatid:      typeChatid,
+		db:              db,
+		defaultChain:    defaultChain,
+	}
+}
+
+// dispatch handles one update: a button tap goes to the callback handler,
+// a command to its handler.
+func (d *dispatcher) dispatch(up update) {
+	token, db, typeChatid := d.token, d.db, d.typeChatid
+	if up.CallbackQuery != nil {
+		if up.CallbackQuery.Message == nil || up.CallbackQuery.Message.Chat.ID == 0 {
+			return
+		}
+		_ = AnswerCallbackQuery(token, up.CallbackQuery.ID)
+		if d.callbackHandler != nil {
+			go d.callbackHandler(
+				up.CallbackQuery.Message.Chat.ID,
+				up.CallbackQuery.Message.MessageID,
+				up.CallbackQuery.Data,
+				up.CallbackQuery.From.label(),
+			)
+		}
+		return
+	}
+	if up.Message == nil || up.Message.Chat.ID == 0 {
+		return
+	}
+	// For save Chat ID into db (fire-and-forget, idempotent upsert)
+	chatIDToInsert := up.Message.Chat.ID
+	go func() {
+		insert, err := database.InsertChatID(db, chatIDToInsert, typeChatid, d.defaultChain)
+		if err != nil {
+			log.Printf("⚠️ InsertChatID failed for chat_id=%d: %v", chatIDToInsert, err)
+			return
+		}
+		if insert && typeChatid == "govdao" {
+			log.Println("Send ultimate govdao telegram")
+			govdaolist, err := database.GetLastGovDaoInfo(db)
+			if err != nil {
+				log.Printf("error get lastid govdao: %s", err)
+				return
 			}
-			if h, found := handlers[cmd]; found {
-				go h(up.Message.Chat.ID, args) // async to not to block
-			} else if h, found := handlers["*"]; found {
-				go h(up.Message.Chat.ID, cmd+" "+args)
+			if err := SendReportGovdaoTelegram(govdaolist.ChainID, govdaolist.Id, govdaolist.Title, govdaolist.Url, govdaolist.Tx, token, chatIDToInsert); err != nil {
+				log.Printf("❌ SendReportGovdaoTelegram failed for chat_id=%d: %v", chatIDToInsert, err)
 			}
 		}
+	}()
+	if HandleSearchInput(token, db, typeChatid, up.Message.Chat.ID, up.Message.Text) {
+		return
+	}
+	// ========================
+	cmd, args, ok := extractCommand(up.Message)
+	if !ok {
+		return
+	}
+	if h, found := d.handlers[cmd]; found {
+		go h(up.Message.Chat.ID, args) // async to not to block
+	} else if h, found := d.handlers["*"]; found {
+		go h(up.Message.Chat.ID, cmd+" "+args)
 	}
 }
 
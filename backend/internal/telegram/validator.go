@@ -1,6 +1,7 @@
 package telegram
 
 import (
+	"errors"
 	"fmt"
 	"html"
 	"log"
@@ -246,6 +247,14 @@ func BuildTelegramHandlers(token string, db *gorm.DB, defaultChainID string, ena
 			chainID := getActiveChain(chatID, defaultChainID)
 			handleSubscribe(token, db, chatID, chainID, args)
 		},
+		"/silence": func(chatID int64, args string) {
+			chainID := getActiveChain(chatID, defaultChainID)
+			handleSilence(token, db, chatID, chainID, args)
+		},
+		"/watch": func(chatID int64, args string) {
+			chainID := getActiveChain(chatID, defaultChainID)
+			handleWatch(token, db, chatID, chainID, args)
+		},
 		"/uptime": func(chatID int64, args string) {
 			chainID := getActiveChain(chatID, defaultChainID)
 			params := parseParams(args)
@@ -834,12 +843,275 @@ func subscribeUsage() string {
 /subscribe off all — disable all`
 }
 
-func BuildTelegramCallbackHandler(token string, db *gorm.DB, defaultChainID string) func(int64, int, string) {
-	return func(chatID int64, messageID int, data string) {
+// handleSilence manages maintenance silences from a chat:
+//
+//	/silence list
+//	/silence <addr|moniker|all> <duration> [reason...]
+//	/silence off <id>
+//
+// A chat's silences only mute the alerts posted to this chat; the list also
+// shows the global silences admins created. A chat can only end the
+// silences it created.
+func handleSilence(token string, db *gorm.DB, chatID int64, chainID, args string) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 || fields[0] == "help" {
+		_ = SendMessageTelegram(token, chatID, silenceUsage())
+		return
+	}
+	createdBy := fmt.Sprintf("telegram:%d", chatID)
+
+	switch strings.ToLower(fields[0]) {
+	case "list":
+		silences, err := database.ListSilences(db, chainID, createdBy, time.Now())
+		if err != nil {
+			log.Printf("[telegram] silence list failed: %v", err)
+			_ = SendMessageTelegram(token, chatID, "⚠️ Unable to fetch silences.")
+			return
+		}
+		_ = SendMessageTelegram(token, chatID, formatSilences(chainID, silences))
+
+	case "off":
+		if len(fields) < 2 {
+			_ = SendMessageTelegram(token, chatID, "Usage: <code>/silence off &lt;id&gt;</code>")
+			return
+		}
+		id, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			_ = SendMessageTelegram(token, chatID, "⚠️ Invalid silence id.")
+			return
+		}
+		if err := database.EndSilence(db, uint(id), createdBy); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				_ = SendMessageTelegram(token, chatID, "⚠️ No running silence with this id was created from this chat.")
+				return
+			}
+			log.Printf("[telegram] silence off failed: %v", err)
+			_ = SendMessageTelegram(token, chatID, "❌ Failed to end the silence.")
+			return
+		}
+		_ = SendMessageTelegram(token, chatID, fmt.Sprintf("🔔 Silence #%d ended, alerts are back on.", id))
+
+	default:
+		target, d, reason, err := parseSilenceArgs(fields)
+		if err != nil {
+			_ = SendMessageTelegram(token, chatID, "⚠️ "+html.EscapeString(err.Error())+"\n\n"+silenceUsage())
+			return
+		}
+		addr, label := "all", "all validators"
+		if target != "all" {
+			v, err := database.ResolveValidator(db, chainID, target)
+			if err != nil {
+				_ = SendMessageTelegram(token, chatID, fmt.Sprintf("⚠️ Unknown validator <code>%s</code> on <code>%s</code>.",
+					html.EscapeString(target), html.EscapeString(chainID)))
+				return
+			}
+			addr, label = v.Addr, v.Moniker
+		}
+		silence := database.Silence{
+			ChainID:   chainID,
+			Addr:      addr,
+			EndAt:     time.Now().Add(d),
+			Reason:    reason,
+			CreatedBy: createdBy,
+		}
+		if err := database.CreateSilence(db, &silence); err != nil {
+			log.Printf("[telegram] silence create failed: %v", err)
+			_ = SendMessageTelegram(token, chatID, "❌ Failed to create the silence.")
+			return
+		}
+		_ = SendMessageTelegram(token, chatID, fmt.Sprintf(
+			"🔕 Silence #%d: alerts for <b>%s</b> on <code>%s</code> are muted in this chat until %s.\nEnd it early with <code>/silence off %d</code>.",
+			silence.ID, html.EscapeString(label), html.EscapeString(chainID),
+			silence.EndAt.UTC().Format("2006-01-02 15:04 UTC"), silence.ID))
+	}
+}
+
+// parseSilenceArgs parses "<target> <duration> [reason...]". The duration
+// uses Go syntax (90m, 2h, 1h30m) plus a "d" suffix for days, and is capped
+// at database.MaxSilenceDuration.
+func parseSilenceArgs(fields []string) (target string, d time.Duration, reason string, err error) {
+	if len(fields) < 2 {
+		return "", 0, "", fmt.Errorf("a target and a duration are required")
+	}
+	target = fields[0]
+	if strings.EqualFold(target, "all") {
+		target = "all"
+	}
+
+	raw := strings.ToLower(fields[1])
+	if days, ok := strings.CutSuffix(raw, "d"); ok {
+		n, convErr := strconv.Atoi(days)
+		if convErr != nil {
+			return "", 0, "", fmt.Errorf("invalid duration %q", fields[1])
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else if d, err = time.ParseDuration(raw); err != nil {
+		return "", 0, "", fmt.Errorf("invalid duration %q", fields[1])
+	}
+	if d <= 0 {
+		return "", 0, "", fmt.Errorf("duration must be positive")
+	}
+	if d > database.MaxSilenceDuration {
+		return "", 0, "", fmt.Errorf("a silence cannot last more than %d days", int(database.MaxSilenceDuration.Hours()/24))
+	}
+	return target, d, strings.Join(fields[2:], " "), nil
+}
+
+func formatSilences(chainID string, silences []database.Silence) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("🔕 <b>Silences</b> (chain: <code>%s</code>)\n", html.EscapeString(chainID)))
+	if len(silences) == 0 {
+		b.WriteString("No active or upcoming silence.")
+		return b.String()
+	}
+	now := time.Now()
+	for _, s := range silences {
+		state := "until"
+		if s.StartAt.After(now) {
+			state = fmt.Sprintf("from %s until", s.StartAt.UTC().Format("2006-01-02 15:04"))
+		}
+		b.WriteString(fmt.Sprintf("• #%d <code>%s</code> %s %s UTC",
+			s.ID, html.EscapeString(s.Addr), state, s.EndAt.UTC().Format("2006-01-02 15:04")))
+		if strings.HasPrefix(s.CreatedBy, database.AdminSilencePrefix) {
+			b.WriteString(" (global)")
+		}
+		if s.Reason != "" {
+			b.WriteString(" — " + html.EscapeString(s.Reason))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func silenceUsage() string {
+	return `🔕 <b>Silence command</b>
+/silence list — show active and upcoming silences
+/silence addr 2h [reason] — mute a validator's alerts in this chat
+/silence all 30m [reason] — mute every alert of the chain in this chat
+/silence off id — end a silence early`
+}
+
+// handleWatch manages the tx watches of a chat:
+//
+//	/watch list
+//	/watch <realm path|address> [label...]
+//	/watch off <id>
+//
+// Every transaction touching a watched target, failed ones included, is
+// posted to the chat by govdao.WatchTransactions.
+func handleWatch(token string, db *gorm.DB, chatID int64, chainID, args string) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 || fields[0] == "help" {
+		_ = SendMessageTelegram(token, chatID, watchUsage())
+		return
+	}
+	createdBy := fmt.Sprintf("telegram:%d", chatID)
+
+	switch strings.ToLower(fields[0]) {
+	case "list":
+		watches, err := database.ListTxWatches(db, createdBy, chainID)
+		if err != nil {
+			log.Printf("[telegram] watch list failed: %v", err)
+			_ = SendMessageTelegram(token, chatID, "⚠️ Unable to fetch watches.")
+			return
+		}
+		_ = SendMessageTelegram(token, chatID, formatTxWatches(chainID, watches))
+
+	case "off":
+		if len(fields) < 2 {
+			_ = SendMessageTelegram(token, chatID, "Usage: <code>/watch off &lt;id&gt;</code>")
+			return
+		}
+		id, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			_ = SendMessageTelegram(token, chatID, "⚠️ Invalid watch id.")
+			return
+		}
+		if err := database.DeleteTxWatch(db, uint(id), createdBy); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				_ = SendMessageTelegram(token, chatID, "⚠️ No watch with this id was created from this chat.")
+				return
+			}
+			log.Printf("[telegram] watch off failed: %v", err)
+			_ = SendMessageTelegram(token, chatID, "❌ Failed to remove the watch.")
+			return
+		}
+		_ = SendMessageTelegram(token, chatID, fmt.Sprintf("🔭 Watch #%d removed.", id))
+
+	default:
+		target, err := database.NormalizeWatchTarget(fields[0])
+		if err != nil {
+			_ = SendMessageTelegram(token, chatID, "⚠️ "+html.EscapeString(err.Error())+"\n\n"+watchUsage())
+			return
+		}
+		watches, err := database.ListTxWatches(db, createdBy, chainID)
+		if err != nil {
+			log.Printf("[telegram] watch list failed: %v", err)
+			_ = SendMessageTelegram(token, chatID, "❌ Failed to create the watch.")
+			return
+		}
+		for _, w := range watches {
+			if w.Target == target {
+				_ = SendMessageTelegram(token, chatID, fmt.Sprintf("ℹ️ <code>%s</code> is already watched (#%d).",
+					html.EscapeString(target), w.ID))
+				return
+			}
+		}
+		watch := database.TxWatch{
+			ChainID:   chainID,
+			Target:    target,
+			Label:     strings.Join(fields[1:], " "),
+			CreatedBy: createdBy,
+		}
+		if err := database.CreateTxWatch(db, &watch); err != nil {
+			log.Printf("[telegram] watch create failed: %v", err)
+			_ = SendMessageTelegram(token, chatID, "❌ Failed to create the watch.")
+			return
+		}
+		_ = SendMessageTelegram(token, chatID, fmt.Sprintf(
+			"🔭 Watch #%d: transactions touching <code>%s</code> on <code>%s</code> will be posted here.\nStop with <code>/watch off %d</code>.",
+			watch.ID, html.EscapeString(target), html.EscapeString(chainID), watch.ID))
+	}
+}
+
+func formatTxWatches(chainID string, watches []database.TxWatch) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("🔭 <b>Tx watches</b> (chain: <code>%s</code>)\n", html.EscapeString(chainID)))
+	if len(watches) == 0 {
+		b.WriteString("No watch in this chat.")
+		return b.String()
+	}
+	for _, w := range watches {
+		b.WriteString(fmt.Sprintf("• #%d <code>%s</code>", w.ID, html.EscapeString(w.Target)))
+		if w.Label != "" {
+			b.WriteString(" — " + html.EscapeString(w.Label))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func watchUsage() string {
+	return `🔭 <b>Watch command</b>
+/watch list — show the watches of this chat
+/watch gno.land/r/gov/dao [label] — post every transaction calling a realm
+/watch g1... [label] — post every transaction sent by or to an address
+/watch off id — remove a watch`
+}
+
+// BuildTelegramCallbackHandler returns the handler for inline button taps:
+// pagination, the /cmd menu and alert acknowledgements. from identifies the
+// user who tapped (see tgUser.label).
+func BuildTelegramCallbackHandler(token string, db *gorm.DB, defaultChainID string) func(int64, int, string, string) {
+	return func(chatID int64, messageID int, data, from string) {
 		chainID := getActiveChain(chatID, defaultChainID)
 
 		// Parse raw params first so menu callbacks can read them directly.
 		rawParams := parseCallbackParams(data)
+		if rawParams["c"] == ackCallbackCode {
+			handleAckCallback(token, db, chatID, messageID, rawParams["id"], from)
+			return
+		}
 		cmdKey := codeToCmd(rawParams["c"])
 
 		// Dispatch interactive menu callbacks before touching paginated paths.
@@ -878,6 +1150,46 @@ func BuildTelegramCallbackHandler(token string, db *gorm.DB, defaultChainID stri
 	}
 }
 
+// ackCallbackCode is the "c" value of the Acknowledge button on alerts.
+const ackCallbackCode = "ack"
+
+// AckMarkup is the inline keyboard attached to acknowledgeable alerts.
+func AckMarkup(alertLogID uint) *InlineKeyboardMarkup {
+	return &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{{{
+		Text:         "👀 Acknowledge",
+		CallbackData: fmt.Sprintf("c=%s&id=%d", ackCallbackCode, alertLogID),
+	}}}}
+}
+
+// handleAckCallback acknowledges an alert from its inline button, removes
+// the button and tells the chat who is on it.
+func handleAckCallback(token string, db *gorm.DB, chatID int64, messageID int, idStr, from string) {
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		return
+	}
+	alert, err := database.AckAlert(db, uint(id), "telegram:"+from)
+	if err != nil {
+		log.Printf("[telegram] ack alert %d failed: %v", id, err)
+		_ = SendMessageTelegram(token, chatID, "⚠️ This alert can no longer be acknowledged.")
+		return
+	}
+	if err := EditMessageReplyMarkup(token, chatID, messageID, nil); err != nil {
+		log.Printf("[telegram] remove ack button failed: %v", err)
+	}
+	_ = SendMessageTelegram(token, chatID, formatAck(alert))
+}
+
+func formatAck(a database.AlertLog) string {
+	who := strings.TrimPrefix(a.AckedBy, "telegram:")
+	target := a.Moniker
+	if a.Addr == "all" {
+		target = "chain"
+	}
+	return fmt.Sprintf("👀 %s alert for <b>%s</b> on <code>%s</code> acknowledged by %s. Resends are paused until it is resolved.",
+		html.EscapeString(a.Level), html.EscapeString(target), html.EscapeString(a.ChainID), html.EscapeString(who))
+}
+
 func sendPaginatedMessage(token string, chatID int64, db *gorm.DB, chainID, cmdKey, period, filter string, page, limit int, sortOrder string) {
 	msg, markup, err := buildPaginatedResponse(db, chainID, cmdKey, period, filter, page, limit, sortOrder)
 	if err != nil {
@@ -2272,6 +2584,17 @@ func formatHelp() string {
 	b.WriteString("Disable alerts for all validators\n")
 	b.WriteString("• <code>/subscribe off all </code>\n")
 
+	b.WriteString("\n🔕 <b>Maintenance silences</b>\n")
+	b.WriteString("• <code>/silence list</code> — show active and upcoming silences\n")
+	b.WriteString("• <code>/silence [addr|moniker] 2h [reason]</code> — mute a validator's alerts in this chat (max 7d)\n")
+	b.WriteString("• <code>/silence all 30m [reason]</code> — mute every alert of the chain in this chat\n")
+	b.WriteString("• <code>/silence off [id]</code> — end a silence early\n")
+
+	b.WriteString("\n🔭 <b>Transaction watches</b>\n")
+	b.WriteString("• <code>/watch list</code> — show the watches of this chat\n")
+	b.WriteString("• <code>/watch [realm path|addr] [label]</code> — post every transaction touching it, failed ones included\n")
+	b.WriteString("• <code>/watch off [id]</code> — remove a watch\n")
+
 	b.WriteString("\n📬 <b>Daily report</b>\n")
 	b.WriteString("• <code>/report</code> — show current status\n")
 	b.WriteString("• <code>/report activate=true</code> — enable daily report\n")
@@ -600,3 +600,57 @@ func TestTierEmoji_AllFourTiers(t *testing.T) {
 	assert.Equal(t, "🟡", tierEmoji(score.TierGood))
 	assert.Equal(t, "🟢", tierEmoji(score.TierExcellent))
 }
+
+// -------------------------------------------------------------------------
+// /silence
+// -------------------------------------------------------------------------
+
+// TestParseSilenceArgs covers the "<target> <duration> [reason...]" syntax of
+// /silence, including the "d" day suffix and the maximum duration.
+func TestParseSilenceArgs(t *testing.T) {
+	target, d, reason, err := parseSilenceArgs(strings.Fields("g1abc 90m node upgrade to v2"))
+	require.NoError(t, err)
+	assert.Equal(t, "g1abc", target)
+	assert.Equal(t, 90*time.Minute, d)
+	assert.Equal(t, "node upgrade to v2", reason)
+
+	target, d, reason, err = parseSilenceArgs(strings.Fields("ALL 2d"))
+	require.NoError(t, err)
+	assert.Equal(t, "all", target)
+	assert.Equal(t, 48*time.Hour, d)
+	assert.Empty(t, reason)
+
+	for _, args := range []string{"g1abc", "g1abc soon", "g1abc -1h", "g1abc 8d", "g1abc 200h"} {
+		_, _, _, err := parseSilenceArgs(strings.Fields(args))
+		assert.Error(t, err, "args %q", args)
+	}
+}
+
+// TestPageRequest_CallbackRoundTrip verifies that the exported page state
+// used by the Discord bot survives the callback encoding of the buttons.
+func TestPageRequest_CallbackRoundTrip(t *testing.T) {
+	r := NewPageRequest("tx_contrib", map[string]string{"period": "current_year", "filter": "samourai", "sort": "asc", "limit": "20", "page": "3"})
+	assert.Equal(t, PageRequest{Cmd: "tx_contrib", Page: 3, Limit: 20, Period: "current_year", Filter: "samourai", SortOrder: "asc"}, r)
+
+	got, ok := ParsePageCallback(r.Callback())
+	require.True(t, ok)
+	assert.Equal(t, r, got)
+
+	assert.Equal(t, healthLimitDefault, NewPageRequest("health", nil).Limit)
+
+	_, ok = ParsePageCallback("c=mn&m=root")
+	assert.False(t, ok, "menu buttons are not pages")
+	_, ok = ParsePageCallback("c=ack&id=3")
+	assert.False(t, ok)
+}
+
+func TestParseAckCallback(t *testing.T) {
+	id, ok := ParseAckCallback(AckMarkup(42).InlineKeyboard[0][0].CallbackData)
+	require.True(t, ok)
+	assert.Equal(t, uint(42), id)
+
+	_, ok = ParseAckCallback("c=ack&id=x")
+	assert.False(t, ok)
+	_, ok = ParseAckCallback("c=rt&p=1")
+	assert.False(t, ok)
+}
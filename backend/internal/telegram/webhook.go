@@ -0,0 +1,122 @@
+package telegram
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+
+	"gorm.io/gorm"
+)
+
+// maxUpdateBody bounds the update body read from Telegram.
+const maxUpdateBody = 1 << 20
+
+// secretTokenHeader carries the secret_token given to setWebhook on every
+// update Telegram posts.
+const secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// webhookSecretRe is the charset and length Telegram accepts for a
+// secret_token.
+var webhookSecretRe = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
+// ValidWebhookSecret reports whether secret can be used as a setWebhook
+// secret_token.
+func ValidWebhookSecret(secret string) bool {
+	return webhookSecretRe.MatchString(secret)
+}
+
+// WebhookSecret returns configured, or when it is empty a secret derived
+// from the bot token. The derived secret is the same on every replica, so
+// replicas registering the webhook concurrently agree on it.
+func WebhookSecret(token, configured string) string {
+	if configured != "" {
+		return configured
+	}
+	sum := sha256.Sum256([]byte("gnomonitoring-telegram-webhook:" + token))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewWebhookHandler serves the updates Telegram posts to a bot in webhook
+// mode. Updates go to the same handlers and callbackHandler as with
+// StartCommandLoop; requests without secret in their secret token header
+// are rejected.
+func NewWebhookHandler(token, secret string, handlers map[string]func(int64, string), callbackHandler func(int64, int, string, string), typeChatid string, db *gorm.DB, chainID ...string) http.Handler {
+	d := newDispatcher(token, handlers, callbackHandler, typeChatid, db, chainID...)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get(secretTokenHeader)), []byte(secret)) != 1 {
+			http.Error(w, "invalid secret token", http.StatusUnauthorized)
+			return
+		}
+		var up update
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxUpdateBody)).Decode(&up); err != nil {
+			// Telegram retries non-2xx answers; a malformed update never
+			// gets better.
+			log.Printf("⚠️ %s bot webhook: invalid update: %v", typeChatid, err)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		d.dispatch(up)
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// SetWebhook points the bot at webhookURL (setWebhook). Telegram then posts
+// message and callback_query updates there with secret in the secret token
+// header, and getUpdates stops working until DeleteWebhook.
+func SetWebhook(token, webhookURL, secret string) error {
+	return callBotAPI(token, "setWebhook", map[string]any{
+		"url":             webhookURL,
+		"secret_token":    secret,
+		"allowed_updates": []string{"message", "callback_query"},
+	})
+}
+
+// DeleteWebhook removes the webhook of the bot so getUpdates can be used.
+// Updates queued meanwhile are kept.
+func DeleteWebhook(token string) error {
+	return callBotAPI(token, "deleteWebhook", map[string]any{"drop_pending_updates": false})
+}
+
+// callBotAPI calls a Bot API method that only answers ok or an error.
+func callBotAPI(token, method string, body map[string]any) error {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+	apiURL := fmt.Sprintf("%s/bot%s/%s", telegramAPIBaseURL, token, method)
+	req, err := http.NewRequest(http.MethodPost, apiURL, bytes.NewReader(jsonBody))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := telegramHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var res struct {
+		Ok          bool   `json:"ok"`
+		Description string `json:"description"`
+		Parameters  struct {
+			RetryAfter int `json:"retry_after"`
+		} `json:"parameters"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&res)
+	if resp.StatusCode/100 != 2 || !res.Ok {
+		return &APIError{StatusCode: resp.StatusCode, Description: res.Description, RetryAfter: res.Parameters.RetryAfter}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package telegram
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBotAPI points telegramAPIBaseURL at a test server answering ok and
+// records the method paths and bodies it gets.
+func fakeBotAPI(t *testing.T) chan map[string]any {
+	t.Helper()
+	calls := make(chan map[string]any, 8)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]any{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		body["_path"] = r.URL.Path
+		calls <- body
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(srv.Close)
+	restore := telegramAPIBaseURL
+	telegramAPIBaseURL = srv.URL
+	t.Cleanup(func() { telegramAPIBaseURL = restore })
+	return calls
+}
+
+func TestWebhookSecret(t *testing.T) {
+	derived := WebhookSecret("123:abc", "")
+	assert.Equal(t, derived, WebhookSecret("123:abc", ""), "replicas derive the same secret")
+	assert.NotEqual(t, derived, WebhookSecret("456:def", ""))
+	assert.True(t, ValidWebhookSecret(derived))
+	assert.Equal(t, "my-secret_1", WebhookSecret("123:abc", "my-secret_1"))
+
+	assert.False(t, ValidWebhookSecret(""))
+	assert.False(t, ValidWebhookSecret("has space"))
+	assert.False(t, ValidWebhookSecret(strings.Repeat("a", 257)))
+}
+
+func TestSetWebhook(t *testing.T) {
+	calls := fakeBotAPI(t)
+	require.NoError(t, SetWebhook("tok", "https://mon.example.com/telegram/webhook/validator", "s3cret"))
+
+	call := <-calls
+	assert.Equal(t, "/bottok/setWebhook", call["_path"])
+	assert.Equal(t, "https://mon.example.com/telegram/webhook/validator", call["url"])
+	assert.Equal(t, "s3cret", call["secret_token"])
+	assert.Equal(t, []any{"message", "callback_query"}, call["allowed_updates"])
+}
+
+func TestWebhookHandler_DispatchesCallbacks(t *testing.T) {
+	calls := fakeBotAPI(t)
+	taps := make(chan string, 1)
+	h := NewWebhookHandler("tok", "s3cret", nil, func(chatID int64, messageID int, data, from string) {
+		taps <- data
+	}, "", nil)
+
+	body := `{"update_id":1,"callback_query":{"id":"cb1","from":{"id":5,"username":"alice"},"message":{"message_id":9,"chat":{"id":42}},"data":"c=rt&p=2&l=10"}}`
+	req := httptest.NewRequest(http.MethodPost, "/telegram/webhook/validator", bytes.NewBufferString(body))
+	req.Header.Set(secretTokenHeader, "s3cret")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	select {
+	case data := <-taps:
+		assert.Equal(t, "c=rt&p=2&l=10", data)
+	case <-time.After(2 * time.Second):
+		t.Fatal("callback handler not called")
+	}
+	assert.Equal(t, "/bottok/answerCallbackQuery", (<-calls)["_path"], "the tap is answered like in polling mode")
+}
+
+func TestWebhookHandler_RejectsWrongSecret(t *testing.T) {
+	h := NewWebhookHandler("tok", "s3cret", nil, nil, "", nil)
+	for name, secret := range map[string]string{"missing": "", "wrong": "nope"} {
+		req := httptest.NewRequest(http.MethodPost, "/telegram/webhook/validator", bytes.NewBufferString(`{"update_id":1}`))
+		if secret != "" {
+			req.Header.Set(secretTokenHeader, secret)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, name)
+	}
+}
@@ -3,14 +3,17 @@ package main
 import (
 	"context"
 	"log"
+	"time"
 
 	"github.com/samouraiworld/gnomonitoring/backend/internal"
 	"github.com/samouraiworld/gnomonitoring/backend/internal/api"
 	"github.com/samouraiworld/gnomonitoring/backend/internal/chainmanager"
 	"github.com/samouraiworld/gnomonitoring/backend/internal/database"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/discord"
 	"github.com/samouraiworld/gnomonitoring/backend/internal/gnovalidator"
 	"github.com/samouraiworld/gnomonitoring/backend/internal/govdao"
 	"github.com/samouraiworld/gnomonitoring/backend/internal/scheduler"
+	"github.com/samouraiworld/gnomonitoring/backend/internal/slack"
 	"github.com/samouraiworld/gnomonitoring/backend/internal/telegram"
 	"gorm.io/gorm"
 )
@@ -39,6 +42,42 @@ func startChainMonitoring(db *gorm.DB, chainID string, chainCfg *internal.ChainC
 	go govdao.StartGovDAo(ctx, db, chainID, chainCfg)
 }
 
+// runTelegramBot serves the updates of a Telegram bot: on its API route when
+// the bot is in webhook mode (see internal.TelegramWebhookConfig), else by
+// long-polling getUpdates.
+func runTelegramBot(ctx context.Context, bot, token string, handlers map[string]func(int64, string), callbackHandler func(int64, int, string, string), db *gorm.DB) {
+	wh := internal.Config.TelegramWebhook
+	if !wh.Enabled(bot) {
+		go func() {
+			if err := telegram.StartCommandLoop(ctx, token, handlers, callbackHandler, bot, db, internal.Config.DefaultChain); err != nil {
+				log.Fatalf("[main] %s bot command loop failed: %v", bot, err)
+			}
+		}()
+		return
+	}
+
+	path := "/telegram/webhook/" + bot
+	secret := telegram.WebhookSecret(token, wh.SecretToken)
+	api.HandlePublic(path, telegram.NewWebhookHandler(token, secret, handlers, callbackHandler, bot, db, internal.Config.DefaultChain))
+	go func() {
+		// Every replica registers the same URL and secret; retry until
+		// Telegram accepts it.
+		for {
+			err := telegram.SetWebhook(token, wh.PublicURL+path, secret)
+			if err == nil {
+				log.Printf("[main] %s bot in webhook mode on %s", bot, path)
+				return
+			}
+			log.Printf("[main] %s bot setWebhook failed: %v", bot, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(30 * time.Second):
+			}
+		}
+	}()
+}
+
 func main() {
 	internal.LoadConfig()
 	// ========================Init Flags ==================== //
@@ -145,9 +184,22 @@ func main() {
 		},
 	)
 
+	// /gnomon report renders the daily report with the Slack webhook blocks.
+	slack.SetDailyReportRenderer(func(db *gorm.DB, chainID string) ([]any, bool) {
+		blocks, ok := gnovalidator.DailyReportSlackBlocks(db, chainID)
+		out := make([]any, len(blocks))
+		for i, b := range blocks {
+			out[i] = b
+		}
+		return out, ok
+	})
+
 	// ==================== Load admin thresholds from DB ============ //
 	gnovalidator.LoadThresholds(db)
 
+	// ==================== Alert delivery worker ==================== //
+	internal.StartDeliveryWorker(db) // drain alert_deliveries outbox, retry failed sends
+
 	// ==================== Per-Chain Monitoring Loops =============== //
 	log.Printf("[main] enabled chains (%d): %v", len(internal.EnabledChains), internal.EnabledChains)
 
@@ -180,11 +232,18 @@ func main() {
 	handlers := telegram.BuildTelegramHandlers(internal.Config.TokenTelegramValidator, db, internal.Config.DefaultChain, internal.EnabledChains)
 	callbackHandler := telegram.BuildTelegramCallbackHandler(internal.Config.TokenTelegramValidator, db, internal.Config.DefaultChain)
 
-	go func() {
-		if err := telegram.StartCommandLoop(ctx, internal.Config.TokenTelegramValidator, handlers, callbackHandler, "validator", db, internal.Config.DefaultChain); err != nil {
-			log.Fatalf("[main] validator bot command loop failed: %v", err)
-		}
-	}()
+	runTelegramBot(ctx, "validator", internal.Config.TokenTelegramValidator, handlers, callbackHandler, db)
+
+	// ======================= Discord bot validator ========================= //
+	// The interactions endpoint is served by the API (see api.StartWebhookAPI);
+	// the bot token registers the slash commands at startup.
+	if dc := internal.Config.DiscordBot; dc.Enabled() && dc.Token != "" {
+		go func() {
+			if err := discord.RegisterCommands(dc.ApplicationID, dc.Token, internal.EnabledChains); err != nil {
+				log.Printf("[main] discord command registration failed: %v", err)
+			}
+		}()
+	}
 
 	// ======================= Telegram govdao bot ====================================== //
 	ctxgovdao, cancelgovdao := context.WithCancel(context.Background())
@@ -197,11 +256,7 @@ func main() {
 		internal.EnabledChains,
 	)
 
-	go func() {
-		if err := telegram.StartCommandLoop(ctxgovdao, internal.Config.TokenTelegramGovdao, handlersgovdao, nil, "govdao", db, internal.Config.DefaultChain); err != nil {
-			log.Fatalf("[main] govdao bot command loop failed: %v", err)
-		}
-	}()
+	runTelegramBot(ctxgovdao, "govdao", internal.Config.TokenTelegramGovdao, handlersgovdao, nil, db)
 
 	// ====================== Metrics for prometheus =============================== //
 
@@ -49,6 +49,10 @@ export function getThresholdUnit(key: string): string {
   if (key.includes('hours')) return 'h'
   if (key.includes('days')) return 'days'
   if (key.includes('threshold')) return 'blocks'
+  if (key.endsWith('_multiple')) return '×'
+  if (key.endsWith('_ms')) return 'ms'
+  if (key.startsWith('peer_count')) return 'peers'
+  if (key.endsWith('_ugnot')) return 'ugnot'
   return ''
 }
 
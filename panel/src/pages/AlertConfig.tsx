@@ -44,7 +44,10 @@ export default function AlertConfig() {
   const groups = [
     { title: 'Alert Thresholds', keys: ['warning_threshold', 'critical_threshold'] },
     { title: 'Alert Resend & Silence', keys: ['alert_critical_resend_hours', 'alert_warning_resend_hours', 'dead_validator_silence_days'] },
-    { title: 'Stagnation Detection', keys: ['stagnation_first_alert_seconds', 'stagnation_repeat_minutes'] },
+    { title: 'Stagnation Detection', keys: ['stagnation_first_alert_seconds', 'stagnation_repeat_minutes', 'consensus_round_warning', 'endpoint_lag_blocks'] },
+    { title: 'Block Time', keys: ['block_time_window', 'block_time_warning_multiple', 'block_time_critical_multiple', 'block_time_baseline_ms'] },
+    { title: 'Peers', keys: ['peer_count_min', 'sentry_missing_minutes'] },
+    { title: 'Balances', keys: ['low_balance_ugnot'] },
     { title: 'Monitoring Intervals', keys: ['rpc_error_cooldown_minutes', 'new_validator_scan_minutes', 'alert_check_interval_seconds'] },
     { title: 'Data Retention', keys: ['raw_retention_days', 'aggregator_period_minutes'] },
   ]